@@ -9,6 +9,7 @@ import (
 	"os"
 
 	"github.com/opiproject/godpu/cmd/common"
+	"github.com/opiproject/godpu/cmd/csi"
 	"github.com/opiproject/godpu/cmd/inventory"
 	"github.com/opiproject/godpu/cmd/ipsec"
 	"github.com/opiproject/godpu/cmd/network"
@@ -37,6 +38,7 @@ func NewCommand() *cobra.Command {
 	c.AddCommand(ipsec.NewIPSecCommand())
 	c.AddCommand(storage.NewStorageCommand())
 	c.AddCommand(network.NewNetworkCommand())
+	c.AddCommand(csi.NewCSICommand())
 
 	flags := c.PersistentFlags()
 	flags.String(common.AddrCmdLineArg, "localhost:50151", "address of OPI gRPC server")
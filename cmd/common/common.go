@@ -5,8 +5,12 @@
 package common
 
 import (
+	"errors"
 	"fmt"
+	"io"
 	"os"
+
+	"github.com/opiproject/godpu/storage/nvme"
 )
 
 // AddrCmdLineArg cmdline arg name for address
@@ -25,3 +29,29 @@ func PrintResponse(response string) {
 		fmt.Fprintf(os.Stderr, "Failed to write to stdout: %v\n", err)
 	}
 }
+
+// ReadKeyFile reads an encryption key stored in the file at the given path.
+// The key is read from stdin if path is "-". Otherwise, the file must not be
+// accessible by group or others. Keys are binary, so the content is returned
+// as is.
+func ReadKeyFile(path string) ([]byte, error) {
+	var key []byte
+	var err error
+	if path == "-" {
+		key, err = io.ReadAll(os.Stdin)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read key from stdin: %w", err)
+		}
+	} else {
+		key, err = nvme.ReadSecretFile(path, "key")
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if len(key) == 0 {
+		return nil, errors.New("key is empty")
+	}
+
+	return key, nil
+}
@@ -0,0 +1,93 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (C) 2024 Intel Corporation
+
+package common
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"slices"
+	"syscall"
+
+	"github.com/opiproject/godpu/testing/report"
+	"github.com/spf13/cobra"
+)
+
+// ReportCmdLineArg cmdline arg name for the report format
+const ReportCmdLineArg = "report"
+
+// ReportFileCmdLineArg cmdline arg name for the report file
+const ReportFileCmdLineArg = "report-file"
+
+// ContinueOnFailureCmdLineArg cmdline arg name for continuing after failed
+// test cases
+const ContinueOnFailureCmdLineArg = "continue-on-failure"
+
+// AddReportFlags adds the flags read by ReportFromFlags and the continue on
+// failure flag to cmd and its subcommands
+func AddReportFlags(cmd *cobra.Command) {
+	flags := cmd.PersistentFlags()
+	flags.String(ReportCmdLineArg, "", fmt.Sprintf("write a report of all test cases in one of the formats %v", report.AllFormats))
+	flags.String(ReportFileCmdLineArg, "-", "file the report is written to, stdout if -")
+	flags.Bool(ContinueOnFailureCmdLineArg, false, "continue with the remaining test cases after a failure")
+}
+
+// ReportFromFlags returns the report format and file given on the command
+// line. The format is empty if no report is requested.
+func ReportFromFlags(cmd *cobra.Command) (report.Format, string) {
+	format, err := cmd.Flags().GetString(ReportCmdLineArg)
+	if err != nil {
+		log.Fatalf("error getting %v argument: %v", ReportCmdLineArg, err)
+	}
+	reportFormat := report.Format(format)
+	if reportFormat != "" && !slices.Contains(report.AllFormats, reportFormat) {
+		log.Fatalf("unknown report format %v, expected one of %v", reportFormat, report.AllFormats)
+	}
+
+	reportFile, err := cmd.Flags().GetString(ReportFileCmdLineArg)
+	if err != nil {
+		log.Fatalf("error getting %v argument: %v", ReportFileCmdLineArg, err)
+	}
+	return reportFormat, reportFile
+}
+
+// WriteReport writes r in the given format to the file at path, to stdout
+// if path is "-"
+func WriteReport(r *report.Report, format report.Format, path string) error {
+	if path == "-" {
+		return r.Write(os.Stdout, format)
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := r.Write(file, format); err != nil {
+		_ = file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// NewTestContext returns a context cancelled after the timeout given on the
+// command line or on SIGINT and SIGTERM. A timeout of 0 means none. Resources
+// created by the tests are still deleted once it is cancelled.
+func NewTestContext(cmd *cobra.Command) (context.Context, context.CancelFunc) {
+	timeout, err := cmd.Flags().GetDuration(TimeoutCmdLineArg)
+	if err != nil {
+		log.Fatalf("error getting %v argument: %v", TimeoutCmdLineArg, err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	if timeout == 0 {
+		return ctx, stop
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	return ctx, func() {
+		cancel()
+		stop()
+	}
+}
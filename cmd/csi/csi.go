@@ -0,0 +1,65 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (C) 2024 Intel Corporation
+
+// Package csi implements the CLI command running the reference CSI driver
+package csi
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/opiproject/godpu/cmd/common"
+	"github.com/opiproject/godpu/storage/csi"
+	"github.com/spf13/cobra"
+)
+
+// NewCSICommand runs the reference CSI driver
+func NewCSICommand() *cobra.Command {
+	endpoint := ""
+	opts := csi.Options{}
+	targetAddr := ""
+
+	cmd := &cobra.Command{
+		Use:   "csi",
+		Short: "Runs the reference CSI driver exposing OPI storage over nvme/tcp",
+		Args:  cobra.NoArgs,
+		Run: func(c *cobra.Command, _ []string) {
+			addr, err := c.Flags().GetString(common.AddrCmdLineArg)
+			cobra.CheckErr(err)
+
+			tlsFiles, err := c.Flags().GetString(common.TLSFiles)
+			cobra.CheckErr(err)
+
+			opts.TargetAddr = net.ParseIP(targetAddr)
+			if opts.TargetAddr == nil {
+				cobra.CheckErr(fmt.Errorf("invalid target address: '%s'", targetAddr))
+			}
+
+			driver, err := csi.New(addr, tlsFiles, opts)
+			cobra.CheckErr(err)
+
+			ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+			defer cancel()
+
+			err = driver.Run(ctx, endpoint)
+			cobra.CheckErr(err)
+		},
+	}
+
+	cmd.Flags().StringVar(&endpoint, "endpoint", "unix:///csi/csi.sock", "CSI endpoint in unix:///path or tcp://host:port format")
+	cmd.Flags().StringVar(&opts.Name, "driver-name", csi.DefaultName, "name of the CSI driver")
+	cmd.Flags().StringVar(&opts.NodeID, "node-id", "", "host nqn of the node. The node service is only run if set.")
+	cmd.Flags().StringVar(&targetAddr, "target-addr", "", "ip address hosts connect to for exposed volumes")
+	cmd.Flags().Uint16Var(&opts.TargetPort, "target-port", csi.DefaultTargetPort, "nvme/tcp port hosts connect to")
+	cmd.Flags().Int64Var(&opts.BlockSize, "block-size", csi.DefaultBlockSize, "block size of created volumes")
+	cmd.Flags().Int64Var(&opts.DefaultVolumeSize, "default-volume-size", csi.DefaultVolumeSize, "size of volumes created without capacity")
+	cmd.Flags().Int64Var(&opts.MaxVolumesPerNode, "max-volumes-per-node", 0, "max volumes published to a node, 0 is unlimited")
+
+	cobra.CheckErr(cmd.MarkFlagRequired("target-addr"))
+
+	return cmd
+}
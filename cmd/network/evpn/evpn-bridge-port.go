@@ -9,10 +9,12 @@ package evpn
 import (
 	"context"
 	"log"
+	"net"
 	"time"
 
 	"github.com/opiproject/godpu/cmd/common"
 	"github.com/opiproject/godpu/network"
+	pb "github.com/opiproject/opi-api/network/evpn-gw/v1alpha1/gen/go"
 	"github.com/spf13/cobra"
 )
 
@@ -206,13 +208,15 @@ func ListBridgePorts() *cobra.Command {
 // UpdateBridgePort update the Bridge Port on OPI server
 func UpdateBridgePort() *cobra.Command {
 	var name string
-	var updateMask []string
+	var mac string
+	var bridgePortType string
+	var logicalBridges []string
 	var allowMissing bool
 
 	cmd := &cobra.Command{
 		Use:   "update-bp",
 		Short: "Update the bridge port",
-		Long:  "updates the Bridge Port with updated mask",
+		Long:  "Update the fields of the Bridge Port set on the command line",
 		Run: func(c *cobra.Command, _ []string) {
 			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 
@@ -222,25 +226,47 @@ func UpdateBridgePort() *cobra.Command {
 			addr, err := c.Flags().GetString(common.AddrCmdLineArg)
 			cobra.CheckErr(err)
 
+			updateMask, err := updateMaskFromFlags(c, []updateField{
+				{"mac", "spec.mac_address"},
+				{"type", "spec.ptype"},
+				{"logicalBridges", "spec.logical_bridges"},
+			})
+			cobra.CheckErr(err)
+
+			spec := &pb.BridgePortSpec{
+				Ptype:          network.ParseBridgePortType(bridgePortType),
+				LogicalBridges: logicalBridges,
+			}
+			if mac != "" {
+				spec.MacAddress, err = net.ParseMAC(mac)
+				if err != nil {
+					log.Fatalf("failed to parse mac: %v", err)
+				}
+			}
+
 			evpnClient, err := network.NewBridgePort(addr, tlsFiles)
 			if err != nil {
 				log.Fatalf("could not create gRPC client: %v", err)
 			}
 			defer cancel()
 
-			// grpc call to create the bridge port
-			bridgePort, err := evpnClient.UpdateBridgePort(ctx, name, updateMask, allowMissing)
+			bridgePort, err := evpnClient.UpdateBridgePort(ctx, name, spec, updateMask, allowMissing)
 			if err != nil {
-				log.Fatalf("UpdateBridgePort: Error occurred while creating Bridge Port: %q", err)
+				log.Fatalf("failed to update bridge port: %v", err)
 			}
 
 			log.Println("Updated Bridge Port:")
 			PrintBP(bridgePort)
 		},
 	}
-	cmd.Flags().StringVar(&name, "name", "", "name of the Bridge Port")
-	cmd.Flags().StringSliceVar(&updateMask, "update-mask", nil, "update mask")
+	cmd.Flags().StringVarP(&name, "name", "n", "", "Specify the name of the BridgePort")
+	cmd.Flags().StringVar(&mac, "mac", "", "Specify the MAC address")
+	cmd.Flags().StringVarP(&bridgePortType, "type", "t", "", "Specify the type (access or trunk)")
+	cmd.Flags().StringSliceVar(&logicalBridges, "logicalBridges", []string{}, "Specify VLAN IDs (multiple values supported)")
 	cmd.Flags().BoolVarP(&allowMissing, "allowMissing", "a", false, "allow the missing")
 
+	if err := cmd.MarkFlagRequired("name"); err != nil {
+		log.Fatalf("Error marking flag as required: %v", err)
+	}
 	return cmd
 }
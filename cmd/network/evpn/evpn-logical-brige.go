@@ -13,6 +13,7 @@ import (
 
 	"github.com/opiproject/godpu/cmd/common"
 	"github.com/opiproject/godpu/network"
+	pb "github.com/opiproject/opi-api/network/evpn-gw/v1alpha1/gen/go"
 	"github.com/spf13/cobra"
 )
 
@@ -212,12 +213,15 @@ func ListLogicalBridges() *cobra.Command {
 // UpdateLogicalBridge update Logical Bridge on OPI server
 func UpdateLogicalBridge() *cobra.Command {
 	var name string
+	var vlanID uint32
+	var vni uint32
+	var vtep string
 	var allowMissing bool
-	var updateMask []string
 
 	cmd := &cobra.Command{
 		Use:   "update-lb",
 		Short: "update the logical bridge",
+		Long:  "Update the fields of the logical bridge set on the command line",
 		Run: func(c *cobra.Command, _ []string) {
 			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 
@@ -227,13 +231,31 @@ func UpdateLogicalBridge() *cobra.Command {
 			addr, err := c.Flags().GetString(common.AddrCmdLineArg)
 			cobra.CheckErr(err)
 
+			updateMask, err := updateMaskFromFlags(c, []updateField{
+				{"vlan-id", "spec.vlan_id"},
+				{"vni", "spec.vni"},
+				{"vtep", "spec.vtep_ip_prefix"},
+			})
+			cobra.CheckErr(err)
+
+			spec := &pb.LogicalBridgeSpec{VlanId: vlanID}
+			if vni != 0 {
+				spec.Vni = &vni
+			}
+			if vtep != "" {
+				spec.VtepIpPrefix, err = network.ParseIPAndPrefix(vtep)
+				if err != nil {
+					log.Fatalf("failed to parse vtep: %v", err)
+				}
+			}
+
 			evpnClient, err := network.NewLogicalBridge(addr, tlsFiles)
 			if err != nil {
 				log.Fatalf("could not create gRPC client: %v", err)
 			}
 			defer cancel()
 
-			lb, err := evpnClient.UpdateLogicalBridge(ctx, name, updateMask, allowMissing)
+			lb, err := evpnClient.UpdateLogicalBridge(ctx, name, spec, updateMask, allowMissing)
 			if err != nil {
 				log.Fatalf("failed to update logical bridge: %v", err)
 			}
@@ -241,9 +263,14 @@ func UpdateLogicalBridge() *cobra.Command {
 			PrintLB(lb)
 		},
 	}
-	cmd.Flags().StringVar(&name, "name", "", "name of the logical bridge")
-	cmd.Flags().StringSliceVar(&updateMask, "update-mask", nil, "update mask")
+	cmd.Flags().StringVarP(&name, "name", "n", "", "Specify the name of the logical bridge")
+	cmd.Flags().Uint32VarP(&vlanID, "vlan-id", "v", 0, "Specify the VLAN ID")
+	cmd.Flags().Uint32VarP(&vni, "vni", "i", 0, "Specify the VNI")
+	cmd.Flags().StringVar(&vtep, "vtep", "", "VTEP IP address")
 	cmd.Flags().BoolVarP(&allowMissing, "allowMissing", "a", false, "Specify allow missing")
 
+	if err := cmd.MarkFlagRequired("name"); err != nil {
+		log.Fatalf("Error marking flag as required: %v", err)
+	}
 	return cmd
 }
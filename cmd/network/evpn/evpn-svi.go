@@ -9,10 +9,12 @@ package evpn
 import (
 	"context"
 	"log"
+	"net"
 	"time"
 
 	"github.com/opiproject/godpu/cmd/common"
 	"github.com/opiproject/godpu/network"
+	pb "github.com/opiproject/opi-api/network/evpn-gw/v1alpha1/gen/go"
 	"github.com/spf13/cobra"
 )
 
@@ -214,12 +216,18 @@ func ListSVIs() *cobra.Command {
 // UpdateSVI update the svi on OPI server
 func UpdateSVI() *cobra.Command {
 	var name string
-	var updateMask []string
+	var vrf string
+	var logicalBridge string
+	var mac string
+	var gwIPs []string
+	var ebgp bool
+	var remoteAS uint32
 	var allowMissing bool
 
 	cmd := &cobra.Command{
 		Use:   "update-svi",
 		Short: "update the SVI",
+		Long:  "Update the fields of the SVI set on the command line",
 		Run: func(c *cobra.Command, _ []string) {
 			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 
@@ -229,23 +237,60 @@ func UpdateSVI() *cobra.Command {
 			addr, err := c.Flags().GetString(common.AddrCmdLineArg)
 			cobra.CheckErr(err)
 
+			updateMask, err := updateMaskFromFlags(c, []updateField{
+				{"vrf", "spec.vrf"},
+				{"logicalBridge", "spec.logical_bridge"},
+				{"mac", "spec.mac_address"},
+				{"gw-ips", "spec.gw_ip_prefix"},
+				{"ebgp", "spec.enable_bgp"},
+				{"remote-as", "spec.remote_as"},
+			})
+			cobra.CheckErr(err)
+
+			spec := &pb.SviSpec{
+				Vrf:           vrf,
+				LogicalBridge: logicalBridge,
+				EnableBgp:     ebgp,
+				RemoteAs:      remoteAS,
+			}
+			if mac != "" {
+				spec.MacAddress, err = net.ParseMAC(mac)
+				if err != nil {
+					log.Fatalf("failed to parse mac: %v", err)
+				}
+			}
+			if len(gwIPs) != 0 {
+				spec.GwIpPrefix, err = network.ParseIPPrefixes(gwIPs)
+				if err != nil {
+					log.Fatalf("failed to parse gw-ips: %v", err)
+				}
+			}
+
 			evpnClient, err := network.NewSVI(addr, tlsFiles)
 			if err != nil {
 				log.Fatalf("could not create gRPC client: %v", err)
 			}
 			defer cancel()
 
-			// grpc call to create the bridge port
-			svi, err := evpnClient.UpdateSvi(ctx, name, updateMask, allowMissing)
+			svi, err := evpnClient.UpdateSvi(ctx, name, spec, updateMask, allowMissing)
 			if err != nil {
-				log.Fatalf("GetBridgePort: Error occurred while creating Bridge Port: %q", err)
+				log.Fatalf("failed to update svi: %v", err)
 			}
 			log.Println("Updated SVI:")
 			PrintSvi(svi)
 		},
 	}
-	cmd.Flags().StringSliceVar(&updateMask, "update-mask", nil, "update mask")
+	cmd.Flags().StringVar(&name, "name", "", "SVI Name")
+	cmd.Flags().StringVar(&vrf, "vrf", "", "Must be unique")
+	cmd.Flags().StringVar(&logicalBridge, "logicalBridge", "", "Pair of vni and vlan_id must be unique")
+	cmd.Flags().StringVar(&mac, "mac", "", "GW MAC address")
+	cmd.Flags().StringSliceVar(&gwIPs, "gw-ips", nil, "List of GW IP addresses")
+	cmd.Flags().BoolVar(&ebgp, "ebgp", false, "Enable eBGP in VRF for tenants connected through this SVI")
+	cmd.Flags().Uint32VarP(&remoteAS, "remote-as", "", 0, "The remote AS")
 	cmd.Flags().BoolVarP(&allowMissing, "allowMissing", "a", false, "allow the missing")
 
+	if err := cmd.MarkFlagRequired("name"); err != nil {
+		log.Fatalf("Error marking flag as required: %v", err)
+	}
 	return cmd
 }
@@ -7,6 +7,7 @@
 package evpn
 
 import (
+	"errors"
 	"fmt"
 	"log"
 	"net"
@@ -15,8 +16,29 @@ import (
 	"github.com/PraserX/ipconv"
 	pb "github.com/opiproject/opi-api/network/evpn-gw/v1alpha1/gen/go"
 	pc "github.com/opiproject/opi-api/network/opinetcommon/v1alpha1/gen/go"
+	"github.com/spf13/cobra"
 )
 
+// updateField maps a flag of an update command to the field it updates
+type updateField struct {
+	flag  string
+	field string
+}
+
+// updateMaskFromFlags returns the fields of the flags set on the command line
+func updateMaskFromFlags(c *cobra.Command, fields []updateField) ([]string, error) {
+	updateMask := []string{}
+	for _, f := range fields {
+		if c.Flags().Changed(f.flag) {
+			updateMask = append(updateMask, f.field)
+		}
+	}
+	if len(updateMask) == 0 {
+		return nil, errors.New("no fields to update are specified")
+	}
+	return updateMask, nil
+}
+
 // ComposeComponentsInfo composes the components with their details
 func ComposeComponentsInfo(comp []*pb.Component) string {
 	var status string
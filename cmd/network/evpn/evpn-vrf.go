@@ -13,6 +13,7 @@ import (
 
 	"github.com/opiproject/godpu/cmd/common"
 	"github.com/opiproject/godpu/network"
+	pb "github.com/opiproject/opi-api/network/evpn-gw/v1alpha1/gen/go"
 	"github.com/spf13/cobra"
 )
 
@@ -189,11 +190,14 @@ func ListVRFs() *cobra.Command {
 // UpdateVRF update the vrf on OPI server
 func UpdateVRF() *cobra.Command {
 	var name string
-	var updateMask []string
+	var vni uint32
+	var loopback string
+	var vtep string
 	var allowMissing bool
 	cmd := &cobra.Command{
 		Use:   "update-vrf",
 		Short: "update the VRF",
+		Long:  "Update the fields of the VRF set on the command line",
 		Run: func(c *cobra.Command, _ []string) {
 			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 			tlsFiles, err := c.Flags().GetString(common.TLSFiles)
@@ -202,23 +206,52 @@ func UpdateVRF() *cobra.Command {
 			addr, err := c.Flags().GetString(common.AddrCmdLineArg)
 			cobra.CheckErr(err)
 
+			updateMask, err := updateMaskFromFlags(c, []updateField{
+				{"vni", "spec.vni"},
+				{"loopback", "spec.loopback_ip_prefix"},
+				{"vtep", "spec.vtep_ip_prefix"},
+			})
+			cobra.CheckErr(err)
+
+			spec := &pb.VrfSpec{}
+			if vni != 0 {
+				spec.Vni = &vni
+			}
+			if loopback != "" {
+				spec.LoopbackIpPrefix, err = network.ParseIPAndPrefix(loopback)
+				if err != nil {
+					log.Fatalf("failed to parse loopback: %v", err)
+				}
+			}
+			if vtep != "" {
+				spec.VtepIpPrefix, err = network.ParseIPAndPrefix(vtep)
+				if err != nil {
+					log.Fatalf("failed to parse vtep: %v", err)
+				}
+			}
+
 			evpnClient, err := network.NewVRF(addr, tlsFiles)
 			if err != nil {
 				log.Fatalf("could not create gRPC client: %v", err)
 			}
 			defer cancel()
 
-			// grpc call to create the bridge port
-			vrf, err := evpnClient.UpdateVrf(ctx, name, updateMask, allowMissing)
+			vrf, err := evpnClient.UpdateVrf(ctx, name, spec, updateMask, allowMissing)
 			if err != nil {
-				log.Fatalf("GetBridgePort: Error occurred while creating Bridge Port: %q", err)
+				log.Fatalf("failed to update vrf: %v", err)
 			}
 			log.Println("Updated VRF:")
 			PrintVrf(vrf)
 		},
 	}
 	cmd.Flags().StringVarP(&name, "name", "n", "", "Specify the name of the vrf")
-	cmd.Flags().StringSliceVar(&updateMask, "update-mask", nil, "update mask")
+	cmd.Flags().Uint32VarP(&vni, "vni", "v", 0, "Must be unique ")
+	cmd.Flags().StringVar(&loopback, "loopback", "", "Loopback IP address")
+	cmd.Flags().StringVar(&vtep, "vtep", "", "VTEP IP address")
 	cmd.Flags().BoolVarP(&allowMissing, "allowMissing", "a", false, "allow the missing")
+
+	if err := cmd.MarkFlagRequired("name"); err != nil {
+		log.Fatalf("Error marking flag as required: %v", err)
+	}
 	return cmd
 }
@@ -35,6 +35,7 @@ func NewNetworkCommand() *cobra.Command {
 
 	cmd.AddCommand(NewEvpnCommand())
 	cmd.AddCommand(NewNetIntfCommand())
+	cmd.AddCommand(NewTestCommand())
 
 	return cmd
 }
@@ -0,0 +1,153 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (C) 2024 Intel Corporation
+
+// Package network implements the network related CLI commands
+package network
+
+import (
+	"log"
+	"time"
+
+	"github.com/opiproject/godpu/cmd/common"
+	grpcOpi "github.com/opiproject/godpu/grpc"
+	"github.com/opiproject/godpu/network/test"
+	"github.com/opiproject/godpu/testing/report"
+	"github.com/spf13/cobra"
+)
+
+const (
+	prefixCmdLineArg            = "prefix"
+	randomSuffixCmdLineArg      = "random-suffix"
+	vrfVniCmdLineArg            = "vrf-vni"
+	loopbackCmdLineArg          = "loopback"
+	vtepCmdLineArg              = "vtep"
+	vlanIDCmdLineArg            = "vlan-id"
+	bridgeVniCmdLineArg         = "bridge-vni"
+	sviMacCmdLineArg            = "svi-mac"
+	gwIPsCmdLineArg             = "gw-ips"
+	portMacCmdLineArg           = "port-mac"
+	operStatusTimeoutCmdLineArg = "oper-status-timeout"
+)
+
+// NewTestCommand tests the EVPN gateway API of a server
+func NewTestCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "test",
+		Short: "Test EVPN gateway functionality",
+		Long:  "Creates, checks and deletes a vrf, logical bridge, svi and bridge port",
+		Args:  cobra.NoArgs,
+		Run: func(c *cobra.Command, _ []string) {
+			runTests(c)
+		},
+	}
+
+	common.AddReportFlags(cmd)
+
+	flags := cmd.Flags()
+	defaults := test.DefaultFixtures()
+	flags.String(prefixCmdLineArg, "", "prefix of the ids of all created resources")
+	flags.Bool(randomSuffixCmdLineArg, false, "append a random suffix to the ids of all created resources to isolate parallel runs")
+	flags.Uint32(vrfVniCmdLineArg, defaults.VrfVni, "vni of the created vrf")
+	flags.String(loopbackCmdLineArg, defaults.LoopbackIPPrefix, "loopback ip prefix of the created vrf")
+	flags.String(vtepCmdLineArg, defaults.VtepIPPrefix, "VXLAN tunnel endpoint ip prefix of the created vrf and logical bridge")
+	flags.Uint32(vlanIDCmdLineArg, defaults.VlanID, "vlan id of the created logical bridge")
+	flags.Uint32(bridgeVniCmdLineArg, defaults.BridgeVni, "vni of the created logical bridge")
+	flags.String(sviMacCmdLineArg, defaults.SviMacAddress, "gateway mac address of the created svi")
+	flags.StringSlice(gwIPsCmdLineArg, defaults.GwIPPrefixes, "gateway ip prefixes of the created svi")
+	flags.String(portMacCmdLineArg, defaults.PortMacAddress, "mac address of the created bridge port")
+	flags.Duration(operStatusTimeoutCmdLineArg, defaults.OperStatusTimeout, "how long to wait for created resources to be up and deleted resources to be gone")
+	// the tests wait for the oper status of every resource twice, which
+	// takes longer than the timeout of a single cmd
+	flags.Duration(common.TimeoutCmdLineArg, 5*time.Minute, "timeout for all test cases")
+
+	return cmd
+}
+
+func runTests(cmd *cobra.Command) {
+	addr, err := cmd.Flags().GetString(common.AddrCmdLineArg)
+	if err != nil {
+		log.Fatalf("error getting %v argument: %v", common.AddrCmdLineArg, err)
+	}
+
+	tlsFiles, err := cmd.Flags().GetString(common.TLSFiles)
+	if err != nil {
+		log.Fatalf("error getting %v argument: %v", common.TLSFiles, err)
+	}
+
+	reportFormat, reportFile := common.ReportFromFlags(cmd)
+
+	continueOnFailure, err := cmd.Flags().GetBool(common.ContinueOnFailureCmdLineArg)
+	if err != nil {
+		log.Fatalf("error getting %v argument: %v", common.ContinueOnFailureCmdLineArg, err)
+	}
+
+	fixtures, err := fixturesFromFlags(cmd)
+	if err != nil {
+		log.Fatalf("error getting fixtures: %v", err)
+	}
+
+	// Set up a connection to the server.
+	client, err := grpcOpi.New(addr, tlsFiles)
+	if err != nil {
+		log.Fatalf("error creating new client: %v", err)
+	}
+
+	conn, closer, err := client.NewConn()
+	if err != nil {
+		log.Fatalf("error creating gRPC connection: %v", err)
+	}
+	defer closer()
+
+	ctx, cancel := common.NewTestContext(cmd)
+	defer cancel()
+
+	report := test.NewReport(continueOnFailure)
+	err = test.RunEvpn(ctx, conn, fixtures, report)
+
+	if reportFormat != "" {
+		if err := common.WriteReport(report, reportFormat, reportFile); err != nil {
+			log.Fatalf("error writing report: %v", err)
+		}
+	}
+
+	if err != nil {
+		log.Panicf("evpn tests failed with error: %v", err)
+	}
+	if report.Failures() > 0 {
+		log.Panicf("%d test cases failed: %v", report.Failures(), report.Err())
+	}
+}
+
+// fixturesFromFlags returns the default fixtures with the fixture flags set
+// on the command line applied
+func fixturesFromFlags(cmd *cobra.Command) (*test.Fixtures, error) {
+	flags := cmd.Flags()
+	fixtures := test.DefaultFixtures()
+	var err error
+
+	fixtures.Prefix, err = flags.GetString(prefixCmdLineArg)
+	cobra.CheckErr(err)
+	if randomSuffix, _ := flags.GetBool(randomSuffixCmdLineArg); randomSuffix {
+		fixtures.Suffix = report.RandomSuffix()
+	}
+	fixtures.VrfVni, err = flags.GetUint32(vrfVniCmdLineArg)
+	cobra.CheckErr(err)
+	fixtures.LoopbackIPPrefix, err = flags.GetString(loopbackCmdLineArg)
+	cobra.CheckErr(err)
+	fixtures.VtepIPPrefix, err = flags.GetString(vtepCmdLineArg)
+	cobra.CheckErr(err)
+	fixtures.VlanID, err = flags.GetUint32(vlanIDCmdLineArg)
+	cobra.CheckErr(err)
+	fixtures.BridgeVni, err = flags.GetUint32(bridgeVniCmdLineArg)
+	cobra.CheckErr(err)
+	fixtures.SviMacAddress, err = flags.GetString(sviMacCmdLineArg)
+	cobra.CheckErr(err)
+	fixtures.GwIPPrefixes, err = flags.GetStringSlice(gwIPsCmdLineArg)
+	cobra.CheckErr(err)
+	fixtures.PortMacAddress, err = flags.GetString(portMacCmdLineArg)
+	cobra.CheckErr(err)
+	fixtures.OperStatusTimeout, err = flags.GetDuration(operStatusTimeoutCmdLineArg)
+	cobra.CheckErr(err)
+
+	return fixtures, fixtures.Validate()
+}
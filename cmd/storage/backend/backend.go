@@ -20,6 +20,7 @@ func NewCreateCommand() *cobra.Command {
 	}
 
 	cmd.AddCommand(newCreateNvmeCommand())
+	cmd.AddCommand(newCreateVolumeCommand())
 
 	return cmd
 }
@@ -42,6 +43,25 @@ func newCreateNvmeCommand() *cobra.Command {
 	return cmd
 }
 
+func newCreateVolumeCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "volume",
+		Aliases: []string{"v"},
+		Short:   "Creates volume resource",
+		Args:    cobra.NoArgs,
+		Run: func(c *cobra.Command, _ []string) {
+			err := c.Help()
+			cobra.CheckErr(err)
+		},
+	}
+
+	cmd.AddCommand(newCreateNullVolumeCommand())
+	cmd.AddCommand(newCreateAioVolumeCommand())
+	cmd.AddCommand(newCreateMallocVolumeCommand())
+
+	return cmd
+}
+
 // NewDeleteCommand creates a new command to delete backend resources
 func NewDeleteCommand() *cobra.Command {
 	cmd := &cobra.Command{
@@ -56,6 +76,7 @@ func NewDeleteCommand() *cobra.Command {
 	}
 
 	cmd.AddCommand(newDeleteNvmeCommand())
+	cmd.AddCommand(newDeleteVolumeCommand())
 
 	return cmd
 }
@@ -78,6 +99,25 @@ func newDeleteNvmeCommand() *cobra.Command {
 	return cmd
 }
 
+func newDeleteVolumeCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "volume",
+		Aliases: []string{"v"},
+		Short:   "Deletes volume resource",
+		Args:    cobra.NoArgs,
+		Run: func(c *cobra.Command, _ []string) {
+			err := c.Help()
+			cobra.CheckErr(err)
+		},
+	}
+
+	cmd.AddCommand(newDeleteNullVolumeCommand())
+	cmd.AddCommand(newDeleteAioVolumeCommand())
+	cmd.AddCommand(newDeleteMallocVolumeCommand())
+
+	return cmd
+}
+
 // NewGetCommand creates a new command to get backend resources
 func NewGetCommand() *cobra.Command {
 	cmd := &cobra.Command{
@@ -92,6 +132,7 @@ func NewGetCommand() *cobra.Command {
 	}
 
 	cmd.AddCommand(newGetNvmeCommand())
+	cmd.AddCommand(newGetVolumeCommand())
 
 	return cmd
 }
@@ -110,6 +151,169 @@ func newGetNvmeCommand() *cobra.Command {
 
 	cmd.AddCommand(newGetNvmeControllerCommand())
 	cmd.AddCommand(newGetNvmePathCommand())
+	cmd.AddCommand(newGetNvmeNamespaceCommand())
+
+	return cmd
+}
+
+func newGetVolumeCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "volume",
+		Aliases: []string{"v"},
+		Short:   "Gets volume resource",
+		Args:    cobra.NoArgs,
+		Run: func(c *cobra.Command, _ []string) {
+			err := c.Help()
+			cobra.CheckErr(err)
+		},
+	}
+
+	cmd.AddCommand(newGetNullVolumeCommand())
+	cmd.AddCommand(newGetAioVolumeCommand())
+	cmd.AddCommand(newGetMallocVolumeCommand())
+
+	return cmd
+}
+
+// NewListCommand creates a new command to list backend resources
+func NewListCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "backend",
+		Aliases: []string{"b"},
+		Short:   "Lists backend resource",
+		Args:    cobra.NoArgs,
+		Run: func(c *cobra.Command, _ []string) {
+			err := c.Help()
+			cobra.CheckErr(err)
+		},
+	}
+
+	cmd.AddCommand(newListNvmeCommand())
+
+	return cmd
+}
+
+func newListNvmeCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "nvme",
+		Aliases: []string{"n"},
+		Short:   "Lists nvme resource",
+		Args:    cobra.NoArgs,
+		Run: func(c *cobra.Command, _ []string) {
+			err := c.Help()
+			cobra.CheckErr(err)
+		},
+	}
+
+	cmd.AddCommand(newListNvmeControllerCommand())
+	cmd.AddCommand(newListNvmeNamespaceCommand())
+	cmd.AddCommand(newListNvmePathCommand())
+
+	return cmd
+}
+
+// NewUpdateCommand creates a new command to update backend resources
+func NewUpdateCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "backend",
+		Aliases: []string{"b"},
+		Short:   "Updates backend resource",
+		Args:    cobra.NoArgs,
+		Run: func(c *cobra.Command, _ []string) {
+			err := c.Help()
+			cobra.CheckErr(err)
+		},
+	}
+
+	cmd.AddCommand(newUpdateNvmeCommand())
+
+	return cmd
+}
+
+func newUpdateNvmeCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "nvme",
+		Aliases: []string{"n"},
+		Short:   "Updates nvme resource",
+		Args:    cobra.NoArgs,
+		Run: func(c *cobra.Command, _ []string) {
+			err := c.Help()
+			cobra.CheckErr(err)
+		},
+	}
+
+	cmd.AddCommand(newUpdateNvmeControllerCommand())
+
+	return cmd
+}
+
+// NewResetCommand creates a new command to reset backend resources
+func NewResetCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "backend",
+		Aliases: []string{"b"},
+		Short:   "Resets backend resource",
+		Args:    cobra.NoArgs,
+		Run: func(c *cobra.Command, _ []string) {
+			err := c.Help()
+			cobra.CheckErr(err)
+		},
+	}
+
+	cmd.AddCommand(newResetNvmeCommand())
+
+	return cmd
+}
+
+func newResetNvmeCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "nvme",
+		Aliases: []string{"n"},
+		Short:   "Resets nvme resource",
+		Args:    cobra.NoArgs,
+		Run: func(c *cobra.Command, _ []string) {
+			err := c.Help()
+			cobra.CheckErr(err)
+		},
+	}
+
+	cmd.AddCommand(newResetNvmeControllerCommand())
+
+	return cmd
+}
+
+// NewStatsCommand creates a new command to get statistics of backend resources
+func NewStatsCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "backend",
+		Aliases: []string{"b"},
+		Short:   "Gets statistics of backend resource",
+		Args:    cobra.NoArgs,
+		Run: func(c *cobra.Command, _ []string) {
+			err := c.Help()
+			cobra.CheckErr(err)
+		},
+	}
+
+	cmd.AddCommand(newStatsNvmeCommand())
+
+	return cmd
+}
+
+func newStatsNvmeCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "nvme",
+		Aliases: []string{"n"},
+		Short:   "Gets statistics of nvme resource",
+		Args:    cobra.NoArgs,
+		Run: func(c *cobra.Command, _ []string) {
+			err := c.Help()
+			cobra.CheckErr(err)
+		},
+	}
+
+	cmd.AddCommand(newStatsNvmeControllerCommand())
+	cmd.AddCommand(newStatsNvmePathCommand())
 
 	return cmd
 }
@@ -0,0 +1,131 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (C) 2024 Intel Corporation
+
+// Package backend implements the CLI commands for storage backend
+package backend
+
+import (
+	"context"
+
+	"github.com/opiproject/godpu/cmd/common"
+	backendclient "github.com/opiproject/godpu/storage/backend"
+	"github.com/spf13/cobra"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+func newCreateMallocVolumeCommand() *cobra.Command {
+	id := ""
+	var blockSize int64
+	var blocksCount int64
+	cmd := &cobra.Command{
+		Use:     "malloc",
+		Aliases: []string{"m"},
+		Short:   "Creates malloc volume backed by target memory",
+		Args:    cobra.NoArgs,
+		Run: func(c *cobra.Command, _ []string) {
+			addr, err := c.Flags().GetString(common.AddrCmdLineArg)
+			cobra.CheckErr(err)
+
+			timeout, err := c.Flags().GetDuration(common.TimeoutCmdLineArg)
+			cobra.CheckErr(err)
+
+			tlsFiles, err := c.Flags().GetString(common.TLSFiles)
+			cobra.CheckErr(err)
+
+			client, err := backendclient.New(addr, tlsFiles)
+			cobra.CheckErr(err)
+
+			ctx, cancel := context.WithTimeout(context.Background(), timeout)
+			defer cancel()
+
+			response, err := client.CreateMallocVolume(ctx, id, blockSize, blocksCount)
+			cobra.CheckErr(err)
+
+			common.PrintResponse(response.Name)
+		},
+	}
+
+	cmd.Flags().StringVar(&id, "id", "", "id for created resource. Assigned by server if omitted.")
+	cmd.Flags().Int64Var(&blockSize, "block-size", 512, "block size of the volume in bytes")
+	cmd.Flags().Int64Var(&blocksCount, "blocks-count", 0, "number of blocks in the volume")
+
+	cobra.CheckErr(cmd.MarkFlagRequired("blocks-count"))
+
+	return cmd
+}
+
+func newDeleteMallocVolumeCommand() *cobra.Command {
+	name := ""
+	allowMissing := false
+
+	cmd := &cobra.Command{
+		Use:     "malloc",
+		Aliases: []string{"m"},
+		Short:   "Deletes malloc volume",
+		Args:    cobra.NoArgs,
+		Run: func(c *cobra.Command, _ []string) {
+			addr, err := c.Flags().GetString(common.AddrCmdLineArg)
+			cobra.CheckErr(err)
+
+			timeout, err := c.Flags().GetDuration(common.TimeoutCmdLineArg)
+			cobra.CheckErr(err)
+
+			tlsFiles, err := c.Flags().GetString(common.TLSFiles)
+			cobra.CheckErr(err)
+
+			client, err := backendclient.New(addr, tlsFiles)
+			cobra.CheckErr(err)
+
+			ctx, cancel := context.WithTimeout(context.Background(), timeout)
+			defer cancel()
+
+			err = client.DeleteMallocVolume(ctx, name, allowMissing)
+			cobra.CheckErr(err)
+		},
+	}
+
+	cmd.Flags().StringVar(&name, "name", "", "name of deleted malloc volume")
+	cmd.Flags().BoolVar(&allowMissing, "allowMissing", false, "cmd succeeds if attempts to delete a resource that is not present")
+
+	cobra.CheckErr(cmd.MarkFlagRequired("name"))
+
+	return cmd
+}
+
+func newGetMallocVolumeCommand() *cobra.Command {
+	name := ""
+
+	cmd := &cobra.Command{
+		Use:     "malloc",
+		Aliases: []string{"m"},
+		Short:   "Gets malloc volume",
+		Args:    cobra.NoArgs,
+		Run: func(c *cobra.Command, _ []string) {
+			addr, err := c.Flags().GetString(common.AddrCmdLineArg)
+			cobra.CheckErr(err)
+
+			timeout, err := c.Flags().GetDuration(common.TimeoutCmdLineArg)
+			cobra.CheckErr(err)
+
+			tlsFiles, err := c.Flags().GetString(common.TLSFiles)
+			cobra.CheckErr(err)
+
+			client, err := backendclient.New(addr, tlsFiles)
+			cobra.CheckErr(err)
+
+			ctx, cancel := context.WithTimeout(context.Background(), timeout)
+			defer cancel()
+
+			volume, err := client.GetMallocVolume(ctx, name)
+			cobra.CheckErr(err)
+
+			common.PrintResponse(protojson.Format(volume))
+		},
+	}
+
+	cmd.Flags().StringVar(&name, "name", "", "name of malloc volume to get")
+
+	cobra.CheckErr(cmd.MarkFlagRequired("name"))
+
+	return cmd
+}
@@ -6,6 +6,7 @@ package backend
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strings"
 
@@ -19,6 +20,7 @@ import (
 func newCreateNvmeControllerCommand() *cobra.Command {
 	id := ""
 	multipath := ""
+	opts := backendclient.NvmeControllerOptions{}
 	cmd := &cobra.Command{
 		Use:     "controller",
 		Aliases: []string{"c"},
@@ -40,18 +42,10 @@ func newCreateNvmeControllerCommand() *cobra.Command {
 			ctx, cancel := context.WithTimeout(context.Background(), timeout)
 			defer cancel()
 
-			allowedModes := map[string]pb.NvmeMultipath{
-				"disable":   pb.NvmeMultipath_NVME_MULTIPATH_DISABLE,
-				"failover":  pb.NvmeMultipath_NVME_MULTIPATH_FAILOVER,
-				"multipath": pb.NvmeMultipath_NVME_MULTIPATH_MULTIPATH,
-			}
-
-			mode, ok := allowedModes[strings.ToLower(multipath)]
-			if !ok {
-				cobra.CheckErr(fmt.Errorf("not allowed multipath mode: '%s'", multipath))
-			}
+			mode, err := parseMultipath(multipath)
+			cobra.CheckErr(err)
 
-			response, err := client.CreateNvmeController(ctx, id, mode)
+			response, err := client.CreateNvmeControllerWithOptions(ctx, id, mode, opts)
 			cobra.CheckErr(err)
 
 			common.PrintResponse(response.Name)
@@ -59,7 +53,8 @@ func newCreateNvmeControllerCommand() *cobra.Command {
 	}
 
 	cmd.Flags().StringVar(&id, "id", "", "id for created resource. Assigned by server if omitted.")
-	cmd.Flags().StringVar(&multipath, "multipath", "disable", "multipath mode (disable, failover, enable)")
+	cmd.Flags().StringVar(&multipath, "multipath", "disable", "multipath mode (disable, failover, multipath)")
+	addNvmeControllerOptionsFlags(cmd, &opts)
 
 	return cmd
 }
@@ -139,3 +134,216 @@ func newGetNvmeControllerCommand() *cobra.Command {
 
 	return cmd
 }
+
+func newListNvmeControllerCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "controller",
+		Aliases: []string{"c"},
+		Short:   "Lists nvme controllers representing external nvme devices",
+		Args:    cobra.NoArgs,
+		Run: func(c *cobra.Command, _ []string) {
+			addr, err := c.Flags().GetString(common.AddrCmdLineArg)
+			cobra.CheckErr(err)
+
+			timeout, err := c.Flags().GetDuration(common.TimeoutCmdLineArg)
+			cobra.CheckErr(err)
+
+			tlsFiles, err := c.Flags().GetString(common.TLSFiles)
+			cobra.CheckErr(err)
+
+			client, err := backendclient.New(addr, tlsFiles)
+			cobra.CheckErr(err)
+
+			ctx, cancel := context.WithTimeout(context.Background(), timeout)
+			defer cancel()
+
+			pageToken := ""
+			for {
+				response, err := client.ListNvmeControllers(ctx, 0, pageToken)
+				cobra.CheckErr(err)
+
+				for _, ctrl := range response.NvmeRemoteControllers {
+					common.PrintResponse(ctrl.Name)
+				}
+
+				pageToken = response.NextPageToken
+				if pageToken == "" {
+					break
+				}
+			}
+		},
+	}
+
+	return cmd
+}
+
+func newUpdateNvmeControllerCommand() *cobra.Command {
+	name := ""
+	multipath := ""
+	allowMissing := false
+	opts := backendclient.NvmeControllerOptions{}
+
+	cmd := &cobra.Command{
+		Use:     "controller",
+		Aliases: []string{"c"},
+		Short:   "Updates nvme controller representing an external nvme device",
+		Args:    cobra.NoArgs,
+		Run: func(c *cobra.Command, _ []string) {
+			addr, err := c.Flags().GetString(common.AddrCmdLineArg)
+			cobra.CheckErr(err)
+
+			timeout, err := c.Flags().GetDuration(common.TimeoutCmdLineArg)
+			cobra.CheckErr(err)
+
+			tlsFiles, err := c.Flags().GetString(common.TLSFiles)
+			cobra.CheckErr(err)
+
+			client, err := backendclient.New(addr, tlsFiles)
+			cobra.CheckErr(err)
+
+			ctx, cancel := context.WithTimeout(context.Background(), timeout)
+			defer cancel()
+
+			ctrl := &pb.NvmeRemoteController{
+				Name:          name,
+				IoQueuesCount: opts.IoQueuesCount,
+				QueueSize:     opts.QueueSize,
+				Tcp: &pb.TcpController{
+					Hdgst: opts.HeaderDigest,
+					Ddgst: opts.DataDigest,
+				},
+			}
+			if c.Flags().Changed("multipath") {
+				ctrl.Multipath, err = parseMultipath(multipath)
+				cobra.CheckErr(err)
+			}
+
+			flagToField := [][2]string{
+				{"multipath", "multipath"},
+				{"io-queues-count", "io_queues_count"},
+				{"queue-size", "queue_size"},
+				{"hdgst", "tcp.hdgst"},
+				{"ddgst", "tcp.ddgst"},
+			}
+			updateMask := []string{}
+			for _, f := range flagToField {
+				if c.Flags().Changed(f[0]) {
+					updateMask = append(updateMask, f[1])
+				}
+			}
+			if len(updateMask) == 0 {
+				cobra.CheckErr(errors.New("no fields to update are specified"))
+			}
+
+			response, err := client.UpdateNvmeController(ctx, ctrl, updateMask, allowMissing)
+			cobra.CheckErr(err)
+
+			common.PrintResponse(response.Name)
+		},
+	}
+
+	cmd.Flags().StringVar(&name, "name", "", "name of updated remote controller")
+	cmd.Flags().StringVar(&multipath, "multipath", "disable", "multipath mode (disable, failover, multipath)")
+	cmd.Flags().BoolVar(&allowMissing, "allowMissing", false, "cmd succeeds if attempts to update a resource that is not present")
+	addNvmeControllerOptionsFlags(cmd, &opts)
+
+	cobra.CheckErr(cmd.MarkFlagRequired("name"))
+
+	return cmd
+}
+
+func newResetNvmeControllerCommand() *cobra.Command {
+	name := ""
+
+	cmd := &cobra.Command{
+		Use:     "controller",
+		Aliases: []string{"c"},
+		Short:   "Resets nvme controller representing an external nvme device",
+		Args:    cobra.NoArgs,
+		Run: func(c *cobra.Command, _ []string) {
+			addr, err := c.Flags().GetString(common.AddrCmdLineArg)
+			cobra.CheckErr(err)
+
+			timeout, err := c.Flags().GetDuration(common.TimeoutCmdLineArg)
+			cobra.CheckErr(err)
+
+			tlsFiles, err := c.Flags().GetString(common.TLSFiles)
+			cobra.CheckErr(err)
+
+			client, err := backendclient.New(addr, tlsFiles)
+			cobra.CheckErr(err)
+
+			ctx, cancel := context.WithTimeout(context.Background(), timeout)
+			defer cancel()
+
+			err = client.ResetNvmeController(ctx, name)
+			cobra.CheckErr(err)
+		},
+	}
+
+	cmd.Flags().StringVar(&name, "name", "", "name of remote controller to reset")
+
+	cobra.CheckErr(cmd.MarkFlagRequired("name"))
+
+	return cmd
+}
+
+func newStatsNvmeControllerCommand() *cobra.Command {
+	name := ""
+
+	cmd := &cobra.Command{
+		Use:     "controller",
+		Aliases: []string{"c"},
+		Short:   "Gets io statistics of nvme controller representing an external nvme device",
+		Args:    cobra.NoArgs,
+		Run: func(c *cobra.Command, _ []string) {
+			addr, err := c.Flags().GetString(common.AddrCmdLineArg)
+			cobra.CheckErr(err)
+
+			timeout, err := c.Flags().GetDuration(common.TimeoutCmdLineArg)
+			cobra.CheckErr(err)
+
+			tlsFiles, err := c.Flags().GetString(common.TLSFiles)
+			cobra.CheckErr(err)
+
+			client, err := backendclient.New(addr, tlsFiles)
+			cobra.CheckErr(err)
+
+			ctx, cancel := context.WithTimeout(context.Background(), timeout)
+			defer cancel()
+
+			stats, err := client.StatsNvmeController(ctx, name)
+			cobra.CheckErr(err)
+
+			common.PrintResponse(protojson.Format(stats))
+		},
+	}
+
+	cmd.Flags().StringVar(&name, "name", "", "name of remote controller to get statistics of")
+
+	cobra.CheckErr(cmd.MarkFlagRequired("name"))
+
+	return cmd
+}
+
+func addNvmeControllerOptionsFlags(cmd *cobra.Command, opts *backendclient.NvmeControllerOptions) {
+	cmd.Flags().Int64Var(&opts.IoQueuesCount, "io-queues-count", 0, "number of io queues. Server default if omitted.")
+	cmd.Flags().Int64Var(&opts.QueueSize, "queue-size", 0, "size of io queues. Server default if omitted.")
+	cmd.Flags().BoolVar(&opts.HeaderDigest, "hdgst", false, "enable nvme/tcp header digest")
+	cmd.Flags().BoolVar(&opts.DataDigest, "ddgst", false, "enable nvme/tcp data digest")
+}
+
+func parseMultipath(multipath string) (pb.NvmeMultipath, error) {
+	allowedModes := map[string]pb.NvmeMultipath{
+		"disable":   pb.NvmeMultipath_NVME_MULTIPATH_DISABLE,
+		"failover":  pb.NvmeMultipath_NVME_MULTIPATH_FAILOVER,
+		"multipath": pb.NvmeMultipath_NVME_MULTIPATH_MULTIPATH,
+	}
+
+	mode, ok := allowedModes[strings.ToLower(multipath)]
+	if !ok {
+		return pb.NvmeMultipath_NVME_MULTIPATH_UNSPECIFIED, fmt.Errorf("not allowed multipath mode: '%s'", multipath)
+	}
+
+	return mode, nil
+}
@@ -0,0 +1,100 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (C) 2024 Intel Corporation
+
+// Package backend implements the CLI commands for storage backend
+package backend
+
+import (
+	"context"
+
+	"github.com/opiproject/godpu/cmd/common"
+	backendclient "github.com/opiproject/godpu/storage/backend"
+	"github.com/spf13/cobra"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+func newListNvmeNamespaceCommand() *cobra.Command {
+	controller := ""
+
+	cmd := &cobra.Command{
+		Use:     "namespace",
+		Aliases: []string{"ns"},
+		Short:   "Lists namespaces exposed by nvme controller representing an external nvme device",
+		Args:    cobra.NoArgs,
+		Run: func(c *cobra.Command, _ []string) {
+			addr, err := c.Flags().GetString(common.AddrCmdLineArg)
+			cobra.CheckErr(err)
+
+			timeout, err := c.Flags().GetDuration(common.TimeoutCmdLineArg)
+			cobra.CheckErr(err)
+
+			tlsFiles, err := c.Flags().GetString(common.TLSFiles)
+			cobra.CheckErr(err)
+
+			client, err := backendclient.New(addr, tlsFiles)
+			cobra.CheckErr(err)
+
+			ctx, cancel := context.WithTimeout(context.Background(), timeout)
+			defer cancel()
+
+			pageToken := ""
+			for {
+				response, err := client.ListNvmeNamespaces(ctx, controller, 0, pageToken)
+				cobra.CheckErr(err)
+
+				for _, namespace := range response.NvmeRemoteNamespaces {
+					common.PrintResponse(protojson.Format(namespace))
+				}
+
+				pageToken = response.NextPageToken
+				if pageToken == "" {
+					break
+				}
+			}
+		},
+	}
+
+	cmd.Flags().StringVar(&controller, "controller", "", "name of remote controller to list namespaces of")
+
+	cobra.CheckErr(cmd.MarkFlagRequired("controller"))
+
+	return cmd
+}
+
+func newGetNvmeNamespaceCommand() *cobra.Command {
+	name := ""
+
+	cmd := &cobra.Command{
+		Use:     "namespace",
+		Aliases: []string{"ns"},
+		Short:   "Gets namespace exposed by nvme controller representing an external nvme device",
+		Args:    cobra.NoArgs,
+		Run: func(c *cobra.Command, _ []string) {
+			addr, err := c.Flags().GetString(common.AddrCmdLineArg)
+			cobra.CheckErr(err)
+
+			timeout, err := c.Flags().GetDuration(common.TimeoutCmdLineArg)
+			cobra.CheckErr(err)
+
+			tlsFiles, err := c.Flags().GetString(common.TLSFiles)
+			cobra.CheckErr(err)
+
+			client, err := backendclient.New(addr, tlsFiles)
+			cobra.CheckErr(err)
+
+			ctx, cancel := context.WithTimeout(context.Background(), timeout)
+			defer cancel()
+
+			namespace, err := client.GetNvmeNamespace(ctx, name)
+			cobra.CheckErr(err)
+
+			common.PrintResponse(protojson.Format(namespace))
+		},
+	}
+
+	cmd.Flags().StringVar(&name, "name", "", "name of remote namespace to get")
+
+	cobra.CheckErr(cmd.MarkFlagRequired("name"))
+
+	return cmd
+}
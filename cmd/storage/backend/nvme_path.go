@@ -10,6 +10,8 @@ import (
 
 	"github.com/opiproject/godpu/cmd/common"
 	backendclient "github.com/opiproject/godpu/storage/backend"
+	"github.com/opiproject/godpu/storage/nvme"
+	pb "github.com/opiproject/opi-api/storage/v1alpha1/gen/go"
 	"github.com/spf13/cobra"
 	"google.golang.org/protobuf/encoding/protojson"
 )
@@ -37,6 +39,7 @@ func newCreateNvmePathTCPCommand() *cobra.Command {
 	nqn := ""
 	hostnqn := ""
 	controller := ""
+	pskFile := ""
 	var ip net.IP
 	var port uint16
 
@@ -61,8 +64,18 @@ func newCreateNvmePathTCPCommand() *cobra.Command {
 			ctx, cancel := context.WithTimeout(context.Background(), timeout)
 			defer cancel()
 
-			response, err := client.CreateNvmeTCPPath(ctx, id, controller, ip, port, nqn, hostnqn)
-			cobra.CheckErr(err)
+			var response *pb.NvmePath
+			if pskFile != "" {
+				// cobra.CheckErr exits, so the psk is cleared before checking
+				psk, err := nvme.ReadPskFile(pskFile)
+				cobra.CheckErr(err)
+				response, err = client.CreateNvmeTCPTLSPath(ctx, id, controller, ip, port, nqn, hostnqn, psk)
+				clear(psk)
+				cobra.CheckErr(err)
+			} else {
+				response, err = client.CreateNvmeTCPPath(ctx, id, controller, ip, port, nqn, hostnqn)
+				cobra.CheckErr(err)
+			}
 
 			common.PrintResponse(response.Name)
 		},
@@ -74,6 +87,7 @@ func newCreateNvmePathTCPCommand() *cobra.Command {
 	cmd.Flags().Uint16Var(&port, "port", 0, "port of the path to connect to.")
 	cmd.Flags().StringVar(&nqn, "nqn", "", "nqn of the target subsystem.")
 	cmd.Flags().StringVar(&hostnqn, "hostnqn", "", "host nqn")
+	cmd.Flags().StringVar(&pskFile, "psk-file", "", "file with TLS pre-shared key. Path is established over secure channel if set.")
 
 	cobra.CheckErr(cmd.MarkFlagRequired("controller"))
 	cobra.CheckErr(cmd.MarkFlagRequired("ip"))
@@ -200,3 +214,89 @@ func newGetNvmePathCommand() *cobra.Command {
 
 	return cmd
 }
+
+func newListNvmePathCommand() *cobra.Command {
+	controller := ""
+
+	cmd := &cobra.Command{
+		Use:     "path",
+		Aliases: []string{"p"},
+		Short:   "Lists nvme paths of nvme controller representing an external nvme device",
+		Args:    cobra.NoArgs,
+		Run: func(c *cobra.Command, _ []string) {
+			addr, err := c.Flags().GetString(common.AddrCmdLineArg)
+			cobra.CheckErr(err)
+
+			timeout, err := c.Flags().GetDuration(common.TimeoutCmdLineArg)
+			cobra.CheckErr(err)
+
+			tlsFiles, err := c.Flags().GetString(common.TLSFiles)
+			cobra.CheckErr(err)
+
+			client, err := backendclient.New(addr, tlsFiles)
+			cobra.CheckErr(err)
+
+			ctx, cancel := context.WithTimeout(context.Background(), timeout)
+			defer cancel()
+
+			pageToken := ""
+			for {
+				response, err := client.ListNvmePaths(ctx, controller, 0, pageToken)
+				cobra.CheckErr(err)
+
+				for _, path := range response.NvmePaths {
+					common.PrintResponse(protojson.Format(path))
+				}
+
+				pageToken = response.NextPageToken
+				if pageToken == "" {
+					break
+				}
+			}
+		},
+	}
+
+	cmd.Flags().StringVar(&controller, "controller", "", "name of remote controller to list paths of")
+
+	cobra.CheckErr(cmd.MarkFlagRequired("controller"))
+
+	return cmd
+}
+
+func newStatsNvmePathCommand() *cobra.Command {
+	name := ""
+
+	cmd := &cobra.Command{
+		Use:     "path",
+		Aliases: []string{"p"},
+		Short:   "Gets io statistics of nvme path to an external nvme device",
+		Args:    cobra.NoArgs,
+		Run: func(c *cobra.Command, _ []string) {
+			addr, err := c.Flags().GetString(common.AddrCmdLineArg)
+			cobra.CheckErr(err)
+
+			timeout, err := c.Flags().GetDuration(common.TimeoutCmdLineArg)
+			cobra.CheckErr(err)
+
+			tlsFiles, err := c.Flags().GetString(common.TLSFiles)
+			cobra.CheckErr(err)
+
+			client, err := backendclient.New(addr, tlsFiles)
+			cobra.CheckErr(err)
+
+			ctx, cancel := context.WithTimeout(context.Background(), timeout)
+			defer cancel()
+
+			stats, err := client.StatsNvmePath(ctx, name)
+			cobra.CheckErr(err)
+
+			common.PrintResponse(protojson.Format(stats))
+		},
+	}
+
+	cmd.Flags().StringVar(&name, "name", "", "name of path to get statistics of")
+
+	cobra.CheckErr(cmd.MarkFlagRequired("name"))
+
+	return cmd
+}
@@ -0,0 +1,110 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (C) 2024 Intel Corporation
+
+// Package storage implements the storage related CLI commands
+package storage
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"slices"
+	"time"
+
+	"github.com/opiproject/godpu/cmd/common"
+	"github.com/opiproject/godpu/storage/test"
+	"github.com/spf13/cobra"
+)
+
+const (
+	kindsCmdLineArg       = "kinds"
+	concurrencyCmdLineArg = "concurrency"
+	durationCmdLineArg    = "duration"
+	resourcesCmdLineArg   = "resources"
+	formatCmdLineArg      = "format"
+)
+
+func newStorageBenchCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "bench",
+		Short: "Measures throughput and latency of storage resource provisioning",
+		Args:  cobra.NoArgs,
+		Run: func(c *cobra.Command, _ []string) {
+			runBench(c)
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.StringSlice(kindsCmdLineArg, test.AllBenchKinds, fmt.Sprintf("resource types to benchmark, any of %v", test.AllBenchKinds))
+	flags.Int(concurrencyCmdLineArg, 4, "number of concurrent workers per resource type")
+	flags.Duration(durationCmdLineArg, 30*time.Second, "how long resources are created, fetched, listed and deleted")
+	flags.Int(resourcesCmdLineArg, 10, "number of resources a worker creates per iteration")
+	flags.String(formatCmdLineArg, string(test.BenchFormatTable), fmt.Sprintf("output format, one of %v", test.AllBenchFormats))
+	flags.Duration(common.TimeoutCmdLineArg, 0, "timeout for the benchmark, none if 0 since it stops after the duration anyway")
+
+	addFixtureFlags(cmd)
+
+	return cmd
+}
+
+func runBench(cmd *cobra.Command) {
+	kinds, err := cmd.Flags().GetStringSlice(kindsCmdLineArg)
+	if err != nil {
+		log.Fatalf("error getting %v argument: %v", kindsCmdLineArg, err)
+	}
+
+	concurrency, err := cmd.Flags().GetInt(concurrencyCmdLineArg)
+	if err != nil {
+		log.Fatalf("error getting %v argument: %v", concurrencyCmdLineArg, err)
+	}
+
+	duration, err := cmd.Flags().GetDuration(durationCmdLineArg)
+	if err != nil {
+		log.Fatalf("error getting %v argument: %v", durationCmdLineArg, err)
+	}
+
+	resources, err := cmd.Flags().GetInt(resourcesCmdLineArg)
+	if err != nil {
+		log.Fatalf("error getting %v argument: %v", resourcesCmdLineArg, err)
+	}
+
+	format, err := cmd.Flags().GetString(formatCmdLineArg)
+	if err != nil {
+		log.Fatalf("error getting %v argument: %v", formatCmdLineArg, err)
+	}
+	benchFormat := test.BenchFormat(format)
+	if !slices.Contains(test.AllBenchFormats, benchFormat) {
+		log.Fatalf("unknown output format %v, expected one of %v", benchFormat, test.AllBenchFormats)
+	}
+
+	options := test.BenchOptions{
+		Kinds:       kinds,
+		Concurrency: concurrency,
+		Duration:    duration,
+		Resources:   resources,
+	}
+	if err := options.Validate(); err != nil {
+		log.Fatalf("invalid benchmark options: %v", err)
+	}
+
+	fixtures, err := fixturesFromFlags(cmd)
+	if err != nil {
+		log.Fatalf("error getting fixtures: %v", err)
+	}
+
+	conn, closer := newTestConn(cmd)
+	defer closer()
+
+	ctx, cancel := common.NewTestContext(cmd)
+	defer cancel()
+
+	result, err := test.Bench(ctx, conn, fixtures, options)
+	if result != nil {
+		if err := result.Write(os.Stdout, benchFormat); err != nil {
+			log.Fatalf("error writing result: %v", err)
+		}
+	}
+	if err != nil {
+		log.Panic(err)
+	}
+}
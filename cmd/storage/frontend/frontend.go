@@ -38,6 +38,7 @@ func newCreateNvmeCommand() *cobra.Command {
 	}
 
 	cmd.AddCommand(newCreateNvmeSubsystemCommand())
+	cmd.AddCommand(newCreateNvmeSubsystemHostCommand())
 	cmd.AddCommand(newCreateNvmeNamespaceCommand())
 	cmd.AddCommand(newCreateNvmeControllerCommand())
 
@@ -93,6 +94,7 @@ func newDeleteNvmeCommand() *cobra.Command {
 	}
 
 	cmd.AddCommand(newDeleteNvmeSubsystemCommand())
+	cmd.AddCommand(newDeleteNvmeSubsystemHostCommand())
 	cmd.AddCommand(newDeleteNvmeNamespaceCommand())
 	cmd.AddCommand(newDeleteNvmeControllerCommand())
 
@@ -115,3 +117,73 @@ func newDeleteVirtioCommand() *cobra.Command {
 
 	return cmd
 }
+
+// NewUpdateCommand creates a new command to update frontend resources
+func NewUpdateCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "frontend",
+		Aliases: []string{"f"},
+		Short:   "Updates frontend resource",
+		Args:    cobra.NoArgs,
+		Run: func(c *cobra.Command, _ []string) {
+			err := c.Help()
+			cobra.CheckErr(err)
+		},
+	}
+
+	cmd.AddCommand(newUpdateNvmeCommand())
+
+	return cmd
+}
+
+func newUpdateNvmeCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "nvme",
+		Aliases: []string{"n"},
+		Short:   "Updates nvme resource",
+		Args:    cobra.NoArgs,
+		Run: func(c *cobra.Command, _ []string) {
+			err := c.Help()
+			cobra.CheckErr(err)
+		},
+	}
+
+	cmd.AddCommand(newUpdateNvmeSubsystemCommand())
+
+	return cmd
+}
+
+// NewListCommand creates a new command to list frontend resources
+func NewListCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "frontend",
+		Aliases: []string{"f"},
+		Short:   "Lists frontend resources",
+		Args:    cobra.NoArgs,
+		Run: func(c *cobra.Command, _ []string) {
+			err := c.Help()
+			cobra.CheckErr(err)
+		},
+	}
+
+	cmd.AddCommand(newListNvmeCommand())
+
+	return cmd
+}
+
+func newListNvmeCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "nvme",
+		Aliases: []string{"n"},
+		Short:   "Lists nvme resources",
+		Args:    cobra.NoArgs,
+		Run: func(c *cobra.Command, _ []string) {
+			err := c.Help()
+			cobra.CheckErr(err)
+		},
+	}
+
+	cmd.AddCommand(newListNvmeSubsystemHostCommand())
+
+	return cmd
+}
@@ -10,6 +10,8 @@ import (
 
 	"github.com/opiproject/godpu/cmd/common"
 	frontendclient "github.com/opiproject/godpu/storage/frontend"
+	"github.com/opiproject/godpu/storage/nvme"
+	pb "github.com/opiproject/opi-api/storage/v1alpha1/gen/go"
 	"github.com/spf13/cobra"
 )
 
@@ -26,6 +28,7 @@ func newCreateNvmeControllerCommand() *cobra.Command {
 	}
 
 	cmd.AddCommand(newCreateNvmeControllerTCPCommand())
+	cmd.AddCommand(newCreateNvmeControllerRdmaCommand())
 	cmd.AddCommand(newCreateNvmeControllerPcieCommand())
 
 	return cmd
@@ -34,6 +37,7 @@ func newCreateNvmeControllerCommand() *cobra.Command {
 func newCreateNvmeControllerTCPCommand() *cobra.Command {
 	id := ""
 	subsystem := ""
+	pskFile := ""
 	var ip net.IP
 	var port uint16
 
@@ -58,7 +62,64 @@ func newCreateNvmeControllerTCPCommand() *cobra.Command {
 			ctx, cancel := context.WithTimeout(context.Background(), timeout)
 			defer cancel()
 
-			response, err := client.CreateNvmeTCPController(ctx, id, subsystem, ip, port)
+			var response *pb.NvmeController
+			if pskFile != "" {
+				// cobra.CheckErr exits, so the psk is cleared before checking
+				psk, err := nvme.ReadPskFile(pskFile)
+				cobra.CheckErr(err)
+				response, err = client.CreateNvmeTCPTLSController(ctx, id, subsystem, ip, port, psk)
+				clear(psk)
+				cobra.CheckErr(err)
+			} else {
+				response, err = client.CreateNvmeTCPController(ctx, id, subsystem, ip, port)
+				cobra.CheckErr(err)
+			}
+
+			common.PrintResponse(response.Name)
+		},
+	}
+
+	cmd.Flags().StringVar(&id, "id", "", "id for created resource. Assigned by server if omitted.")
+	cmd.Flags().StringVar(&subsystem, "subsystem", "", "subsystem name to attach the controller to")
+	cmd.Flags().IPVar(&ip, "ip", nil, "ip address of the created controller")
+	cmd.Flags().Uint16Var(&port, "port", 0, "port of the created controller")
+	cmd.Flags().StringVar(&pskFile, "psk-file", "", "file with TLS pre-shared key. Controller requires secure channel if set.")
+
+	cobra.CheckErr(cmd.MarkFlagRequired("subsystem"))
+	cobra.CheckErr(cmd.MarkFlagRequired("ip"))
+	cobra.CheckErr(cmd.MarkFlagRequired("port"))
+
+	return cmd
+}
+
+func newCreateNvmeControllerRdmaCommand() *cobra.Command {
+	id := ""
+	subsystem := ""
+	var ip net.IP
+	var port uint16
+
+	cmd := &cobra.Command{
+		Use:     "rdma",
+		Aliases: []string{"r"},
+		Short:   "Creates nvme RDMA controller",
+		Args:    cobra.NoArgs,
+		Run: func(c *cobra.Command, _ []string) {
+			addr, err := c.Flags().GetString(common.AddrCmdLineArg)
+			cobra.CheckErr(err)
+
+			timeout, err := c.Flags().GetDuration(common.TimeoutCmdLineArg)
+			cobra.CheckErr(err)
+
+			tlsFiles, err := c.Flags().GetString(common.TLSFiles)
+			cobra.CheckErr(err)
+
+			client, err := frontendclient.New(addr, tlsFiles)
+			cobra.CheckErr(err)
+
+			ctx, cancel := context.WithTimeout(context.Background(), timeout)
+			defer cancel()
+
+			response, err := client.CreateNvmeRdmaController(ctx, id, subsystem, ip, port)
 			cobra.CheckErr(err)
 
 			common.PrintResponse(response.Name)
@@ -9,6 +9,7 @@ import (
 
 	"github.com/opiproject/godpu/cmd/common"
 	frontendclient "github.com/opiproject/godpu/storage/frontend"
+	"github.com/opiproject/godpu/storage/nvme"
 	"github.com/spf13/cobra"
 )
 
@@ -16,6 +17,10 @@ func newCreateNvmeNamespaceCommand() *cobra.Command {
 	id := ""
 	subsystem := ""
 	volume := ""
+	nguid := ""
+	eui64 := ""
+	nsUUID := ""
+	deriveIDs := false
 
 	cmd := &cobra.Command{
 		Use:     "namespace",
@@ -23,6 +28,9 @@ func newCreateNvmeNamespaceCommand() *cobra.Command {
 		Short:   "Creates nvme namespace",
 		Args:    cobra.NoArgs,
 		Run: func(c *cobra.Command, _ []string) {
+			ids, err := parseNamespaceIdentifiers(volume, nguid, eui64, nsUUID, deriveIDs)
+			cobra.CheckErr(err)
+
 			addr, err := c.Flags().GetString(common.AddrCmdLineArg)
 			cobra.CheckErr(err)
 
@@ -38,7 +46,7 @@ func newCreateNvmeNamespaceCommand() *cobra.Command {
 			ctx, cancel := context.WithTimeout(context.Background(), timeout)
 			defer cancel()
 
-			response, err := client.CreateNvmeNamespace(ctx, id, subsystem, volume)
+			response, err := client.CreateNvmeNamespaceWithIdentifiers(ctx, id, subsystem, volume, ids)
 			cobra.CheckErr(err)
 
 			common.PrintResponse(response.Name)
@@ -48,6 +56,10 @@ func newCreateNvmeNamespaceCommand() *cobra.Command {
 	cmd.Flags().StringVar(&id, "id", "", "id for created resource. Assigned by server if omitted.")
 	cmd.Flags().StringVar(&subsystem, "subsystem", "", "subsystem name to attach the namespace to")
 	cmd.Flags().StringVar(&volume, "volume", "", "volume name to attach as a namespace")
+	cmd.Flags().StringVar(&nguid, "nguid", "", "nguid of the namespace as 32 hex digits")
+	cmd.Flags().StringVar(&eui64, "eui64", "", "eui64 of the namespace as 16 hex digits")
+	cmd.Flags().StringVar(&nsUUID, "uuid", "", "uuid of the namespace")
+	cmd.Flags().BoolVar(&deriveIDs, "derive-ids", false, "derive identifiers not set explicitly from the volume name")
 
 	cobra.CheckErr(cmd.MarkFlagRequired("subsystem"))
 	cobra.CheckErr(cmd.MarkFlagRequired("volume"))
@@ -55,6 +67,44 @@ func newCreateNvmeNamespaceCommand() *cobra.Command {
 	return cmd
 }
 
+func parseNamespaceIdentifiers(
+	volume, nguid, eui64, nsUUID string,
+	deriveIDs bool,
+) (nvme.NamespaceIdentifiers, error) {
+	ids := nvme.NamespaceIdentifiers{}
+	if deriveIDs {
+		var err error
+		ids, err = nvme.IdentifiersFromVolumeID(volume)
+		if err != nil {
+			return nvme.NamespaceIdentifiers{}, err
+		}
+	}
+
+	if nguid != "" {
+		parsed, err := nvme.ParseNGUID(nguid)
+		if err != nil {
+			return nvme.NamespaceIdentifiers{}, err
+		}
+		ids.NGUID = parsed
+	}
+	if eui64 != "" {
+		parsed, err := nvme.ParseEUI64(eui64)
+		if err != nil {
+			return nvme.NamespaceIdentifiers{}, err
+		}
+		ids.EUI64 = parsed
+	}
+	if nsUUID != "" {
+		parsed, err := nvme.ParseUUID(nsUUID)
+		if err != nil {
+			return nvme.NamespaceIdentifiers{}, err
+		}
+		ids.UUID = parsed
+	}
+
+	return ids, nil
+}
+
 func newDeleteNvmeNamespaceCommand() *cobra.Command {
 	name := ""
 	allowMissing := false
@@ -91,3 +91,167 @@ func newDeleteNvmeSubsystemCommand() *cobra.Command {
 
 	return cmd
 }
+
+func newUpdateNvmeSubsystemCommand() *cobra.Command {
+	name := ""
+	allowAnyHost := false
+
+	cmd := &cobra.Command{
+		Use:     "subsystem",
+		Aliases: []string{"s"},
+		Short:   "Updates nvme subsystem",
+		Args:    cobra.NoArgs,
+		Run: func(c *cobra.Command, _ []string) {
+			addr, err := c.Flags().GetString(common.AddrCmdLineArg)
+			cobra.CheckErr(err)
+
+			timeout, err := c.Flags().GetDuration(common.TimeoutCmdLineArg)
+			cobra.CheckErr(err)
+
+			tlsFiles, err := c.Flags().GetString(common.TLSFiles)
+			cobra.CheckErr(err)
+
+			client, err := frontendclient.New(addr, tlsFiles)
+			cobra.CheckErr(err)
+
+			ctx, cancel := context.WithTimeout(context.Background(), timeout)
+			defer cancel()
+
+			response, err := client.SetNvmeSubsystemAllowAnyHost(ctx, name, allowAnyHost)
+			cobra.CheckErr(err)
+
+			common.PrintResponse(response.Name)
+		},
+	}
+
+	cmd.Flags().StringVar(&name, "name", "", "name of updated subsystem")
+	cmd.Flags().BoolVar(&allowAnyHost, "allow-any-host", false, "allow any host to connect to the subsystem. Drops the allowed host if set.")
+
+	cobra.CheckErr(cmd.MarkFlagRequired("name"))
+	cobra.CheckErr(cmd.MarkFlagRequired("allow-any-host"))
+
+	return cmd
+}
+
+func newCreateNvmeSubsystemHostCommand() *cobra.Command {
+	subsystem := ""
+	hostnqn := ""
+
+	cmd := &cobra.Command{
+		Use:     "host",
+		Aliases: []string{"h"},
+		Short:   "Allows host to connect to nvme subsystem",
+		Args:    cobra.NoArgs,
+		Run: func(c *cobra.Command, _ []string) {
+			addr, err := c.Flags().GetString(common.AddrCmdLineArg)
+			cobra.CheckErr(err)
+
+			timeout, err := c.Flags().GetDuration(common.TimeoutCmdLineArg)
+			cobra.CheckErr(err)
+
+			tlsFiles, err := c.Flags().GetString(common.TLSFiles)
+			cobra.CheckErr(err)
+
+			client, err := frontendclient.New(addr, tlsFiles)
+			cobra.CheckErr(err)
+
+			ctx, cancel := context.WithTimeout(context.Background(), timeout)
+			defer cancel()
+
+			response, err := client.AddNvmeSubsystemHost(ctx, subsystem, hostnqn)
+			cobra.CheckErr(err)
+
+			common.PrintResponse(response.Name)
+		},
+	}
+
+	cmd.Flags().StringVar(&subsystem, "subsystem", "", "subsystem name to allow the host on")
+	cmd.Flags().StringVar(&hostnqn, "hostnqn", "", "nqn of allowed host")
+
+	cobra.CheckErr(cmd.MarkFlagRequired("subsystem"))
+	cobra.CheckErr(cmd.MarkFlagRequired("hostnqn"))
+
+	return cmd
+}
+
+func newDeleteNvmeSubsystemHostCommand() *cobra.Command {
+	subsystem := ""
+	hostnqn := ""
+
+	cmd := &cobra.Command{
+		Use:     "host",
+		Aliases: []string{"h"},
+		Short:   "Revokes host access to nvme subsystem",
+		Args:    cobra.NoArgs,
+		Run: func(c *cobra.Command, _ []string) {
+			addr, err := c.Flags().GetString(common.AddrCmdLineArg)
+			cobra.CheckErr(err)
+
+			timeout, err := c.Flags().GetDuration(common.TimeoutCmdLineArg)
+			cobra.CheckErr(err)
+
+			tlsFiles, err := c.Flags().GetString(common.TLSFiles)
+			cobra.CheckErr(err)
+
+			client, err := frontendclient.New(addr, tlsFiles)
+			cobra.CheckErr(err)
+
+			ctx, cancel := context.WithTimeout(context.Background(), timeout)
+			defer cancel()
+
+			_, err = client.RemoveNvmeSubsystemHost(ctx, subsystem, hostnqn)
+			cobra.CheckErr(err)
+		},
+	}
+
+	cmd.Flags().StringVar(&subsystem, "subsystem", "", "subsystem name to revoke the host access on")
+	cmd.Flags().StringVar(&hostnqn, "hostnqn", "", "nqn of revoked host")
+
+	cobra.CheckErr(cmd.MarkFlagRequired("subsystem"))
+	cobra.CheckErr(cmd.MarkFlagRequired("hostnqn"))
+
+	return cmd
+}
+
+func newListNvmeSubsystemHostCommand() *cobra.Command {
+	subsystem := ""
+
+	cmd := &cobra.Command{
+		Use:     "host",
+		Aliases: []string{"h"},
+		Short:   "Lists hosts allowed to connect to nvme subsystem. Prints * if any host is allowed.",
+		Args:    cobra.NoArgs,
+		Run: func(c *cobra.Command, _ []string) {
+			addr, err := c.Flags().GetString(common.AddrCmdLineArg)
+			cobra.CheckErr(err)
+
+			timeout, err := c.Flags().GetDuration(common.TimeoutCmdLineArg)
+			cobra.CheckErr(err)
+
+			tlsFiles, err := c.Flags().GetString(common.TLSFiles)
+			cobra.CheckErr(err)
+
+			client, err := frontendclient.New(addr, tlsFiles)
+			cobra.CheckErr(err)
+
+			ctx, cancel := context.WithTimeout(context.Background(), timeout)
+			defer cancel()
+
+			hosts, allowAnyHost, err := client.ListNvmeSubsystemHosts(ctx, subsystem)
+			cobra.CheckErr(err)
+
+			if allowAnyHost {
+				common.PrintResponse("*")
+			}
+			for _, host := range hosts {
+				common.PrintResponse(host)
+			}
+		},
+	}
+
+	cmd.Flags().StringVar(&subsystem, "subsystem", "", "subsystem name to list allowed hosts of")
+
+	cobra.CheckErr(cmd.MarkFlagRequired("subsystem"))
+
+	return cmd
+}
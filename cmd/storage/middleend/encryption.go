@@ -0,0 +1,308 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (C) 2024 Intel Corporation
+
+// Package middleend implements the CLI commands for storage middleend
+package middleend
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/opiproject/godpu/cmd/common"
+	middleendclient "github.com/opiproject/godpu/storage/middleend"
+	pb "github.com/opiproject/opi-api/storage/v1alpha1/gen/go"
+	"github.com/spf13/cobra"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+const keyFileUsage = "file with encryption key or - to read the key from stdin. " +
+	"The file must not be accessible by group or others."
+
+func newCreateEncryptedVolumeCommand() *cobra.Command {
+	id := ""
+	volume := ""
+	cipher := ""
+	keyFile := ""
+	cmd := &cobra.Command{
+		Use:     "encryption",
+		Aliases: []string{"e"},
+		Short:   "Creates encrypted volume on top of a volume",
+		Args:    cobra.NoArgs,
+		Run: func(c *cobra.Command, _ []string) {
+			addr, err := c.Flags().GetString(common.AddrCmdLineArg)
+			cobra.CheckErr(err)
+
+			timeout, err := c.Flags().GetDuration(common.TimeoutCmdLineArg)
+			cobra.CheckErr(err)
+
+			tlsFiles, err := c.Flags().GetString(common.TLSFiles)
+			cobra.CheckErr(err)
+
+			encryptionType, err := parseCipher(cipher)
+			cobra.CheckErr(err)
+
+			client, err := middleendclient.New(addr, tlsFiles)
+			cobra.CheckErr(err)
+
+			ctx, cancel := context.WithTimeout(context.Background(), timeout)
+			defer cancel()
+
+			// cobra.CheckErr exits, so the key is cleared before checking
+			key, err := common.ReadKeyFile(keyFile)
+			cobra.CheckErr(err)
+			response, err := client.CreateEncryptedVolume(ctx, id, volume, encryptionType, key)
+			clear(key)
+			cobra.CheckErr(err)
+
+			common.PrintResponse(response.Name)
+		},
+	}
+
+	cmd.Flags().StringVar(&id, "id", "", "id for created resource. Assigned by server if omitted.")
+	cmd.Flags().StringVar(&volume, "volume", "", "name of volume to encrypt")
+	cmd.Flags().StringVar(&cipher, "cipher", "", "cipher (aes-xts-128, aes-xts-192, aes-xts-256)")
+	cmd.Flags().StringVar(&keyFile, "key-file", "", keyFileUsage)
+
+	cobra.CheckErr(cmd.MarkFlagRequired("volume"))
+	cobra.CheckErr(cmd.MarkFlagRequired("cipher"))
+	cobra.CheckErr(cmd.MarkFlagRequired("key-file"))
+
+	return cmd
+}
+
+func newDeleteEncryptedVolumeCommand() *cobra.Command {
+	name := ""
+	allowMissing := false
+
+	cmd := &cobra.Command{
+		Use:     "encryption",
+		Aliases: []string{"e"},
+		Short:   "Deletes encrypted volume",
+		Args:    cobra.NoArgs,
+		Run: func(c *cobra.Command, _ []string) {
+			addr, err := c.Flags().GetString(common.AddrCmdLineArg)
+			cobra.CheckErr(err)
+
+			timeout, err := c.Flags().GetDuration(common.TimeoutCmdLineArg)
+			cobra.CheckErr(err)
+
+			tlsFiles, err := c.Flags().GetString(common.TLSFiles)
+			cobra.CheckErr(err)
+
+			client, err := middleendclient.New(addr, tlsFiles)
+			cobra.CheckErr(err)
+
+			ctx, cancel := context.WithTimeout(context.Background(), timeout)
+			defer cancel()
+
+			err = client.DeleteEncryptedVolume(ctx, name, allowMissing)
+			cobra.CheckErr(err)
+		},
+	}
+
+	cmd.Flags().StringVar(&name, "name", "", "name of deleted encrypted volume")
+	cmd.Flags().BoolVar(&allowMissing, "allowMissing", false, "cmd succeeds if attempts to delete a resource that is not present")
+
+	cobra.CheckErr(cmd.MarkFlagRequired("name"))
+
+	return cmd
+}
+
+func newGetEncryptedVolumeCommand() *cobra.Command {
+	name := ""
+
+	cmd := &cobra.Command{
+		Use:     "encryption",
+		Aliases: []string{"e"},
+		Short:   "Gets encrypted volume",
+		Args:    cobra.NoArgs,
+		Run: func(c *cobra.Command, _ []string) {
+			addr, err := c.Flags().GetString(common.AddrCmdLineArg)
+			cobra.CheckErr(err)
+
+			timeout, err := c.Flags().GetDuration(common.TimeoutCmdLineArg)
+			cobra.CheckErr(err)
+
+			tlsFiles, err := c.Flags().GetString(common.TLSFiles)
+			cobra.CheckErr(err)
+
+			client, err := middleendclient.New(addr, tlsFiles)
+			cobra.CheckErr(err)
+
+			ctx, cancel := context.WithTimeout(context.Background(), timeout)
+			defer cancel()
+
+			volume, err := client.GetEncryptedVolume(ctx, name)
+			cobra.CheckErr(err)
+
+			// never print the key
+			clear(volume.Key)
+			volume.Key = nil
+			common.PrintResponse(protojson.Format(volume))
+		},
+	}
+
+	cmd.Flags().StringVar(&name, "name", "", "name of encrypted volume to get")
+
+	cobra.CheckErr(cmd.MarkFlagRequired("name"))
+
+	return cmd
+}
+
+func newListEncryptedVolumeCommand() *cobra.Command {
+	parent := ""
+
+	cmd := &cobra.Command{
+		Use:     "encryption",
+		Aliases: []string{"e"},
+		Short:   "Lists encrypted volumes",
+		Args:    cobra.NoArgs,
+		Run: func(c *cobra.Command, _ []string) {
+			addr, err := c.Flags().GetString(common.AddrCmdLineArg)
+			cobra.CheckErr(err)
+
+			timeout, err := c.Flags().GetDuration(common.TimeoutCmdLineArg)
+			cobra.CheckErr(err)
+
+			tlsFiles, err := c.Flags().GetString(common.TLSFiles)
+			cobra.CheckErr(err)
+
+			client, err := middleendclient.New(addr, tlsFiles)
+			cobra.CheckErr(err)
+
+			ctx, cancel := context.WithTimeout(context.Background(), timeout)
+			defer cancel()
+
+			pageToken := ""
+			for {
+				response, err := client.ListEncryptedVolumes(ctx, parent, 0, pageToken)
+				cobra.CheckErr(err)
+
+				for _, volume := range response.EncryptedVolumes {
+					common.PrintResponse(volume.Name)
+				}
+
+				pageToken = response.NextPageToken
+				if pageToken == "" {
+					break
+				}
+			}
+		},
+	}
+
+	cmd.Flags().StringVar(&parent, "parent", "", "parent of listed encrypted volumes")
+
+	return cmd
+}
+
+func newUpdateEncryptedVolumeCommand() *cobra.Command {
+	name := ""
+	cipher := ""
+	keyFile := ""
+	allowMissing := false
+
+	cmd := &cobra.Command{
+		Use:     "encryption",
+		Aliases: []string{"e"},
+		Short:   "Updates cipher and key of encrypted volume",
+		Args:    cobra.NoArgs,
+		Run: func(c *cobra.Command, _ []string) {
+			addr, err := c.Flags().GetString(common.AddrCmdLineArg)
+			cobra.CheckErr(err)
+
+			timeout, err := c.Flags().GetDuration(common.TimeoutCmdLineArg)
+			cobra.CheckErr(err)
+
+			tlsFiles, err := c.Flags().GetString(common.TLSFiles)
+			cobra.CheckErr(err)
+
+			encryptionType, err := parseCipher(cipher)
+			cobra.CheckErr(err)
+
+			client, err := middleendclient.New(addr, tlsFiles)
+			cobra.CheckErr(err)
+
+			ctx, cancel := context.WithTimeout(context.Background(), timeout)
+			defer cancel()
+
+			// cobra.CheckErr exits, so the key is cleared before checking
+			key, err := common.ReadKeyFile(keyFile)
+			cobra.CheckErr(err)
+			volume := &pb.EncryptedVolume{
+				Name:   name,
+				Cipher: encryptionType,
+				Key:    key,
+			}
+			response, err := client.UpdateEncryptedVolume(ctx, volume, []string{"cipher", "key"}, allowMissing)
+			clear(key)
+			cobra.CheckErr(err)
+
+			common.PrintResponse(response.Name)
+		},
+	}
+
+	cmd.Flags().StringVar(&name, "name", "", "name of updated encrypted volume")
+	cmd.Flags().StringVar(&cipher, "cipher", "", "cipher (aes-xts-128, aes-xts-192, aes-xts-256)")
+	cmd.Flags().StringVar(&keyFile, "key-file", "", keyFileUsage)
+	cmd.Flags().BoolVar(&allowMissing, "allowMissing", false, "cmd succeeds if attempts to update a resource that is not present")
+
+	cobra.CheckErr(cmd.MarkFlagRequired("name"))
+	cobra.CheckErr(cmd.MarkFlagRequired("cipher"))
+	cobra.CheckErr(cmd.MarkFlagRequired("key-file"))
+
+	return cmd
+}
+
+func newStatsEncryptedVolumeCommand() *cobra.Command {
+	name := ""
+
+	cmd := &cobra.Command{
+		Use:     "encryption",
+		Aliases: []string{"e"},
+		Short:   "Gets io statistics of encrypted volume",
+		Args:    cobra.NoArgs,
+		Run: func(c *cobra.Command, _ []string) {
+			addr, err := c.Flags().GetString(common.AddrCmdLineArg)
+			cobra.CheckErr(err)
+
+			timeout, err := c.Flags().GetDuration(common.TimeoutCmdLineArg)
+			cobra.CheckErr(err)
+
+			tlsFiles, err := c.Flags().GetString(common.TLSFiles)
+			cobra.CheckErr(err)
+
+			client, err := middleendclient.New(addr, tlsFiles)
+			cobra.CheckErr(err)
+
+			ctx, cancel := context.WithTimeout(context.Background(), timeout)
+			defer cancel()
+
+			stats, err := client.StatsEncryptedVolume(ctx, name)
+			cobra.CheckErr(err)
+
+			common.PrintResponse(protojson.Format(stats))
+		},
+	}
+
+	cmd.Flags().StringVar(&name, "name", "", "name of encrypted volume to get statistics of")
+
+	cobra.CheckErr(cmd.MarkFlagRequired("name"))
+
+	return cmd
+}
+
+func parseCipher(cipher string) (pb.EncryptionType, error) {
+	allowedCiphers := map[string]pb.EncryptionType{
+		"aes-xts-128": pb.EncryptionType_ENCRYPTION_TYPE_AES_XTS_128,
+		"aes-xts-192": pb.EncryptionType_ENCRYPTION_TYPE_AES_XTS_192,
+		"aes-xts-256": pb.EncryptionType_ENCRYPTION_TYPE_AES_XTS_256,
+	}
+
+	encryptionType, ok := allowedCiphers[strings.ToLower(cipher)]
+	if !ok {
+		return pb.EncryptionType_ENCRYPTION_TYPE_UNSPECIFIED, fmt.Errorf("not allowed cipher: '%s'", cipher)
+	}
+
+	return encryptionType, nil
+}
@@ -0,0 +1,155 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (C) 2024 Intel Corporation
+
+// Package middleend implements the CLI commands for storage middleend
+package middleend
+
+import "github.com/spf13/cobra"
+
+// NewCreateCommand creates a new command to create middleend resources
+func NewCreateCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "middleend",
+		Aliases: []string{"m"},
+		Short:   "Creates middleend resource",
+		Args:    cobra.NoArgs,
+		Run: func(c *cobra.Command, _ []string) {
+			err := c.Help()
+			cobra.CheckErr(err)
+		},
+	}
+
+	cmd.AddCommand(newCreateEncryptedVolumeCommand())
+	cmd.AddCommand(newCreateQosVolumeCommand())
+
+	return cmd
+}
+
+// NewDeleteCommand creates a new command to delete middleend resources
+func NewDeleteCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "middleend",
+		Aliases: []string{"m"},
+		Short:   "Deletes middleend resource",
+		Args:    cobra.NoArgs,
+		Run: func(c *cobra.Command, _ []string) {
+			err := c.Help()
+			cobra.CheckErr(err)
+		},
+	}
+
+	cmd.AddCommand(newDeleteEncryptedVolumeCommand())
+	cmd.AddCommand(newDeleteQosVolumeCommand())
+
+	return cmd
+}
+
+// NewGetCommand creates a new command to get middleend resources
+func NewGetCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "middleend",
+		Aliases: []string{"m"},
+		Short:   "Gets middleend resource",
+		Args:    cobra.NoArgs,
+		Run: func(c *cobra.Command, _ []string) {
+			err := c.Help()
+			cobra.CheckErr(err)
+		},
+	}
+
+	cmd.AddCommand(newGetEncryptedVolumeCommand())
+	cmd.AddCommand(newGetQosVolumeCommand())
+
+	return cmd
+}
+
+// NewListCommand creates a new command to list middleend resources
+func NewListCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "middleend",
+		Aliases: []string{"m"},
+		Short:   "Lists middleend resource",
+		Args:    cobra.NoArgs,
+		Run: func(c *cobra.Command, _ []string) {
+			err := c.Help()
+			cobra.CheckErr(err)
+		},
+	}
+
+	cmd.AddCommand(newListEncryptedVolumeCommand())
+	cmd.AddCommand(newListQosVolumeCommand())
+
+	return cmd
+}
+
+// NewUpdateCommand creates a new command to update middleend resources
+func NewUpdateCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "middleend",
+		Aliases: []string{"m"},
+		Short:   "Updates middleend resource",
+		Args:    cobra.NoArgs,
+		Run: func(c *cobra.Command, _ []string) {
+			err := c.Help()
+			cobra.CheckErr(err)
+		},
+	}
+
+	cmd.AddCommand(newUpdateEncryptedVolumeCommand())
+	cmd.AddCommand(newUpdateQosVolumeCommand())
+
+	return cmd
+}
+
+// NewStatsCommand creates a new command to get statistics of middleend resources
+func NewStatsCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "middleend",
+		Aliases: []string{"m"},
+		Short:   "Gets statistics of middleend resource",
+		Args:    cobra.NoArgs,
+		Run: func(c *cobra.Command, _ []string) {
+			err := c.Help()
+			cobra.CheckErr(err)
+		},
+	}
+
+	cmd.AddCommand(newStatsEncryptedVolumeCommand())
+	cmd.AddCommand(newStatsQosVolumeCommand())
+
+	return cmd
+}
+
+// NewQosCommand creates a new command grouping the QoS volume commands under
+// the resource, e.g. "storage qos create", next to "storage create middleend qos"
+func NewQosCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "qos",
+		Aliases: []string{"q"},
+		Short:   "Manages QoS volumes",
+		Args:    cobra.NoArgs,
+		Run: func(c *cobra.Command, _ []string) {
+			err := c.Help()
+			cobra.CheckErr(err)
+		},
+	}
+
+	for _, verb := range []struct {
+		use   string
+		alias string
+		cmd   *cobra.Command
+	}{
+		{"create", "c", newCreateQosVolumeCommand()},
+		{"delete", "d", newDeleteQosVolumeCommand()},
+		{"get", "g", newGetQosVolumeCommand()},
+		{"list", "l", newListQosVolumeCommand()},
+		{"update", "u", newUpdateQosVolumeCommand()},
+		{"stats", "st", newStatsQosVolumeCommand()},
+	} {
+		verb.cmd.Use = verb.use
+		verb.cmd.Aliases = []string{verb.alias}
+		cmd.AddCommand(verb.cmd)
+	}
+
+	return cmd
+}
@@ -0,0 +1,319 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (C) 2024 Intel Corporation
+
+// Package middleend implements the CLI commands for storage middleend
+package middleend
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+
+	"github.com/opiproject/godpu/cmd/common"
+	middleendclient "github.com/opiproject/godpu/storage/middleend"
+	pb "github.com/opiproject/opi-api/storage/v1alpha1/gen/go"
+	"github.com/spf13/cobra"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+func newCreateQosVolumeCommand() *cobra.Command {
+	id := ""
+	volume := ""
+	limits := newQosLimits()
+	cmd := &cobra.Command{
+		Use:     "qos",
+		Aliases: []string{"q"},
+		Short:   "Creates QoS volume limiting iops and bandwidth of a volume",
+		Args:    cobra.NoArgs,
+		Run: func(c *cobra.Command, _ []string) {
+			addr, err := c.Flags().GetString(common.AddrCmdLineArg)
+			cobra.CheckErr(err)
+
+			timeout, err := c.Flags().GetDuration(common.TimeoutCmdLineArg)
+			cobra.CheckErr(err)
+
+			tlsFiles, err := c.Flags().GetString(common.TLSFiles)
+			cobra.CheckErr(err)
+
+			client, err := middleendclient.New(addr, tlsFiles)
+			cobra.CheckErr(err)
+
+			ctx, cancel := context.WithTimeout(context.Background(), timeout)
+			defer cancel()
+
+			response, err := client.CreateQosVolume(ctx, id, volume, limits)
+			cobra.CheckErr(err)
+
+			common.PrintResponse(response.Name)
+		},
+	}
+
+	cmd.Flags().StringVar(&id, "id", "", "id for created resource. Assigned by server if omitted.")
+	cmd.Flags().StringVar(&volume, "volume", "", "name of volume to limit")
+	addQosLimitsFlags(cmd, limits)
+
+	cobra.CheckErr(cmd.MarkFlagRequired("volume"))
+
+	return cmd
+}
+
+func newDeleteQosVolumeCommand() *cobra.Command {
+	name := ""
+	allowMissing := false
+
+	cmd := &cobra.Command{
+		Use:     "qos",
+		Aliases: []string{"q"},
+		Short:   "Deletes QoS volume",
+		Args:    cobra.NoArgs,
+		Run: func(c *cobra.Command, _ []string) {
+			addr, err := c.Flags().GetString(common.AddrCmdLineArg)
+			cobra.CheckErr(err)
+
+			timeout, err := c.Flags().GetDuration(common.TimeoutCmdLineArg)
+			cobra.CheckErr(err)
+
+			tlsFiles, err := c.Flags().GetString(common.TLSFiles)
+			cobra.CheckErr(err)
+
+			client, err := middleendclient.New(addr, tlsFiles)
+			cobra.CheckErr(err)
+
+			ctx, cancel := context.WithTimeout(context.Background(), timeout)
+			defer cancel()
+
+			err = client.DeleteQosVolume(ctx, name, allowMissing)
+			cobra.CheckErr(err)
+		},
+	}
+
+	cmd.Flags().StringVar(&name, "name", "", "name of deleted QoS volume")
+	cmd.Flags().BoolVar(&allowMissing, "allowMissing", false, "cmd succeeds if attempts to delete a resource that is not present")
+
+	cobra.CheckErr(cmd.MarkFlagRequired("name"))
+
+	return cmd
+}
+
+func newGetQosVolumeCommand() *cobra.Command {
+	name := ""
+
+	cmd := &cobra.Command{
+		Use:     "qos",
+		Aliases: []string{"q"},
+		Short:   "Gets QoS volume",
+		Args:    cobra.NoArgs,
+		Run: func(c *cobra.Command, _ []string) {
+			addr, err := c.Flags().GetString(common.AddrCmdLineArg)
+			cobra.CheckErr(err)
+
+			timeout, err := c.Flags().GetDuration(common.TimeoutCmdLineArg)
+			cobra.CheckErr(err)
+
+			tlsFiles, err := c.Flags().GetString(common.TLSFiles)
+			cobra.CheckErr(err)
+
+			client, err := middleendclient.New(addr, tlsFiles)
+			cobra.CheckErr(err)
+
+			ctx, cancel := context.WithTimeout(context.Background(), timeout)
+			defer cancel()
+
+			volume, err := client.GetQosVolume(ctx, name)
+			cobra.CheckErr(err)
+
+			common.PrintResponse(protojson.Format(volume))
+		},
+	}
+
+	cmd.Flags().StringVar(&name, "name", "", "name of QoS volume to get")
+
+	cobra.CheckErr(cmd.MarkFlagRequired("name"))
+
+	return cmd
+}
+
+func newListQosVolumeCommand() *cobra.Command {
+	parent := ""
+
+	cmd := &cobra.Command{
+		Use:     "qos",
+		Aliases: []string{"q"},
+		Short:   "Lists QoS volumes",
+		Args:    cobra.NoArgs,
+		Run: func(c *cobra.Command, _ []string) {
+			addr, err := c.Flags().GetString(common.AddrCmdLineArg)
+			cobra.CheckErr(err)
+
+			timeout, err := c.Flags().GetDuration(common.TimeoutCmdLineArg)
+			cobra.CheckErr(err)
+
+			tlsFiles, err := c.Flags().GetString(common.TLSFiles)
+			cobra.CheckErr(err)
+
+			client, err := middleendclient.New(addr, tlsFiles)
+			cobra.CheckErr(err)
+
+			ctx, cancel := context.WithTimeout(context.Background(), timeout)
+			defer cancel()
+
+			pageToken := ""
+			for {
+				response, err := client.ListQosVolumes(ctx, parent, 0, pageToken)
+				cobra.CheckErr(err)
+
+				for _, volume := range response.QosVolumes {
+					common.PrintResponse(volume.Name)
+				}
+
+				pageToken = response.NextPageToken
+				if pageToken == "" {
+					break
+				}
+			}
+		},
+	}
+
+	cmd.Flags().StringVar(&parent, "parent", "", "parent of listed QoS volumes")
+
+	return cmd
+}
+
+func newUpdateQosVolumeCommand() *cobra.Command {
+	name := ""
+	limits := newQosLimits()
+	allowMissing := false
+
+	cmd := &cobra.Command{
+		Use:     "qos",
+		Aliases: []string{"q"},
+		Short:   "Updates limits of QoS volume",
+		Args:    cobra.NoArgs,
+		Run: func(c *cobra.Command, _ []string) {
+			addr, err := c.Flags().GetString(common.AddrCmdLineArg)
+			cobra.CheckErr(err)
+
+			timeout, err := c.Flags().GetDuration(common.TimeoutCmdLineArg)
+			cobra.CheckErr(err)
+
+			tlsFiles, err := c.Flags().GetString(common.TLSFiles)
+			cobra.CheckErr(err)
+
+			client, err := middleendclient.New(addr, tlsFiles)
+			cobra.CheckErr(err)
+
+			ctx, cancel := context.WithTimeout(context.Background(), timeout)
+			defer cancel()
+
+			updateMask := []string{}
+			for _, f := range qosLimitFlags(limits) {
+				if c.Flags().Changed(f.flag) {
+					updateMask = append(updateMask, f.field)
+				}
+			}
+			if len(updateMask) == 0 {
+				cobra.CheckErr(errors.New("no fields to update are specified"))
+			}
+
+			volume := &pb.QosVolume{
+				Name:   name,
+				Limits: limits,
+			}
+			response, err := client.UpdateQosVolume(ctx, volume, updateMask, allowMissing)
+			cobra.CheckErr(err)
+
+			common.PrintResponse(response.Name)
+		},
+	}
+
+	cmd.Flags().StringVar(&name, "name", "", "name of updated QoS volume")
+	cmd.Flags().BoolVar(&allowMissing, "allowMissing", false, "cmd succeeds if attempts to update a resource that is not present")
+	addQosLimitsFlags(cmd, limits)
+
+	cobra.CheckErr(cmd.MarkFlagRequired("name"))
+
+	return cmd
+}
+
+func newStatsQosVolumeCommand() *cobra.Command {
+	name := ""
+
+	cmd := &cobra.Command{
+		Use:     "qos",
+		Aliases: []string{"q"},
+		Short:   "Gets io statistics of QoS volume",
+		Args:    cobra.NoArgs,
+		Run: func(c *cobra.Command, _ []string) {
+			addr, err := c.Flags().GetString(common.AddrCmdLineArg)
+			cobra.CheckErr(err)
+
+			timeout, err := c.Flags().GetDuration(common.TimeoutCmdLineArg)
+			cobra.CheckErr(err)
+
+			tlsFiles, err := c.Flags().GetString(common.TLSFiles)
+			cobra.CheckErr(err)
+
+			client, err := middleendclient.New(addr, tlsFiles)
+			cobra.CheckErr(err)
+
+			ctx, cancel := context.WithTimeout(context.Background(), timeout)
+			defer cancel()
+
+			stats, err := client.StatsQosVolume(ctx, name)
+			cobra.CheckErr(err)
+
+			common.PrintResponse(protojson.Format(stats))
+		},
+	}
+
+	cmd.Flags().StringVar(&name, "name", "", "name of QoS volume to get statistics of")
+
+	cobra.CheckErr(cmd.MarkFlagRequired("name"))
+
+	return cmd
+}
+
+type qosLimitFlag struct {
+	flag  string
+	field string
+	value *int64
+}
+
+func newQosLimits() *pb.Limits {
+	return &pb.Limits{
+		Min: &pb.QosLimit{},
+		Max: &pb.QosLimit{},
+	}
+}
+
+// qosLimitFlags maps limit flags to update mask fields and limit values
+func qosLimitFlags(limits *pb.Limits) []qosLimitFlag {
+	flags := []qosLimitFlag{}
+	for bound, limit := range map[string]*pb.QosLimit{"min": limits.Min, "max": limits.Max} {
+		for field, value := range map[string]*int64{
+			"rd_iops_kiops":    &limit.RdIopsKiops,
+			"wr_iops_kiops":    &limit.WrIopsKiops,
+			"rw_iops_kiops":    &limit.RwIopsKiops,
+			"rd_bandwidth_mbs": &limit.RdBandwidthMbs,
+			"wr_bandwidth_mbs": &limit.WrBandwidthMbs,
+			"rw_bandwidth_mbs": &limit.RwBandwidthMbs,
+		} {
+			flags = append(flags, qosLimitFlag{
+				flag:  bound + "-" + strings.ReplaceAll(field, "_", "-"),
+				field: "limits." + bound + "." + field,
+				value: value,
+			})
+		}
+	}
+	sort.Slice(flags, func(i, j int) bool { return flags[i].flag < flags[j].flag })
+
+	return flags
+}
+
+func addQosLimitsFlags(cmd *cobra.Command, limits *pb.Limits) {
+	for _, f := range qosLimitFlags(limits) {
+		usage := strings.ReplaceAll(f.flag, "-", " ") + " limit. Unset if omitted."
+		cmd.Flags().Int64Var(f.value, f.flag, 0, usage)
+	}
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/opiproject/godpu/cmd/common"
 	"github.com/opiproject/godpu/cmd/storage/backend"
 	"github.com/opiproject/godpu/cmd/storage/frontend"
+	"github.com/opiproject/godpu/cmd/storage/middleend"
 	"github.com/spf13/cobra"
 )
 
@@ -32,7 +33,13 @@ func NewStorageCommand() *cobra.Command {
 	cmd.AddCommand(newStorageCreateCommand())
 	cmd.AddCommand(newStorageDeleteCommand())
 	cmd.AddCommand(newStorageGetCommand())
+	cmd.AddCommand(newStorageListCommand())
+	cmd.AddCommand(newStorageUpdateCommand())
+	cmd.AddCommand(newStorageResetCommand())
+	cmd.AddCommand(newStorageStatsCommand())
+	cmd.AddCommand(middleend.NewQosCommand())
 	cmd.AddCommand(newStorageTestCommand())
+	cmd.AddCommand(newStorageBenchCommand())
 
 	return cmd
 }
@@ -50,6 +57,7 @@ func newStorageCreateCommand() *cobra.Command {
 	}
 
 	cmd.AddCommand(frontend.NewCreateCommand())
+	cmd.AddCommand(middleend.NewCreateCommand())
 	cmd.AddCommand(backend.NewCreateCommand())
 
 	return cmd
@@ -68,6 +76,7 @@ func newStorageDeleteCommand() *cobra.Command {
 	}
 
 	cmd.AddCommand(frontend.NewDeleteCommand())
+	cmd.AddCommand(middleend.NewDeleteCommand())
 	cmd.AddCommand(backend.NewDeleteCommand())
 
 	return cmd
@@ -85,7 +94,81 @@ func newStorageGetCommand() *cobra.Command {
 		},
 	}
 
+	cmd.AddCommand(middleend.NewGetCommand())
 	cmd.AddCommand(backend.NewGetCommand())
 
 	return cmd
 }
+
+func newStorageListCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "list",
+		Aliases: []string{"l"},
+		Short:   "Lists resources",
+		Args:    cobra.NoArgs,
+		Run: func(c *cobra.Command, _ []string) {
+			err := c.Help()
+			cobra.CheckErr(err)
+		},
+	}
+
+	cmd.AddCommand(frontend.NewListCommand())
+	cmd.AddCommand(middleend.NewListCommand())
+	cmd.AddCommand(backend.NewListCommand())
+
+	return cmd
+}
+
+func newStorageUpdateCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "update",
+		Aliases: []string{"u"},
+		Short:   "Updates resource",
+		Args:    cobra.NoArgs,
+		Run: func(c *cobra.Command, _ []string) {
+			err := c.Help()
+			cobra.CheckErr(err)
+		},
+	}
+
+	cmd.AddCommand(frontend.NewUpdateCommand())
+	cmd.AddCommand(middleend.NewUpdateCommand())
+	cmd.AddCommand(backend.NewUpdateCommand())
+
+	return cmd
+}
+
+func newStorageResetCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "reset",
+		Aliases: []string{"r"},
+		Short:   "Resets resource",
+		Args:    cobra.NoArgs,
+		Run: func(c *cobra.Command, _ []string) {
+			err := c.Help()
+			cobra.CheckErr(err)
+		},
+	}
+
+	cmd.AddCommand(backend.NewResetCommand())
+
+	return cmd
+}
+
+func newStorageStatsCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "stats",
+		Aliases: []string{"st"},
+		Short:   "Gets resource statistics",
+		Args:    cobra.NoArgs,
+		Run: func(c *cobra.Command, _ []string) {
+			err := c.Help()
+			cobra.CheckErr(err)
+		},
+	}
+
+	cmd.AddCommand(middleend.NewStatsCommand())
+	cmd.AddCommand(backend.NewStatsCommand())
+
+	return cmd
+}
@@ -6,13 +6,15 @@
 package storage
 
 import (
-	"context"
+	"fmt"
 	"log"
 
 	"github.com/opiproject/godpu/cmd/common"
-	"github.com/opiproject/godpu/grpc"
+	grpcOpi "github.com/opiproject/godpu/grpc"
 	"github.com/opiproject/godpu/storage/test"
+	"github.com/opiproject/godpu/testing/report"
 	"github.com/spf13/cobra"
+	"google.golang.org/grpc"
 )
 
 type storagePartition string
@@ -29,6 +31,19 @@ var allStoragePartitions = []storagePartition{
 	storagePartitionMiddleend,
 }
 
+const (
+	fixturesCmdLineArg     = "fixtures"
+	prefixCmdLineArg       = "prefix"
+	randomSuffixCmdLineArg = "random-suffix"
+	targetAddrCmdLineArg   = "target-addr"
+	targetPortCmdLineArg   = "target-port"
+	targetNqnCmdLineArg    = "target-nqn"
+	tlsCmdLineArg          = "tls"
+	pskFileCmdLineArg      = "psk-file"
+	virtioBlkCmdLineArg    = "virtio-blk"
+	virtioScsiCmdLineArg   = "virtio-scsi"
+)
+
 func newStorageTestCommand() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:     "test",
@@ -44,13 +59,58 @@ func newStorageTestCommand() *cobra.Command {
 		},
 	}
 
+	common.AddReportFlags(cmd)
+	addFixtureFlags(cmd)
+
 	cmd.AddCommand(newStorageTestFrontendCommand())
 	cmd.AddCommand(newStorageTestBackendCommand())
 	cmd.AddCommand(newStorageTestMiddleendCommand())
+	cmd.AddCommand(newStorageTestCleanupCommand())
 
 	return cmd
 }
 
+// addFixtureFlags adds the flags read by fixturesFromFlags to cmd and its
+// subcommands
+func addFixtureFlags(cmd *cobra.Command) {
+	flags := cmd.PersistentFlags()
+	defaults := test.DefaultFixtures()
+	flags.String(fixturesCmdLineArg, "", "JSON file with the resources and addresses used by the tests, overridden by the flags below")
+	flags.String(prefixCmdLineArg, "", "prefix of the ids of all created resources")
+	flags.Bool(randomSuffixCmdLineArg, false, "append a random suffix to the ids of all created resources to isolate parallel runs")
+	flags.String(targetAddrCmdLineArg, defaults.Target.Addr, "ip address or host name of the remote nvme/tcp target")
+	flags.Int(targetPortCmdLineArg, defaults.Target.Port, "port of the remote nvme/tcp target")
+	flags.String(targetNqnCmdLineArg, defaults.Target.Nqn, "nqn of the remote nvme/tcp target")
+	flags.Bool(tlsCmdLineArg, defaults.Target.TLS, "test the remote nvme/tcp target over a TLS secure channel as well")
+	flags.String(pskFileCmdLineArg, "", "file with the TLS pre-shared key of the remote nvme/tcp target")
+	addPciFunctionFlags(cmd, virtioBlkCmdLineArg, defaults.VirtioBlk)
+	addPciFunctionFlags(cmd, virtioScsiCmdLineArg, defaults.VirtioScsi)
+}
+
+// addPciFunctionFlags adds the -port, -pf and -vf flags of a device
+func addPciFunctionFlags(cmd *cobra.Command, device string, defaults test.PciFunction) {
+	flags := cmd.PersistentFlags()
+	flags.Int32(device+"-port", defaults.Port, "PCIe port of "+device+" devices")
+	flags.Int32(device+"-pf", defaults.PhysicalFunction, "PCIe physical function of "+device+" devices")
+	flags.Int32(device+"-vf", defaults.VirtualFunction, "PCIe virtual function of "+device+" devices")
+}
+
+// readPciFunctionFlags overrides a device's PCIe function with its changed flags
+func readPciFunctionFlags(cmd *cobra.Command, device string, function *test.PciFunction) {
+	flags := cmd.Flags()
+	for name, field := range map[string]*int32{
+		device + "-port": &function.Port,
+		device + "-pf":   &function.PhysicalFunction,
+		device + "-vf":   &function.VirtualFunction,
+	} {
+		if flags.Changed(name) {
+			value, err := flags.GetInt32(name)
+			cobra.CheckErr(err)
+			*field = value
+		}
+	}
+}
+
 func newStorageTestFrontendCommand() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   string(storagePartitionFrontend),
@@ -144,51 +204,168 @@ func runTests(
 	partitions []storagePartition,
 	frontendPartitions []test.FrontendPartition,
 ) {
-	addr, err := cmd.Flags().GetString(common.AddrCmdLineArg)
-	if err != nil {
-		log.Fatalf("error getting %v argument: %v", common.AddrCmdLineArg, err)
-	}
+	conn, closer := newTestConn(cmd)
+	defer closer()
 
-	timeout, err := cmd.Flags().GetDuration(common.TimeoutCmdLineArg)
-	if err != nil {
-		log.Fatalf("error getting %v argument: %v", common.AddrCmdLineArg, err)
-	}
+	reportFormat, reportFile := common.ReportFromFlags(cmd)
 
-	// Set up a connection to the server.
-	client, err := grpc.New(addr, "")
+	continueOnFailure, err := cmd.Flags().GetBool(common.ContinueOnFailureCmdLineArg)
 	if err != nil {
-		log.Fatalf("error creating new client: %v", err)
+		log.Fatalf("error getting %v argument: %v", common.ContinueOnFailureCmdLineArg, err)
 	}
 
-	// Contact the server and print out its response.
-	conn, closer, err := client.NewConn()
+	fixtures, err := fixturesFromFlags(cmd)
 	if err != nil {
-		log.Fatalf("error creating gRPC connection: %v", err)
+		log.Fatalf("error getting fixtures: %v", err)
 	}
-	defer closer()
 
-	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	ctx, cancel := common.NewTestContext(cmd)
 	defer cancel()
 
+	report := test.NewReport(continueOnFailure)
 	for _, partition := range partitions {
 		log.Printf("==============================================================================")
 		log.Printf("Test %v", partition)
 		log.Printf("==============================================================================")
 
-		var err error
 		switch partition {
 		case storagePartitionFrontend:
-			err = test.DoFrontend(ctx, conn, frontendPartitions)
+			err = test.RunFrontend(ctx, conn, frontendPartitions, fixtures, report)
 		case storagePartitionBackend:
-			err = test.DoBackend(ctx, conn)
+			err = test.RunBackend(ctx, conn, fixtures, report)
 		case storagePartitionMiddleend:
-			err = test.DoMiddleend(ctx, conn)
+			err = test.RunMiddleend(ctx, conn, fixtures, report)
 		default:
 			log.Panicf("Unknown storage partition: %v", partition)
 		}
 
 		if err != nil {
-			log.Panicf("%v tests failed with error: %v", partition, err)
+			err = fmt.Errorf("%v tests failed with error: %w", partition, err)
+			break
+		}
+	}
+
+	if reportFormat != "" {
+		if err := common.WriteReport(report, reportFormat, reportFile); err != nil {
+			log.Fatalf("error writing report: %v", err)
+		}
+	}
+
+	if err != nil {
+		log.Panic(err)
+	}
+	if report.Failures() > 0 {
+		log.Panicf("%d test cases failed: %v", report.Failures(), report.Err())
+	}
+}
+
+func newStorageTestCleanupCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "cleanup",
+		Short: "Deletes resources left behind by earlier test runs",
+		Long:  "Deletes all resources whose id or parent id starts with --prefix, e.g. after a test run was killed",
+		Args:  cobra.NoArgs,
+		Run: func(c *cobra.Command, _ []string) {
+			conn, closer := newTestConn(c)
+			defer closer()
+
+			reportFormat, reportFile := common.ReportFromFlags(c)
+
+			fixtures, err := fixturesFromFlags(c)
+			if err != nil {
+				log.Fatalf("error getting fixtures: %v", err)
+			}
+			if fixtures.Prefix == "" {
+				log.Fatalf("%v is required to find leftover resources", prefixCmdLineArg)
+			}
+
+			ctx, cancel := common.NewTestContext(c)
+			defer cancel()
+
+			report := test.NewReport(true)
+			err = test.Cleanup(ctx, conn, fixtures.Prefix, report)
+
+			if reportFormat != "" {
+				if err := common.WriteReport(report, reportFormat, reportFile); err != nil {
+					log.Fatalf("error writing report: %v", err)
+				}
+			}
+
+			if err != nil {
+				log.Panicf("cleanup failed: %v", err)
+			}
+		},
+	}
+
+	return cmd
+}
+
+// newTestConn connects to the server given on the command line
+func newTestConn(cmd *cobra.Command) (grpc.ClientConnInterface, grpcOpi.Closer) {
+	addr, err := cmd.Flags().GetString(common.AddrCmdLineArg)
+	if err != nil {
+		log.Fatalf("error getting %v argument: %v", common.AddrCmdLineArg, err)
+	}
+
+	// Set up a connection to the server.
+	client, err := grpcOpi.New(addr, "")
+	if err != nil {
+		log.Fatalf("error creating new client: %v", err)
+	}
+
+	// Contact the server and print out its response.
+	conn, closer, err := client.NewConn()
+	if err != nil {
+		log.Fatalf("error creating gRPC connection: %v", err)
+	}
+	return conn, closer
+}
+
+// fixturesFromFlags loads the fixtures file if given and applies the fixture
+// flags set on the command line
+func fixturesFromFlags(cmd *cobra.Command) (*test.Fixtures, error) {
+	flags := cmd.Flags()
+	fixtures := test.DefaultFixtures()
+	var err error
+	if path, _ := flags.GetString(fixturesCmdLineArg); path != "" {
+		fixtures, err = test.LoadFixtures(path)
+		if err != nil {
+			return nil, err
 		}
 	}
+
+	if flags.Changed(prefixCmdLineArg) {
+		fixtures.Prefix, err = flags.GetString(prefixCmdLineArg)
+		cobra.CheckErr(err)
+	}
+	if randomSuffix, _ := flags.GetBool(randomSuffixCmdLineArg); randomSuffix {
+		fixtures.Suffix = report.RandomSuffix()
+	}
+	if flags.Changed(targetAddrCmdLineArg) {
+		fixtures.Target.Addr, err = flags.GetString(targetAddrCmdLineArg)
+		cobra.CheckErr(err)
+	}
+	if flags.Changed(targetPortCmdLineArg) {
+		fixtures.Target.Port, err = flags.GetInt(targetPortCmdLineArg)
+		cobra.CheckErr(err)
+	}
+	if flags.Changed(targetNqnCmdLineArg) {
+		fixtures.Target.Nqn, err = flags.GetString(targetNqnCmdLineArg)
+		cobra.CheckErr(err)
+	}
+	if flags.Changed(tlsCmdLineArg) {
+		fixtures.Target.TLS, err = flags.GetBool(tlsCmdLineArg)
+		cobra.CheckErr(err)
+	}
+	if flags.Changed(pskFileCmdLineArg) {
+		fixtures.Target.PskFile, err = flags.GetString(pskFileCmdLineArg)
+		cobra.CheckErr(err)
+		if err := fixtures.Target.ReadPskFile(); err != nil {
+			return nil, err
+		}
+	}
+	readPciFunctionFlags(cmd, virtioBlkCmdLineArg, &fixtures.VirtioBlk)
+	readPciFunctionFlags(cmd, virtioScsiCmdLineArg, &fixtures.VirtioScsi)
+
+	return fixtures, fixtures.Validate()
 }
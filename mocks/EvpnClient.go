@@ -932,25 +932,25 @@ func (_c *EvpnClient_ListVrfs_Call) RunAndReturn(run func(context.Context, int32
 	return _c
 }
 
-// UpdateBridgePort provides a mock function with given fields: ctx, name, updateMask, allowMissing
-func (_m *EvpnClient) UpdateBridgePort(ctx context.Context, name string, updateMask []string, allowMissing bool) (*_go.BridgePort, error) {
-	ret := _m.Called(ctx, name, updateMask, allowMissing)
+// UpdateBridgePort provides a mock function with given fields: ctx, name, spec, updateMask, allowMissing
+func (_m *EvpnClient) UpdateBridgePort(ctx context.Context, name string, spec *_go.BridgePortSpec, updateMask []string, allowMissing bool) (*_go.BridgePort, error) {
+	ret := _m.Called(ctx, name, spec, updateMask, allowMissing)
 
 	var r0 *_go.BridgePort
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, string, []string, bool) (*_go.BridgePort, error)); ok {
-		return rf(ctx, name, updateMask, allowMissing)
+	if rf, ok := ret.Get(0).(func(context.Context, string, *_go.BridgePortSpec, []string, bool) (*_go.BridgePort, error)); ok {
+		return rf(ctx, name, spec, updateMask, allowMissing)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, string, []string, bool) *_go.BridgePort); ok {
-		r0 = rf(ctx, name, updateMask, allowMissing)
+	if rf, ok := ret.Get(0).(func(context.Context, string, *_go.BridgePortSpec, []string, bool) *_go.BridgePort); ok {
+		r0 = rf(ctx, name, spec, updateMask, allowMissing)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*_go.BridgePort)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, string, []string, bool) error); ok {
-		r1 = rf(ctx, name, updateMask, allowMissing)
+	if rf, ok := ret.Get(1).(func(context.Context, string, *_go.BridgePortSpec, []string, bool) error); ok {
+		r1 = rf(ctx, name, spec, updateMask, allowMissing)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -966,15 +966,16 @@ type EvpnClient_UpdateBridgePort_Call struct {
 // UpdateBridgePort is a helper method to define mock.On call
 //   - ctx context.Context
 //   - name string
+//   - spec *_go.BridgePortSpec
 //   - updateMask []string
 //   - allowMissing bool
-func (_e *EvpnClient_Expecter) UpdateBridgePort(ctx interface{}, name interface{}, updateMask interface{}, allowMissing interface{}) *EvpnClient_UpdateBridgePort_Call {
-	return &EvpnClient_UpdateBridgePort_Call{Call: _e.mock.On("UpdateBridgePort", ctx, name, updateMask, allowMissing)}
+func (_e *EvpnClient_Expecter) UpdateBridgePort(ctx interface{}, name interface{}, spec interface{}, updateMask interface{}, allowMissing interface{}) *EvpnClient_UpdateBridgePort_Call {
+	return &EvpnClient_UpdateBridgePort_Call{Call: _e.mock.On("UpdateBridgePort", ctx, name, spec, updateMask, allowMissing)}
 }
 
-func (_c *EvpnClient_UpdateBridgePort_Call) Run(run func(ctx context.Context, name string, updateMask []string, allowMissing bool)) *EvpnClient_UpdateBridgePort_Call {
+func (_c *EvpnClient_UpdateBridgePort_Call) Run(run func(ctx context.Context, name string, spec *_go.BridgePortSpec, updateMask []string, allowMissing bool)) *EvpnClient_UpdateBridgePort_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(string), args[2].([]string), args[3].(bool))
+		run(args[0].(context.Context), args[1].(string), args[2].(*_go.BridgePortSpec), args[3].([]string), args[4].(bool))
 	})
 	return _c
 }
@@ -984,30 +985,30 @@ func (_c *EvpnClient_UpdateBridgePort_Call) Return(_a0 *_go.BridgePort, _a1 erro
 	return _c
 }
 
-func (_c *EvpnClient_UpdateBridgePort_Call) RunAndReturn(run func(context.Context, string, []string, bool) (*_go.BridgePort, error)) *EvpnClient_UpdateBridgePort_Call {
+func (_c *EvpnClient_UpdateBridgePort_Call) RunAndReturn(run func(context.Context, string, *_go.BridgePortSpec, []string, bool) (*_go.BridgePort, error)) *EvpnClient_UpdateBridgePort_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// UpdateLogicalBridge provides a mock function with given fields: ctx, name, updateMask
-func (_m *EvpnClient) UpdateLogicalBridge(ctx context.Context, name string, updateMask []string) (*_go.LogicalBridge, error) {
-	ret := _m.Called(ctx, name, updateMask)
+// UpdateLogicalBridge provides a mock function with given fields: ctx, name, spec, updateMask, allowMissing
+func (_m *EvpnClient) UpdateLogicalBridge(ctx context.Context, name string, spec *_go.LogicalBridgeSpec, updateMask []string, allowMissing bool) (*_go.LogicalBridge, error) {
+	ret := _m.Called(ctx, name, spec, updateMask, allowMissing)
 
 	var r0 *_go.LogicalBridge
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, string, []string) (*_go.LogicalBridge, error)); ok {
-		return rf(ctx, name, updateMask)
+	if rf, ok := ret.Get(0).(func(context.Context, string, *_go.LogicalBridgeSpec, []string, bool) (*_go.LogicalBridge, error)); ok {
+		return rf(ctx, name, spec, updateMask, allowMissing)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, string, []string) *_go.LogicalBridge); ok {
-		r0 = rf(ctx, name, updateMask)
+	if rf, ok := ret.Get(0).(func(context.Context, string, *_go.LogicalBridgeSpec, []string, bool) *_go.LogicalBridge); ok {
+		r0 = rf(ctx, name, spec, updateMask, allowMissing)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*_go.LogicalBridge)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, string, []string) error); ok {
-		r1 = rf(ctx, name, updateMask)
+	if rf, ok := ret.Get(1).(func(context.Context, string, *_go.LogicalBridgeSpec, []string, bool) error); ok {
+		r1 = rf(ctx, name, spec, updateMask, allowMissing)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -1023,14 +1024,16 @@ type EvpnClient_UpdateLogicalBridge_Call struct {
 // UpdateLogicalBridge is a helper method to define mock.On call
 //   - ctx context.Context
 //   - name string
+//   - spec *_go.LogicalBridgeSpec
 //   - updateMask []string
-func (_e *EvpnClient_Expecter) UpdateLogicalBridge(ctx interface{}, name interface{}, updateMask interface{}) *EvpnClient_UpdateLogicalBridge_Call {
-	return &EvpnClient_UpdateLogicalBridge_Call{Call: _e.mock.On("UpdateLogicalBridge", ctx, name, updateMask)}
+//   - allowMissing bool
+func (_e *EvpnClient_Expecter) UpdateLogicalBridge(ctx interface{}, name interface{}, spec interface{}, updateMask interface{}, allowMissing interface{}) *EvpnClient_UpdateLogicalBridge_Call {
+	return &EvpnClient_UpdateLogicalBridge_Call{Call: _e.mock.On("UpdateLogicalBridge", ctx, name, spec, updateMask, allowMissing)}
 }
 
-func (_c *EvpnClient_UpdateLogicalBridge_Call) Run(run func(ctx context.Context, name string, updateMask []string)) *EvpnClient_UpdateLogicalBridge_Call {
+func (_c *EvpnClient_UpdateLogicalBridge_Call) Run(run func(ctx context.Context, name string, spec *_go.LogicalBridgeSpec, updateMask []string, allowMissing bool)) *EvpnClient_UpdateLogicalBridge_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(string), args[2].([]string))
+		run(args[0].(context.Context), args[1].(string), args[2].(*_go.LogicalBridgeSpec), args[3].([]string), args[4].(bool))
 	})
 	return _c
 }
@@ -1040,30 +1043,30 @@ func (_c *EvpnClient_UpdateLogicalBridge_Call) Return(_a0 *_go.LogicalBridge, _a
 	return _c
 }
 
-func (_c *EvpnClient_UpdateLogicalBridge_Call) RunAndReturn(run func(context.Context, string, []string) (*_go.LogicalBridge, error)) *EvpnClient_UpdateLogicalBridge_Call {
+func (_c *EvpnClient_UpdateLogicalBridge_Call) RunAndReturn(run func(context.Context, string, *_go.LogicalBridgeSpec, []string, bool) (*_go.LogicalBridge, error)) *EvpnClient_UpdateLogicalBridge_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// UpdateSvi provides a mock function with given fields: ctx, name, updateMask, allowMissing
-func (_m *EvpnClient) UpdateSvi(ctx context.Context, name string, updateMask []string, allowMissing bool) (*_go.Svi, error) {
-	ret := _m.Called(ctx, name, updateMask, allowMissing)
+// UpdateSvi provides a mock function with given fields: ctx, name, spec, updateMask, allowMissing
+func (_m *EvpnClient) UpdateSvi(ctx context.Context, name string, spec *_go.SviSpec, updateMask []string, allowMissing bool) (*_go.Svi, error) {
+	ret := _m.Called(ctx, name, spec, updateMask, allowMissing)
 
 	var r0 *_go.Svi
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, string, []string, bool) (*_go.Svi, error)); ok {
-		return rf(ctx, name, updateMask, allowMissing)
+	if rf, ok := ret.Get(0).(func(context.Context, string, *_go.SviSpec, []string, bool) (*_go.Svi, error)); ok {
+		return rf(ctx, name, spec, updateMask, allowMissing)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, string, []string, bool) *_go.Svi); ok {
-		r0 = rf(ctx, name, updateMask, allowMissing)
+	if rf, ok := ret.Get(0).(func(context.Context, string, *_go.SviSpec, []string, bool) *_go.Svi); ok {
+		r0 = rf(ctx, name, spec, updateMask, allowMissing)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*_go.Svi)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, string, []string, bool) error); ok {
-		r1 = rf(ctx, name, updateMask, allowMissing)
+	if rf, ok := ret.Get(1).(func(context.Context, string, *_go.SviSpec, []string, bool) error); ok {
+		r1 = rf(ctx, name, spec, updateMask, allowMissing)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -1079,15 +1082,16 @@ type EvpnClient_UpdateSvi_Call struct {
 // UpdateSvi is a helper method to define mock.On call
 //   - ctx context.Context
 //   - name string
+//   - spec *_go.SviSpec
 //   - updateMask []string
 //   - allowMissing bool
-func (_e *EvpnClient_Expecter) UpdateSvi(ctx interface{}, name interface{}, updateMask interface{}, allowMissing interface{}) *EvpnClient_UpdateSvi_Call {
-	return &EvpnClient_UpdateSvi_Call{Call: _e.mock.On("UpdateSvi", ctx, name, updateMask, allowMissing)}
+func (_e *EvpnClient_Expecter) UpdateSvi(ctx interface{}, name interface{}, spec interface{}, updateMask interface{}, allowMissing interface{}) *EvpnClient_UpdateSvi_Call {
+	return &EvpnClient_UpdateSvi_Call{Call: _e.mock.On("UpdateSvi", ctx, name, spec, updateMask, allowMissing)}
 }
 
-func (_c *EvpnClient_UpdateSvi_Call) Run(run func(ctx context.Context, name string, updateMask []string, allowMissing bool)) *EvpnClient_UpdateSvi_Call {
+func (_c *EvpnClient_UpdateSvi_Call) Run(run func(ctx context.Context, name string, spec *_go.SviSpec, updateMask []string, allowMissing bool)) *EvpnClient_UpdateSvi_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(string), args[2].([]string), args[3].(bool))
+		run(args[0].(context.Context), args[1].(string), args[2].(*_go.SviSpec), args[3].([]string), args[4].(bool))
 	})
 	return _c
 }
@@ -1097,30 +1101,30 @@ func (_c *EvpnClient_UpdateSvi_Call) Return(_a0 *_go.Svi, _a1 error) *EvpnClient
 	return _c
 }
 
-func (_c *EvpnClient_UpdateSvi_Call) RunAndReturn(run func(context.Context, string, []string, bool) (*_go.Svi, error)) *EvpnClient_UpdateSvi_Call {
+func (_c *EvpnClient_UpdateSvi_Call) RunAndReturn(run func(context.Context, string, *_go.SviSpec, []string, bool) (*_go.Svi, error)) *EvpnClient_UpdateSvi_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// UpdateVrf provides a mock function with given fields: ctx, name, updateMask, allowMissing
-func (_m *EvpnClient) UpdateVrf(ctx context.Context, name string, updateMask []string, allowMissing bool) (*_go.Vrf, error) {
-	ret := _m.Called(ctx, name, updateMask, allowMissing)
+// UpdateVrf provides a mock function with given fields: ctx, name, spec, updateMask, allowMissing
+func (_m *EvpnClient) UpdateVrf(ctx context.Context, name string, spec *_go.VrfSpec, updateMask []string, allowMissing bool) (*_go.Vrf, error) {
+	ret := _m.Called(ctx, name, spec, updateMask, allowMissing)
 
 	var r0 *_go.Vrf
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, string, []string, bool) (*_go.Vrf, error)); ok {
-		return rf(ctx, name, updateMask, allowMissing)
+	if rf, ok := ret.Get(0).(func(context.Context, string, *_go.VrfSpec, []string, bool) (*_go.Vrf, error)); ok {
+		return rf(ctx, name, spec, updateMask, allowMissing)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, string, []string, bool) *_go.Vrf); ok {
-		r0 = rf(ctx, name, updateMask, allowMissing)
+	if rf, ok := ret.Get(0).(func(context.Context, string, *_go.VrfSpec, []string, bool) *_go.Vrf); ok {
+		r0 = rf(ctx, name, spec, updateMask, allowMissing)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*_go.Vrf)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, string, []string, bool) error); ok {
-		r1 = rf(ctx, name, updateMask, allowMissing)
+	if rf, ok := ret.Get(1).(func(context.Context, string, *_go.VrfSpec, []string, bool) error); ok {
+		r1 = rf(ctx, name, spec, updateMask, allowMissing)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -1136,15 +1140,16 @@ type EvpnClient_UpdateVrf_Call struct {
 // UpdateVrf is a helper method to define mock.On call
 //   - ctx context.Context
 //   - name string
+//   - spec *_go.VrfSpec
 //   - updateMask []string
 //   - allowMissing bool
-func (_e *EvpnClient_Expecter) UpdateVrf(ctx interface{}, name interface{}, updateMask interface{}, allowMissing interface{}) *EvpnClient_UpdateVrf_Call {
-	return &EvpnClient_UpdateVrf_Call{Call: _e.mock.On("UpdateVrf", ctx, name, updateMask, allowMissing)}
+func (_e *EvpnClient_Expecter) UpdateVrf(ctx interface{}, name interface{}, spec interface{}, updateMask interface{}, allowMissing interface{}) *EvpnClient_UpdateVrf_Call {
+	return &EvpnClient_UpdateVrf_Call{Call: _e.mock.On("UpdateVrf", ctx, name, spec, updateMask, allowMissing)}
 }
 
-func (_c *EvpnClient_UpdateVrf_Call) Run(run func(ctx context.Context, name string, updateMask []string, allowMissing bool)) *EvpnClient_UpdateVrf_Call {
+func (_c *EvpnClient_UpdateVrf_Call) Run(run func(ctx context.Context, name string, spec *_go.VrfSpec, updateMask []string, allowMissing bool)) *EvpnClient_UpdateVrf_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(string), args[2].([]string), args[3].(bool))
+		run(args[0].(context.Context), args[1].(string), args[2].(*_go.VrfSpec), args[3].([]string), args[4].(bool))
 	})
 	return _c
 }
@@ -1154,7 +1159,7 @@ func (_c *EvpnClient_UpdateVrf_Call) Return(_a0 *_go.Vrf, _a1 error) *EvpnClient
 	return _c
 }
 
-func (_c *EvpnClient_UpdateVrf_Call) RunAndReturn(run func(context.Context, string, []string, bool) (*_go.Vrf, error)) *EvpnClient_UpdateVrf_Call {
+func (_c *EvpnClient_UpdateVrf_Call) RunAndReturn(run func(context.Context, string, *_go.VrfSpec, []string, bool) (*_go.Vrf, error)) *EvpnClient_UpdateVrf_Call {
 	_c.Call.Return(run)
 	return _c
 }
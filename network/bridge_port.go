@@ -13,6 +13,7 @@ import (
 	"net"
 
 	pb "github.com/opiproject/opi-api/network/evpn-gw/v1alpha1/gen/go"
+	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/types/known/emptypb"
 	"google.golang.org/protobuf/types/known/fieldmaskpb"
 )
@@ -46,14 +47,7 @@ func (c evpnClientImpl) CreateBridgePort(ctx context.Context, name string, mac s
 		return nil, err
 	}
 
-	switch bridgePortType {
-	case "access":
-		typeOfPort = pb.BridgePortType_BRIDGE_PORT_TYPE_ACCESS
-	case "trunk":
-		typeOfPort = pb.BridgePortType_BRIDGE_PORT_TYPE_TRUNK
-	default:
-		typeOfPort = pb.BridgePortType_BRIDGE_PORT_TYPE_UNSPECIFIED
-	}
+	typeOfPort = ParseBridgePortType(bridgePortType)
 	data, err := client.CreateBridgePort(ctx, &pb.CreateBridgePortRequest{
 		BridgePortId: name,
 		BridgePort: &pb.BridgePort{
@@ -144,8 +138,8 @@ func (c evpnClientImpl) ListBridgePorts(ctx context.Context, pageSize int32, pag
 	return data, nil
 }
 
-// UpdateBridgePort update the Bridge Port on OPI server
-func (c evpnClientImpl) UpdateBridgePort(ctx context.Context, name string, updateMask []string, allowMissing bool) (*pb.BridgePort, error) {
+// UpdateBridgePort updates the fields of the Bridge Port listed in updateMask with the values of spec
+func (c evpnClientImpl) UpdateBridgePort(ctx context.Context, name string, spec *pb.BridgePortSpec, updateMask []string, allowMissing bool) (*pb.BridgePort, error) {
 	conn, closer, err := c.NewConn()
 	if err != nil {
 		log.Printf("error creating connection: %s\n", err)
@@ -154,8 +148,16 @@ func (c evpnClientImpl) UpdateBridgePort(ctx context.Context, name string, updat
 	defer closer()
 
 	client := c.getEvpnBridgePortClient(conn)
+	if spec != nil {
+		// logical bridges are resource ids like in CreateBridgePort
+		spec = proto.Clone(spec).(*pb.BridgePortSpec)
+		for i, lb := range spec.LogicalBridges {
+			spec.LogicalBridges[i] = resourceIDToFullName("bridges", lb)
+		}
+	}
 	Port := &pb.BridgePort{
 		Name: resourceIDToFullName("ports", name),
+		Spec: spec,
 	}
 	data, err := client.UpdateBridgePort(ctx, &pb.UpdateBridgePortRequest{
 		BridgePort:   Port,
@@ -169,3 +171,16 @@ func (c evpnClientImpl) UpdateBridgePort(ctx context.Context, name string, updat
 
 	return data, nil
 }
+
+// ParseBridgePortType converts a bridge port type of access or trunk to its
+// protobuf value. Other types are unspecified.
+func ParseBridgePortType(bridgePortType string) pb.BridgePortType {
+	switch bridgePortType {
+	case "access":
+		return pb.BridgePortType_BRIDGE_PORT_TYPE_ACCESS
+	case "trunk":
+		return pb.BridgePortType_BRIDGE_PORT_TYPE_TRUNK
+	default:
+		return pb.BridgePortType_BRIDGE_PORT_TYPE_UNSPECIFIED
+	}
+}
@@ -329,11 +329,18 @@ func TestListBridgePorts(t *testing.T) {
 
 func TestUpdateBridgePort(t *testing.T) {
 	name := "bp1"
-	updateMask := []string{""}
+	spec := &pb.BridgePortSpec{Ptype: pb.BridgePortType_BRIDGE_PORT_TYPE_TRUNK, LogicalBridges: []string{"lb1"}}
+	updateMask := []string{"spec.ptype", "spec.logical_bridges"}
 	allowMissing := false
 
 	testRequest := &pb.UpdateBridgePortRequest{
-		BridgePort:   &pb.BridgePort{Name: resourceIDToFullName("ports", name)},
+		BridgePort: &pb.BridgePort{
+			Name: resourceIDToFullName("ports", name),
+			Spec: &pb.BridgePortSpec{
+				Ptype:          pb.BridgePortType_BRIDGE_PORT_TYPE_TRUNK,
+				LogicalBridges: []string{resourceIDToFullName("bridges", "lb1")},
+			},
+		},
 		UpdateMask:   &fieldmaskpb.FieldMask{Paths: updateMask},
 		AllowMissing: allowMissing,
 	}
@@ -398,7 +405,7 @@ func TestUpdateBridgePort(t *testing.T) {
 				},
 			)
 
-			response, err := c.UpdateBridgePort(context.Background(), name, updateMask, allowMissing)
+			response, err := c.UpdateBridgePort(context.Background(), name, spec, updateMask, allowMissing)
 
 			assert.Equal(t, tt.wantErr, err)
 			assert.Equal(t, tt.wantConnClosed, connClosed)
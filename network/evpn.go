@@ -45,28 +45,28 @@ type EvpnClient interface {
 	DeleteLogicalBridge(ctx context.Context, name string, allowMissing bool) (*emptypb.Empty, error)
 	GetLogicalBridge(ctx context.Context, name string) (*pb.LogicalBridge, error)
 	ListLogicalBridges(ctx context.Context, pageSize int32, pageToken string) (*pb.ListLogicalBridgesResponse, error)
-	UpdateLogicalBridge(ctx context.Context, name string, updateMask []string, allowMissing bool) (*pb.LogicalBridge, error)
+	UpdateLogicalBridge(ctx context.Context, name string, spec *pb.LogicalBridgeSpec, updateMask []string, allowMissing bool) (*pb.LogicalBridge, error)
 
 	// Bridge Port Interfaces
 	CreateBridgePort(ctx context.Context, name string, mac string, bridgePortType string, logicalBridges []string) (*pb.BridgePort, error)
 	DeleteBridgePort(ctx context.Context, name string, allowMissing bool) (*emptypb.Empty, error)
 	GetBridgePort(ctx context.Context, name string) (*pb.BridgePort, error)
 	ListBridgePorts(ctx context.Context, pageSize int32, pageToken string) (*pb.ListBridgePortsResponse, error)
-	UpdateBridgePort(ctx context.Context, name string, updateMask []string, allowMissing bool) (*pb.BridgePort, error)
+	UpdateBridgePort(ctx context.Context, name string, spec *pb.BridgePortSpec, updateMask []string, allowMissing bool) (*pb.BridgePort, error)
 
 	// VRF Interfaces
 	CreateVrf(ctx context.Context, name string, vni *uint32, loopback string, vtep string) (*pb.Vrf, error)
 	DeleteVrf(ctx context.Context, name string, allowMissing bool) (*emptypb.Empty, error)
 	GetVrf(ctx context.Context, name string) (*pb.Vrf, error)
 	ListVrfs(ctx context.Context, pageSize int32, pageToken string) (*pb.ListVrfsResponse, error)
-	UpdateVrf(ctx context.Context, name string, updateMask []string, allowMissing bool) (*pb.Vrf, error)
+	UpdateVrf(ctx context.Context, name string, spec *pb.VrfSpec, updateMask []string, allowMissing bool) (*pb.Vrf, error)
 
 	// SVI Interfaces
 	CreateSvi(ctx context.Context, name string, vrf string, logicalBridge string, mac string, gwIPs []string, ebgp bool, remoteAS uint32) (*pb.Svi, error)
 	DeleteSvi(ctx context.Context, name string, allowMissing bool) (*emptypb.Empty, error)
 	GetSvi(ctx context.Context, name string) (*pb.Svi, error)
 	ListSvis(ctx context.Context, pageSize int32, pageToken string) (*pb.ListSvisResponse, error)
-	UpdateSvi(ctx context.Context, name string, updateMask []string, allowMissing bool) (*pb.Svi, error)
+	UpdateSvi(ctx context.Context, name string, spec *pb.SviSpec, updateMask []string, allowMissing bool) (*pb.Svi, error)
 }
 
 func resourceIDToFullName(container string, resourceID string) string {
@@ -34,7 +34,7 @@ func (c evpnClientImpl) CreateLogicalBridge(ctx context.Context, name string, vl
 	}
 
 	if vni != nil && vtepIP != "" {
-		ipVtep, err = parseIPAndPrefix(vtepIP)
+		ipVtep, err = ParseIPAndPrefix(vtepIP)
 		if err != nil {
 			log.Printf("parseIPAndPrefix: error creating Logical Bridge: %s\n", err)
 			return nil, err
@@ -132,8 +132,8 @@ func (c evpnClientImpl) ListLogicalBridges(ctx context.Context, pageSize int32,
 	return data, nil
 }
 
-// UpdateLogicalBridge update Logical Bridge on OPI server
-func (c evpnClientImpl) UpdateLogicalBridge(ctx context.Context, name string, updateMask []string, allowMissing bool) (*pb.LogicalBridge, error) {
+// UpdateLogicalBridge updates the fields of the Logical Bridge listed in updateMask with the values of spec
+func (c evpnClientImpl) UpdateLogicalBridge(ctx context.Context, name string, spec *pb.LogicalBridgeSpec, updateMask []string, allowMissing bool) (*pb.LogicalBridge, error) {
 	conn, closer, err := c.NewConn()
 	if err != nil {
 		log.Printf("error creating connection: %s\n", err)
@@ -144,6 +144,7 @@ func (c evpnClientImpl) UpdateLogicalBridge(ctx context.Context, name string, up
 	client := c.getEvpnLogicalBridgeClient(conn)
 	Bridge := &pb.LogicalBridge{
 		Name: resourceIDToFullName("bridges", name),
+		Spec: spec,
 	}
 	data, err := client.UpdateLogicalBridge(ctx, &pb.UpdateLogicalBridgeRequest{
 		LogicalBridge: Bridge,
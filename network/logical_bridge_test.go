@@ -338,11 +338,12 @@ func TestListLogicalBridges(t *testing.T) {
 
 func TestUpdateLogicalBridge(t *testing.T) {
 	name := "lb1"
-	updateMask := []string{""}
+	spec := &pb.LogicalBridgeSpec{VlanId: 20}
+	updateMask := []string{"spec.vlan_id"}
 	allowMissing := false
 
 	testRequest := &pb.UpdateLogicalBridgeRequest{
-		LogicalBridge: &pb.LogicalBridge{Name: resourceIDToFullName("bridges", name)},
+		LogicalBridge: &pb.LogicalBridge{Name: resourceIDToFullName("bridges", name), Spec: spec},
 		UpdateMask:    &fieldmaskpb.FieldMask{Paths: updateMask},
 		AllowMissing:  allowMissing,
 	}
@@ -407,7 +408,7 @@ func TestUpdateLogicalBridge(t *testing.T) {
 				},
 			)
 
-			response, err := c.UpdateLogicalBridge(context.Background(), name, updateMask, allowMissing)
+			response, err := c.UpdateLogicalBridge(context.Background(), name, spec, updateMask, allowMissing)
 
 			assert.Equal(t, tt.wantErr, err)
 			assert.Equal(t, tt.wantConnClosed, connClosed)
@@ -13,6 +13,7 @@ import (
 	"net"
 
 	pb "github.com/opiproject/opi-api/network/evpn-gw/v1alpha1/gen/go"
+	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/types/known/emptypb"
 	"google.golang.org/protobuf/types/known/fieldmaskpb"
 )
@@ -35,7 +36,7 @@ func (c evpnClientImpl) CreateSvi(ctx context.Context, name string, vrf string,
 
 	lBridge := resourceIDToFullName("bridges", logicalBridge)
 
-	gwPrefixes, err := parseIPPrefixes(gwIPs)
+	gwPrefixes, err := ParseIPPrefixes(gwIPs)
 	if err != nil {
 		log.Printf("error parsing GwIPs: %s\n", err)
 		return nil, err
@@ -138,8 +139,8 @@ func (c evpnClientImpl) ListSvis(ctx context.Context, pageSize int32, pageToken
 	return data, nil
 }
 
-// UpdateSvi update the svi on OPI server
-func (c evpnClientImpl) UpdateSvi(ctx context.Context, name string, updateMask []string, allowMissing bool) (*pb.Svi, error) {
+// UpdateSvi updates the fields of the svi listed in updateMask with the values of spec
+func (c evpnClientImpl) UpdateSvi(ctx context.Context, name string, spec *pb.SviSpec, updateMask []string, allowMissing bool) (*pb.Svi, error) {
 	conn, closer, err := c.NewConn()
 	if err != nil {
 		log.Printf("error creating connection: %s\n", err)
@@ -148,8 +149,19 @@ func (c evpnClientImpl) UpdateSvi(ctx context.Context, name string, updateMask [
 	defer closer()
 	client := c.getEvpnSVIClient(conn)
 
+	if spec != nil {
+		// vrf and logical bridge are resource ids like in CreateSvi
+		spec = proto.Clone(spec).(*pb.SviSpec)
+		if spec.Vrf != "" {
+			spec.Vrf = resourceIDToFullName("vrfs", spec.Vrf)
+		}
+		if spec.LogicalBridge != "" {
+			spec.LogicalBridge = resourceIDToFullName("bridges", spec.LogicalBridge)
+		}
+	}
 	svi := &pb.Svi{
 		Name: resourceIDToFullName("svis", name),
+		Spec: spec,
 	}
 	data, err := client.UpdateSvi(ctx, &pb.UpdateSviRequest{
 		Svi:          svi,
@@ -344,11 +344,15 @@ func TestListSvis(t *testing.T) {
 
 func TestUpdateSvi(t *testing.T) {
 	name := "svi4"
-	updateMask := []string{""}
+	spec := &pb.SviSpec{Vrf: "vrf1", EnableBgp: true}
+	updateMask := []string{"spec.vrf", "spec.enable_bgp"}
 	allowMissing := false
 
 	testRequest := &pb.UpdateSviRequest{
-		Svi:          &pb.Svi{Name: resourceIDToFullName("svis", name)},
+		Svi: &pb.Svi{
+			Name: resourceIDToFullName("svis", name),
+			Spec: &pb.SviSpec{Vrf: resourceIDToFullName("vrfs", "vrf1"), EnableBgp: true},
+		},
 		UpdateMask:   &fieldmaskpb.FieldMask{Paths: updateMask},
 		AllowMissing: allowMissing,
 	}
@@ -413,7 +417,7 @@ func TestUpdateSvi(t *testing.T) {
 				},
 			)
 
-			response, err := c.UpdateSvi(context.Background(), name, updateMask, allowMissing)
+			response, err := c.UpdateSvi(context.Background(), name, spec, updateMask, allowMissing)
 
 			assert.Equal(t, tt.wantErr, err)
 			assert.Equal(t, tt.wantConnClosed, connClosed)
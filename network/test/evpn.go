@@ -0,0 +1,569 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (C) 2024 Intel Corporation
+
+// Package test implements compliance tests of the EVPN gateway API
+package test
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net"
+	"time"
+
+	"github.com/opiproject/godpu/network"
+	"github.com/opiproject/godpu/testing/report"
+	pb "github.com/opiproject/opi-api/network/evpn-gw/v1alpha1/gen/go"
+	"go.einride.tech/aip/resourcename"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+)
+
+// operStatusPollInterval is the interval the oper status of a resource is
+// fetched at while waiting for a transition
+const operStatusPollInterval = 100 * time.Millisecond
+
+// NewReport creates an empty report of the network tests
+func NewReport(continueOnFailure bool) *report.Report {
+	return report.New("network", continueOnFailure)
+}
+
+// RunEvpn creates a vrf, a logical bridge, an svi and a bridge port in
+// dependency order, checks every resource and deletes them in reverse
+// order. Every step is recorded in r.
+func RunEvpn(ctx context.Context, conn grpc.ClientConnInterface, fixtures *Fixtures, r *report.Report) (err error) {
+	vrfs := pb.NewVrfServiceClient(conn)
+	bridges := pb.NewLogicalBridgeServiceClient(conn)
+	svis := pb.NewSviServiceClient(conn)
+	ports := pb.NewBridgePortServiceClient(conn)
+
+	defer r.Cleanup(ctx, &err)
+
+	vrf, err := executeVrf(ctx, vrfs, fixtures, r)
+	if r.Fatal(err) {
+		return err
+	}
+	bridge, err := executeLogicalBridge(ctx, bridges, fixtures, r)
+	if r.Fatal(err) {
+		return err
+	}
+	var svi *pb.Svi
+	if vrf != nil && bridge != nil {
+		svi, err = executeSvi(ctx, svis, vrf.Name, bridge.Name, fixtures, r)
+		if r.Fatal(err) {
+			return err
+		}
+	}
+	var port *pb.BridgePort
+	if bridge != nil {
+		port, err = executeBridgePort(ctx, ports, bridge.Name, fixtures, r)
+		if r.Fatal(err) {
+			return err
+		}
+	}
+
+	// resources are deleted before the resources they reference
+	if port != nil {
+		err = executeDelete(ctx, r, "BridgePort", port.Name, fixtures,
+			func() error {
+				_, err := ports.DeleteBridgePort(ctx, &pb.DeleteBridgePortRequest{Name: port.Name})
+				return err
+			},
+			func() (pb.BPOperStatus, error) {
+				port, err := ports.GetBridgePort(ctx, &pb.GetBridgePortRequest{Name: port.Name})
+				return port.GetStatus().GetOperStatus(), err
+			},
+			pb.BPOperStatus_BP_OPER_STATUS_TO_BE_DELETED)
+		if r.Fatal(err) {
+			return err
+		}
+	}
+	if svi != nil {
+		err = executeDelete(ctx, r, "Svi", svi.Name, fixtures,
+			func() error {
+				_, err := svis.DeleteSvi(ctx, &pb.DeleteSviRequest{Name: svi.Name})
+				return err
+			},
+			func() (pb.SVIOperStatus, error) {
+				svi, err := svis.GetSvi(ctx, &pb.GetSviRequest{Name: svi.Name})
+				return svi.GetStatus().GetOperStatus(), err
+			},
+			pb.SVIOperStatus_SVI_OPER_STATUS_TO_BE_DELETED)
+		if r.Fatal(err) {
+			return err
+		}
+	}
+	if bridge != nil {
+		err = executeDelete(ctx, r, "LogicalBridge", bridge.Name, fixtures,
+			func() error {
+				_, err := bridges.DeleteLogicalBridge(ctx, &pb.DeleteLogicalBridgeRequest{Name: bridge.Name})
+				return err
+			},
+			func() (pb.LBOperStatus, error) {
+				bridge, err := bridges.GetLogicalBridge(ctx, &pb.GetLogicalBridgeRequest{Name: bridge.Name})
+				return bridge.GetStatus().GetOperStatus(), err
+			},
+			pb.LBOperStatus_LB_OPER_STATUS_TO_BE_DELETED)
+		if r.Fatal(err) {
+			return err
+		}
+	}
+	if vrf != nil {
+		err = executeDelete(ctx, r, "Vrf", vrf.Name, fixtures,
+			func() error {
+				_, err := vrfs.DeleteVrf(ctx, &pb.DeleteVrfRequest{Name: vrf.Name})
+				return err
+			},
+			func() (pb.VRFOperStatus, error) {
+				vrf, err := vrfs.GetVrf(ctx, &pb.GetVrfRequest{Name: vrf.Name})
+				return vrf.GetStatus().GetOperStatus(), err
+			},
+			pb.VRFOperStatus_VRF_OPER_STATUS_TO_BE_DELETED)
+		if r.Fatal(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// executeVrf creates a vrf and checks it. The vrf is nil if it could not be
+// created.
+func executeVrf(ctx context.Context, c pb.VrfServiceClient, f *Fixtures, r *report.Report) (*pb.Vrf, error) {
+	r.Begin("Vrf")
+
+	loopback, err := network.ParseIPAndPrefix(f.LoopbackIPPrefix)
+	if err != nil {
+		return nil, err
+	}
+	vtep, err := network.ParseIPAndPrefix(f.VtepIPPrefix)
+	if err != nil {
+		return nil, err
+	}
+	spec := &pb.VrfSpec{
+		Vni:              proto.Uint32(f.VrfVni),
+		LoopbackIpPrefix: loopback,
+		VtepIpPrefix:     vtep,
+	}
+
+	id := f.ID("opi-vrf")
+	var vrf *pb.Vrf
+	err = r.Step("CreateVrf", func() (err error) {
+		vrf, err = c.CreateVrf(ctx, &pb.CreateVrfRequest{VrfId: id, Vrf: &pb.Vrf{Spec: spec}})
+		if err != nil {
+			return err
+		}
+		r.Track(vrf.Name, func(ctx context.Context) error {
+			_, err := c.DeleteVrf(ctx, &pb.DeleteVrfRequest{Name: vrf.Name, AllowMissing: true})
+			return err
+		})
+		log.Printf("Created Vrf: %v", vrf)
+		return verifyResource(vrf.Name, vrf.Spec, "vrfs", id, spec)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = r.Step("VrfOperStatus up", func() error {
+		return waitUp(ctx, f.OperStatusTimeout,
+			func() (pb.VRFOperStatus, error) {
+				vrf, err := c.GetVrf(ctx, &pb.GetVrfRequest{Name: vrf.Name})
+				return vrf.GetStatus().GetOperStatus(), err
+			},
+			pb.VRFOperStatus_VRF_OPER_STATUS_UP, pb.VRFOperStatus_VRF_OPER_STATUS_TO_BE_DELETED)
+	})
+	if r.Fatal(err) {
+		return vrf, err
+	}
+	err = r.Step("GetVrf", func() error {
+		got, err := c.GetVrf(ctx, &pb.GetVrfRequest{Name: vrf.Name})
+		if err != nil {
+			return err
+		}
+		log.Printf("Got Vrf: %v", got)
+		return verifyResource(got.Name, got.Spec, "vrfs", id, spec)
+	})
+	if r.Fatal(err) {
+		return vrf, err
+	}
+	err = r.Step("ListVrfs", func() error {
+		return verifyListed(vrf.Name, func(pageToken string) ([]*pb.Vrf, string, error) {
+			list, err := c.ListVrfs(ctx, &pb.ListVrfsRequest{PageToken: pageToken})
+			return list.GetVrfs(), list.GetNextPageToken(), err
+		})
+	})
+	if r.Fatal(err) {
+		return vrf, err
+	}
+	err = r.Step("UpdateVrf", func() error {
+		got, err := c.UpdateVrf(ctx, &pb.UpdateVrfRequest{
+			Vrf:        &pb.Vrf{Name: vrf.Name, Spec: spec},
+			UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"*"}},
+		})
+		if err != nil {
+			return err
+		}
+		log.Printf("Updated Vrf: %v", got)
+		return verifyResource(got.Name, got.Spec, "vrfs", id, spec)
+	})
+	return vrf, err
+}
+
+// executeLogicalBridge creates a logical bridge and checks it. The logical
+// bridge is nil if it could not be created.
+func executeLogicalBridge(ctx context.Context, c pb.LogicalBridgeServiceClient, f *Fixtures, r *report.Report) (*pb.LogicalBridge, error) {
+	r.Begin("LogicalBridge")
+
+	vtep, err := network.ParseIPAndPrefix(f.VtepIPPrefix)
+	if err != nil {
+		return nil, err
+	}
+	spec := &pb.LogicalBridgeSpec{
+		VlanId:       f.VlanID,
+		Vni:          proto.Uint32(f.BridgeVni),
+		VtepIpPrefix: vtep,
+	}
+
+	id := f.ID("opi-bridge")
+	var bridge *pb.LogicalBridge
+	err = r.Step("CreateLogicalBridge", func() (err error) {
+		bridge, err = c.CreateLogicalBridge(ctx, &pb.CreateLogicalBridgeRequest{LogicalBridgeId: id, LogicalBridge: &pb.LogicalBridge{Spec: spec}})
+		if err != nil {
+			return err
+		}
+		r.Track(bridge.Name, func(ctx context.Context) error {
+			_, err := c.DeleteLogicalBridge(ctx, &pb.DeleteLogicalBridgeRequest{Name: bridge.Name, AllowMissing: true})
+			return err
+		})
+		log.Printf("Created LogicalBridge: %v", bridge)
+		return verifyResource(bridge.Name, bridge.Spec, "bridges", id, spec)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = r.Step("LogicalBridgeOperStatus up", func() error {
+		return waitUp(ctx, f.OperStatusTimeout,
+			func() (pb.LBOperStatus, error) {
+				bridge, err := c.GetLogicalBridge(ctx, &pb.GetLogicalBridgeRequest{Name: bridge.Name})
+				return bridge.GetStatus().GetOperStatus(), err
+			},
+			pb.LBOperStatus_LB_OPER_STATUS_UP, pb.LBOperStatus_LB_OPER_STATUS_TO_BE_DELETED)
+	})
+	if r.Fatal(err) {
+		return bridge, err
+	}
+	err = r.Step("GetLogicalBridge", func() error {
+		got, err := c.GetLogicalBridge(ctx, &pb.GetLogicalBridgeRequest{Name: bridge.Name})
+		if err != nil {
+			return err
+		}
+		log.Printf("Got LogicalBridge: %v", got)
+		return verifyResource(got.Name, got.Spec, "bridges", id, spec)
+	})
+	if r.Fatal(err) {
+		return bridge, err
+	}
+	err = r.Step("ListLogicalBridges", func() error {
+		return verifyListed(bridge.Name, func(pageToken string) ([]*pb.LogicalBridge, string, error) {
+			list, err := c.ListLogicalBridges(ctx, &pb.ListLogicalBridgesRequest{PageToken: pageToken})
+			return list.GetLogicalBridges(), list.GetNextPageToken(), err
+		})
+	})
+	if r.Fatal(err) {
+		return bridge, err
+	}
+	err = r.Step("UpdateLogicalBridge", func() error {
+		got, err := c.UpdateLogicalBridge(ctx, &pb.UpdateLogicalBridgeRequest{
+			LogicalBridge: &pb.LogicalBridge{Name: bridge.Name, Spec: spec},
+			UpdateMask:    &fieldmaskpb.FieldMask{Paths: []string{"*"}},
+		})
+		if err != nil {
+			return err
+		}
+		log.Printf("Updated LogicalBridge: %v", got)
+		return verifyResource(got.Name, got.Spec, "bridges", id, spec)
+	})
+	return bridge, err
+}
+
+// executeSvi creates an svi connecting the vrf and logical bridge and checks
+// it. The svi is nil if it could not be created.
+func executeSvi(ctx context.Context, c pb.SviServiceClient, vrf string, bridge string, f *Fixtures, r *report.Report) (*pb.Svi, error) {
+	r.Begin("Svi")
+
+	mac, err := net.ParseMAC(f.SviMacAddress)
+	if err != nil {
+		return nil, err
+	}
+	gwIPs, err := network.ParseIPPrefixes(f.GwIPPrefixes)
+	if err != nil {
+		return nil, err
+	}
+	spec := &pb.SviSpec{
+		Vrf:           vrf,
+		LogicalBridge: bridge,
+		MacAddress:    mac,
+		GwIpPrefix:    gwIPs,
+	}
+
+	id := f.ID("opi-svi")
+	var svi *pb.Svi
+	err = r.Step("CreateSvi", func() (err error) {
+		svi, err = c.CreateSvi(ctx, &pb.CreateSviRequest{SviId: id, Svi: &pb.Svi{Spec: spec}})
+		if err != nil {
+			return err
+		}
+		r.Track(svi.Name, func(ctx context.Context) error {
+			_, err := c.DeleteSvi(ctx, &pb.DeleteSviRequest{Name: svi.Name, AllowMissing: true})
+			return err
+		})
+		log.Printf("Created Svi: %v", svi)
+		return verifyResource(svi.Name, svi.Spec, "svis", id, spec)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = r.Step("SviOperStatus up", func() error {
+		return waitUp(ctx, f.OperStatusTimeout,
+			func() (pb.SVIOperStatus, error) {
+				svi, err := c.GetSvi(ctx, &pb.GetSviRequest{Name: svi.Name})
+				return svi.GetStatus().GetOperStatus(), err
+			},
+			pb.SVIOperStatus_SVI_OPER_STATUS_UP, pb.SVIOperStatus_SVI_OPER_STATUS_TO_BE_DELETED)
+	})
+	if r.Fatal(err) {
+		return svi, err
+	}
+	err = r.Step("GetSvi", func() error {
+		got, err := c.GetSvi(ctx, &pb.GetSviRequest{Name: svi.Name})
+		if err != nil {
+			return err
+		}
+		log.Printf("Got Svi: %v", got)
+		return verifyResource(got.Name, got.Spec, "svis", id, spec)
+	})
+	if r.Fatal(err) {
+		return svi, err
+	}
+	err = r.Step("ListSvis", func() error {
+		return verifyListed(svi.Name, func(pageToken string) ([]*pb.Svi, string, error) {
+			list, err := c.ListSvis(ctx, &pb.ListSvisRequest{PageToken: pageToken})
+			return list.GetSvis(), list.GetNextPageToken(), err
+		})
+	})
+	if r.Fatal(err) {
+		return svi, err
+	}
+	err = r.Step("UpdateSvi", func() error {
+		got, err := c.UpdateSvi(ctx, &pb.UpdateSviRequest{
+			Svi:        &pb.Svi{Name: svi.Name, Spec: spec},
+			UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"*"}},
+		})
+		if err != nil {
+			return err
+		}
+		log.Printf("Updated Svi: %v", got)
+		return verifyResource(got.Name, got.Spec, "svis", id, spec)
+	})
+	return svi, err
+}
+
+// executeBridgePort creates an access bridge port of the logical bridge and
+// checks it. The bridge port is nil if it could not be created.
+func executeBridgePort(ctx context.Context, c pb.BridgePortServiceClient, bridge string, f *Fixtures, r *report.Report) (*pb.BridgePort, error) {
+	r.Begin("BridgePort")
+
+	mac, err := net.ParseMAC(f.PortMacAddress)
+	if err != nil {
+		return nil, err
+	}
+	spec := &pb.BridgePortSpec{
+		MacAddress:     mac,
+		Ptype:          pb.BridgePortType_BRIDGE_PORT_TYPE_ACCESS,
+		LogicalBridges: []string{bridge},
+	}
+
+	id := f.ID("opi-port")
+	var port *pb.BridgePort
+	err = r.Step("CreateBridgePort", func() (err error) {
+		port, err = c.CreateBridgePort(ctx, &pb.CreateBridgePortRequest{BridgePortId: id, BridgePort: &pb.BridgePort{Spec: spec}})
+		if err != nil {
+			return err
+		}
+		r.Track(port.Name, func(ctx context.Context) error {
+			_, err := c.DeleteBridgePort(ctx, &pb.DeleteBridgePortRequest{Name: port.Name, AllowMissing: true})
+			return err
+		})
+		log.Printf("Created BridgePort: %v", port)
+		return verifyResource(port.Name, port.Spec, "ports", id, spec)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = r.Step("BridgePortOperStatus up", func() error {
+		return waitUp(ctx, f.OperStatusTimeout,
+			func() (pb.BPOperStatus, error) {
+				port, err := c.GetBridgePort(ctx, &pb.GetBridgePortRequest{Name: port.Name})
+				return port.GetStatus().GetOperStatus(), err
+			},
+			pb.BPOperStatus_BP_OPER_STATUS_UP, pb.BPOperStatus_BP_OPER_STATUS_TO_BE_DELETED)
+	})
+	if r.Fatal(err) {
+		return port, err
+	}
+	err = r.Step("GetBridgePort", func() error {
+		got, err := c.GetBridgePort(ctx, &pb.GetBridgePortRequest{Name: port.Name})
+		if err != nil {
+			return err
+		}
+		log.Printf("Got BridgePort: %v", got)
+		return verifyResource(got.Name, got.Spec, "ports", id, spec)
+	})
+	if r.Fatal(err) {
+		return port, err
+	}
+	err = r.Step("ListBridgePorts", func() error {
+		return verifyListed(port.Name, func(pageToken string) ([]*pb.BridgePort, string, error) {
+			list, err := c.ListBridgePorts(ctx, &pb.ListBridgePortsRequest{PageToken: pageToken})
+			return list.GetBridgePorts(), list.GetNextPageToken(), err
+		})
+	})
+	if r.Fatal(err) {
+		return port, err
+	}
+	err = r.Step("UpdateBridgePort", func() error {
+		got, err := c.UpdateBridgePort(ctx, &pb.UpdateBridgePortRequest{
+			BridgePort: &pb.BridgePort{Name: port.Name, Spec: spec},
+			UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"*"}},
+		})
+		if err != nil {
+			return err
+		}
+		log.Printf("Updated BridgePort: %v", got)
+		return verifyResource(got.Name, got.Spec, "ports", id, spec)
+	})
+	return port, err
+}
+
+// executeDelete deletes a resource and waits until it is gone
+func executeDelete[S operStatus](ctx context.Context, r *report.Report, kind string, name string, f *Fixtures, del func() error, get func() (S, error), toBeDeleted S) error {
+	r.Begin(kind)
+
+	err := r.Step("Delete"+kind, func() error {
+		if err := del(); err != nil {
+			return err
+		}
+		r.Untrack(name)
+		log.Printf("Deleted %v: %v", kind, name)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	return r.Step(kind+"OperStatus deleted", func() error {
+		return waitDeleted(ctx, f.OperStatusTimeout, get, toBeDeleted)
+	})
+}
+
+// operStatus is the oper status enum of an EVPN gateway resource
+type operStatus interface {
+	~int32
+	String() string
+}
+
+// waitUp polls the oper status of a created resource until it is up. A
+// created resource must never be reported as to be deleted.
+func waitUp[S operStatus](ctx context.Context, timeout time.Duration, get func() (S, error), up S, toBeDeleted S) error {
+	deadline := time.Now().Add(timeout)
+	for {
+		current, err := get()
+		if err != nil {
+			return err
+		}
+		switch current {
+		case up:
+			return nil
+		case toBeDeleted:
+			return fmt.Errorf("created resource has oper status %v", current)
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("oper status is %v after %v, expected %v", current, timeout, up)
+		}
+		if err := sleep(ctx, operStatusPollInterval); err != nil {
+			return err
+		}
+	}
+}
+
+// waitDeleted polls the oper status of a deleted resource until it is not
+// found. Until then it must be reported as to be deleted.
+func waitDeleted[S operStatus](ctx context.Context, timeout time.Duration, get func() (S, error), toBeDeleted S) error {
+	deadline := time.Now().Add(timeout)
+	for {
+		current, err := get()
+		if status.Code(err) == codes.NotFound {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if current != toBeDeleted {
+			return fmt.Errorf("deleted resource has oper status %v, expected %v", current, toBeDeleted)
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("deleted resource still exists after %v", timeout)
+		}
+		if err := sleep(ctx, operStatusPollInterval); err != nil {
+			return err
+		}
+	}
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// verifyResource checks the name the server filled for a resource created
+// with resourceID and the spec it returned
+func verifyResource(name string, spec proto.Message, container string, resourceID string, want proto.Message) error {
+	fullname := resourcename.Join("//network.opiproject.org/", container, resourceID)
+	if name != fullname {
+		return fmt.Errorf("server filled value '%s' is not matching user requested '%s'", name, fullname)
+	}
+	if !proto.Equal(spec, want) {
+		return fmt.Errorf("server returned spec %v, expected %v", spec, want)
+	}
+	return nil
+}
+
+// verifyListed pages through list until the resource with name is found
+func verifyListed[T interface{ GetName() string }](name string, list func(pageToken string) ([]T, string, error)) error {
+	pageToken := ""
+	for {
+		items, next, err := list(pageToken)
+		if err != nil {
+			return err
+		}
+		for _, item := range items {
+			if item.GetName() == name {
+				return nil
+			}
+		}
+		if next == "" || next == pageToken {
+			return fmt.Errorf("%v is not listed", name)
+		}
+		pageToken = next
+	}
+}
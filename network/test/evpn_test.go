@@ -0,0 +1,318 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (C) 2024 Intel Corporation
+
+// Package test implements compliance tests of the EVPN gateway API
+package test
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	pb "github.com/opiproject/opi-api/network/evpn-gw/v1alpha1/gen/go"
+	"github.com/stretchr/testify/require"
+	"go.einride.tech/aip/resourcename"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+// fakeEvpnServer implements the EVPN gateway services in memory
+type fakeEvpnServer struct {
+	pb.UnimplementedVrfServiceServer
+	pb.UnimplementedLogicalBridgeServiceServer
+	pb.UnimplementedSviServiceServer
+	pb.UnimplementedBridgePortServiceServer
+
+	specs   map[string]proto.Message
+	deleted []string
+	// down keeps the oper status of all resources down
+	down bool
+}
+
+func (s *fakeEvpnServer) create(container string, id string, spec proto.Message) (string, error) {
+	name := resourcename.Join("//network.opiproject.org/", container, id)
+	if _, ok := s.specs[name]; ok {
+		return "", status.Errorf(codes.AlreadyExists, "%v exists", name)
+	}
+	s.specs[name] = spec
+	return name, nil
+}
+
+func (s *fakeEvpnServer) get(name string) (proto.Message, error) {
+	spec, ok := s.specs[name]
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "unable to find %v", name)
+	}
+	return spec, nil
+}
+
+func (s *fakeEvpnServer) delete(name string, allowMissing bool) (*emptypb.Empty, error) {
+	if _, ok := s.specs[name]; !ok {
+		if allowMissing {
+			return &emptypb.Empty{}, nil
+		}
+		return nil, status.Errorf(codes.NotFound, "unable to find %v", name)
+	}
+	delete(s.specs, name)
+	s.deleted = append(s.deleted, name)
+	return &emptypb.Empty{}, nil
+}
+
+func (s *fakeEvpnServer) CreateVrf(_ context.Context, in *pb.CreateVrfRequest) (*pb.Vrf, error) {
+	name, err := s.create("vrfs", in.VrfId, in.Vrf.Spec)
+	if err != nil {
+		return nil, err
+	}
+	return s.GetVrf(context.Background(), &pb.GetVrfRequest{Name: name})
+}
+
+func (s *fakeEvpnServer) GetVrf(_ context.Context, in *pb.GetVrfRequest) (*pb.Vrf, error) {
+	spec, err := s.get(in.Name)
+	if err != nil {
+		return nil, err
+	}
+	operStatus := pb.VRFOperStatus_VRF_OPER_STATUS_UP
+	if s.down {
+		operStatus = pb.VRFOperStatus_VRF_OPER_STATUS_DOWN
+	}
+	return &pb.Vrf{Name: in.Name, Spec: spec.(*pb.VrfSpec), Status: &pb.VrfStatus{OperStatus: operStatus}}, nil
+}
+
+func (s *fakeEvpnServer) ListVrfs(ctx context.Context, _ *pb.ListVrfsRequest) (*pb.ListVrfsResponse, error) {
+	response := &pb.ListVrfsResponse{}
+	for name, spec := range s.specs {
+		if _, ok := spec.(*pb.VrfSpec); ok {
+			vrf, _ := s.GetVrf(ctx, &pb.GetVrfRequest{Name: name})
+			response.Vrfs = append(response.Vrfs, vrf)
+		}
+	}
+	return response, nil
+}
+
+func (s *fakeEvpnServer) UpdateVrf(ctx context.Context, in *pb.UpdateVrfRequest) (*pb.Vrf, error) {
+	return s.GetVrf(ctx, &pb.GetVrfRequest{Name: in.Vrf.Name})
+}
+
+func (s *fakeEvpnServer) DeleteVrf(_ context.Context, in *pb.DeleteVrfRequest) (*emptypb.Empty, error) {
+	return s.delete(in.Name, in.AllowMissing)
+}
+
+func (s *fakeEvpnServer) CreateLogicalBridge(_ context.Context, in *pb.CreateLogicalBridgeRequest) (*pb.LogicalBridge, error) {
+	name, err := s.create("bridges", in.LogicalBridgeId, in.LogicalBridge.Spec)
+	if err != nil {
+		return nil, err
+	}
+	return s.GetLogicalBridge(context.Background(), &pb.GetLogicalBridgeRequest{Name: name})
+}
+
+func (s *fakeEvpnServer) GetLogicalBridge(_ context.Context, in *pb.GetLogicalBridgeRequest) (*pb.LogicalBridge, error) {
+	spec, err := s.get(in.Name)
+	if err != nil {
+		return nil, err
+	}
+	operStatus := pb.LBOperStatus_LB_OPER_STATUS_UP
+	if s.down {
+		operStatus = pb.LBOperStatus_LB_OPER_STATUS_DOWN
+	}
+	return &pb.LogicalBridge{Name: in.Name, Spec: spec.(*pb.LogicalBridgeSpec), Status: &pb.LogicalBridgeStatus{OperStatus: operStatus}}, nil
+}
+
+func (s *fakeEvpnServer) ListLogicalBridges(ctx context.Context, _ *pb.ListLogicalBridgesRequest) (*pb.ListLogicalBridgesResponse, error) {
+	response := &pb.ListLogicalBridgesResponse{}
+	for name, spec := range s.specs {
+		if _, ok := spec.(*pb.LogicalBridgeSpec); ok {
+			bridge, _ := s.GetLogicalBridge(ctx, &pb.GetLogicalBridgeRequest{Name: name})
+			response.LogicalBridges = append(response.LogicalBridges, bridge)
+		}
+	}
+	return response, nil
+}
+
+func (s *fakeEvpnServer) UpdateLogicalBridge(ctx context.Context, in *pb.UpdateLogicalBridgeRequest) (*pb.LogicalBridge, error) {
+	return s.GetLogicalBridge(ctx, &pb.GetLogicalBridgeRequest{Name: in.LogicalBridge.Name})
+}
+
+func (s *fakeEvpnServer) DeleteLogicalBridge(_ context.Context, in *pb.DeleteLogicalBridgeRequest) (*emptypb.Empty, error) {
+	return s.delete(in.Name, in.AllowMissing)
+}
+
+func (s *fakeEvpnServer) CreateSvi(_ context.Context, in *pb.CreateSviRequest) (*pb.Svi, error) {
+	name, err := s.create("svis", in.SviId, in.Svi.Spec)
+	if err != nil {
+		return nil, err
+	}
+	return s.GetSvi(context.Background(), &pb.GetSviRequest{Name: name})
+}
+
+func (s *fakeEvpnServer) GetSvi(_ context.Context, in *pb.GetSviRequest) (*pb.Svi, error) {
+	spec, err := s.get(in.Name)
+	if err != nil {
+		return nil, err
+	}
+	operStatus := pb.SVIOperStatus_SVI_OPER_STATUS_UP
+	if s.down {
+		operStatus = pb.SVIOperStatus_SVI_OPER_STATUS_DOWN
+	}
+	return &pb.Svi{Name: in.Name, Spec: spec.(*pb.SviSpec), Status: &pb.SviStatus{OperStatus: operStatus}}, nil
+}
+
+func (s *fakeEvpnServer) ListSvis(ctx context.Context, _ *pb.ListSvisRequest) (*pb.ListSvisResponse, error) {
+	response := &pb.ListSvisResponse{}
+	for name, spec := range s.specs {
+		if _, ok := spec.(*pb.SviSpec); ok {
+			svi, _ := s.GetSvi(ctx, &pb.GetSviRequest{Name: name})
+			response.Svis = append(response.Svis, svi)
+		}
+	}
+	return response, nil
+}
+
+func (s *fakeEvpnServer) UpdateSvi(ctx context.Context, in *pb.UpdateSviRequest) (*pb.Svi, error) {
+	return s.GetSvi(ctx, &pb.GetSviRequest{Name: in.Svi.Name})
+}
+
+func (s *fakeEvpnServer) DeleteSvi(_ context.Context, in *pb.DeleteSviRequest) (*emptypb.Empty, error) {
+	return s.delete(in.Name, in.AllowMissing)
+}
+
+func (s *fakeEvpnServer) CreateBridgePort(_ context.Context, in *pb.CreateBridgePortRequest) (*pb.BridgePort, error) {
+	name, err := s.create("ports", in.BridgePortId, in.BridgePort.Spec)
+	if err != nil {
+		return nil, err
+	}
+	return s.GetBridgePort(context.Background(), &pb.GetBridgePortRequest{Name: name})
+}
+
+func (s *fakeEvpnServer) GetBridgePort(_ context.Context, in *pb.GetBridgePortRequest) (*pb.BridgePort, error) {
+	spec, err := s.get(in.Name)
+	if err != nil {
+		return nil, err
+	}
+	operStatus := pb.BPOperStatus_BP_OPER_STATUS_UP
+	if s.down {
+		operStatus = pb.BPOperStatus_BP_OPER_STATUS_DOWN
+	}
+	return &pb.BridgePort{Name: in.Name, Spec: spec.(*pb.BridgePortSpec), Status: &pb.BridgePortStatus{OperStatus: operStatus}}, nil
+}
+
+func (s *fakeEvpnServer) ListBridgePorts(ctx context.Context, _ *pb.ListBridgePortsRequest) (*pb.ListBridgePortsResponse, error) {
+	response := &pb.ListBridgePortsResponse{}
+	for name, spec := range s.specs {
+		if _, ok := spec.(*pb.BridgePortSpec); ok {
+			port, _ := s.GetBridgePort(ctx, &pb.GetBridgePortRequest{Name: name})
+			response.BridgePorts = append(response.BridgePorts, port)
+		}
+	}
+	return response, nil
+}
+
+func (s *fakeEvpnServer) UpdateBridgePort(ctx context.Context, in *pb.UpdateBridgePortRequest) (*pb.BridgePort, error) {
+	return s.GetBridgePort(ctx, &pb.GetBridgePortRequest{Name: in.BridgePort.Name})
+}
+
+func (s *fakeEvpnServer) DeleteBridgePort(_ context.Context, in *pb.DeleteBridgePortRequest) (*emptypb.Empty, error) {
+	return s.delete(in.Name, in.AllowMissing)
+}
+
+func TestRunEvpn(t *testing.T) {
+	tests := map[string]struct {
+		down              bool
+		continueOnFailure bool
+		wantErr           bool
+		wantFailures      int
+		wantDeleted       []string
+	}{
+		"compliant server": {
+			wantDeleted: []string{
+				"//network.opiproject.org/ports/opi-port",
+				"//network.opiproject.org/svis/opi-svi",
+				"//network.opiproject.org/bridges/opi-bridge",
+				"//network.opiproject.org/vrfs/opi-vrf",
+			},
+		},
+		"resources never up": {
+			down:         true,
+			wantErr:      true,
+			wantFailures: 1,
+			wantDeleted:  []string{"//network.opiproject.org/vrfs/opi-vrf"},
+		},
+		"resources never up continue on failure": {
+			down:              true,
+			continueOnFailure: true,
+			wantFailures:      4,
+			wantDeleted: []string{
+				"//network.opiproject.org/ports/opi-port",
+				"//network.opiproject.org/svis/opi-svi",
+				"//network.opiproject.org/bridges/opi-bridge",
+				"//network.opiproject.org/vrfs/opi-vrf",
+			},
+		},
+	}
+
+	for testName, tt := range tests {
+		t.Run(testName, func(t *testing.T) {
+			listener, err := net.Listen("tcp", "127.0.0.1:0")
+			require.NoError(t, err)
+			server := grpc.NewServer()
+			evpn := &fakeEvpnServer{specs: map[string]proto.Message{}, down: tt.down}
+			pb.RegisterVrfServiceServer(server, evpn)
+			pb.RegisterLogicalBridgeServiceServer(server, evpn)
+			pb.RegisterSviServiceServer(server, evpn)
+			pb.RegisterBridgePortServiceServer(server, evpn)
+			go func() { _ = server.Serve(listener) }()
+			t.Cleanup(server.Stop)
+
+			conn, err := grpc.NewClient(listener.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+			require.NoError(t, err)
+			t.Cleanup(func() { _ = conn.Close() })
+
+			fixtures := DefaultFixtures()
+			fixtures.OperStatusTimeout = 200 * time.Millisecond
+			report := NewReport(tt.continueOnFailure)
+			err = RunEvpn(context.Background(), conn, fixtures, report)
+			require.Equal(t, tt.wantErr, err != nil)
+			require.Equal(t, tt.wantFailures, report.Failures())
+			require.Equal(t, tt.wantDeleted, evpn.deleted)
+			require.Empty(t, evpn.specs)
+		})
+	}
+}
+
+func TestWaitDeleted(t *testing.T) {
+	tests := map[string]struct {
+		statuses []pb.VRFOperStatus
+		wantErr  string
+	}{
+		"deleted immediately": {},
+		"to be deleted first": {
+			statuses: []pb.VRFOperStatus{pb.VRFOperStatus_VRF_OPER_STATUS_TO_BE_DELETED},
+		},
+		"still up": {
+			statuses: []pb.VRFOperStatus{pb.VRFOperStatus_VRF_OPER_STATUS_UP},
+			wantErr:  "deleted resource has oper status VRF_OPER_STATUS_UP, expected VRF_OPER_STATUS_TO_BE_DELETED",
+		},
+	}
+
+	for testName, tt := range tests {
+		t.Run(testName, func(t *testing.T) {
+			calls := 0
+			err := waitDeleted(context.Background(), time.Second, func() (pb.VRFOperStatus, error) {
+				defer func() { calls++ }()
+				if calls < len(tt.statuses) {
+					return tt.statuses[calls], nil
+				}
+				return pb.VRFOperStatus_VRF_OPER_STATUS_UNSPECIFIED, status.Error(codes.NotFound, "missing")
+			}, pb.VRFOperStatus_VRF_OPER_STATUS_TO_BE_DELETED)
+			if tt.wantErr == "" {
+				require.NoError(t, err)
+			} else {
+				require.EqualError(t, err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,78 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (C) 2024 Intel Corporation
+
+// Package test implements compliance tests of the EVPN gateway API
+package test
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/opiproject/godpu/network"
+	"github.com/opiproject/godpu/testing/report"
+)
+
+// Fixtures are the resources and addresses used by the compliance tests
+type Fixtures struct {
+	report.ResourceIDs
+	// VrfVni and LoopbackIPPrefix are the vni and loopback address of the
+	// created vrf
+	VrfVni           uint32
+	LoopbackIPPrefix string
+	// VtepIPPrefix is the VXLAN tunnel endpoint of the created vrf and
+	// logical bridge
+	VtepIPPrefix string
+	// VlanID and BridgeVni are the vlan and vni of the created logical bridge
+	VlanID    uint32
+	BridgeVni uint32
+	// SviMacAddress and GwIPPrefixes are the gateway addresses of the
+	// created svi
+	SviMacAddress string
+	GwIPPrefixes  []string
+	// PortMacAddress is the mac address of the created bridge port
+	PortMacAddress string
+	// OperStatusTimeout bounds waiting for a created resource to be up and a
+	// deleted resource to be gone
+	OperStatusTimeout time.Duration
+}
+
+// DefaultFixtures returns fixtures matching the OPI evpn bridge of
+// docker-compose.yml
+func DefaultFixtures() *Fixtures {
+	return &Fixtures{
+		VrfVni:            1000,
+		LoopbackIPPrefix:  "10.0.0.1/32",
+		VtepIPPrefix:      "10.0.1.1/32",
+		VlanID:            10,
+		BridgeVni:         10,
+		SviMacAddress:     "00:11:22:33:44:55",
+		GwIPPrefixes:      []string{"10.0.10.1/24"},
+		PortMacAddress:    "00:11:22:33:44:66",
+		OperStatusTimeout: 10 * time.Second,
+	}
+}
+
+// Validate checks that fixtures can be used to run the tests
+func (f *Fixtures) Validate() error {
+	switch {
+	case f.VlanID == 0 || f.VlanID > 4094:
+		return fmt.Errorf("invalid vlan id: %v", f.VlanID)
+	case len(f.GwIPPrefixes) == 0:
+		return errors.New("gateway ip prefix is required")
+	case f.OperStatusTimeout <= 0:
+		return fmt.Errorf("invalid oper status timeout: %v", f.OperStatusTimeout)
+	}
+	for _, prefix := range append([]string{f.LoopbackIPPrefix, f.VtepIPPrefix}, f.GwIPPrefixes...) {
+		if _, err := network.ParseIPAndPrefix(prefix); err != nil {
+			return fmt.Errorf("invalid ip prefix: %w", err)
+		}
+	}
+	for _, mac := range []string{f.SviMacAddress, f.PortMacAddress} {
+		if _, err := net.ParseMAC(mac); err != nil {
+			return fmt.Errorf("invalid mac address: %w", err)
+		}
+	}
+	return nil
+}
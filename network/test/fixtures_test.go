@@ -0,0 +1,55 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (C) 2024 Intel Corporation
+
+// Package test implements compliance tests of the EVPN gateway API
+package test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFixturesValidate(t *testing.T) {
+	tests := map[string]struct {
+		give    func(*Fixtures)
+		wantErr string
+	}{
+		"defaults": {
+			give: func(*Fixtures) {},
+		},
+		"invalid vlan": {
+			give:    func(f *Fixtures) { f.VlanID = 4095 },
+			wantErr: "invalid vlan id: 4095",
+		},
+		"missing gateway": {
+			give:    func(f *Fixtures) { f.GwIPPrefixes = nil },
+			wantErr: "gateway ip prefix is required",
+		},
+		"invalid ip prefix": {
+			give:    func(f *Fixtures) { f.VtepIPPrefix = "10.0.1.1" },
+			wantErr: "invalid ip prefix: invalid CIDR address: 10.0.1.1",
+		},
+		"invalid mac address": {
+			give:    func(f *Fixtures) { f.PortMacAddress = "00:11:22" },
+			wantErr: "invalid mac address: address 00:11:22: invalid MAC address",
+		},
+		"invalid timeout": {
+			give:    func(f *Fixtures) { f.OperStatusTimeout = 0 },
+			wantErr: "invalid oper status timeout: 0s",
+		},
+	}
+
+	for testName, tt := range tests {
+		t.Run(testName, func(t *testing.T) {
+			fixtures := DefaultFixtures()
+			tt.give(fixtures)
+			err := fixtures.Validate()
+			if tt.wantErr == "" {
+				require.NoError(t, err)
+			} else {
+				require.EqualError(t, err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -17,8 +17,8 @@ func ip4ToInt(ip net.IP) uint32 {
 	return uint32(ip[0])<<24 | uint32(ip[1])<<16 | uint32(ip[2])<<8 | uint32(ip[3])
 }
 
-// Function to parse IP address and prefix from a string of the form "IP/PREFIX"
-func parseIPAndPrefix(ipPrefixStr string) (*pc.IPPrefix, error) {
+// ParseIPAndPrefix parses an IP address and prefix from a string of the form "IP/PREFIX"
+func ParseIPAndPrefix(ipPrefixStr string) (*pc.IPPrefix, error) {
 	ip, ipnet, err := net.ParseCIDR(ipPrefixStr)
 	if err != nil {
 		return nil, err
@@ -45,12 +45,12 @@ func parseIPAndPrefix(ipPrefixStr string) (*pc.IPPrefix, error) {
 	}, nil
 }
 
-// Function to parse an array of IP prefixes from strings to pb.IPPrefix messages
-func parseIPPrefixes(ipPrefixesStr []string) ([]*pc.IPPrefix, error) {
+// ParseIPPrefixes parses an array of IP prefixes from strings to pb.IPPrefix messages
+func ParseIPPrefixes(ipPrefixesStr []string) ([]*pc.IPPrefix, error) {
 	ipPrefixes := make([]*pc.IPPrefix, len(ipPrefixesStr))
 
 	for i, ipPrefixStr := range ipPrefixesStr {
-		ipPrefix, err := parseIPAndPrefix(ipPrefixStr)
+		ipPrefix, err := ParseIPAndPrefix(ipPrefixStr)
 		if err != nil {
 			return nil, fmt.Errorf("failed to parse IP prefix: %v", err)
 		}
@@ -30,13 +30,13 @@ func (c evpnClientImpl) CreateVrf(ctx context.Context, name string, vni *uint32,
 	if loopbackIP == "" {
 		return nil, errors.New("required together parameter [loopbackIP] wasn't passed ")
 	}
-	ipLoopback, err := parseIPAndPrefix(loopbackIP)
+	ipLoopback, err := ParseIPAndPrefix(loopbackIP)
 	if err != nil {
 		log.Printf("parseIPAndPrefix: error creating vrf: %s\n", err)
 		return nil, err
 	}
 	if vni != nil && vtepIP != "" {
-		ipVtep, err = parseIPAndPrefix(vtepIP)
+		ipVtep, err = ParseIPAndPrefix(vtepIP)
 		if err != nil {
 			log.Printf("parseIPAndPrefix: error creating vrf: %s\n", err)
 			return nil, err
@@ -132,8 +132,8 @@ func (c evpnClientImpl) ListVrfs(ctx context.Context, pageSize int32, pageToken
 	return data, nil
 }
 
-// UpdateVrf update the vrf on OPI server
-func (c evpnClientImpl) UpdateVrf(ctx context.Context, name string, updateMask []string, allowMissing bool) (*pb.Vrf, error) {
+// UpdateVrf updates the fields of the vrf listed in updateMask with the values of spec
+func (c evpnClientImpl) UpdateVrf(ctx context.Context, name string, spec *pb.VrfSpec, updateMask []string, allowMissing bool) (*pb.Vrf, error) {
 	conn, closer, err := c.NewConn()
 	if err != nil {
 		log.Printf("error creating connection: %s\n", err)
@@ -142,6 +142,7 @@ func (c evpnClientImpl) UpdateVrf(ctx context.Context, name string, updateMask [
 	defer closer()
 	vrf := &pb.Vrf{
 		Name: resourceIDToFullName("vrfs", name),
+		Spec: spec,
 	}
 	client := c.getEvpnVRFClient(conn)
 	data, err := client.UpdateVrf(ctx, &pb.UpdateVrfRequest{
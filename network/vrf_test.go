@@ -22,10 +22,10 @@ import (
 )
 
 func TestCreateVrf(t *testing.T) {
-	loopback, err := parseIPAndPrefix("192.168.1.1/24")
+	loopback, err := ParseIPAndPrefix("192.168.1.1/24")
 	assert.NoError(t, err)
 	var vni = uint32(100)
-	vtep, err := parseIPAndPrefix("10.0.0.1/32")
+	vtep, err := ParseIPAndPrefix("10.0.0.1/32")
 	assert.NoError(t, err)
 
 	testVrf := &pb.Vrf{
@@ -330,11 +330,12 @@ func TestListVrfs(t *testing.T) {
 
 func TestUpdateVrf(t *testing.T) {
 	name := "Vrf1"
-	updateMask := []string{""}
+	spec := &pb.VrfSpec{Vni: proto.Uint32(1000)}
+	updateMask := []string{"spec.vni"}
 	allowMissing := false
 
 	testRequest := &pb.UpdateVrfRequest{
-		Vrf:          &pb.Vrf{Name: resourceIDToFullName("vrfs", name)},
+		Vrf:          &pb.Vrf{Name: resourceIDToFullName("vrfs", name), Spec: spec},
 		UpdateMask:   &fieldmaskpb.FieldMask{Paths: updateMask},
 		AllowMissing: allowMissing,
 	}
@@ -399,7 +400,7 @@ func TestUpdateVrf(t *testing.T) {
 				},
 			)
 
-			response, err := c.UpdateVrf(context.Background(), name, updateMask, allowMissing)
+			response, err := c.UpdateVrf(context.Background(), name, spec, updateMask, allowMissing)
 
 			assert.Equal(t, tt.wantErr, err)
 			assert.Equal(t, tt.wantConnClosed, connClosed)
@@ -0,0 +1,151 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (C) 2024 Intel Corporation
+
+// Package backend implements the go library for OPI backend storage
+package backend
+
+import (
+	"context"
+
+	pb "github.com/opiproject/opi-api/storage/v1alpha1/gen/go"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+)
+
+// CreateAioVolume creates an aio volume backed by a file or
+// block device accessed with linux aio
+func (c *Client) CreateAioVolume(
+	ctx context.Context,
+	id string,
+	filename string,
+	blockSize int64,
+	blocksCount int64,
+) (*pb.AioVolume, error) {
+	conn, connClose, err := c.connector.NewConn()
+	if err != nil {
+		return nil, err
+	}
+	defer connClose()
+
+	client := c.createAioClient(conn)
+	return client.CreateAioVolume(
+		ctx,
+		&pb.CreateAioVolumeRequest{
+			AioVolumeId: id,
+			AioVolume: &pb.AioVolume{
+				BlockSize:   blockSize,
+				BlocksCount: blocksCount,
+				Filename:    filename,
+			},
+		})
+}
+
+// DeleteAioVolume deletes an aio volume
+func (c *Client) DeleteAioVolume(
+	ctx context.Context,
+	name string,
+	allowMissing bool,
+) error {
+	conn, connClose, err := c.connector.NewConn()
+	if err != nil {
+		return err
+	}
+	defer connClose()
+
+	client := c.createAioClient(conn)
+	_, err = client.DeleteAioVolume(
+		ctx,
+		&pb.DeleteAioVolumeRequest{
+			Name:         name,
+			AllowMissing: allowMissing,
+		})
+
+	return err
+}
+
+// GetAioVolume gets an aio volume
+func (c *Client) GetAioVolume(
+	ctx context.Context,
+	name string,
+) (*pb.AioVolume, error) {
+	conn, connClose, err := c.connector.NewConn()
+	if err != nil {
+		return nil, err
+	}
+	defer connClose()
+
+	client := c.createAioClient(conn)
+	return client.GetAioVolume(
+		ctx,
+		&pb.GetAioVolumeRequest{
+			Name: name,
+		})
+}
+
+// ListAioVolumes lists a page of aio volumes
+func (c *Client) ListAioVolumes(
+	ctx context.Context,
+	pageSize int32,
+	pageToken string,
+) (*pb.ListAioVolumesResponse, error) {
+	conn, connClose, err := c.connector.NewConn()
+	if err != nil {
+		return nil, err
+	}
+	defer connClose()
+
+	client := c.createAioClient(conn)
+	return client.ListAioVolumes(
+		ctx,
+		&pb.ListAioVolumesRequest{
+			PageSize:  pageSize,
+			PageToken: pageToken,
+		})
+}
+
+// UpdateAioVolume updates the fields of an aio volume listed in updateMask.
+// All fields are updated if updateMask is empty.
+func (c *Client) UpdateAioVolume(
+	ctx context.Context,
+	volume *pb.AioVolume,
+	updateMask []string,
+	allowMissing bool,
+) (*pb.AioVolume, error) {
+	conn, connClose, err := c.connector.NewConn()
+	if err != nil {
+		return nil, err
+	}
+	defer connClose()
+
+	client := c.createAioClient(conn)
+	return client.UpdateAioVolume(
+		ctx,
+		&pb.UpdateAioVolumeRequest{
+			AioVolume:    volume,
+			UpdateMask:   &fieldmaskpb.FieldMask{Paths: updateMask},
+			AllowMissing: allowMissing,
+		})
+}
+
+// StatsAioVolume gets io statistics of an aio volume
+func (c *Client) StatsAioVolume(
+	ctx context.Context,
+	name string,
+) (*pb.VolumeStats, error) {
+	conn, connClose, err := c.connector.NewConn()
+	if err != nil {
+		return nil, err
+	}
+	defer connClose()
+
+	client := c.createAioClient(conn)
+	response, err := client.StatsAioVolume(
+		ctx,
+		&pb.StatsAioVolumeRequest{
+			Name: name,
+		})
+	if err != nil {
+		return nil, err
+	}
+
+	return response.GetStats(), nil
+}
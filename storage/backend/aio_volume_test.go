@@ -0,0 +1,520 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (C) 2024 Intel Corporation
+
+// Package backend implements the go library for OPI backend storage
+package backend
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/opiproject/godpu/mocks"
+	pb "github.com/opiproject/opi-api/storage/v1alpha1/gen/go"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+)
+
+var testAioVolume = &pb.AioVolume{
+	Name:        "aioVolumes/aio0",
+	BlockSize:   512,
+	BlocksCount: 64,
+	Filename:    "/dev/sdb",
+}
+
+func TestCreateAioVolume(t *testing.T) {
+	testRequest := &pb.CreateAioVolumeRequest{
+		AioVolumeId: "aio0",
+		AioVolume: &pb.AioVolume{
+			BlockSize:   testAioVolume.BlockSize,
+			BlocksCount: testAioVolume.BlocksCount,
+			Filename:    testAioVolume.Filename,
+		},
+	}
+
+	tests := map[string]struct {
+		giveClientErr    error
+		giveConnectorErr error
+		wantErr          error
+		wantRequest      *pb.CreateAioVolumeRequest
+		wantResponse     *pb.AioVolume
+		wantConnClosed   bool
+	}{
+		"successful call": {
+			giveConnectorErr: nil,
+			giveClientErr:    nil,
+			wantErr:          nil,
+			wantRequest:      proto.Clone(testRequest).(*pb.CreateAioVolumeRequest),
+			wantResponse:     proto.Clone(testAioVolume).(*pb.AioVolume),
+			wantConnClosed:   true,
+		},
+		"client err": {
+			giveConnectorErr: nil,
+			giveClientErr:    errors.New("Some client error"),
+			wantErr:          errors.New("Some client error"),
+			wantRequest:      proto.Clone(testRequest).(*pb.CreateAioVolumeRequest),
+			wantResponse:     nil,
+			wantConnClosed:   true,
+		},
+		"connector err": {
+			giveConnectorErr: errors.New("Some conn error"),
+			giveClientErr:    nil,
+			wantErr:          errors.New("Some conn error"),
+			wantRequest:      nil,
+			wantResponse:     nil,
+			wantConnClosed:   false,
+		},
+	}
+
+	for testName, tt := range tests {
+		t.Run(testName, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
+
+			mockClient := mocks.NewAioVolumeServiceClient(t)
+			if tt.wantRequest != nil {
+				toReturn := proto.Clone(tt.wantResponse).(*pb.AioVolume)
+				mockClient.EXPECT().CreateAioVolume(ctx, tt.wantRequest).
+					Return(toReturn, tt.giveClientErr)
+			}
+
+			connClosed := false
+			mockConn := mocks.NewConnector(t)
+			mockConn.EXPECT().NewConn().Return(
+				&grpc.ClientConn{},
+				func() { connClosed = true },
+				tt.giveConnectorErr,
+			)
+
+			c, _ := NewWithArgs(
+				mockConn,
+				pb.NewNvmeRemoteControllerServiceClient,
+				pb.NewNullVolumeServiceClient,
+				func(grpc.ClientConnInterface) pb.AioVolumeServiceClient {
+					return mockClient
+				},
+				pb.NewMallocVolumeServiceClient,
+			)
+
+			response, err := c.CreateAioVolume(
+				ctx,
+				"aio0",
+				testAioVolume.Filename,
+				testAioVolume.BlockSize,
+				testAioVolume.BlocksCount,
+			)
+
+			require.Equal(t, tt.wantErr, err)
+			require.True(t, proto.Equal(response, tt.wantResponse))
+			require.Equal(t, tt.wantConnClosed, connClosed)
+		})
+	}
+}
+
+func TestDeleteAioVolume(t *testing.T) {
+	testRequest := &pb.DeleteAioVolumeRequest{
+		Name:         testAioVolume.Name,
+		AllowMissing: true,
+	}
+
+	tests := map[string]struct {
+		giveClientErr    error
+		giveConnectorErr error
+		wantErr          error
+		wantRequest      *pb.DeleteAioVolumeRequest
+		wantConnClosed   bool
+	}{
+		"successful call": {
+			giveConnectorErr: nil,
+			giveClientErr:    nil,
+			wantErr:          nil,
+			wantRequest:      proto.Clone(testRequest).(*pb.DeleteAioVolumeRequest),
+			wantConnClosed:   true,
+		},
+		"client err": {
+			giveConnectorErr: nil,
+			giveClientErr:    errors.New("Some client error"),
+			wantErr:          errors.New("Some client error"),
+			wantRequest:      proto.Clone(testRequest).(*pb.DeleteAioVolumeRequest),
+			wantConnClosed:   true,
+		},
+		"connector err": {
+			giveConnectorErr: errors.New("Some conn error"),
+			giveClientErr:    nil,
+			wantErr:          errors.New("Some conn error"),
+			wantRequest:      nil,
+			wantConnClosed:   false,
+		},
+	}
+
+	for testName, tt := range tests {
+		t.Run(testName, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
+
+			mockClient := mocks.NewAioVolumeServiceClient(t)
+			if tt.wantRequest != nil {
+				mockClient.EXPECT().DeleteAioVolume(ctx, tt.wantRequest).
+					Return(&emptypb.Empty{}, tt.giveClientErr)
+			}
+
+			connClosed := false
+			mockConn := mocks.NewConnector(t)
+			mockConn.EXPECT().NewConn().Return(
+				&grpc.ClientConn{},
+				func() { connClosed = true },
+				tt.giveConnectorErr,
+			)
+
+			c, _ := NewWithArgs(
+				mockConn,
+				pb.NewNvmeRemoteControllerServiceClient,
+				pb.NewNullVolumeServiceClient,
+				func(grpc.ClientConnInterface) pb.AioVolumeServiceClient {
+					return mockClient
+				},
+				pb.NewMallocVolumeServiceClient,
+			)
+
+			err := c.DeleteAioVolume(ctx, testAioVolume.Name, true)
+
+			require.Equal(t, tt.wantErr, err)
+			require.Equal(t, tt.wantConnClosed, connClosed)
+		})
+	}
+}
+
+func TestGetAioVolume(t *testing.T) {
+	testRequest := &pb.GetAioVolumeRequest{
+		Name: testAioVolume.Name,
+	}
+
+	tests := map[string]struct {
+		giveClientErr    error
+		giveConnectorErr error
+		wantErr          error
+		wantRequest      *pb.GetAioVolumeRequest
+		wantResponse     *pb.AioVolume
+		wantConnClosed   bool
+	}{
+		"successful call": {
+			giveConnectorErr: nil,
+			giveClientErr:    nil,
+			wantErr:          nil,
+			wantRequest:      proto.Clone(testRequest).(*pb.GetAioVolumeRequest),
+			wantResponse:     proto.Clone(testAioVolume).(*pb.AioVolume),
+			wantConnClosed:   true,
+		},
+		"client err": {
+			giveConnectorErr: nil,
+			giveClientErr:    errors.New("Some client error"),
+			wantErr:          errors.New("Some client error"),
+			wantRequest:      proto.Clone(testRequest).(*pb.GetAioVolumeRequest),
+			wantResponse:     nil,
+			wantConnClosed:   true,
+		},
+		"connector err": {
+			giveConnectorErr: errors.New("Some conn error"),
+			giveClientErr:    nil,
+			wantErr:          errors.New("Some conn error"),
+			wantRequest:      nil,
+			wantResponse:     nil,
+			wantConnClosed:   false,
+		},
+	}
+
+	for testName, tt := range tests {
+		t.Run(testName, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
+
+			mockClient := mocks.NewAioVolumeServiceClient(t)
+			if tt.wantRequest != nil {
+				toReturn := proto.Clone(tt.wantResponse).(*pb.AioVolume)
+				mockClient.EXPECT().GetAioVolume(ctx, tt.wantRequest).
+					Return(toReturn, tt.giveClientErr)
+			}
+
+			connClosed := false
+			mockConn := mocks.NewConnector(t)
+			mockConn.EXPECT().NewConn().Return(
+				&grpc.ClientConn{},
+				func() { connClosed = true },
+				tt.giveConnectorErr,
+			)
+
+			c, _ := NewWithArgs(
+				mockConn,
+				pb.NewNvmeRemoteControllerServiceClient,
+				pb.NewNullVolumeServiceClient,
+				func(grpc.ClientConnInterface) pb.AioVolumeServiceClient {
+					return mockClient
+				},
+				pb.NewMallocVolumeServiceClient,
+			)
+
+			response, err := c.GetAioVolume(ctx, testAioVolume.Name)
+
+			require.Equal(t, tt.wantErr, err)
+			require.True(t, proto.Equal(response, tt.wantResponse))
+			require.Equal(t, tt.wantConnClosed, connClosed)
+		})
+	}
+}
+
+func TestListAioVolumes(t *testing.T) {
+	testRequest := &pb.ListAioVolumesRequest{
+		PageSize:  10,
+		PageToken: "token",
+	}
+	testResponse := &pb.ListAioVolumesResponse{
+		AioVolumes:    []*pb.AioVolume{testAioVolume},
+		NextPageToken: "next",
+	}
+
+	tests := map[string]struct {
+		giveClientErr    error
+		giveConnectorErr error
+		wantErr          error
+		wantRequest      *pb.ListAioVolumesRequest
+		wantResponse     *pb.ListAioVolumesResponse
+		wantConnClosed   bool
+	}{
+		"successful call": {
+			giveConnectorErr: nil,
+			giveClientErr:    nil,
+			wantErr:          nil,
+			wantRequest:      proto.Clone(testRequest).(*pb.ListAioVolumesRequest),
+			wantResponse:     proto.Clone(testResponse).(*pb.ListAioVolumesResponse),
+			wantConnClosed:   true,
+		},
+		"client err": {
+			giveConnectorErr: nil,
+			giveClientErr:    errors.New("Some client error"),
+			wantErr:          errors.New("Some client error"),
+			wantRequest:      proto.Clone(testRequest).(*pb.ListAioVolumesRequest),
+			wantResponse:     nil,
+			wantConnClosed:   true,
+		},
+		"connector err": {
+			giveConnectorErr: errors.New("Some conn error"),
+			giveClientErr:    nil,
+			wantErr:          errors.New("Some conn error"),
+			wantRequest:      nil,
+			wantResponse:     nil,
+			wantConnClosed:   false,
+		},
+	}
+
+	for testName, tt := range tests {
+		t.Run(testName, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
+
+			mockClient := mocks.NewAioVolumeServiceClient(t)
+			if tt.wantRequest != nil {
+				toReturn := proto.Clone(tt.wantResponse).(*pb.ListAioVolumesResponse)
+				mockClient.EXPECT().ListAioVolumes(ctx, tt.wantRequest).
+					Return(toReturn, tt.giveClientErr)
+			}
+
+			connClosed := false
+			mockConn := mocks.NewConnector(t)
+			mockConn.EXPECT().NewConn().Return(
+				&grpc.ClientConn{},
+				func() { connClosed = true },
+				tt.giveConnectorErr,
+			)
+
+			c, _ := NewWithArgs(
+				mockConn,
+				pb.NewNvmeRemoteControllerServiceClient,
+				pb.NewNullVolumeServiceClient,
+				func(grpc.ClientConnInterface) pb.AioVolumeServiceClient {
+					return mockClient
+				},
+				pb.NewMallocVolumeServiceClient,
+			)
+
+			response, err := c.ListAioVolumes(ctx, 10, "token")
+
+			require.Equal(t, tt.wantErr, err)
+			require.True(t, proto.Equal(response, tt.wantResponse))
+			require.Equal(t, tt.wantConnClosed, connClosed)
+		})
+	}
+}
+
+func TestUpdateAioVolume(t *testing.T) {
+	testRequest := &pb.UpdateAioVolumeRequest{
+		AioVolume:    proto.Clone(testAioVolume).(*pb.AioVolume),
+		UpdateMask:   &fieldmaskpb.FieldMask{Paths: []string{"blocks_count"}},
+		AllowMissing: false,
+	}
+
+	tests := map[string]struct {
+		giveClientErr    error
+		giveConnectorErr error
+		wantErr          error
+		wantRequest      *pb.UpdateAioVolumeRequest
+		wantResponse     *pb.AioVolume
+		wantConnClosed   bool
+	}{
+		"successful call": {
+			giveConnectorErr: nil,
+			giveClientErr:    nil,
+			wantErr:          nil,
+			wantRequest:      proto.Clone(testRequest).(*pb.UpdateAioVolumeRequest),
+			wantResponse:     proto.Clone(testAioVolume).(*pb.AioVolume),
+			wantConnClosed:   true,
+		},
+		"client err": {
+			giveConnectorErr: nil,
+			giveClientErr:    errors.New("Some client error"),
+			wantErr:          errors.New("Some client error"),
+			wantRequest:      proto.Clone(testRequest).(*pb.UpdateAioVolumeRequest),
+			wantResponse:     nil,
+			wantConnClosed:   true,
+		},
+		"connector err": {
+			giveConnectorErr: errors.New("Some conn error"),
+			giveClientErr:    nil,
+			wantErr:          errors.New("Some conn error"),
+			wantRequest:      nil,
+			wantResponse:     nil,
+			wantConnClosed:   false,
+		},
+	}
+
+	for testName, tt := range tests {
+		t.Run(testName, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
+
+			mockClient := mocks.NewAioVolumeServiceClient(t)
+			if tt.wantRequest != nil {
+				toReturn := proto.Clone(tt.wantResponse).(*pb.AioVolume)
+				mockClient.EXPECT().UpdateAioVolume(ctx, tt.wantRequest).
+					Return(toReturn, tt.giveClientErr)
+			}
+
+			connClosed := false
+			mockConn := mocks.NewConnector(t)
+			mockConn.EXPECT().NewConn().Return(
+				&grpc.ClientConn{},
+				func() { connClosed = true },
+				tt.giveConnectorErr,
+			)
+
+			c, _ := NewWithArgs(
+				mockConn,
+				pb.NewNvmeRemoteControllerServiceClient,
+				pb.NewNullVolumeServiceClient,
+				func(grpc.ClientConnInterface) pb.AioVolumeServiceClient {
+					return mockClient
+				},
+				pb.NewMallocVolumeServiceClient,
+			)
+
+			response, err := c.UpdateAioVolume(
+				ctx,
+				proto.Clone(testAioVolume).(*pb.AioVolume),
+				[]string{"blocks_count"},
+				false,
+			)
+
+			require.Equal(t, tt.wantErr, err)
+			require.True(t, proto.Equal(response, tt.wantResponse))
+			require.Equal(t, tt.wantConnClosed, connClosed)
+		})
+	}
+}
+
+func TestStatsAioVolume(t *testing.T) {
+	testRequest := &pb.StatsAioVolumeRequest{
+		Name: testAioVolume.Name,
+	}
+	testStats := &pb.VolumeStats{
+		ReadBytesCount: 4096,
+		ReadOpsCount:   8,
+	}
+
+	tests := map[string]struct {
+		giveClientErr    error
+		giveConnectorErr error
+		wantErr          error
+		wantRequest      *pb.StatsAioVolumeRequest
+		wantResponse     *pb.VolumeStats
+		wantConnClosed   bool
+	}{
+		"successful call": {
+			giveConnectorErr: nil,
+			giveClientErr:    nil,
+			wantErr:          nil,
+			wantRequest:      proto.Clone(testRequest).(*pb.StatsAioVolumeRequest),
+			wantResponse:     proto.Clone(testStats).(*pb.VolumeStats),
+			wantConnClosed:   true,
+		},
+		"client err": {
+			giveConnectorErr: nil,
+			giveClientErr:    errors.New("Some client error"),
+			wantErr:          errors.New("Some client error"),
+			wantRequest:      proto.Clone(testRequest).(*pb.StatsAioVolumeRequest),
+			wantResponse:     nil,
+			wantConnClosed:   true,
+		},
+		"connector err": {
+			giveConnectorErr: errors.New("Some conn error"),
+			giveClientErr:    nil,
+			wantErr:          errors.New("Some conn error"),
+			wantRequest:      nil,
+			wantResponse:     nil,
+			wantConnClosed:   false,
+		},
+	}
+
+	for testName, tt := range tests {
+		t.Run(testName, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
+
+			mockClient := mocks.NewAioVolumeServiceClient(t)
+			if tt.wantRequest != nil {
+				var toReturn *pb.StatsAioVolumeResponse
+				if tt.giveClientErr == nil {
+					toReturn = &pb.StatsAioVolumeResponse{Stats: proto.Clone(tt.wantResponse).(*pb.VolumeStats)}
+				}
+				mockClient.EXPECT().StatsAioVolume(ctx, tt.wantRequest).
+					Return(toReturn, tt.giveClientErr)
+			}
+
+			connClosed := false
+			mockConn := mocks.NewConnector(t)
+			mockConn.EXPECT().NewConn().Return(
+				&grpc.ClientConn{},
+				func() { connClosed = true },
+				tt.giveConnectorErr,
+			)
+
+			c, _ := NewWithArgs(
+				mockConn,
+				pb.NewNvmeRemoteControllerServiceClient,
+				pb.NewNullVolumeServiceClient,
+				func(grpc.ClientConnInterface) pb.AioVolumeServiceClient {
+					return mockClient
+				},
+				pb.NewMallocVolumeServiceClient,
+			)
+
+			response, err := c.StatsAioVolume(ctx, testAioVolume.Name)
+
+			require.Equal(t, tt.wantErr, err)
+			require.True(t, proto.Equal(response, tt.wantResponse))
+			require.Equal(t, tt.wantConnClosed, connClosed)
+		})
+	}
+}
@@ -13,10 +13,22 @@ import (
 // CreateNvmeClient defines the function type used to retrieve NvmeRemoteControllerServiceClient
 type CreateNvmeClient func(cc grpc.ClientConnInterface) pb.NvmeRemoteControllerServiceClient
 
+// CreateNullClient defines the function type used to retrieve NullVolumeServiceClient
+type CreateNullClient func(cc grpc.ClientConnInterface) pb.NullVolumeServiceClient
+
+// CreateAioClient defines the function type used to retrieve AioVolumeServiceClient
+type CreateAioClient func(cc grpc.ClientConnInterface) pb.AioVolumeServiceClient
+
+// CreateMallocClient defines the function type used to retrieve MallocVolumeServiceClient
+type CreateMallocClient func(cc grpc.ClientConnInterface) pb.MallocVolumeServiceClient
+
 // Client is used for managing storage devices on OPI server
 type Client struct {
-	connector        grpcOpi.Connector
-	createNvmeClient CreateNvmeClient
+	connector          grpcOpi.Connector
+	createNvmeClient   CreateNvmeClient
+	createNullClient   CreateNullClient
+	createAioClient    CreateAioClient
+	createMallocClient CreateMallocClient
 }
 
 // New creates a new instance of Client
@@ -29,6 +41,9 @@ func New(addr string, tls string) (*Client, error) {
 	return NewWithArgs(
 		connector,
 		pb.NewNvmeRemoteControllerServiceClient,
+		pb.NewNullVolumeServiceClient,
+		pb.NewAioVolumeServiceClient,
+		pb.NewMallocVolumeServiceClient,
 	)
 }
 
@@ -36,9 +51,15 @@ func New(addr string, tls string) (*Client, error) {
 func NewWithArgs(
 	connector grpcOpi.Connector,
 	createNvmeClient CreateNvmeClient,
+	createNullClient CreateNullClient,
+	createAioClient CreateAioClient,
+	createMallocClient CreateMallocClient,
 ) (*Client, error) {
 	return &Client{
-		connector:        connector,
-		createNvmeClient: createNvmeClient,
+		connector:          connector,
+		createNvmeClient:   createNvmeClient,
+		createNullClient:   createNullClient,
+		createAioClient:    createAioClient,
+		createMallocClient: createMallocClient,
 	}, nil
 }
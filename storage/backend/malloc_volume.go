@@ -0,0 +1,149 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (C) 2024 Intel Corporation
+
+// Package backend implements the go library for OPI backend storage
+package backend
+
+import (
+	"context"
+
+	pb "github.com/opiproject/opi-api/storage/v1alpha1/gen/go"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+)
+
+// CreateMallocVolume creates a malloc volume backed by memory
+// of the target
+func (c *Client) CreateMallocVolume(
+	ctx context.Context,
+	id string,
+	blockSize int64,
+	blocksCount int64,
+) (*pb.MallocVolume, error) {
+	conn, connClose, err := c.connector.NewConn()
+	if err != nil {
+		return nil, err
+	}
+	defer connClose()
+
+	client := c.createMallocClient(conn)
+	return client.CreateMallocVolume(
+		ctx,
+		&pb.CreateMallocVolumeRequest{
+			MallocVolumeId: id,
+			MallocVolume: &pb.MallocVolume{
+				BlockSize:   blockSize,
+				BlocksCount: blocksCount,
+			},
+		})
+}
+
+// DeleteMallocVolume deletes a malloc volume
+func (c *Client) DeleteMallocVolume(
+	ctx context.Context,
+	name string,
+	allowMissing bool,
+) error {
+	conn, connClose, err := c.connector.NewConn()
+	if err != nil {
+		return err
+	}
+	defer connClose()
+
+	client := c.createMallocClient(conn)
+	_, err = client.DeleteMallocVolume(
+		ctx,
+		&pb.DeleteMallocVolumeRequest{
+			Name:         name,
+			AllowMissing: allowMissing,
+		})
+
+	return err
+}
+
+// GetMallocVolume gets a malloc volume
+func (c *Client) GetMallocVolume(
+	ctx context.Context,
+	name string,
+) (*pb.MallocVolume, error) {
+	conn, connClose, err := c.connector.NewConn()
+	if err != nil {
+		return nil, err
+	}
+	defer connClose()
+
+	client := c.createMallocClient(conn)
+	return client.GetMallocVolume(
+		ctx,
+		&pb.GetMallocVolumeRequest{
+			Name: name,
+		})
+}
+
+// ListMallocVolumes lists a page of malloc volumes
+func (c *Client) ListMallocVolumes(
+	ctx context.Context,
+	pageSize int32,
+	pageToken string,
+) (*pb.ListMallocVolumesResponse, error) {
+	conn, connClose, err := c.connector.NewConn()
+	if err != nil {
+		return nil, err
+	}
+	defer connClose()
+
+	client := c.createMallocClient(conn)
+	return client.ListMallocVolumes(
+		ctx,
+		&pb.ListMallocVolumesRequest{
+			PageSize:  pageSize,
+			PageToken: pageToken,
+		})
+}
+
+// UpdateMallocVolume updates the fields of a malloc volume listed in updateMask.
+// All fields are updated if updateMask is empty.
+func (c *Client) UpdateMallocVolume(
+	ctx context.Context,
+	volume *pb.MallocVolume,
+	updateMask []string,
+	allowMissing bool,
+) (*pb.MallocVolume, error) {
+	conn, connClose, err := c.connector.NewConn()
+	if err != nil {
+		return nil, err
+	}
+	defer connClose()
+
+	client := c.createMallocClient(conn)
+	return client.UpdateMallocVolume(
+		ctx,
+		&pb.UpdateMallocVolumeRequest{
+			MallocVolume: volume,
+			UpdateMask:   &fieldmaskpb.FieldMask{Paths: updateMask},
+			AllowMissing: allowMissing,
+		})
+}
+
+// StatsMallocVolume gets io statistics of a malloc volume
+func (c *Client) StatsMallocVolume(
+	ctx context.Context,
+	name string,
+) (*pb.VolumeStats, error) {
+	conn, connClose, err := c.connector.NewConn()
+	if err != nil {
+		return nil, err
+	}
+	defer connClose()
+
+	client := c.createMallocClient(conn)
+	response, err := client.StatsMallocVolume(
+		ctx,
+		&pb.StatsMallocVolumeRequest{
+			Name: name,
+		})
+	if err != nil {
+		return nil, err
+	}
+
+	return response.GetStats(), nil
+}
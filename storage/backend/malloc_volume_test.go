@@ -0,0 +1,517 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (C) 2024 Intel Corporation
+
+// Package backend implements the go library for OPI backend storage
+package backend
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/opiproject/godpu/mocks"
+	pb "github.com/opiproject/opi-api/storage/v1alpha1/gen/go"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+)
+
+var testMallocVolume = &pb.MallocVolume{
+	Name:        "mallocVolumes/malloc0",
+	BlockSize:   512,
+	BlocksCount: 64,
+}
+
+func TestCreateMallocVolume(t *testing.T) {
+	testRequest := &pb.CreateMallocVolumeRequest{
+		MallocVolumeId: "malloc0",
+		MallocVolume: &pb.MallocVolume{
+			BlockSize:   testMallocVolume.BlockSize,
+			BlocksCount: testMallocVolume.BlocksCount,
+		},
+	}
+
+	tests := map[string]struct {
+		giveClientErr    error
+		giveConnectorErr error
+		wantErr          error
+		wantRequest      *pb.CreateMallocVolumeRequest
+		wantResponse     *pb.MallocVolume
+		wantConnClosed   bool
+	}{
+		"successful call": {
+			giveConnectorErr: nil,
+			giveClientErr:    nil,
+			wantErr:          nil,
+			wantRequest:      proto.Clone(testRequest).(*pb.CreateMallocVolumeRequest),
+			wantResponse:     proto.Clone(testMallocVolume).(*pb.MallocVolume),
+			wantConnClosed:   true,
+		},
+		"client err": {
+			giveConnectorErr: nil,
+			giveClientErr:    errors.New("Some client error"),
+			wantErr:          errors.New("Some client error"),
+			wantRequest:      proto.Clone(testRequest).(*pb.CreateMallocVolumeRequest),
+			wantResponse:     nil,
+			wantConnClosed:   true,
+		},
+		"connector err": {
+			giveConnectorErr: errors.New("Some conn error"),
+			giveClientErr:    nil,
+			wantErr:          errors.New("Some conn error"),
+			wantRequest:      nil,
+			wantResponse:     nil,
+			wantConnClosed:   false,
+		},
+	}
+
+	for testName, tt := range tests {
+		t.Run(testName, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
+
+			mockClient := mocks.NewMallocVolumeServiceClient(t)
+			if tt.wantRequest != nil {
+				toReturn := proto.Clone(tt.wantResponse).(*pb.MallocVolume)
+				mockClient.EXPECT().CreateMallocVolume(ctx, tt.wantRequest).
+					Return(toReturn, tt.giveClientErr)
+			}
+
+			connClosed := false
+			mockConn := mocks.NewConnector(t)
+			mockConn.EXPECT().NewConn().Return(
+				&grpc.ClientConn{},
+				func() { connClosed = true },
+				tt.giveConnectorErr,
+			)
+
+			c, _ := NewWithArgs(
+				mockConn,
+				pb.NewNvmeRemoteControllerServiceClient,
+				pb.NewNullVolumeServiceClient,
+				pb.NewAioVolumeServiceClient,
+				func(grpc.ClientConnInterface) pb.MallocVolumeServiceClient {
+					return mockClient
+				},
+			)
+
+			response, err := c.CreateMallocVolume(
+				ctx,
+				"malloc0",
+				testMallocVolume.BlockSize,
+				testMallocVolume.BlocksCount,
+			)
+
+			require.Equal(t, tt.wantErr, err)
+			require.True(t, proto.Equal(response, tt.wantResponse))
+			require.Equal(t, tt.wantConnClosed, connClosed)
+		})
+	}
+}
+
+func TestDeleteMallocVolume(t *testing.T) {
+	testRequest := &pb.DeleteMallocVolumeRequest{
+		Name:         testMallocVolume.Name,
+		AllowMissing: true,
+	}
+
+	tests := map[string]struct {
+		giveClientErr    error
+		giveConnectorErr error
+		wantErr          error
+		wantRequest      *pb.DeleteMallocVolumeRequest
+		wantConnClosed   bool
+	}{
+		"successful call": {
+			giveConnectorErr: nil,
+			giveClientErr:    nil,
+			wantErr:          nil,
+			wantRequest:      proto.Clone(testRequest).(*pb.DeleteMallocVolumeRequest),
+			wantConnClosed:   true,
+		},
+		"client err": {
+			giveConnectorErr: nil,
+			giveClientErr:    errors.New("Some client error"),
+			wantErr:          errors.New("Some client error"),
+			wantRequest:      proto.Clone(testRequest).(*pb.DeleteMallocVolumeRequest),
+			wantConnClosed:   true,
+		},
+		"connector err": {
+			giveConnectorErr: errors.New("Some conn error"),
+			giveClientErr:    nil,
+			wantErr:          errors.New("Some conn error"),
+			wantRequest:      nil,
+			wantConnClosed:   false,
+		},
+	}
+
+	for testName, tt := range tests {
+		t.Run(testName, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
+
+			mockClient := mocks.NewMallocVolumeServiceClient(t)
+			if tt.wantRequest != nil {
+				mockClient.EXPECT().DeleteMallocVolume(ctx, tt.wantRequest).
+					Return(&emptypb.Empty{}, tt.giveClientErr)
+			}
+
+			connClosed := false
+			mockConn := mocks.NewConnector(t)
+			mockConn.EXPECT().NewConn().Return(
+				&grpc.ClientConn{},
+				func() { connClosed = true },
+				tt.giveConnectorErr,
+			)
+
+			c, _ := NewWithArgs(
+				mockConn,
+				pb.NewNvmeRemoteControllerServiceClient,
+				pb.NewNullVolumeServiceClient,
+				pb.NewAioVolumeServiceClient,
+				func(grpc.ClientConnInterface) pb.MallocVolumeServiceClient {
+					return mockClient
+				},
+			)
+
+			err := c.DeleteMallocVolume(ctx, testMallocVolume.Name, true)
+
+			require.Equal(t, tt.wantErr, err)
+			require.Equal(t, tt.wantConnClosed, connClosed)
+		})
+	}
+}
+
+func TestGetMallocVolume(t *testing.T) {
+	testRequest := &pb.GetMallocVolumeRequest{
+		Name: testMallocVolume.Name,
+	}
+
+	tests := map[string]struct {
+		giveClientErr    error
+		giveConnectorErr error
+		wantErr          error
+		wantRequest      *pb.GetMallocVolumeRequest
+		wantResponse     *pb.MallocVolume
+		wantConnClosed   bool
+	}{
+		"successful call": {
+			giveConnectorErr: nil,
+			giveClientErr:    nil,
+			wantErr:          nil,
+			wantRequest:      proto.Clone(testRequest).(*pb.GetMallocVolumeRequest),
+			wantResponse:     proto.Clone(testMallocVolume).(*pb.MallocVolume),
+			wantConnClosed:   true,
+		},
+		"client err": {
+			giveConnectorErr: nil,
+			giveClientErr:    errors.New("Some client error"),
+			wantErr:          errors.New("Some client error"),
+			wantRequest:      proto.Clone(testRequest).(*pb.GetMallocVolumeRequest),
+			wantResponse:     nil,
+			wantConnClosed:   true,
+		},
+		"connector err": {
+			giveConnectorErr: errors.New("Some conn error"),
+			giveClientErr:    nil,
+			wantErr:          errors.New("Some conn error"),
+			wantRequest:      nil,
+			wantResponse:     nil,
+			wantConnClosed:   false,
+		},
+	}
+
+	for testName, tt := range tests {
+		t.Run(testName, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
+
+			mockClient := mocks.NewMallocVolumeServiceClient(t)
+			if tt.wantRequest != nil {
+				toReturn := proto.Clone(tt.wantResponse).(*pb.MallocVolume)
+				mockClient.EXPECT().GetMallocVolume(ctx, tt.wantRequest).
+					Return(toReturn, tt.giveClientErr)
+			}
+
+			connClosed := false
+			mockConn := mocks.NewConnector(t)
+			mockConn.EXPECT().NewConn().Return(
+				&grpc.ClientConn{},
+				func() { connClosed = true },
+				tt.giveConnectorErr,
+			)
+
+			c, _ := NewWithArgs(
+				mockConn,
+				pb.NewNvmeRemoteControllerServiceClient,
+				pb.NewNullVolumeServiceClient,
+				pb.NewAioVolumeServiceClient,
+				func(grpc.ClientConnInterface) pb.MallocVolumeServiceClient {
+					return mockClient
+				},
+			)
+
+			response, err := c.GetMallocVolume(ctx, testMallocVolume.Name)
+
+			require.Equal(t, tt.wantErr, err)
+			require.True(t, proto.Equal(response, tt.wantResponse))
+			require.Equal(t, tt.wantConnClosed, connClosed)
+		})
+	}
+}
+
+func TestListMallocVolumes(t *testing.T) {
+	testRequest := &pb.ListMallocVolumesRequest{
+		PageSize:  10,
+		PageToken: "token",
+	}
+	testResponse := &pb.ListMallocVolumesResponse{
+		MallocVolumes: []*pb.MallocVolume{testMallocVolume},
+		NextPageToken: "next",
+	}
+
+	tests := map[string]struct {
+		giveClientErr    error
+		giveConnectorErr error
+		wantErr          error
+		wantRequest      *pb.ListMallocVolumesRequest
+		wantResponse     *pb.ListMallocVolumesResponse
+		wantConnClosed   bool
+	}{
+		"successful call": {
+			giveConnectorErr: nil,
+			giveClientErr:    nil,
+			wantErr:          nil,
+			wantRequest:      proto.Clone(testRequest).(*pb.ListMallocVolumesRequest),
+			wantResponse:     proto.Clone(testResponse).(*pb.ListMallocVolumesResponse),
+			wantConnClosed:   true,
+		},
+		"client err": {
+			giveConnectorErr: nil,
+			giveClientErr:    errors.New("Some client error"),
+			wantErr:          errors.New("Some client error"),
+			wantRequest:      proto.Clone(testRequest).(*pb.ListMallocVolumesRequest),
+			wantResponse:     nil,
+			wantConnClosed:   true,
+		},
+		"connector err": {
+			giveConnectorErr: errors.New("Some conn error"),
+			giveClientErr:    nil,
+			wantErr:          errors.New("Some conn error"),
+			wantRequest:      nil,
+			wantResponse:     nil,
+			wantConnClosed:   false,
+		},
+	}
+
+	for testName, tt := range tests {
+		t.Run(testName, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
+
+			mockClient := mocks.NewMallocVolumeServiceClient(t)
+			if tt.wantRequest != nil {
+				toReturn := proto.Clone(tt.wantResponse).(*pb.ListMallocVolumesResponse)
+				mockClient.EXPECT().ListMallocVolumes(ctx, tt.wantRequest).
+					Return(toReturn, tt.giveClientErr)
+			}
+
+			connClosed := false
+			mockConn := mocks.NewConnector(t)
+			mockConn.EXPECT().NewConn().Return(
+				&grpc.ClientConn{},
+				func() { connClosed = true },
+				tt.giveConnectorErr,
+			)
+
+			c, _ := NewWithArgs(
+				mockConn,
+				pb.NewNvmeRemoteControllerServiceClient,
+				pb.NewNullVolumeServiceClient,
+				pb.NewAioVolumeServiceClient,
+				func(grpc.ClientConnInterface) pb.MallocVolumeServiceClient {
+					return mockClient
+				},
+			)
+
+			response, err := c.ListMallocVolumes(ctx, 10, "token")
+
+			require.Equal(t, tt.wantErr, err)
+			require.True(t, proto.Equal(response, tt.wantResponse))
+			require.Equal(t, tt.wantConnClosed, connClosed)
+		})
+	}
+}
+
+func TestUpdateMallocVolume(t *testing.T) {
+	testRequest := &pb.UpdateMallocVolumeRequest{
+		MallocVolume: proto.Clone(testMallocVolume).(*pb.MallocVolume),
+		UpdateMask:   &fieldmaskpb.FieldMask{Paths: []string{"blocks_count"}},
+		AllowMissing: false,
+	}
+
+	tests := map[string]struct {
+		giveClientErr    error
+		giveConnectorErr error
+		wantErr          error
+		wantRequest      *pb.UpdateMallocVolumeRequest
+		wantResponse     *pb.MallocVolume
+		wantConnClosed   bool
+	}{
+		"successful call": {
+			giveConnectorErr: nil,
+			giveClientErr:    nil,
+			wantErr:          nil,
+			wantRequest:      proto.Clone(testRequest).(*pb.UpdateMallocVolumeRequest),
+			wantResponse:     proto.Clone(testMallocVolume).(*pb.MallocVolume),
+			wantConnClosed:   true,
+		},
+		"client err": {
+			giveConnectorErr: nil,
+			giveClientErr:    errors.New("Some client error"),
+			wantErr:          errors.New("Some client error"),
+			wantRequest:      proto.Clone(testRequest).(*pb.UpdateMallocVolumeRequest),
+			wantResponse:     nil,
+			wantConnClosed:   true,
+		},
+		"connector err": {
+			giveConnectorErr: errors.New("Some conn error"),
+			giveClientErr:    nil,
+			wantErr:          errors.New("Some conn error"),
+			wantRequest:      nil,
+			wantResponse:     nil,
+			wantConnClosed:   false,
+		},
+	}
+
+	for testName, tt := range tests {
+		t.Run(testName, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
+
+			mockClient := mocks.NewMallocVolumeServiceClient(t)
+			if tt.wantRequest != nil {
+				toReturn := proto.Clone(tt.wantResponse).(*pb.MallocVolume)
+				mockClient.EXPECT().UpdateMallocVolume(ctx, tt.wantRequest).
+					Return(toReturn, tt.giveClientErr)
+			}
+
+			connClosed := false
+			mockConn := mocks.NewConnector(t)
+			mockConn.EXPECT().NewConn().Return(
+				&grpc.ClientConn{},
+				func() { connClosed = true },
+				tt.giveConnectorErr,
+			)
+
+			c, _ := NewWithArgs(
+				mockConn,
+				pb.NewNvmeRemoteControllerServiceClient,
+				pb.NewNullVolumeServiceClient,
+				pb.NewAioVolumeServiceClient,
+				func(grpc.ClientConnInterface) pb.MallocVolumeServiceClient {
+					return mockClient
+				},
+			)
+
+			response, err := c.UpdateMallocVolume(
+				ctx,
+				proto.Clone(testMallocVolume).(*pb.MallocVolume),
+				[]string{"blocks_count"},
+				false,
+			)
+
+			require.Equal(t, tt.wantErr, err)
+			require.True(t, proto.Equal(response, tt.wantResponse))
+			require.Equal(t, tt.wantConnClosed, connClosed)
+		})
+	}
+}
+
+func TestStatsMallocVolume(t *testing.T) {
+	testRequest := &pb.StatsMallocVolumeRequest{
+		Name: testMallocVolume.Name,
+	}
+	testStats := &pb.VolumeStats{
+		ReadBytesCount: 4096,
+		ReadOpsCount:   8,
+	}
+
+	tests := map[string]struct {
+		giveClientErr    error
+		giveConnectorErr error
+		wantErr          error
+		wantRequest      *pb.StatsMallocVolumeRequest
+		wantResponse     *pb.VolumeStats
+		wantConnClosed   bool
+	}{
+		"successful call": {
+			giveConnectorErr: nil,
+			giveClientErr:    nil,
+			wantErr:          nil,
+			wantRequest:      proto.Clone(testRequest).(*pb.StatsMallocVolumeRequest),
+			wantResponse:     proto.Clone(testStats).(*pb.VolumeStats),
+			wantConnClosed:   true,
+		},
+		"client err": {
+			giveConnectorErr: nil,
+			giveClientErr:    errors.New("Some client error"),
+			wantErr:          errors.New("Some client error"),
+			wantRequest:      proto.Clone(testRequest).(*pb.StatsMallocVolumeRequest),
+			wantResponse:     nil,
+			wantConnClosed:   true,
+		},
+		"connector err": {
+			giveConnectorErr: errors.New("Some conn error"),
+			giveClientErr:    nil,
+			wantErr:          errors.New("Some conn error"),
+			wantRequest:      nil,
+			wantResponse:     nil,
+			wantConnClosed:   false,
+		},
+	}
+
+	for testName, tt := range tests {
+		t.Run(testName, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
+
+			mockClient := mocks.NewMallocVolumeServiceClient(t)
+			if tt.wantRequest != nil {
+				var toReturn *pb.StatsMallocVolumeResponse
+				if tt.giveClientErr == nil {
+					toReturn = &pb.StatsMallocVolumeResponse{Stats: proto.Clone(tt.wantResponse).(*pb.VolumeStats)}
+				}
+				mockClient.EXPECT().StatsMallocVolume(ctx, tt.wantRequest).
+					Return(toReturn, tt.giveClientErr)
+			}
+
+			connClosed := false
+			mockConn := mocks.NewConnector(t)
+			mockConn.EXPECT().NewConn().Return(
+				&grpc.ClientConn{},
+				func() { connClosed = true },
+				tt.giveConnectorErr,
+			)
+
+			c, _ := NewWithArgs(
+				mockConn,
+				pb.NewNvmeRemoteControllerServiceClient,
+				pb.NewNullVolumeServiceClient,
+				pb.NewAioVolumeServiceClient,
+				func(grpc.ClientConnInterface) pb.MallocVolumeServiceClient {
+					return mockClient
+				},
+			)
+
+			response, err := c.StatsMallocVolume(ctx, testMallocVolume.Name)
+
+			require.Equal(t, tt.wantErr, err)
+			require.True(t, proto.Equal(response, tt.wantResponse))
+			require.Equal(t, tt.wantConnClosed, connClosed)
+		})
+	}
+}
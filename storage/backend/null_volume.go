@@ -0,0 +1,149 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (C) 2024 Intel Corporation
+
+// Package backend implements the go library for OPI backend storage
+package backend
+
+import (
+	"context"
+
+	pb "github.com/opiproject/opi-api/storage/v1alpha1/gen/go"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+)
+
+// CreateNullVolume creates a null volume discarding writes and
+// returning zeroes on reads
+func (c *Client) CreateNullVolume(
+	ctx context.Context,
+	id string,
+	blockSize int64,
+	blocksCount int64,
+) (*pb.NullVolume, error) {
+	conn, connClose, err := c.connector.NewConn()
+	if err != nil {
+		return nil, err
+	}
+	defer connClose()
+
+	client := c.createNullClient(conn)
+	return client.CreateNullVolume(
+		ctx,
+		&pb.CreateNullVolumeRequest{
+			NullVolumeId: id,
+			NullVolume: &pb.NullVolume{
+				BlockSize:   blockSize,
+				BlocksCount: blocksCount,
+			},
+		})
+}
+
+// DeleteNullVolume deletes a null volume
+func (c *Client) DeleteNullVolume(
+	ctx context.Context,
+	name string,
+	allowMissing bool,
+) error {
+	conn, connClose, err := c.connector.NewConn()
+	if err != nil {
+		return err
+	}
+	defer connClose()
+
+	client := c.createNullClient(conn)
+	_, err = client.DeleteNullVolume(
+		ctx,
+		&pb.DeleteNullVolumeRequest{
+			Name:         name,
+			AllowMissing: allowMissing,
+		})
+
+	return err
+}
+
+// GetNullVolume gets a null volume
+func (c *Client) GetNullVolume(
+	ctx context.Context,
+	name string,
+) (*pb.NullVolume, error) {
+	conn, connClose, err := c.connector.NewConn()
+	if err != nil {
+		return nil, err
+	}
+	defer connClose()
+
+	client := c.createNullClient(conn)
+	return client.GetNullVolume(
+		ctx,
+		&pb.GetNullVolumeRequest{
+			Name: name,
+		})
+}
+
+// ListNullVolumes lists a page of null volumes
+func (c *Client) ListNullVolumes(
+	ctx context.Context,
+	pageSize int32,
+	pageToken string,
+) (*pb.ListNullVolumesResponse, error) {
+	conn, connClose, err := c.connector.NewConn()
+	if err != nil {
+		return nil, err
+	}
+	defer connClose()
+
+	client := c.createNullClient(conn)
+	return client.ListNullVolumes(
+		ctx,
+		&pb.ListNullVolumesRequest{
+			PageSize:  pageSize,
+			PageToken: pageToken,
+		})
+}
+
+// UpdateNullVolume updates the fields of a null volume listed in updateMask.
+// All fields are updated if updateMask is empty.
+func (c *Client) UpdateNullVolume(
+	ctx context.Context,
+	volume *pb.NullVolume,
+	updateMask []string,
+	allowMissing bool,
+) (*pb.NullVolume, error) {
+	conn, connClose, err := c.connector.NewConn()
+	if err != nil {
+		return nil, err
+	}
+	defer connClose()
+
+	client := c.createNullClient(conn)
+	return client.UpdateNullVolume(
+		ctx,
+		&pb.UpdateNullVolumeRequest{
+			NullVolume:   volume,
+			UpdateMask:   &fieldmaskpb.FieldMask{Paths: updateMask},
+			AllowMissing: allowMissing,
+		})
+}
+
+// StatsNullVolume gets io statistics of a null volume
+func (c *Client) StatsNullVolume(
+	ctx context.Context,
+	name string,
+) (*pb.VolumeStats, error) {
+	conn, connClose, err := c.connector.NewConn()
+	if err != nil {
+		return nil, err
+	}
+	defer connClose()
+
+	client := c.createNullClient(conn)
+	response, err := client.StatsNullVolume(
+		ctx,
+		&pb.StatsNullVolumeRequest{
+			Name: name,
+		})
+	if err != nil {
+		return nil, err
+	}
+
+	return response.GetStats(), nil
+}
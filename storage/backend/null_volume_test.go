@@ -0,0 +1,517 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (C) 2024 Intel Corporation
+
+// Package backend implements the go library for OPI backend storage
+package backend
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/opiproject/godpu/mocks"
+	pb "github.com/opiproject/opi-api/storage/v1alpha1/gen/go"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+)
+
+var testNullVolume = &pb.NullVolume{
+	Name:        "nullVolumes/null0",
+	BlockSize:   512,
+	BlocksCount: 64,
+}
+
+func TestCreateNullVolume(t *testing.T) {
+	testRequest := &pb.CreateNullVolumeRequest{
+		NullVolumeId: "null0",
+		NullVolume: &pb.NullVolume{
+			BlockSize:   testNullVolume.BlockSize,
+			BlocksCount: testNullVolume.BlocksCount,
+		},
+	}
+
+	tests := map[string]struct {
+		giveClientErr    error
+		giveConnectorErr error
+		wantErr          error
+		wantRequest      *pb.CreateNullVolumeRequest
+		wantResponse     *pb.NullVolume
+		wantConnClosed   bool
+	}{
+		"successful call": {
+			giveConnectorErr: nil,
+			giveClientErr:    nil,
+			wantErr:          nil,
+			wantRequest:      proto.Clone(testRequest).(*pb.CreateNullVolumeRequest),
+			wantResponse:     proto.Clone(testNullVolume).(*pb.NullVolume),
+			wantConnClosed:   true,
+		},
+		"client err": {
+			giveConnectorErr: nil,
+			giveClientErr:    errors.New("Some client error"),
+			wantErr:          errors.New("Some client error"),
+			wantRequest:      proto.Clone(testRequest).(*pb.CreateNullVolumeRequest),
+			wantResponse:     nil,
+			wantConnClosed:   true,
+		},
+		"connector err": {
+			giveConnectorErr: errors.New("Some conn error"),
+			giveClientErr:    nil,
+			wantErr:          errors.New("Some conn error"),
+			wantRequest:      nil,
+			wantResponse:     nil,
+			wantConnClosed:   false,
+		},
+	}
+
+	for testName, tt := range tests {
+		t.Run(testName, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
+
+			mockClient := mocks.NewNullVolumeServiceClient(t)
+			if tt.wantRequest != nil {
+				toReturn := proto.Clone(tt.wantResponse).(*pb.NullVolume)
+				mockClient.EXPECT().CreateNullVolume(ctx, tt.wantRequest).
+					Return(toReturn, tt.giveClientErr)
+			}
+
+			connClosed := false
+			mockConn := mocks.NewConnector(t)
+			mockConn.EXPECT().NewConn().Return(
+				&grpc.ClientConn{},
+				func() { connClosed = true },
+				tt.giveConnectorErr,
+			)
+
+			c, _ := NewWithArgs(
+				mockConn,
+				pb.NewNvmeRemoteControllerServiceClient,
+				func(grpc.ClientConnInterface) pb.NullVolumeServiceClient {
+					return mockClient
+				},
+				pb.NewAioVolumeServiceClient,
+				pb.NewMallocVolumeServiceClient,
+			)
+
+			response, err := c.CreateNullVolume(
+				ctx,
+				"null0",
+				testNullVolume.BlockSize,
+				testNullVolume.BlocksCount,
+			)
+
+			require.Equal(t, tt.wantErr, err)
+			require.True(t, proto.Equal(response, tt.wantResponse))
+			require.Equal(t, tt.wantConnClosed, connClosed)
+		})
+	}
+}
+
+func TestDeleteNullVolume(t *testing.T) {
+	testRequest := &pb.DeleteNullVolumeRequest{
+		Name:         testNullVolume.Name,
+		AllowMissing: true,
+	}
+
+	tests := map[string]struct {
+		giveClientErr    error
+		giveConnectorErr error
+		wantErr          error
+		wantRequest      *pb.DeleteNullVolumeRequest
+		wantConnClosed   bool
+	}{
+		"successful call": {
+			giveConnectorErr: nil,
+			giveClientErr:    nil,
+			wantErr:          nil,
+			wantRequest:      proto.Clone(testRequest).(*pb.DeleteNullVolumeRequest),
+			wantConnClosed:   true,
+		},
+		"client err": {
+			giveConnectorErr: nil,
+			giveClientErr:    errors.New("Some client error"),
+			wantErr:          errors.New("Some client error"),
+			wantRequest:      proto.Clone(testRequest).(*pb.DeleteNullVolumeRequest),
+			wantConnClosed:   true,
+		},
+		"connector err": {
+			giveConnectorErr: errors.New("Some conn error"),
+			giveClientErr:    nil,
+			wantErr:          errors.New("Some conn error"),
+			wantRequest:      nil,
+			wantConnClosed:   false,
+		},
+	}
+
+	for testName, tt := range tests {
+		t.Run(testName, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
+
+			mockClient := mocks.NewNullVolumeServiceClient(t)
+			if tt.wantRequest != nil {
+				mockClient.EXPECT().DeleteNullVolume(ctx, tt.wantRequest).
+					Return(&emptypb.Empty{}, tt.giveClientErr)
+			}
+
+			connClosed := false
+			mockConn := mocks.NewConnector(t)
+			mockConn.EXPECT().NewConn().Return(
+				&grpc.ClientConn{},
+				func() { connClosed = true },
+				tt.giveConnectorErr,
+			)
+
+			c, _ := NewWithArgs(
+				mockConn,
+				pb.NewNvmeRemoteControllerServiceClient,
+				func(grpc.ClientConnInterface) pb.NullVolumeServiceClient {
+					return mockClient
+				},
+				pb.NewAioVolumeServiceClient,
+				pb.NewMallocVolumeServiceClient,
+			)
+
+			err := c.DeleteNullVolume(ctx, testNullVolume.Name, true)
+
+			require.Equal(t, tt.wantErr, err)
+			require.Equal(t, tt.wantConnClosed, connClosed)
+		})
+	}
+}
+
+func TestGetNullVolume(t *testing.T) {
+	testRequest := &pb.GetNullVolumeRequest{
+		Name: testNullVolume.Name,
+	}
+
+	tests := map[string]struct {
+		giveClientErr    error
+		giveConnectorErr error
+		wantErr          error
+		wantRequest      *pb.GetNullVolumeRequest
+		wantResponse     *pb.NullVolume
+		wantConnClosed   bool
+	}{
+		"successful call": {
+			giveConnectorErr: nil,
+			giveClientErr:    nil,
+			wantErr:          nil,
+			wantRequest:      proto.Clone(testRequest).(*pb.GetNullVolumeRequest),
+			wantResponse:     proto.Clone(testNullVolume).(*pb.NullVolume),
+			wantConnClosed:   true,
+		},
+		"client err": {
+			giveConnectorErr: nil,
+			giveClientErr:    errors.New("Some client error"),
+			wantErr:          errors.New("Some client error"),
+			wantRequest:      proto.Clone(testRequest).(*pb.GetNullVolumeRequest),
+			wantResponse:     nil,
+			wantConnClosed:   true,
+		},
+		"connector err": {
+			giveConnectorErr: errors.New("Some conn error"),
+			giveClientErr:    nil,
+			wantErr:          errors.New("Some conn error"),
+			wantRequest:      nil,
+			wantResponse:     nil,
+			wantConnClosed:   false,
+		},
+	}
+
+	for testName, tt := range tests {
+		t.Run(testName, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
+
+			mockClient := mocks.NewNullVolumeServiceClient(t)
+			if tt.wantRequest != nil {
+				toReturn := proto.Clone(tt.wantResponse).(*pb.NullVolume)
+				mockClient.EXPECT().GetNullVolume(ctx, tt.wantRequest).
+					Return(toReturn, tt.giveClientErr)
+			}
+
+			connClosed := false
+			mockConn := mocks.NewConnector(t)
+			mockConn.EXPECT().NewConn().Return(
+				&grpc.ClientConn{},
+				func() { connClosed = true },
+				tt.giveConnectorErr,
+			)
+
+			c, _ := NewWithArgs(
+				mockConn,
+				pb.NewNvmeRemoteControllerServiceClient,
+				func(grpc.ClientConnInterface) pb.NullVolumeServiceClient {
+					return mockClient
+				},
+				pb.NewAioVolumeServiceClient,
+				pb.NewMallocVolumeServiceClient,
+			)
+
+			response, err := c.GetNullVolume(ctx, testNullVolume.Name)
+
+			require.Equal(t, tt.wantErr, err)
+			require.True(t, proto.Equal(response, tt.wantResponse))
+			require.Equal(t, tt.wantConnClosed, connClosed)
+		})
+	}
+}
+
+func TestListNullVolumes(t *testing.T) {
+	testRequest := &pb.ListNullVolumesRequest{
+		PageSize:  10,
+		PageToken: "token",
+	}
+	testResponse := &pb.ListNullVolumesResponse{
+		NullVolumes:   []*pb.NullVolume{testNullVolume},
+		NextPageToken: "next",
+	}
+
+	tests := map[string]struct {
+		giveClientErr    error
+		giveConnectorErr error
+		wantErr          error
+		wantRequest      *pb.ListNullVolumesRequest
+		wantResponse     *pb.ListNullVolumesResponse
+		wantConnClosed   bool
+	}{
+		"successful call": {
+			giveConnectorErr: nil,
+			giveClientErr:    nil,
+			wantErr:          nil,
+			wantRequest:      proto.Clone(testRequest).(*pb.ListNullVolumesRequest),
+			wantResponse:     proto.Clone(testResponse).(*pb.ListNullVolumesResponse),
+			wantConnClosed:   true,
+		},
+		"client err": {
+			giveConnectorErr: nil,
+			giveClientErr:    errors.New("Some client error"),
+			wantErr:          errors.New("Some client error"),
+			wantRequest:      proto.Clone(testRequest).(*pb.ListNullVolumesRequest),
+			wantResponse:     nil,
+			wantConnClosed:   true,
+		},
+		"connector err": {
+			giveConnectorErr: errors.New("Some conn error"),
+			giveClientErr:    nil,
+			wantErr:          errors.New("Some conn error"),
+			wantRequest:      nil,
+			wantResponse:     nil,
+			wantConnClosed:   false,
+		},
+	}
+
+	for testName, tt := range tests {
+		t.Run(testName, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
+
+			mockClient := mocks.NewNullVolumeServiceClient(t)
+			if tt.wantRequest != nil {
+				toReturn := proto.Clone(tt.wantResponse).(*pb.ListNullVolumesResponse)
+				mockClient.EXPECT().ListNullVolumes(ctx, tt.wantRequest).
+					Return(toReturn, tt.giveClientErr)
+			}
+
+			connClosed := false
+			mockConn := mocks.NewConnector(t)
+			mockConn.EXPECT().NewConn().Return(
+				&grpc.ClientConn{},
+				func() { connClosed = true },
+				tt.giveConnectorErr,
+			)
+
+			c, _ := NewWithArgs(
+				mockConn,
+				pb.NewNvmeRemoteControllerServiceClient,
+				func(grpc.ClientConnInterface) pb.NullVolumeServiceClient {
+					return mockClient
+				},
+				pb.NewAioVolumeServiceClient,
+				pb.NewMallocVolumeServiceClient,
+			)
+
+			response, err := c.ListNullVolumes(ctx, 10, "token")
+
+			require.Equal(t, tt.wantErr, err)
+			require.True(t, proto.Equal(response, tt.wantResponse))
+			require.Equal(t, tt.wantConnClosed, connClosed)
+		})
+	}
+}
+
+func TestUpdateNullVolume(t *testing.T) {
+	testRequest := &pb.UpdateNullVolumeRequest{
+		NullVolume:   proto.Clone(testNullVolume).(*pb.NullVolume),
+		UpdateMask:   &fieldmaskpb.FieldMask{Paths: []string{"blocks_count"}},
+		AllowMissing: false,
+	}
+
+	tests := map[string]struct {
+		giveClientErr    error
+		giveConnectorErr error
+		wantErr          error
+		wantRequest      *pb.UpdateNullVolumeRequest
+		wantResponse     *pb.NullVolume
+		wantConnClosed   bool
+	}{
+		"successful call": {
+			giveConnectorErr: nil,
+			giveClientErr:    nil,
+			wantErr:          nil,
+			wantRequest:      proto.Clone(testRequest).(*pb.UpdateNullVolumeRequest),
+			wantResponse:     proto.Clone(testNullVolume).(*pb.NullVolume),
+			wantConnClosed:   true,
+		},
+		"client err": {
+			giveConnectorErr: nil,
+			giveClientErr:    errors.New("Some client error"),
+			wantErr:          errors.New("Some client error"),
+			wantRequest:      proto.Clone(testRequest).(*pb.UpdateNullVolumeRequest),
+			wantResponse:     nil,
+			wantConnClosed:   true,
+		},
+		"connector err": {
+			giveConnectorErr: errors.New("Some conn error"),
+			giveClientErr:    nil,
+			wantErr:          errors.New("Some conn error"),
+			wantRequest:      nil,
+			wantResponse:     nil,
+			wantConnClosed:   false,
+		},
+	}
+
+	for testName, tt := range tests {
+		t.Run(testName, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
+
+			mockClient := mocks.NewNullVolumeServiceClient(t)
+			if tt.wantRequest != nil {
+				toReturn := proto.Clone(tt.wantResponse).(*pb.NullVolume)
+				mockClient.EXPECT().UpdateNullVolume(ctx, tt.wantRequest).
+					Return(toReturn, tt.giveClientErr)
+			}
+
+			connClosed := false
+			mockConn := mocks.NewConnector(t)
+			mockConn.EXPECT().NewConn().Return(
+				&grpc.ClientConn{},
+				func() { connClosed = true },
+				tt.giveConnectorErr,
+			)
+
+			c, _ := NewWithArgs(
+				mockConn,
+				pb.NewNvmeRemoteControllerServiceClient,
+				func(grpc.ClientConnInterface) pb.NullVolumeServiceClient {
+					return mockClient
+				},
+				pb.NewAioVolumeServiceClient,
+				pb.NewMallocVolumeServiceClient,
+			)
+
+			response, err := c.UpdateNullVolume(
+				ctx,
+				proto.Clone(testNullVolume).(*pb.NullVolume),
+				[]string{"blocks_count"},
+				false,
+			)
+
+			require.Equal(t, tt.wantErr, err)
+			require.True(t, proto.Equal(response, tt.wantResponse))
+			require.Equal(t, tt.wantConnClosed, connClosed)
+		})
+	}
+}
+
+func TestStatsNullVolume(t *testing.T) {
+	testRequest := &pb.StatsNullVolumeRequest{
+		Name: testNullVolume.Name,
+	}
+	testStats := &pb.VolumeStats{
+		ReadBytesCount: 4096,
+		ReadOpsCount:   8,
+	}
+
+	tests := map[string]struct {
+		giveClientErr    error
+		giveConnectorErr error
+		wantErr          error
+		wantRequest      *pb.StatsNullVolumeRequest
+		wantResponse     *pb.VolumeStats
+		wantConnClosed   bool
+	}{
+		"successful call": {
+			giveConnectorErr: nil,
+			giveClientErr:    nil,
+			wantErr:          nil,
+			wantRequest:      proto.Clone(testRequest).(*pb.StatsNullVolumeRequest),
+			wantResponse:     proto.Clone(testStats).(*pb.VolumeStats),
+			wantConnClosed:   true,
+		},
+		"client err": {
+			giveConnectorErr: nil,
+			giveClientErr:    errors.New("Some client error"),
+			wantErr:          errors.New("Some client error"),
+			wantRequest:      proto.Clone(testRequest).(*pb.StatsNullVolumeRequest),
+			wantResponse:     nil,
+			wantConnClosed:   true,
+		},
+		"connector err": {
+			giveConnectorErr: errors.New("Some conn error"),
+			giveClientErr:    nil,
+			wantErr:          errors.New("Some conn error"),
+			wantRequest:      nil,
+			wantResponse:     nil,
+			wantConnClosed:   false,
+		},
+	}
+
+	for testName, tt := range tests {
+		t.Run(testName, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
+
+			mockClient := mocks.NewNullVolumeServiceClient(t)
+			if tt.wantRequest != nil {
+				var toReturn *pb.StatsNullVolumeResponse
+				if tt.giveClientErr == nil {
+					toReturn = &pb.StatsNullVolumeResponse{Stats: proto.Clone(tt.wantResponse).(*pb.VolumeStats)}
+				}
+				mockClient.EXPECT().StatsNullVolume(ctx, tt.wantRequest).
+					Return(toReturn, tt.giveClientErr)
+			}
+
+			connClosed := false
+			mockConn := mocks.NewConnector(t)
+			mockConn.EXPECT().NewConn().Return(
+				&grpc.ClientConn{},
+				func() { connClosed = true },
+				tt.giveConnectorErr,
+			)
+
+			c, _ := NewWithArgs(
+				mockConn,
+				pb.NewNvmeRemoteControllerServiceClient,
+				func(grpc.ClientConnInterface) pb.NullVolumeServiceClient {
+					return mockClient
+				},
+				pb.NewAioVolumeServiceClient,
+				pb.NewMallocVolumeServiceClient,
+			)
+
+			response, err := c.StatsNullVolume(ctx, testNullVolume.Name)
+
+			require.Equal(t, tt.wantErr, err)
+			require.True(t, proto.Equal(response, tt.wantResponse))
+			require.Equal(t, tt.wantConnClosed, connClosed)
+		})
+	}
+}
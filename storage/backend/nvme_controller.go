@@ -8,14 +8,37 @@ import (
 	"context"
 
 	pb "github.com/opiproject/opi-api/storage/v1alpha1/gen/go"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
 )
 
+// NvmeControllerOptions defines optional settings of an nvme controller
+// representing an external nvme device. Zero values leave the settings to
+// the server defaults.
+type NvmeControllerOptions struct {
+	IoQueuesCount int64
+	QueueSize     int64
+	// HeaderDigest and DataDigest enable digests on nvme/tcp connections
+	HeaderDigest bool
+	DataDigest   bool
+}
+
 // CreateNvmeController creates an nvme controller representing
 // an external nvme device
 func (c *Client) CreateNvmeController(
 	ctx context.Context,
 	id string,
 	multipath pb.NvmeMultipath,
+) (*pb.NvmeRemoteController, error) {
+	return c.CreateNvmeControllerWithOptions(ctx, id, multipath, NvmeControllerOptions{})
+}
+
+// CreateNvmeControllerWithOptions creates an nvme controller representing
+// an external nvme device with non-default queue and transport settings
+func (c *Client) CreateNvmeControllerWithOptions(
+	ctx context.Context,
+	id string,
+	multipath pb.NvmeMultipath,
+	opts NvmeControllerOptions,
 ) (*pb.NvmeRemoteController, error) {
 	conn, connClose, err := c.connector.NewConn()
 	if err != nil {
@@ -28,9 +51,7 @@ func (c *Client) CreateNvmeController(
 		ctx,
 		&pb.CreateNvmeRemoteControllerRequest{
 			NvmeRemoteControllerId: id,
-			NvmeRemoteController: &pb.NvmeRemoteController{
-				Multipath: multipath,
-			},
+			NvmeRemoteController:   newNvmeRemoteController(multipath, opts),
 		})
 
 	return response, err
@@ -79,3 +100,115 @@ func (c *Client) GetNvmeController(
 			Name: name,
 		})
 }
+
+// ListNvmeControllers lists a page of nvme controllers representing
+// external nvme devices
+func (c *Client) ListNvmeControllers(
+	ctx context.Context,
+	pageSize int32,
+	pageToken string,
+) (*pb.ListNvmeRemoteControllersResponse, error) {
+	conn, connClose, err := c.connector.NewConn()
+	if err != nil {
+		return nil, err
+	}
+	defer connClose()
+
+	client := c.createNvmeClient(conn)
+	return client.ListNvmeRemoteControllers(
+		ctx,
+		&pb.ListNvmeRemoteControllersRequest{
+			PageSize:  pageSize,
+			PageToken: pageToken,
+		})
+}
+
+// UpdateNvmeController updates the fields of an nvme controller listed in
+// updateMask. All fields are updated if updateMask is empty.
+func (c *Client) UpdateNvmeController(
+	ctx context.Context,
+	controller *pb.NvmeRemoteController,
+	updateMask []string,
+	allowMissing bool,
+) (*pb.NvmeRemoteController, error) {
+	conn, connClose, err := c.connector.NewConn()
+	if err != nil {
+		return nil, err
+	}
+	defer connClose()
+
+	client := c.createNvmeClient(conn)
+	return client.UpdateNvmeRemoteController(
+		ctx,
+		&pb.UpdateNvmeRemoteControllerRequest{
+			NvmeRemoteController: controller,
+			UpdateMask:           &fieldmaskpb.FieldMask{Paths: updateMask},
+			AllowMissing:         allowMissing,
+		})
+}
+
+// ResetNvmeController resets an nvme controller representing
+// an external nvme device. It is used to recover stuck controllers.
+func (c *Client) ResetNvmeController(
+	ctx context.Context,
+	name string,
+) error {
+	conn, connClose, err := c.connector.NewConn()
+	if err != nil {
+		return err
+	}
+	defer connClose()
+
+	client := c.createNvmeClient(conn)
+	_, err = client.ResetNvmeRemoteController(
+		ctx,
+		&pb.ResetNvmeRemoteControllerRequest{
+			Name: name,
+		})
+
+	return err
+}
+
+// StatsNvmeController gets io statistics of an nvme controller
+// representing an external nvme device
+func (c *Client) StatsNvmeController(
+	ctx context.Context,
+	name string,
+) (*pb.VolumeStats, error) {
+	conn, connClose, err := c.connector.NewConn()
+	if err != nil {
+		return nil, err
+	}
+	defer connClose()
+
+	client := c.createNvmeClient(conn)
+	response, err := client.StatsNvmeRemoteController(
+		ctx,
+		&pb.StatsNvmeRemoteControllerRequest{
+			Name: name,
+		})
+	if err != nil {
+		return nil, err
+	}
+
+	return response.GetStats(), nil
+}
+
+func newNvmeRemoteController(
+	multipath pb.NvmeMultipath,
+	opts NvmeControllerOptions,
+) *pb.NvmeRemoteController {
+	controller := &pb.NvmeRemoteController{
+		Multipath:     multipath,
+		IoQueuesCount: opts.IoQueuesCount,
+		QueueSize:     opts.QueueSize,
+	}
+	if opts.HeaderDigest || opts.DataDigest {
+		controller.Tcp = &pb.TcpController{
+			Hdgst: opts.HeaderDigest,
+			Ddgst: opts.DataDigest,
+		}
+	}
+
+	return controller
+}
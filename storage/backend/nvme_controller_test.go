@@ -16,6 +16,7 @@ import (
 	"google.golang.org/grpc"
 	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
 )
 
 func TestCreateNvmeController(t *testing.T) {
@@ -89,6 +90,9 @@ func TestCreateNvmeController(t *testing.T) {
 				func(grpc.ClientConnInterface) pb.NvmeRemoteControllerServiceClient {
 					return mockClient
 				},
+				pb.NewNullVolumeServiceClient,
+				pb.NewAioVolumeServiceClient,
+				pb.NewMallocVolumeServiceClient,
 			)
 
 			response, err := c.CreateNvmeController(
@@ -164,6 +168,9 @@ func TestDeleteNvmeController(t *testing.T) {
 				func(grpc.ClientConnInterface) pb.NvmeRemoteControllerServiceClient {
 					return mockClient
 				},
+				pb.NewNullVolumeServiceClient,
+				pb.NewAioVolumeServiceClient,
+				pb.NewMallocVolumeServiceClient,
 			)
 
 			err := c.DeleteNvmeController(ctx, testControllerName, true)
@@ -241,6 +248,9 @@ func TestGetNvmeController(t *testing.T) {
 				func(grpc.ClientConnInterface) pb.NvmeRemoteControllerServiceClient {
 					return mockClient
 				},
+				pb.NewNullVolumeServiceClient,
+				pb.NewAioVolumeServiceClient,
+				pb.NewMallocVolumeServiceClient,
 			)
 
 			response, err := c.GetNvmeController(ctx, testControllerName)
@@ -251,3 +261,436 @@ func TestGetNvmeController(t *testing.T) {
 		})
 	}
 }
+
+func TestCreateNvmeControllerWithOptions(t *testing.T) {
+	tests := map[string]struct {
+		giveOptions      NvmeControllerOptions
+		giveClientErr    error
+		giveConnectorErr error
+		wantErr          error
+		wantRequest      *pb.CreateNvmeRemoteControllerRequest
+		wantConnClosed   bool
+	}{
+		"tcp digests and queues": {
+			giveOptions: NvmeControllerOptions{
+				IoQueuesCount: 4,
+				QueueSize:     128,
+				HeaderDigest:  true,
+				DataDigest:    true,
+			},
+			giveConnectorErr: nil,
+			giveClientErr:    nil,
+			wantErr:          nil,
+			wantRequest: &pb.CreateNvmeRemoteControllerRequest{
+				NvmeRemoteControllerId: "remotenvme0",
+				NvmeRemoteController: &pb.NvmeRemoteController{
+					Multipath:     pb.NvmeMultipath_NVME_MULTIPATH_MULTIPATH,
+					IoQueuesCount: 4,
+					QueueSize:     128,
+					Tcp:           &pb.TcpController{Hdgst: true, Ddgst: true},
+				},
+			},
+			wantConnClosed: true,
+		},
+		"only queues": {
+			giveOptions: NvmeControllerOptions{
+				IoQueuesCount: 2,
+				QueueSize:     64,
+			},
+			giveConnectorErr: nil,
+			giveClientErr:    nil,
+			wantErr:          nil,
+			wantRequest: &pb.CreateNvmeRemoteControllerRequest{
+				NvmeRemoteControllerId: "remotenvme0",
+				NvmeRemoteController: &pb.NvmeRemoteController{
+					Multipath:     pb.NvmeMultipath_NVME_MULTIPATH_MULTIPATH,
+					IoQueuesCount: 2,
+					QueueSize:     64,
+				},
+			},
+			wantConnClosed: true,
+		},
+		"connector err": {
+			giveOptions:      NvmeControllerOptions{},
+			giveConnectorErr: errors.New("Some conn error"),
+			giveClientErr:    nil,
+			wantErr:          errors.New("Some conn error"),
+			wantRequest:      nil,
+			wantConnClosed:   false,
+		},
+	}
+
+	for testName, tt := range tests {
+		t.Run(testName, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
+
+			mockClient := mocks.NewNvmeRemoteControllerServiceClient(t)
+			var wantResponse *pb.NvmeRemoteController
+			if tt.wantRequest != nil {
+				wantResponse = proto.Clone(tt.wantRequest.NvmeRemoteController).(*pb.NvmeRemoteController)
+				mockClient.EXPECT().CreateNvmeRemoteController(ctx, tt.wantRequest).
+					Return(proto.Clone(wantResponse).(*pb.NvmeRemoteController), tt.giveClientErr)
+			}
+
+			connClosed := false
+			mockConn := mocks.NewConnector(t)
+			mockConn.EXPECT().NewConn().Return(
+				&grpc.ClientConn{},
+				func() { connClosed = true },
+				tt.giveConnectorErr,
+			)
+
+			c, _ := NewWithArgs(
+				mockConn,
+				func(grpc.ClientConnInterface) pb.NvmeRemoteControllerServiceClient {
+					return mockClient
+				},
+				pb.NewNullVolumeServiceClient,
+				pb.NewAioVolumeServiceClient,
+				pb.NewMallocVolumeServiceClient,
+			)
+
+			response, err := c.CreateNvmeControllerWithOptions(
+				ctx,
+				"remotenvme0",
+				pb.NvmeMultipath_NVME_MULTIPATH_MULTIPATH,
+				tt.giveOptions,
+			)
+
+			require.Equal(t, tt.wantErr, err)
+			require.True(t, proto.Equal(wantResponse, response))
+			require.Equal(t, tt.wantConnClosed, connClosed)
+		})
+	}
+}
+
+func TestListNvmeControllers(t *testing.T) {
+	testRequest := &pb.ListNvmeRemoteControllersRequest{
+		PageSize:  10,
+		PageToken: "token",
+	}
+	testResponse := &pb.ListNvmeRemoteControllersResponse{
+		NvmeRemoteControllers: []*pb.NvmeRemoteController{
+			{Name: "nvmeRemoteControllers/remotenvme0"},
+		},
+		NextPageToken: "next",
+	}
+
+	tests := map[string]struct {
+		giveClientErr    error
+		giveConnectorErr error
+		wantErr          error
+		wantRequest      *pb.ListNvmeRemoteControllersRequest
+		wantResponse     *pb.ListNvmeRemoteControllersResponse
+		wantConnClosed   bool
+	}{
+		"successful call": {
+			giveConnectorErr: nil,
+			giveClientErr:    nil,
+			wantErr:          nil,
+			wantRequest:      proto.Clone(testRequest).(*pb.ListNvmeRemoteControllersRequest),
+			wantResponse:     proto.Clone(testResponse).(*pb.ListNvmeRemoteControllersResponse),
+			wantConnClosed:   true,
+		},
+		"client err": {
+			giveConnectorErr: nil,
+			giveClientErr:    errors.New("Some client error"),
+			wantErr:          errors.New("Some client error"),
+			wantRequest:      proto.Clone(testRequest).(*pb.ListNvmeRemoteControllersRequest),
+			wantResponse:     nil,
+			wantConnClosed:   true,
+		},
+		"connector err": {
+			giveConnectorErr: errors.New("Some conn error"),
+			giveClientErr:    nil,
+			wantErr:          errors.New("Some conn error"),
+			wantRequest:      nil,
+			wantResponse:     nil,
+			wantConnClosed:   false,
+		},
+	}
+
+	for testName, tt := range tests {
+		t.Run(testName, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
+
+			mockClient := mocks.NewNvmeRemoteControllerServiceClient(t)
+			if tt.wantRequest != nil {
+				mockClient.EXPECT().ListNvmeRemoteControllers(ctx, tt.wantRequest).
+					Return(proto.Clone(tt.wantResponse).(*pb.ListNvmeRemoteControllersResponse), tt.giveClientErr)
+			}
+
+			connClosed := false
+			mockConn := mocks.NewConnector(t)
+			mockConn.EXPECT().NewConn().Return(
+				&grpc.ClientConn{},
+				func() { connClosed = true },
+				tt.giveConnectorErr,
+			)
+
+			c, _ := NewWithArgs(
+				mockConn,
+				func(grpc.ClientConnInterface) pb.NvmeRemoteControllerServiceClient {
+					return mockClient
+				},
+				pb.NewNullVolumeServiceClient,
+				pb.NewAioVolumeServiceClient,
+				pb.NewMallocVolumeServiceClient,
+			)
+
+			response, err := c.ListNvmeControllers(ctx, 10, "token")
+
+			require.Equal(t, tt.wantErr, err)
+			require.True(t, proto.Equal(tt.wantResponse, response))
+			require.Equal(t, tt.wantConnClosed, connClosed)
+		})
+	}
+}
+
+func TestUpdateNvmeController(t *testing.T) {
+	testController := &pb.NvmeRemoteController{
+		Name:      "nvmeRemoteControllers/remotenvme0",
+		Multipath: pb.NvmeMultipath_NVME_MULTIPATH_FAILOVER,
+		QueueSize: 256,
+	}
+	testRequest := &pb.UpdateNvmeRemoteControllerRequest{
+		NvmeRemoteController: proto.Clone(testController).(*pb.NvmeRemoteController),
+		UpdateMask:           &fieldmaskpb.FieldMask{Paths: []string{"queue_size"}},
+		AllowMissing:         false,
+	}
+
+	tests := map[string]struct {
+		giveClientErr    error
+		giveConnectorErr error
+		wantErr          error
+		wantRequest      *pb.UpdateNvmeRemoteControllerRequest
+		wantResponse     *pb.NvmeRemoteController
+		wantConnClosed   bool
+	}{
+		"successful call": {
+			giveConnectorErr: nil,
+			giveClientErr:    nil,
+			wantErr:          nil,
+			wantRequest:      proto.Clone(testRequest).(*pb.UpdateNvmeRemoteControllerRequest),
+			wantResponse:     proto.Clone(testController).(*pb.NvmeRemoteController),
+			wantConnClosed:   true,
+		},
+		"client err": {
+			giveConnectorErr: nil,
+			giveClientErr:    errors.New("Some client error"),
+			wantErr:          errors.New("Some client error"),
+			wantRequest:      proto.Clone(testRequest).(*pb.UpdateNvmeRemoteControllerRequest),
+			wantResponse:     nil,
+			wantConnClosed:   true,
+		},
+		"connector err": {
+			giveConnectorErr: errors.New("Some conn error"),
+			giveClientErr:    nil,
+			wantErr:          errors.New("Some conn error"),
+			wantRequest:      nil,
+			wantResponse:     nil,
+			wantConnClosed:   false,
+		},
+	}
+
+	for testName, tt := range tests {
+		t.Run(testName, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
+
+			mockClient := mocks.NewNvmeRemoteControllerServiceClient(t)
+			if tt.wantRequest != nil {
+				mockClient.EXPECT().UpdateNvmeRemoteController(ctx, tt.wantRequest).
+					Return(proto.Clone(tt.wantResponse).(*pb.NvmeRemoteController), tt.giveClientErr)
+			}
+
+			connClosed := false
+			mockConn := mocks.NewConnector(t)
+			mockConn.EXPECT().NewConn().Return(
+				&grpc.ClientConn{},
+				func() { connClosed = true },
+				tt.giveConnectorErr,
+			)
+
+			c, _ := NewWithArgs(
+				mockConn,
+				func(grpc.ClientConnInterface) pb.NvmeRemoteControllerServiceClient {
+					return mockClient
+				},
+				pb.NewNullVolumeServiceClient,
+				pb.NewAioVolumeServiceClient,
+				pb.NewMallocVolumeServiceClient,
+			)
+
+			response, err := c.UpdateNvmeController(
+				ctx,
+				proto.Clone(testController).(*pb.NvmeRemoteController),
+				[]string{"queue_size"},
+				false,
+			)
+
+			require.Equal(t, tt.wantErr, err)
+			require.True(t, proto.Equal(tt.wantResponse, response))
+			require.Equal(t, tt.wantConnClosed, connClosed)
+		})
+	}
+}
+
+func TestResetNvmeController(t *testing.T) {
+	testRequest := &pb.ResetNvmeRemoteControllerRequest{
+		Name: "nvmeRemoteControllers/remotenvme0",
+	}
+
+	tests := map[string]struct {
+		giveClientErr    error
+		giveConnectorErr error
+		wantErr          error
+		wantRequest      *pb.ResetNvmeRemoteControllerRequest
+		wantConnClosed   bool
+	}{
+		"successful call": {
+			giveConnectorErr: nil,
+			giveClientErr:    nil,
+			wantErr:          nil,
+			wantRequest:      proto.Clone(testRequest).(*pb.ResetNvmeRemoteControllerRequest),
+			wantConnClosed:   true,
+		},
+		"client err": {
+			giveConnectorErr: nil,
+			giveClientErr:    errors.New("Some client error"),
+			wantErr:          errors.New("Some client error"),
+			wantRequest:      proto.Clone(testRequest).(*pb.ResetNvmeRemoteControllerRequest),
+			wantConnClosed:   true,
+		},
+		"connector err": {
+			giveConnectorErr: errors.New("Some conn error"),
+			giveClientErr:    nil,
+			wantErr:          errors.New("Some conn error"),
+			wantRequest:      nil,
+			wantConnClosed:   false,
+		},
+	}
+
+	for testName, tt := range tests {
+		t.Run(testName, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
+
+			mockClient := mocks.NewNvmeRemoteControllerServiceClient(t)
+			if tt.wantRequest != nil {
+				mockClient.EXPECT().ResetNvmeRemoteController(ctx, tt.wantRequest).
+					Return(&emptypb.Empty{}, tt.giveClientErr)
+			}
+
+			connClosed := false
+			mockConn := mocks.NewConnector(t)
+			mockConn.EXPECT().NewConn().Return(
+				&grpc.ClientConn{},
+				func() { connClosed = true },
+				tt.giveConnectorErr,
+			)
+
+			c, _ := NewWithArgs(
+				mockConn,
+				func(grpc.ClientConnInterface) pb.NvmeRemoteControllerServiceClient {
+					return mockClient
+				},
+				pb.NewNullVolumeServiceClient,
+				pb.NewAioVolumeServiceClient,
+				pb.NewMallocVolumeServiceClient,
+			)
+
+			err := c.ResetNvmeController(ctx, testRequest.Name)
+
+			require.Equal(t, tt.wantErr, err)
+			require.Equal(t, tt.wantConnClosed, connClosed)
+		})
+	}
+}
+
+func TestStatsNvmeController(t *testing.T) {
+	testRequest := &pb.StatsNvmeRemoteControllerRequest{
+		Name: "nvmeRemoteControllers/remotenvme0",
+	}
+	testStats := &pb.VolumeStats{
+		WriteBytesCount: 8192,
+		WriteOpsCount:   2,
+	}
+
+	tests := map[string]struct {
+		giveClientErr    error
+		giveConnectorErr error
+		wantErr          error
+		wantRequest      *pb.StatsNvmeRemoteControllerRequest
+		wantResponse     *pb.VolumeStats
+		wantConnClosed   bool
+	}{
+		"successful call": {
+			giveConnectorErr: nil,
+			giveClientErr:    nil,
+			wantErr:          nil,
+			wantRequest:      proto.Clone(testRequest).(*pb.StatsNvmeRemoteControllerRequest),
+			wantResponse:     proto.Clone(testStats).(*pb.VolumeStats),
+			wantConnClosed:   true,
+		},
+		"client err": {
+			giveConnectorErr: nil,
+			giveClientErr:    errors.New("Some client error"),
+			wantErr:          errors.New("Some client error"),
+			wantRequest:      proto.Clone(testRequest).(*pb.StatsNvmeRemoteControllerRequest),
+			wantResponse:     nil,
+			wantConnClosed:   true,
+		},
+		"connector err": {
+			giveConnectorErr: errors.New("Some conn error"),
+			giveClientErr:    nil,
+			wantErr:          errors.New("Some conn error"),
+			wantRequest:      nil,
+			wantResponse:     nil,
+			wantConnClosed:   false,
+		},
+	}
+
+	for testName, tt := range tests {
+		t.Run(testName, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
+
+			mockClient := mocks.NewNvmeRemoteControllerServiceClient(t)
+			if tt.wantRequest != nil {
+				var toReturn *pb.StatsNvmeRemoteControllerResponse
+				if tt.giveClientErr == nil {
+					toReturn = &pb.StatsNvmeRemoteControllerResponse{Stats: proto.Clone(tt.wantResponse).(*pb.VolumeStats)}
+				}
+				mockClient.EXPECT().StatsNvmeRemoteController(ctx, tt.wantRequest).
+					Return(toReturn, tt.giveClientErr)
+			}
+
+			connClosed := false
+			mockConn := mocks.NewConnector(t)
+			mockConn.EXPECT().NewConn().Return(
+				&grpc.ClientConn{},
+				func() { connClosed = true },
+				tt.giveConnectorErr,
+			)
+
+			c, _ := NewWithArgs(
+				mockConn,
+				func(grpc.ClientConnInterface) pb.NvmeRemoteControllerServiceClient {
+					return mockClient
+				},
+				pb.NewNullVolumeServiceClient,
+				pb.NewAioVolumeServiceClient,
+				pb.NewMallocVolumeServiceClient,
+			)
+
+			response, err := c.StatsNvmeController(ctx, testRequest.Name)
+
+			require.Equal(t, tt.wantErr, err)
+			require.True(t, proto.Equal(tt.wantResponse, response))
+			require.Equal(t, tt.wantConnClosed, connClosed)
+		})
+	}
+}
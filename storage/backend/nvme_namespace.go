@@ -0,0 +1,55 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (C) 2024 Intel Corporation
+
+// Package backend implements the go library for OPI backend storage
+package backend
+
+import (
+	"context"
+
+	pb "github.com/opiproject/opi-api/storage/v1alpha1/gen/go"
+)
+
+// ListNvmeNamespaces lists a page of namespaces exposed by an nvme
+// controller representing an external nvme device
+func (c *Client) ListNvmeNamespaces(
+	ctx context.Context,
+	controller string,
+	pageSize int32,
+	pageToken string,
+) (*pb.ListNvmeRemoteNamespacesResponse, error) {
+	conn, connClose, err := c.connector.NewConn()
+	if err != nil {
+		return nil, err
+	}
+	defer connClose()
+
+	client := c.createNvmeClient(conn)
+	return client.ListNvmeRemoteNamespaces(
+		ctx,
+		&pb.ListNvmeRemoteNamespacesRequest{
+			Parent:    controller,
+			PageSize:  pageSize,
+			PageToken: pageToken,
+		})
+}
+
+// GetNvmeNamespace gets a namespace exposed by an nvme controller
+// representing an external nvme device
+func (c *Client) GetNvmeNamespace(
+	ctx context.Context,
+	name string,
+) (*pb.NvmeRemoteNamespace, error) {
+	conn, connClose, err := c.connector.NewConn()
+	if err != nil {
+		return nil, err
+	}
+	defer connClose()
+
+	client := c.createNvmeClient(conn)
+	return client.GetNvmeRemoteNamespace(
+		ctx,
+		&pb.GetNvmeRemoteNamespaceRequest{
+			Name: name,
+		})
+}
@@ -0,0 +1,183 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (C) 2024 Intel Corporation
+
+// Package backend implements the go library for OPI backend storage
+package backend
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/opiproject/godpu/mocks"
+	pb "github.com/opiproject/opi-api/storage/v1alpha1/gen/go"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/proto"
+)
+
+var testRemoteNamespace = &pb.NvmeRemoteNamespace{
+	Name:  "nvmeRemoteControllers/remotenvme0/nvmeRemoteNamespaces/ns1",
+	Nsid:  1,
+	Nguid: "0123456789abcdef0123456789abcdef",
+}
+
+func TestListNvmeNamespaces(t *testing.T) {
+	testRequest := &pb.ListNvmeRemoteNamespacesRequest{
+		Parent:    "nvmeRemoteControllers/remotenvme0",
+		PageSize:  10,
+		PageToken: "token",
+	}
+	testResponse := &pb.ListNvmeRemoteNamespacesResponse{
+		NvmeRemoteNamespaces: []*pb.NvmeRemoteNamespace{testRemoteNamespace},
+	}
+
+	tests := map[string]struct {
+		giveClientErr    error
+		giveConnectorErr error
+		wantErr          error
+		wantRequest      *pb.ListNvmeRemoteNamespacesRequest
+		wantResponse     *pb.ListNvmeRemoteNamespacesResponse
+		wantConnClosed   bool
+	}{
+		"successful call": {
+			giveConnectorErr: nil,
+			giveClientErr:    nil,
+			wantErr:          nil,
+			wantRequest:      proto.Clone(testRequest).(*pb.ListNvmeRemoteNamespacesRequest),
+			wantResponse:     proto.Clone(testResponse).(*pb.ListNvmeRemoteNamespacesResponse),
+			wantConnClosed:   true,
+		},
+		"client err": {
+			giveConnectorErr: nil,
+			giveClientErr:    errors.New("Some client error"),
+			wantErr:          errors.New("Some client error"),
+			wantRequest:      proto.Clone(testRequest).(*pb.ListNvmeRemoteNamespacesRequest),
+			wantResponse:     nil,
+			wantConnClosed:   true,
+		},
+		"connector err": {
+			giveConnectorErr: errors.New("Some conn error"),
+			giveClientErr:    nil,
+			wantErr:          errors.New("Some conn error"),
+			wantRequest:      nil,
+			wantResponse:     nil,
+			wantConnClosed:   false,
+		},
+	}
+
+	for testName, tt := range tests {
+		t.Run(testName, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
+
+			mockClient := mocks.NewNvmeRemoteControllerServiceClient(t)
+			if tt.wantRequest != nil {
+				mockClient.EXPECT().ListNvmeRemoteNamespaces(ctx, tt.wantRequest).
+					Return(proto.Clone(tt.wantResponse).(*pb.ListNvmeRemoteNamespacesResponse), tt.giveClientErr)
+			}
+
+			connClosed := false
+			mockConn := mocks.NewConnector(t)
+			mockConn.EXPECT().NewConn().Return(
+				&grpc.ClientConn{},
+				func() { connClosed = true },
+				tt.giveConnectorErr,
+			)
+
+			c, _ := NewWithArgs(
+				mockConn,
+				func(grpc.ClientConnInterface) pb.NvmeRemoteControllerServiceClient {
+					return mockClient
+				},
+				pb.NewNullVolumeServiceClient,
+				pb.NewAioVolumeServiceClient,
+				pb.NewMallocVolumeServiceClient,
+			)
+
+			response, err := c.ListNvmeNamespaces(ctx, testRequest.Parent, 10, "token")
+
+			require.Equal(t, tt.wantErr, err)
+			require.True(t, proto.Equal(tt.wantResponse, response))
+			require.Equal(t, tt.wantConnClosed, connClosed)
+		})
+	}
+}
+
+func TestGetNvmeNamespace(t *testing.T) {
+	testRequest := &pb.GetNvmeRemoteNamespaceRequest{
+		Name: testRemoteNamespace.Name,
+	}
+
+	tests := map[string]struct {
+		giveClientErr    error
+		giveConnectorErr error
+		wantErr          error
+		wantRequest      *pb.GetNvmeRemoteNamespaceRequest
+		wantResponse     *pb.NvmeRemoteNamespace
+		wantConnClosed   bool
+	}{
+		"successful call": {
+			giveConnectorErr: nil,
+			giveClientErr:    nil,
+			wantErr:          nil,
+			wantRequest:      proto.Clone(testRequest).(*pb.GetNvmeRemoteNamespaceRequest),
+			wantResponse:     proto.Clone(testRemoteNamespace).(*pb.NvmeRemoteNamespace),
+			wantConnClosed:   true,
+		},
+		"client err": {
+			giveConnectorErr: nil,
+			giveClientErr:    errors.New("Some client error"),
+			wantErr:          errors.New("Some client error"),
+			wantRequest:      proto.Clone(testRequest).(*pb.GetNvmeRemoteNamespaceRequest),
+			wantResponse:     nil,
+			wantConnClosed:   true,
+		},
+		"connector err": {
+			giveConnectorErr: errors.New("Some conn error"),
+			giveClientErr:    nil,
+			wantErr:          errors.New("Some conn error"),
+			wantRequest:      nil,
+			wantResponse:     nil,
+			wantConnClosed:   false,
+		},
+	}
+
+	for testName, tt := range tests {
+		t.Run(testName, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
+
+			mockClient := mocks.NewNvmeRemoteControllerServiceClient(t)
+			if tt.wantRequest != nil {
+				mockClient.EXPECT().GetNvmeRemoteNamespace(ctx, tt.wantRequest).
+					Return(proto.Clone(tt.wantResponse).(*pb.NvmeRemoteNamespace), tt.giveClientErr)
+			}
+
+			connClosed := false
+			mockConn := mocks.NewConnector(t)
+			mockConn.EXPECT().NewConn().Return(
+				&grpc.ClientConn{},
+				func() { connClosed = true },
+				tt.giveConnectorErr,
+			)
+
+			c, _ := NewWithArgs(
+				mockConn,
+				func(grpc.ClientConnInterface) pb.NvmeRemoteControllerServiceClient {
+					return mockClient
+				},
+				pb.NewNullVolumeServiceClient,
+				pb.NewAioVolumeServiceClient,
+				pb.NewMallocVolumeServiceClient,
+			)
+
+			response, err := c.GetNvmeNamespace(ctx, testRemoteNamespace.Name)
+
+			require.Equal(t, tt.wantErr, err)
+			require.True(t, proto.Equal(tt.wantResponse, response))
+			require.Equal(t, tt.wantConnClosed, connClosed)
+		})
+	}
+}
@@ -5,11 +5,16 @@
 package backend
 
 import (
+	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"net"
 
+	"github.com/opiproject/godpu/storage/frontend"
+	"github.com/opiproject/godpu/storage/nvme"
 	pb "github.com/opiproject/opi-api/storage/v1alpha1/gen/go"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
 )
 
 // CreateNvmeTCPPath creates a path to nvme TCP controller
@@ -21,14 +26,51 @@ func (c *Client) CreateNvmeTCPPath(
 	port uint16,
 	nqn, hostnqn string,
 ) (*pb.NvmePath, error) {
-	var adrfam pb.NvmeAddressFamily
-	switch {
-	case ip.To4() != nil:
-		adrfam = pb.NvmeAddressFamily_NVME_ADDRESS_FAMILY_IPV4
-	case ip.To16() != nil:
-		adrfam = pb.NvmeAddressFamily_NVME_ADDRESS_FAMILY_IPV6
-	default:
-		return nil, fmt.Errorf("invalid ip address format: %v", ip)
+	return c.createNvmeTCPPath(ctx, id, controller, ip, port, nqn, hostnqn, nil)
+}
+
+// CreateNvmeTCPTLSPath creates a path to nvme TCP controller over a secure
+// channel established with the given TLS pre-shared key.
+// OPI carries the key in the remote controller, so it is set on the parent
+// controller before the path is created and cleared again if creating the
+// path fails. A controller already using a different key is not changed,
+// a *frontend.ConflictError is returned instead.
+func (c *Client) CreateNvmeTCPTLSPath(
+	ctx context.Context,
+	id string,
+	controller string,
+	ip net.IP,
+	port uint16,
+	nqn, hostnqn string,
+	psk []byte,
+) (*pb.NvmePath, error) {
+	if len(psk) == 0 {
+		return nil, errors.New("empty psk is not allowed for TLS path")
+	}
+
+	return c.createNvmeTCPPath(ctx, id, controller, ip, port, nqn, hostnqn, psk)
+}
+
+func (c *Client) createNvmeTCPPath(
+	ctx context.Context,
+	id string,
+	controller string,
+	ip net.IP,
+	port uint16,
+	nqn, hostnqn string,
+	psk []byte,
+) (*pb.NvmePath, error) {
+	adrfam, err := nvme.AddressFamily(ip)
+	if err != nil {
+		return nil, err
+	}
+	if err := nvme.ValidateNQN(nqn); err != nil {
+		return nil, err
+	}
+	if hostnqn != "" {
+		if err := nvme.ValidateNQN(hostnqn); err != nil {
+			return nil, err
+		}
 	}
 
 	conn, connClose, err := c.connector.NewConn()
@@ -38,6 +80,14 @@ func (c *Client) CreateNvmeTCPPath(
 	defer connClose()
 
 	client := c.createNvmeClient(conn)
+	pskSet := false
+	if len(psk) != 0 {
+		pskSet, err = setNvmeRemoteControllerPsk(ctx, client, controller, psk)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	response, err := client.CreateNvmePath(
 		ctx,
 		&pb.CreateNvmePathRequest{
@@ -54,10 +104,57 @@ func (c *Client) CreateNvmeTCPPath(
 				},
 			},
 		})
+	if err != nil && pskSet {
+		if restoreErr := updateNvmeRemoteControllerPsk(ctx, client, controller, nil); restoreErr != nil {
+			err = errors.Join(err, fmt.Errorf("failed to clear psk of %v: %w", controller, restoreErr))
+		}
+	}
 
 	return response, err
 }
 
+// setNvmeRemoteControllerPsk sets the psk of a remote controller which has
+// none and reports whether it was changed
+func setNvmeRemoteControllerPsk(
+	ctx context.Context,
+	client pb.NvmeRemoteControllerServiceClient,
+	controller string,
+	psk []byte,
+) (bool, error) {
+	current, err := client.GetNvmeRemoteController(ctx, &pb.GetNvmeRemoteControllerRequest{Name: controller})
+	if err != nil {
+		return false, err
+	}
+	currentPsk := current.GetTcp().GetPsk()
+	if bytes.Equal(currentPsk, psk) {
+		return false, nil
+	}
+	if len(currentPsk) != 0 {
+		return false, &frontend.ConflictError{Name: controller, Field: "psk"}
+	}
+	return true, updateNvmeRemoteControllerPsk(ctx, client, controller, psk)
+}
+
+func updateNvmeRemoteControllerPsk(
+	ctx context.Context,
+	client pb.NvmeRemoteControllerServiceClient,
+	controller string,
+	psk []byte,
+) error {
+	_, err := client.UpdateNvmeRemoteController(
+		ctx,
+		&pb.UpdateNvmeRemoteControllerRequest{
+			NvmeRemoteController: &pb.NvmeRemoteController{
+				Name: controller,
+				Tcp: &pb.TcpController{
+					Psk: psk,
+				},
+			},
+			UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"tcp.psk"}},
+		})
+	return err
+}
+
 // CreateNvmePciePath creates a path to nvme PCIe controller
 func (c *Client) CreateNvmePciePath(
 	ctx context.Context,
@@ -127,3 +224,76 @@ func (c *Client) GetNvmePath(
 			Name: name,
 		})
 }
+
+// ListNvmePaths lists a page of nvme paths of an nvme controller
+// representing an external nvme device
+func (c *Client) ListNvmePaths(
+	ctx context.Context,
+	controller string,
+	pageSize int32,
+	pageToken string,
+) (*pb.ListNvmePathsResponse, error) {
+	conn, connClose, err := c.connector.NewConn()
+	if err != nil {
+		return nil, err
+	}
+	defer connClose()
+
+	client := c.createNvmeClient(conn)
+	return client.ListNvmePaths(
+		ctx,
+		&pb.ListNvmePathsRequest{
+			Parent:    controller,
+			PageSize:  pageSize,
+			PageToken: pageToken,
+		})
+}
+
+// UpdateNvmePath updates the fields of an nvme path listed in updateMask.
+// All fields are updated if updateMask is empty.
+func (c *Client) UpdateNvmePath(
+	ctx context.Context,
+	path *pb.NvmePath,
+	updateMask []string,
+	allowMissing bool,
+) (*pb.NvmePath, error) {
+	conn, connClose, err := c.connector.NewConn()
+	if err != nil {
+		return nil, err
+	}
+	defer connClose()
+
+	client := c.createNvmeClient(conn)
+	return client.UpdateNvmePath(
+		ctx,
+		&pb.UpdateNvmePathRequest{
+			NvmePath:     path,
+			UpdateMask:   &fieldmaskpb.FieldMask{Paths: updateMask},
+			AllowMissing: allowMissing,
+		})
+}
+
+// StatsNvmePath gets io statistics of an nvme path to an external
+// nvme controller
+func (c *Client) StatsNvmePath(
+	ctx context.Context,
+	name string,
+) (*pb.VolumeStats, error) {
+	conn, connClose, err := c.connector.NewConn()
+	if err != nil {
+		return nil, err
+	}
+	defer connClose()
+
+	client := c.createNvmeClient(conn)
+	response, err := client.StatsNvmePath(
+		ctx,
+		&pb.StatsNvmePathRequest{
+			Name: name,
+		})
+	if err != nil {
+		return nil, err
+	}
+
+	return response.GetStats(), nil
+}
@@ -0,0 +1,170 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (C) 2024 Intel Corporation
+
+// Package backend implements the go library for OPI backend storage
+package backend
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	pb "github.com/opiproject/opi-api/storage/v1alpha1/gen/go"
+	"google.golang.org/protobuf/proto"
+)
+
+// NvmePathTarget describes a path a multipath nvme controller should have.
+// Paths are matched against targets by transport type, address, port and
+// subsystem nqn. ID is used to create a missing path and can be empty.
+type NvmePathTarget struct {
+	ID   string
+	Path *pb.NvmePath
+}
+
+// NvmePathReconcileResult contains the changes made to the paths of an nvme
+// controller
+type NvmePathReconcileResult struct {
+	Created []*pb.NvmePath
+	Deleted []string
+	// Retained contains stale paths which were not deleted since they were
+	// the only healthy paths of the controller
+	Retained []string
+}
+
+// ReconcileNvmePaths makes the paths of an nvme controller match targets.
+// Missing paths are created before stale ones are deleted. A path is healthy
+// if its statistics can be retrieved, which is checked for created paths too.
+// A stale path is kept if deleting it would leave the controller without a
+// healthy path.
+func (c *Client) ReconcileNvmePaths(
+	ctx context.Context,
+	controller string,
+	targets []NvmePathTarget,
+) (*NvmePathReconcileResult, error) {
+	for _, target := range targets {
+		if target.Path == nil {
+			return nil, errors.New("path of target is required")
+		}
+	}
+
+	conn, connClose, err := c.connector.NewConn()
+	if err != nil {
+		return nil, err
+	}
+	defer connClose()
+
+	client := c.createNvmeClient(conn)
+	current, err := listAllNvmePaths(ctx, client, controller)
+	if err != nil {
+		return nil, err
+	}
+
+	wanted := map[string]bool{}
+	for _, target := range targets {
+		wanted[nvmePathKey(target.Path)] = true
+	}
+	existing := map[string]bool{}
+	healthyPaths := 0
+	var stale []*pb.NvmePath
+	for _, path := range current {
+		key := nvmePathKey(path)
+		if !wanted[key] {
+			stale = append(stale, path)
+			continue
+		}
+		existing[key] = true
+		if isNvmePathHealthy(ctx, client, path.Name) {
+			healthyPaths++
+		}
+	}
+
+	result := &NvmePathReconcileResult{}
+	var errs []error
+	for _, target := range targets {
+		key := nvmePathKey(target.Path)
+		if existing[key] {
+			continue
+		}
+		path, err := client.CreateNvmePath(
+			ctx,
+			&pb.CreateNvmePathRequest{
+				NvmePathId: target.ID,
+				Parent:     controller,
+				NvmePath:   proto.Clone(target.Path).(*pb.NvmePath),
+			})
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to create path to %v: %w", target.Path.Traddr, err))
+			continue
+		}
+		existing[key] = true
+		result.Created = append(result.Created, path)
+		if isNvmePathHealthy(ctx, client, path.Name) {
+			healthyPaths++
+		}
+	}
+
+	for _, path := range stale {
+		if healthyPaths == 0 && isNvmePathHealthy(ctx, client, path.Name) {
+			result.Retained = append(result.Retained, path.Name)
+			healthyPaths++
+			continue
+		}
+		_, err := client.DeleteNvmePath(
+			ctx,
+			&pb.DeleteNvmePathRequest{
+				Name:         path.Name,
+				AllowMissing: true,
+			})
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to delete path %v: %w", path.Name, err))
+			continue
+		}
+		result.Deleted = append(result.Deleted, path.Name)
+	}
+
+	return result, errors.Join(errs...)
+}
+
+func listAllNvmePaths(
+	ctx context.Context,
+	client pb.NvmeRemoteControllerServiceClient,
+	controller string,
+) ([]*pb.NvmePath, error) {
+	var paths []*pb.NvmePath
+	pageToken := ""
+	for {
+		response, err := client.ListNvmePaths(
+			ctx,
+			&pb.ListNvmePathsRequest{
+				Parent:    controller,
+				PageToken: pageToken,
+			})
+		if err != nil {
+			return nil, err
+		}
+		paths = append(paths, response.NvmePaths...)
+
+		pageToken = response.NextPageToken
+		if pageToken == "" {
+			return paths, nil
+		}
+	}
+}
+
+func isNvmePathHealthy(
+	ctx context.Context,
+	client pb.NvmeRemoteControllerServiceClient,
+	name string,
+) bool {
+	_, err := client.StatsNvmePath(ctx, &pb.StatsNvmePathRequest{Name: name})
+	return err == nil
+}
+
+func nvmePathKey(path *pb.NvmePath) string {
+	return fmt.Sprintf("%v/%v/%v/%v",
+		path.GetTrtype(),
+		path.GetTraddr(),
+		path.GetFabrics().GetTrsvcid(),
+		path.GetFabrics().GetSubnqn(),
+	)
+}
@@ -0,0 +1,182 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (C) 2024 Intel Corporation
+
+// Package backend implements the go library for OPI backend storage
+package backend
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/opiproject/godpu/mocks"
+	pb "github.com/opiproject/opi-api/storage/v1alpha1/gen/go"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+func TestReconcileNvmePaths(t *testing.T) {
+	testController := "nvmeRemoteControllers/remotenvme0"
+	newTCPPath := func(name, traddr string) *pb.NvmePath {
+		return &pb.NvmePath{
+			Name:   name,
+			Trtype: pb.NvmeTransportType_NVME_TRANSPORT_TYPE_TCP,
+			Traddr: traddr,
+			Fabrics: &pb.FabricsPath{
+				Trsvcid: 4420,
+				Subnqn:  "nqn.2019-06.io.spdk:8",
+				Adrfam:  pb.NvmeAddressFamily_NVME_ADDRESS_FAMILY_IPV4,
+			},
+		}
+	}
+	pathA := newTCPPath(testController+"/nvmePaths/a", "10.0.0.1")
+	pathB := newTCPPath(testController+"/nvmePaths/b", "10.0.0.2")
+	pathC := newTCPPath(testController+"/nvmePaths/c", "10.0.0.3")
+	targetB := NvmePathTarget{ID: "b", Path: newTCPPath("", "10.0.0.2")}
+	targetC := NvmePathTarget{ID: "c", Path: newTCPPath("", "10.0.0.3")}
+
+	tests := map[string]struct {
+		giveCurrent      []*pb.NvmePath
+		giveUnhealthy    map[string]bool
+		giveTargets      []NvmePathTarget
+		giveCreateErr    error
+		giveListErr      error
+		giveConnectorErr error
+		wantCreated      []*pb.NvmePath
+		wantDeleted      []string
+		wantRetained     []string
+		wantErr          bool
+		wantConnClosed   bool
+	}{
+		"add missing and remove stale paths": {
+			giveCurrent:    []*pb.NvmePath{pathA, pathB},
+			giveTargets:    []NvmePathTarget{targetB, targetC},
+			wantCreated:    []*pb.NvmePath{pathC},
+			wantDeleted:    []string{pathA.Name},
+			wantErr:        false,
+			wantConnClosed: true,
+		},
+		"paths already match targets": {
+			giveCurrent:    []*pb.NvmePath{pathB, pathC},
+			giveTargets:    []NvmePathTarget{targetB, targetC},
+			wantErr:        false,
+			wantConnClosed: true,
+		},
+		"retain last healthy stale path": {
+			giveCurrent:    []*pb.NvmePath{pathA},
+			giveTargets:    []NvmePathTarget{targetC},
+			giveCreateErr:  errors.New("Some client error"),
+			wantRetained:   []string{pathA.Name},
+			wantErr:        true,
+			wantConnClosed: true,
+		},
+		"retain healthy stale path if created path is unhealthy": {
+			giveCurrent:    []*pb.NvmePath{pathA},
+			giveUnhealthy:  map[string]bool{pathC.Name: true},
+			giveTargets:    []NvmePathTarget{targetC},
+			wantCreated:    []*pb.NvmePath{pathC},
+			wantRetained:   []string{pathA.Name},
+			wantErr:        false,
+			wantConnClosed: true,
+		},
+		"delete unhealthy stale path": {
+			giveCurrent:    []*pb.NvmePath{pathA, pathB},
+			giveUnhealthy:  map[string]bool{pathA.Name: true, pathB.Name: true},
+			giveTargets:    []NvmePathTarget{targetB},
+			wantDeleted:    []string{pathA.Name},
+			wantErr:        false,
+			wantConnClosed: true,
+		},
+		"list err": {
+			giveTargets:    []NvmePathTarget{targetB},
+			giveListErr:    errors.New("Some client error"),
+			wantErr:        true,
+			wantConnClosed: true,
+		},
+		"connector err": {
+			giveTargets:      []NvmePathTarget{targetB},
+			giveConnectorErr: errors.New("Some conn error"),
+			wantErr:          true,
+			wantConnClosed:   false,
+		},
+	}
+
+	for testName, tt := range tests {
+		t.Run(testName, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
+
+			mockClient := mocks.NewNvmeRemoteControllerServiceClient(t)
+			if tt.giveConnectorErr == nil {
+				var listResponse *pb.ListNvmePathsResponse
+				if tt.giveListErr == nil {
+					listResponse = &pb.ListNvmePathsResponse{NvmePaths: tt.giveCurrent}
+				}
+				mockClient.EXPECT().ListNvmePaths(ctx, &pb.ListNvmePathsRequest{Parent: testController}).
+					Return(listResponse, tt.giveListErr)
+			}
+			for _, path := range append(tt.giveCurrent, tt.wantCreated...) {
+				var statsErr error
+				if tt.giveUnhealthy[path.Name] {
+					statsErr = errors.New("path is down")
+				}
+				mockClient.EXPECT().StatsNvmePath(ctx, &pb.StatsNvmePathRequest{Name: path.Name}).
+					Return(&pb.StatsNvmePathResponse{}, statsErr).Maybe()
+			}
+			if tt.giveCreateErr != nil {
+				mockClient.EXPECT().CreateNvmePath(ctx, &pb.CreateNvmePathRequest{
+					NvmePathId: targetC.ID,
+					Parent:     testController,
+					NvmePath:   proto.Clone(targetC.Path).(*pb.NvmePath),
+				}).Return(nil, tt.giveCreateErr)
+			}
+			for _, path := range tt.wantCreated {
+				mockClient.EXPECT().CreateNvmePath(ctx, &pb.CreateNvmePathRequest{
+					NvmePathId: targetC.ID,
+					Parent:     testController,
+					NvmePath:   proto.Clone(targetC.Path).(*pb.NvmePath),
+				}).Return(proto.Clone(path).(*pb.NvmePath), nil)
+			}
+			for _, name := range tt.wantDeleted {
+				mockClient.EXPECT().DeleteNvmePath(ctx, &pb.DeleteNvmePathRequest{
+					Name:         name,
+					AllowMissing: true,
+				}).Return(&emptypb.Empty{}, nil)
+			}
+
+			connClosed := false
+			mockConn := mocks.NewConnector(t)
+			mockConn.EXPECT().NewConn().Return(
+				&grpc.ClientConn{},
+				func() { connClosed = true },
+				tt.giveConnectorErr,
+			)
+
+			c, _ := NewWithArgs(
+				mockConn,
+				func(grpc.ClientConnInterface) pb.NvmeRemoteControllerServiceClient {
+					return mockClient
+				},
+				pb.NewNullVolumeServiceClient,
+				pb.NewAioVolumeServiceClient,
+				pb.NewMallocVolumeServiceClient,
+			)
+
+			result, err := c.ReconcileNvmePaths(ctx, testController, tt.giveTargets)
+
+			require.Equal(t, tt.wantErr, err != nil)
+			if tt.giveListErr == nil && tt.giveConnectorErr == nil {
+				require.Len(t, result.Created, len(tt.wantCreated))
+				for i := range tt.wantCreated {
+					require.True(t, proto.Equal(tt.wantCreated[i], result.Created[i]))
+				}
+				require.Equal(t, tt.wantDeleted, result.Deleted)
+				require.Equal(t, tt.wantRetained, result.Retained)
+			}
+			require.Equal(t, tt.wantConnClosed, connClosed)
+		})
+	}
+}
@@ -13,11 +13,13 @@ import (
 	"time"
 
 	"github.com/opiproject/godpu/mocks"
+	"github.com/opiproject/godpu/storage/frontend"
 	pb "github.com/opiproject/opi-api/storage/v1alpha1/gen/go"
 	"github.com/stretchr/testify/require"
 	"google.golang.org/grpc"
 	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
 )
 
 func TestCreateNvmeTCPPath(t *testing.T) {
@@ -39,6 +41,7 @@ func TestCreateNvmeTCPPath(t *testing.T) {
 		giveClientErr    error
 		giveConnectorErr error
 		giveIP           net.IP
+		giveNqn          string
 		wantErr          error
 		wantRequest      *pb.CreateNvmePathRequest
 		wantResponse     *pb.NvmePath
@@ -48,6 +51,7 @@ func TestCreateNvmeTCPPath(t *testing.T) {
 			giveConnectorErr: nil,
 			giveClientErr:    nil,
 			giveIP:           testIPv4,
+			giveNqn:          testNqn,
 			wantErr:          nil,
 			wantRequest: &pb.CreateNvmePathRequest{
 				Parent:     testControllerName,
@@ -61,6 +65,7 @@ func TestCreateNvmeTCPPath(t *testing.T) {
 			giveConnectorErr: nil,
 			giveClientErr:    errors.New("Some client error"),
 			giveIP:           testIPv4,
+			giveNqn:          testNqn,
 			wantErr:          errors.New("Some client error"),
 			wantRequest: &pb.CreateNvmePathRequest{
 				Parent:     testControllerName,
@@ -74,6 +79,7 @@ func TestCreateNvmeTCPPath(t *testing.T) {
 			giveConnectorErr: errors.New("Some conn error"),
 			giveClientErr:    nil,
 			giveIP:           testIPv4,
+			giveNqn:          testNqn,
 			wantErr:          errors.New("Some conn error"),
 			wantRequest:      nil,
 			wantResponse:     nil,
@@ -83,6 +89,7 @@ func TestCreateNvmeTCPPath(t *testing.T) {
 			giveConnectorErr: nil,
 			giveClientErr:    nil,
 			giveIP:           net.ParseIP("2001:db8::68"),
+			giveNqn:          testNqn,
 			wantErr:          nil,
 			wantRequest: &pb.CreateNvmePathRequest{
 				Parent:     testControllerName,
@@ -104,11 +111,22 @@ func TestCreateNvmeTCPPath(t *testing.T) {
 			giveConnectorErr: nil,
 			giveClientErr:    nil,
 			giveIP:           net.ParseIP("invalid ip"),
+			giveNqn:          testNqn,
 			wantErr:          fmt.Errorf("invalid ip address format: %v", "<nil>"),
 			wantRequest:      nil,
 			wantResponse:     nil,
 			wantConnClosed:   false,
 		},
+		"invalid nqn": {
+			giveConnectorErr: nil,
+			giveClientErr:    nil,
+			giveIP:           testIPv4,
+			giveNqn:          "nqn.2019-06:0",
+			wantErr:          errors.New(`invalid nqn format "nqn.2019-06:0", expected nqn.yyyy-mm.reverse-domain[:identifier]`),
+			wantRequest:      nil,
+			wantResponse:     nil,
+			wantConnClosed:   false,
+		},
 	}
 
 	for testName, tt := range tests {
@@ -136,6 +154,9 @@ func TestCreateNvmeTCPPath(t *testing.T) {
 				func(grpc.ClientConnInterface) pb.NvmeRemoteControllerServiceClient {
 					return mockClient
 				},
+				pb.NewNullVolumeServiceClient,
+				pb.NewAioVolumeServiceClient,
+				pb.NewMallocVolumeServiceClient,
 			)
 
 			response, err := c.CreateNvmeTCPPath(
@@ -144,7 +165,7 @@ func TestCreateNvmeTCPPath(t *testing.T) {
 				testControllerName,
 				tt.giveIP,
 				4420,
-				testNqn,
+				tt.giveNqn,
 				"",
 			)
 
@@ -155,6 +176,187 @@ func TestCreateNvmeTCPPath(t *testing.T) {
 	}
 }
 
+func TestCreateNvmeTCPTLSPath(t *testing.T) {
+	testControllerName := "nvmeRemoteControllers/remotenvme0"
+	testPsk := []byte("NVMeTLSkey-1:01:MDAxMTIyMzM0NDU1NjY3Nzg4OTlhYWJiY2NkZGVlZmZwJEiQ:")
+	testNqn := "nqn.2019-06.io.spdk:0"
+	testUpdateRequest := &pb.UpdateNvmeRemoteControllerRequest{
+		NvmeRemoteController: &pb.NvmeRemoteController{
+			Name: testControllerName,
+			Tcp:  &pb.TcpController{Psk: testPsk},
+		},
+		UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"tcp.psk"}},
+	}
+	testCreateRequest := &pb.CreateNvmePathRequest{
+		Parent:     testControllerName,
+		NvmePathId: "remotepath0",
+		NvmePath: &pb.NvmePath{
+			Trtype: pb.NvmeTransportType_NVME_TRANSPORT_TYPE_TCP,
+			Traddr: "127.0.0.1",
+			Fabrics: &pb.FabricsPath{
+				Trsvcid: 5555,
+				Subnqn:  testNqn,
+				Adrfam:  pb.NvmeAddressFamily_NVME_ADDRESS_FAMILY_IPV4,
+			},
+		},
+	}
+
+	testRestoreRequest := &pb.UpdateNvmeRemoteControllerRequest{
+		NvmeRemoteController: &pb.NvmeRemoteController{
+			Name: testControllerName,
+			Tcp:  &pb.TcpController{},
+		},
+		UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"tcp.psk"}},
+	}
+
+	tests := map[string]struct {
+		givePsk           []byte
+		giveControllerPsk []byte
+		giveGetErr        error
+		giveUpdateErr     error
+		giveCreateErr     error
+		giveConnectorErr  error
+		wantErr           error
+		wantGet           bool
+		wantUpdate        bool
+		wantRestore       bool
+		wantCreate        bool
+		wantResponse      bool
+		wantConnClosed    bool
+	}{
+		"successful call": {
+			givePsk:        testPsk,
+			wantErr:        nil,
+			wantGet:        true,
+			wantUpdate:     true,
+			wantCreate:     true,
+			wantResponse:   true,
+			wantConnClosed: true,
+		},
+		"empty psk": {
+			givePsk:        nil,
+			wantErr:        errors.New("empty psk is not allowed for TLS path"),
+			wantUpdate:     false,
+			wantCreate:     false,
+			wantConnClosed: false,
+		},
+		"controller update err": {
+			givePsk:        testPsk,
+			giveUpdateErr:  errors.New("Some client error"),
+			wantErr:        errors.New("Some client error"),
+			wantGet:        true,
+			wantUpdate:     true,
+			wantCreate:     false,
+			wantConnClosed: true,
+		},
+		"path create err restores psk": {
+			givePsk:        testPsk,
+			giveCreateErr:  errors.New("Some create error"),
+			wantErr:        errors.New("Some create error"),
+			wantGet:        true,
+			wantUpdate:     true,
+			wantRestore:    true,
+			wantCreate:     true,
+			wantConnClosed: true,
+		},
+		"same psk already set": {
+			givePsk:           testPsk,
+			giveControllerPsk: testPsk,
+			wantErr:           nil,
+			wantGet:           true,
+			wantUpdate:        false,
+			wantCreate:        true,
+			wantResponse:      true,
+			wantConnClosed:    true,
+		},
+		"different psk already set": {
+			givePsk:           testPsk,
+			giveControllerPsk: []byte("NVMeTLSkey-1:01:other:"),
+			wantErr:           &frontend.ConflictError{Name: testControllerName, Field: "psk"},
+			wantGet:           true,
+			wantUpdate:        false,
+			wantCreate:        false,
+			wantConnClosed:    true,
+		},
+		"get controller err": {
+			givePsk:        testPsk,
+			giveGetErr:     errors.New("Some get error"),
+			wantErr:        errors.New("Some get error"),
+			wantGet:        true,
+			wantUpdate:     false,
+			wantCreate:     false,
+			wantConnClosed: true,
+		},
+		"connector err": {
+			givePsk:          testPsk,
+			giveConnectorErr: errors.New("Some conn error"),
+			wantErr:          errors.New("Some conn error"),
+			wantUpdate:       false,
+			wantCreate:       false,
+			wantConnClosed:   false,
+		},
+	}
+
+	for testName, tt := range tests {
+		t.Run(testName, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
+
+			mockClient := mocks.NewNvmeRemoteControllerServiceClient(t)
+			if tt.wantGet {
+				mockClient.EXPECT().GetNvmeRemoteController(ctx, &pb.GetNvmeRemoteControllerRequest{Name: testControllerName}).
+					Return(&pb.NvmeRemoteController{
+						Name: testControllerName,
+						Tcp:  &pb.TcpController{Psk: tt.giveControllerPsk},
+					}, tt.giveGetErr)
+			}
+			if tt.wantUpdate {
+				mockClient.EXPECT().UpdateNvmeRemoteController(ctx, testUpdateRequest).
+					Return(&pb.NvmeRemoteController{Name: testControllerName}, tt.giveUpdateErr)
+			}
+			if tt.wantRestore {
+				mockClient.EXPECT().UpdateNvmeRemoteController(ctx, testRestoreRequest).
+					Return(&pb.NvmeRemoteController{Name: testControllerName}, nil)
+			}
+			if tt.wantCreate {
+				var toReturn *pb.NvmePath
+				if tt.giveCreateErr == nil {
+					toReturn = proto.Clone(testCreateRequest.NvmePath).(*pb.NvmePath)
+				}
+				mockClient.EXPECT().CreateNvmePath(ctx, testCreateRequest).
+					Return(toReturn, tt.giveCreateErr)
+			}
+
+			connClosed := false
+			mockConn := mocks.NewConnector(t)
+			if len(tt.givePsk) != 0 {
+				mockConn.EXPECT().NewConn().Return(
+					&grpc.ClientConn{},
+					func() { connClosed = true },
+					tt.giveConnectorErr,
+				)
+			}
+
+			c, _ := NewWithArgs(
+				mockConn,
+				func(grpc.ClientConnInterface) pb.NvmeRemoteControllerServiceClient {
+					return mockClient
+				},
+				pb.NewNullVolumeServiceClient,
+				pb.NewAioVolumeServiceClient,
+				pb.NewMallocVolumeServiceClient,
+			)
+
+			response, err := c.CreateNvmeTCPTLSPath(
+				ctx, "remotepath0", testControllerName, net.ParseIP("127.0.0.1"), 5555, testNqn, "", tt.givePsk)
+
+			require.Equal(t, tt.wantErr, err)
+			require.Equal(t, tt.wantResponse, response != nil)
+			require.Equal(t, tt.wantConnClosed, connClosed)
+		})
+	}
+}
+
 func TestCreateNvmePciePath(t *testing.T) {
 	testControllerName := "remotenvme0Name"
 	testPathID := "remotepath0"
@@ -231,6 +433,9 @@ func TestCreateNvmePciePath(t *testing.T) {
 				func(grpc.ClientConnInterface) pb.NvmeRemoteControllerServiceClient {
 					return mockClient
 				},
+				pb.NewNullVolumeServiceClient,
+				pb.NewAioVolumeServiceClient,
+				pb.NewMallocVolumeServiceClient,
 			)
 
 			response, err := c.CreateNvmePciePath(
@@ -307,6 +512,9 @@ func TestDeleteNvmePath(t *testing.T) {
 				func(grpc.ClientConnInterface) pb.NvmeRemoteControllerServiceClient {
 					return mockClient
 				},
+				pb.NewNullVolumeServiceClient,
+				pb.NewAioVolumeServiceClient,
+				pb.NewMallocVolumeServiceClient,
 			)
 
 			err := c.DeleteNvmePath(ctx, testPathName, true)
@@ -390,6 +598,9 @@ func TestGetNvmePath(t *testing.T) {
 				func(grpc.ClientConnInterface) pb.NvmeRemoteControllerServiceClient {
 					return mockClient
 				},
+				pb.NewNullVolumeServiceClient,
+				pb.NewAioVolumeServiceClient,
+				pb.NewMallocVolumeServiceClient,
 			)
 
 			response, err := c.GetNvmePath(ctx, testPathName)
@@ -400,3 +611,267 @@ func TestGetNvmePath(t *testing.T) {
 		})
 	}
 }
+
+func TestListNvmePaths(t *testing.T) {
+	testRequest := &pb.ListNvmePathsRequest{
+		Parent:    "nvmeRemoteControllers/remotenvme0",
+		PageSize:  10,
+		PageToken: "token",
+	}
+	testResponse := &pb.ListNvmePathsResponse{
+		NvmePaths: []*pb.NvmePath{
+			{Name: "nvmeRemoteControllers/remotenvme0/nvmePaths/path0"},
+			{Name: "nvmeRemoteControllers/remotenvme0/nvmePaths/path1"},
+		},
+	}
+
+	tests := map[string]struct {
+		giveClientErr    error
+		giveConnectorErr error
+		wantErr          error
+		wantRequest      *pb.ListNvmePathsRequest
+		wantResponse     *pb.ListNvmePathsResponse
+		wantConnClosed   bool
+	}{
+		"successful call": {
+			giveConnectorErr: nil,
+			giveClientErr:    nil,
+			wantErr:          nil,
+			wantRequest:      proto.Clone(testRequest).(*pb.ListNvmePathsRequest),
+			wantResponse:     proto.Clone(testResponse).(*pb.ListNvmePathsResponse),
+			wantConnClosed:   true,
+		},
+		"client err": {
+			giveConnectorErr: nil,
+			giveClientErr:    errors.New("Some client error"),
+			wantErr:          errors.New("Some client error"),
+			wantRequest:      proto.Clone(testRequest).(*pb.ListNvmePathsRequest),
+			wantResponse:     nil,
+			wantConnClosed:   true,
+		},
+		"connector err": {
+			giveConnectorErr: errors.New("Some conn error"),
+			giveClientErr:    nil,
+			wantErr:          errors.New("Some conn error"),
+			wantRequest:      nil,
+			wantResponse:     nil,
+			wantConnClosed:   false,
+		},
+	}
+
+	for testName, tt := range tests {
+		t.Run(testName, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
+
+			mockClient := mocks.NewNvmeRemoteControllerServiceClient(t)
+			if tt.wantRequest != nil {
+				mockClient.EXPECT().ListNvmePaths(ctx, tt.wantRequest).
+					Return(proto.Clone(tt.wantResponse).(*pb.ListNvmePathsResponse), tt.giveClientErr)
+			}
+
+			connClosed := false
+			mockConn := mocks.NewConnector(t)
+			mockConn.EXPECT().NewConn().Return(
+				&grpc.ClientConn{},
+				func() { connClosed = true },
+				tt.giveConnectorErr,
+			)
+
+			c, _ := NewWithArgs(
+				mockConn,
+				func(grpc.ClientConnInterface) pb.NvmeRemoteControllerServiceClient {
+					return mockClient
+				},
+				pb.NewNullVolumeServiceClient,
+				pb.NewAioVolumeServiceClient,
+				pb.NewMallocVolumeServiceClient,
+			)
+
+			response, err := c.ListNvmePaths(ctx, testRequest.Parent, 10, "token")
+
+			require.Equal(t, tt.wantErr, err)
+			require.True(t, proto.Equal(tt.wantResponse, response))
+			require.Equal(t, tt.wantConnClosed, connClosed)
+		})
+	}
+}
+
+func TestUpdateNvmePath(t *testing.T) {
+	testPath := &pb.NvmePath{
+		Name:   "nvmeRemoteControllers/remotenvme0/nvmePaths/path0",
+		Trtype: pb.NvmeTransportType_NVME_TRANSPORT_TYPE_TCP,
+		Traddr: "127.0.0.1",
+		Fabrics: &pb.FabricsPath{
+			Trsvcid: 4420,
+			Subnqn:  "nqn.2019-06.io.spdk:8",
+			Adrfam:  pb.NvmeAddressFamily_NVME_ADDRESS_FAMILY_IPV4,
+		},
+	}
+	testRequest := &pb.UpdateNvmePathRequest{
+		NvmePath:     proto.Clone(testPath).(*pb.NvmePath),
+		UpdateMask:   &fieldmaskpb.FieldMask{Paths: []string{"traddr"}},
+		AllowMissing: true,
+	}
+
+	tests := map[string]struct {
+		giveClientErr    error
+		giveConnectorErr error
+		wantErr          error
+		wantRequest      *pb.UpdateNvmePathRequest
+		wantResponse     *pb.NvmePath
+		wantConnClosed   bool
+	}{
+		"successful call": {
+			giveConnectorErr: nil,
+			giveClientErr:    nil,
+			wantErr:          nil,
+			wantRequest:      proto.Clone(testRequest).(*pb.UpdateNvmePathRequest),
+			wantResponse:     proto.Clone(testPath).(*pb.NvmePath),
+			wantConnClosed:   true,
+		},
+		"client err": {
+			giveConnectorErr: nil,
+			giveClientErr:    errors.New("Some client error"),
+			wantErr:          errors.New("Some client error"),
+			wantRequest:      proto.Clone(testRequest).(*pb.UpdateNvmePathRequest),
+			wantResponse:     nil,
+			wantConnClosed:   true,
+		},
+		"connector err": {
+			giveConnectorErr: errors.New("Some conn error"),
+			giveClientErr:    nil,
+			wantErr:          errors.New("Some conn error"),
+			wantRequest:      nil,
+			wantResponse:     nil,
+			wantConnClosed:   false,
+		},
+	}
+
+	for testName, tt := range tests {
+		t.Run(testName, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
+
+			mockClient := mocks.NewNvmeRemoteControllerServiceClient(t)
+			if tt.wantRequest != nil {
+				mockClient.EXPECT().UpdateNvmePath(ctx, tt.wantRequest).
+					Return(proto.Clone(tt.wantResponse).(*pb.NvmePath), tt.giveClientErr)
+			}
+
+			connClosed := false
+			mockConn := mocks.NewConnector(t)
+			mockConn.EXPECT().NewConn().Return(
+				&grpc.ClientConn{},
+				func() { connClosed = true },
+				tt.giveConnectorErr,
+			)
+
+			c, _ := NewWithArgs(
+				mockConn,
+				func(grpc.ClientConnInterface) pb.NvmeRemoteControllerServiceClient {
+					return mockClient
+				},
+				pb.NewNullVolumeServiceClient,
+				pb.NewAioVolumeServiceClient,
+				pb.NewMallocVolumeServiceClient,
+			)
+
+			response, err := c.UpdateNvmePath(
+				ctx,
+				proto.Clone(testPath).(*pb.NvmePath),
+				[]string{"traddr"},
+				true,
+			)
+
+			require.Equal(t, tt.wantErr, err)
+			require.True(t, proto.Equal(tt.wantResponse, response))
+			require.Equal(t, tt.wantConnClosed, connClosed)
+		})
+	}
+}
+
+func TestStatsNvmePath(t *testing.T) {
+	testRequest := &pb.StatsNvmePathRequest{
+		Name: "nvmeRemoteControllers/remotenvme0/nvmePaths/path0",
+	}
+	testStats := &pb.VolumeStats{
+		ReadBytesCount: 512,
+		ReadOpsCount:   1,
+	}
+
+	tests := map[string]struct {
+		giveClientErr    error
+		giveConnectorErr error
+		wantErr          error
+		wantRequest      *pb.StatsNvmePathRequest
+		wantResponse     *pb.VolumeStats
+		wantConnClosed   bool
+	}{
+		"successful call": {
+			giveConnectorErr: nil,
+			giveClientErr:    nil,
+			wantErr:          nil,
+			wantRequest:      proto.Clone(testRequest).(*pb.StatsNvmePathRequest),
+			wantResponse:     proto.Clone(testStats).(*pb.VolumeStats),
+			wantConnClosed:   true,
+		},
+		"client err": {
+			giveConnectorErr: nil,
+			giveClientErr:    errors.New("Some client error"),
+			wantErr:          errors.New("Some client error"),
+			wantRequest:      proto.Clone(testRequest).(*pb.StatsNvmePathRequest),
+			wantResponse:     nil,
+			wantConnClosed:   true,
+		},
+		"connector err": {
+			giveConnectorErr: errors.New("Some conn error"),
+			giveClientErr:    nil,
+			wantErr:          errors.New("Some conn error"),
+			wantRequest:      nil,
+			wantResponse:     nil,
+			wantConnClosed:   false,
+		},
+	}
+
+	for testName, tt := range tests {
+		t.Run(testName, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
+
+			mockClient := mocks.NewNvmeRemoteControllerServiceClient(t)
+			if tt.wantRequest != nil {
+				var toReturn *pb.StatsNvmePathResponse
+				if tt.giveClientErr == nil {
+					toReturn = &pb.StatsNvmePathResponse{Stats: proto.Clone(tt.wantResponse).(*pb.VolumeStats)}
+				}
+				mockClient.EXPECT().StatsNvmePath(ctx, tt.wantRequest).
+					Return(toReturn, tt.giveClientErr)
+			}
+
+			connClosed := false
+			mockConn := mocks.NewConnector(t)
+			mockConn.EXPECT().NewConn().Return(
+				&grpc.ClientConn{},
+				func() { connClosed = true },
+				tt.giveConnectorErr,
+			)
+
+			c, _ := NewWithArgs(
+				mockConn,
+				func(grpc.ClientConnInterface) pb.NvmeRemoteControllerServiceClient {
+					return mockClient
+				},
+				pb.NewNullVolumeServiceClient,
+				pb.NewAioVolumeServiceClient,
+				pb.NewMallocVolumeServiceClient,
+			)
+
+			response, err := c.StatsNvmePath(ctx, testRequest.Name)
+
+			require.Equal(t, tt.wantErr, err)
+			require.True(t, proto.Equal(tt.wantResponse, response))
+			require.Equal(t, tt.wantConnClosed, connClosed)
+		})
+	}
+}
@@ -0,0 +1,341 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (C) 2024 Intel Corporation
+
+// Package csi implements a reference CSI driver exposing OPI storage to
+// hosts over nvme/tcp
+package csi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+
+	"github.com/container-storage-interface/spec/lib/go/csi"
+	"github.com/opiproject/godpu/storage/frontend"
+	"github.com/opiproject/godpu/storage/nvme"
+	pb "github.com/opiproject/opi-api/storage/v1alpha1/gen/go"
+	"go.einride.tech/aip/resourcename"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// volumeNQNPrefix prefixes the nqn of the subsystem exposing a volume
+const volumeNQNPrefix = "nqn.2022-09.io.opiproject:"
+
+// Keys of the publish context passed from ControllerPublishVolume to the
+// node service
+const (
+	publishContextNqn       = "nqn"
+	publishContextTransport = "transport"
+	publishContextTrAddr    = "traddr"
+	publishContextTrSvcID   = "trsvcid"
+)
+
+// CreateVolume creates a malloc volume. The volume id is derived from the
+// name, so retried calls return the already created volume.
+func (d *Driver) CreateVolume(
+	ctx context.Context,
+	req *csi.CreateVolumeRequest,
+) (*csi.CreateVolumeResponse, error) {
+	if req.GetName() == "" {
+		return nil, status.Error(codes.InvalidArgument, "name is required")
+	}
+	if err := validateVolumeCapabilities(req.GetVolumeCapabilities()); err != nil {
+		return nil, err
+	}
+	if req.GetVolumeContentSource() != nil {
+		return nil, status.Error(codes.InvalidArgument, "volume content source is not supported")
+	}
+
+	blocks, err := d.volumeBlocks(req.GetCapacityRange())
+	if err != nil {
+		return nil, err
+	}
+
+	id := resourceID("volume", req.GetName())
+	volume, err := d.backend.GetMallocVolume(ctx, volumeName(id))
+	switch {
+	case err == nil:
+		capacity := volume.GetBlockSize() * volume.GetBlocksCount()
+		if !capacityInRange(capacity, req.GetCapacityRange()) {
+			return nil, status.Errorf(codes.AlreadyExists,
+				"volume %v already exists with capacity %v", req.GetName(), capacity)
+		}
+	case status.Code(err) == codes.NotFound:
+		volume, err = d.backend.CreateMallocVolume(ctx, id, d.opts.BlockSize, blocks)
+		if err != nil {
+			return nil, toStatus(err)
+		}
+	default:
+		return nil, toStatus(err)
+	}
+
+	return &csi.CreateVolumeResponse{
+		Volume: &csi.Volume{
+			VolumeId:      id,
+			CapacityBytes: volume.GetBlockSize() * volume.GetBlocksCount(),
+		},
+	}, nil
+}
+
+// DeleteVolume deletes a malloc volume. Deleting a missing volume succeeds.
+func (d *Driver) DeleteVolume(
+	ctx context.Context,
+	req *csi.DeleteVolumeRequest,
+) (*csi.DeleteVolumeResponse, error) {
+	if req.GetVolumeId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "volume id is required")
+	}
+
+	err := d.backend.DeleteMallocVolume(ctx, volumeName(req.GetVolumeId()), true)
+	if err != nil && status.Code(err) != codes.NotFound {
+		return nil, toStatus(err)
+	}
+
+	return &csi.DeleteVolumeResponse{}, nil
+}
+
+// ControllerPublishVolume exposes a volume over nvme/tcp to the node. The
+// node id is the host nqn of the node, only this host is allowed to
+// connect to the subsystem of the volume. Publishing a volume already
+// published to another node fails with FAILED_PRECONDITION.
+func (d *Driver) ControllerPublishVolume(
+	ctx context.Context,
+	req *csi.ControllerPublishVolumeRequest,
+) (*csi.ControllerPublishVolumeResponse, error) {
+	switch {
+	case req.GetVolumeId() == "":
+		return nil, status.Error(codes.InvalidArgument, "volume id is required")
+	case req.GetNodeId() == "":
+		return nil, status.Error(codes.InvalidArgument, "node id is required")
+	case req.GetVolumeCapability() == nil:
+		return nil, status.Error(codes.InvalidArgument, "volume capability is required")
+	case req.GetReadonly():
+		return nil, status.Error(codes.InvalidArgument, "readonly volumes are not supported")
+	}
+	if err := validateVolumeCapabilities([]*csi.VolumeCapability{req.GetVolumeCapability()}); err != nil {
+		return nil, err
+	}
+
+	volume, err := d.backend.GetMallocVolume(ctx, volumeName(req.GetVolumeId()))
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	if err := nvme.ValidateNQN(req.GetNodeId()); err != nil {
+		return nil, status.Errorf(codes.NotFound, "node %v does not exist: %v", req.GetNodeId(), err)
+	}
+
+	spec, err := d.exposeVolumeSpec(req.GetVolumeId(), req.GetNodeId())
+	if err != nil {
+		return nil, err
+	}
+	spec.Volume = volume.GetName()
+	if _, err := d.frontend.ExposeVolume(ctx, spec); err != nil {
+		var conflict *frontend.ConflictError
+		if errors.As(err, &conflict) && conflict.Field == "hostnqn" {
+			return nil, status.Errorf(codes.FailedPrecondition,
+				"volume %v is published to another node", req.GetVolumeId())
+		}
+		return nil, toStatus(err)
+	}
+
+	return &csi.ControllerPublishVolumeResponse{
+		PublishContext: map[string]string{
+			publishContextNqn:       spec.Nqn,
+			publishContextTransport: "tcp",
+			publishContextTrAddr:    d.opts.TargetAddr.String(),
+			publishContextTrSvcID:   strconv.Itoa(int(d.opts.TargetPort)),
+		},
+	}, nil
+}
+
+// ControllerUnpublishVolume removes the nvme/tcp exposure of a volume from
+// the node. Unpublishing from a node the volume is not published to
+// succeeds without touching the exposure of another node. Unpublishing from
+// all nodes at once is not supported.
+func (d *Driver) ControllerUnpublishVolume(
+	ctx context.Context,
+	req *csi.ControllerUnpublishVolumeRequest,
+) (*csi.ControllerUnpublishVolumeResponse, error) {
+	switch {
+	case req.GetVolumeId() == "":
+		return nil, status.Error(codes.InvalidArgument, "volume id is required")
+	case req.GetNodeId() == "":
+		return nil, status.Error(codes.InvalidArgument, "node id is required")
+	}
+
+	spec, err := d.exposeVolumeSpec(req.GetVolumeId(), req.GetNodeId())
+	if err != nil {
+		return nil, err
+	}
+	hosts, _, err := d.frontend.ListNvmeSubsystemHosts(ctx, frontend.SubsystemName(spec.SubsystemID))
+	switch {
+	case status.Code(err) == codes.NotFound:
+		return &csi.ControllerUnpublishVolumeResponse{}, nil
+	case err != nil:
+		return nil, toStatus(err)
+	case !slices.Contains(hosts, spec.Hostnqn):
+		return &csi.ControllerUnpublishVolumeResponse{}, nil
+	}
+	if err := d.frontend.UnexposeVolume(ctx, spec); err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &csi.ControllerUnpublishVolumeResponse{}, nil
+}
+
+// ValidateVolumeCapabilities confirms the capabilities if the driver
+// supports all of them
+func (d *Driver) ValidateVolumeCapabilities(
+	ctx context.Context,
+	req *csi.ValidateVolumeCapabilitiesRequest,
+) (*csi.ValidateVolumeCapabilitiesResponse, error) {
+	switch {
+	case req.GetVolumeId() == "":
+		return nil, status.Error(codes.InvalidArgument, "volume id is required")
+	case len(req.GetVolumeCapabilities()) == 0:
+		return nil, status.Error(codes.InvalidArgument, "volume capabilities are required")
+	}
+
+	if _, err := d.backend.GetMallocVolume(ctx, volumeName(req.GetVolumeId())); err != nil {
+		return nil, toStatus(err)
+	}
+
+	if err := validateVolumeCapabilities(req.GetVolumeCapabilities()); err != nil {
+		return &csi.ValidateVolumeCapabilitiesResponse{Message: status.Convert(err).Message()}, nil
+	}
+
+	return &csi.ValidateVolumeCapabilitiesResponse{
+		Confirmed: &csi.ValidateVolumeCapabilitiesResponse_Confirmed{
+			VolumeContext:      req.GetVolumeContext(),
+			VolumeCapabilities: req.GetVolumeCapabilities(),
+			Parameters:         req.GetParameters(),
+		},
+	}, nil
+}
+
+// ControllerGetCapabilities reports the supported controller calls
+func (d *Driver) ControllerGetCapabilities(
+	_ context.Context,
+	_ *csi.ControllerGetCapabilitiesRequest,
+) (*csi.ControllerGetCapabilitiesResponse, error) {
+	capabilities := []*csi.ControllerServiceCapability{}
+	for _, rpc := range []csi.ControllerServiceCapability_RPC_Type{
+		csi.ControllerServiceCapability_RPC_CREATE_DELETE_VOLUME,
+		csi.ControllerServiceCapability_RPC_PUBLISH_UNPUBLISH_VOLUME,
+	} {
+		capabilities = append(capabilities, &csi.ControllerServiceCapability{
+			Type: &csi.ControllerServiceCapability_Rpc{
+				Rpc: &csi.ControllerServiceCapability_RPC{Type: rpc},
+			},
+		})
+	}
+
+	return &csi.ControllerGetCapabilitiesResponse{Capabilities: capabilities}, nil
+}
+
+func (d *Driver) exposeVolumeSpec(volumeID string, nodeID string) (frontend.ExposeVolumeSpec, error) {
+	ids, err := nvme.IdentifiersFromVolumeID(volumeID)
+	if err != nil {
+		return frontend.ExposeVolumeSpec{}, status.Error(codes.InvalidArgument, err.Error())
+	}
+	adrfam, err := nvme.AddressFamily(d.opts.TargetAddr)
+	if err != nil {
+		return frontend.ExposeVolumeSpec{}, status.Errorf(codes.FailedPrecondition, "target address: %v", err)
+	}
+
+	return frontend.ExposeVolumeSpec{
+		SubsystemID:   volumeID,
+		Nqn:           volumeNQNPrefix + volumeID,
+		Hostnqn:       nodeID,
+		MaxNamespaces: 1,
+		NamespaceID:   volumeID,
+		Identifiers:   ids,
+		ControllerID:  resourceID("controller", nodeID),
+		Controller: &pb.NvmeControllerSpec{
+			Trtype: pb.NvmeTransportType_NVME_TRANSPORT_TYPE_TCP,
+			Endpoint: &pb.NvmeControllerSpec_FabricsId{
+				FabricsId: &pb.FabricsEndpoint{
+					Traddr:  d.opts.TargetAddr.String(),
+					Trsvcid: strconv.Itoa(int(d.opts.TargetPort)),
+					Adrfam:  adrfam,
+				},
+			},
+		},
+	}, nil
+}
+
+// volumeBlocks returns the number of blocks of a volume fitting the
+// capacity range
+func (d *Driver) volumeBlocks(capacity *csi.CapacityRange) (int64, error) {
+	required := capacity.GetRequiredBytes()
+	limit := capacity.GetLimitBytes()
+	switch {
+	case required < 0 || limit < 0:
+		return 0, status.Error(codes.InvalidArgument, "capacity cannot be negative")
+	case limit != 0 && required > limit:
+		return 0, status.Errorf(codes.InvalidArgument, "required bytes %v exceed limit bytes %v", required, limit)
+	case required == 0:
+		required = d.opts.DefaultVolumeSize
+		if limit != 0 && limit < required {
+			required = limit
+		}
+	}
+
+	blocks := (required + d.opts.BlockSize - 1) / d.opts.BlockSize
+	if limit != 0 && blocks*d.opts.BlockSize > limit {
+		return 0, status.Errorf(codes.OutOfRange,
+			"no multiple of block size %v between %v and %v bytes", d.opts.BlockSize, required, limit)
+	}
+
+	return blocks, nil
+}
+
+func capacityInRange(capacity int64, capacityRange *csi.CapacityRange) bool {
+	if capacity < capacityRange.GetRequiredBytes() {
+		return false
+	}
+	return capacityRange.GetLimitBytes() == 0 || capacity <= capacityRange.GetLimitBytes()
+}
+
+// validateVolumeCapabilities accepts block and filesystem volumes accessed
+// from a single node
+func validateVolumeCapabilities(capabilities []*csi.VolumeCapability) error {
+	if len(capabilities) == 0 {
+		return status.Error(codes.InvalidArgument, "volume capabilities are required")
+	}
+
+	for _, capability := range capabilities {
+		if capability.GetBlock() == nil && capability.GetMount() == nil {
+			return status.Error(codes.InvalidArgument, "access type is required")
+		}
+		switch mode := capability.GetAccessMode().GetMode(); mode {
+		case csi.VolumeCapability_AccessMode_SINGLE_NODE_WRITER,
+			csi.VolumeCapability_AccessMode_SINGLE_NODE_SINGLE_WRITER,
+			csi.VolumeCapability_AccessMode_SINGLE_NODE_MULTI_WRITER:
+		default:
+			return status.Errorf(codes.InvalidArgument, "access mode %v is not supported", mode)
+		}
+	}
+
+	return nil
+}
+
+func volumeName(volumeID string) string {
+	return resourcename.Join("volumes", volumeID)
+}
+
+// toStatus converts errors of the storage clients to grpc status errors
+func toStatus(err error) error {
+	var conflict *frontend.ConflictError
+	if errors.As(err, &conflict) {
+		return status.Error(codes.AlreadyExists, err.Error())
+	}
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+
+	return status.Error(codes.Internal, fmt.Sprint(err))
+}
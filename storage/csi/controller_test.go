@@ -0,0 +1,154 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (C) 2024 Intel Corporation
+
+// Package csi implements a reference CSI driver exposing OPI storage to
+// hosts over nvme/tcp
+package csi
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/container-storage-interface/spec/lib/go/csi"
+	"github.com/opiproject/godpu/storage/nvme"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestVolumeBlocks(t *testing.T) {
+	tests := map[string]struct {
+		capacity   *csi.CapacityRange
+		wantBlocks int64
+		wantCode   codes.Code
+	}{
+		"default size": {
+			capacity:   nil,
+			wantBlocks: 2048,
+		},
+		"rounded up to block size": {
+			capacity:   &csi.CapacityRange{RequiredBytes: 1025},
+			wantBlocks: 3,
+		},
+		"limit below default size": {
+			capacity:   &csi.CapacityRange{LimitBytes: 4096},
+			wantBlocks: 8,
+		},
+		"required exceeds limit": {
+			capacity: &csi.CapacityRange{RequiredBytes: 4096, LimitBytes: 2048},
+			wantCode: codes.InvalidArgument,
+		},
+		"no block multiple in range": {
+			capacity: &csi.CapacityRange{RequiredBytes: 1000, LimitBytes: 1000},
+			wantCode: codes.OutOfRange,
+		},
+		"negative": {
+			capacity: &csi.CapacityRange{RequiredBytes: -1},
+			wantCode: codes.InvalidArgument,
+		},
+	}
+
+	driver := &Driver{opts: Options{BlockSize: 512, DefaultVolumeSize: 1 << 20}}
+	for testName, tt := range tests {
+		t.Run(testName, func(t *testing.T) {
+			blocks, err := driver.volumeBlocks(tt.capacity)
+			require.Equal(t, tt.wantCode, status.Code(err))
+			require.Equal(t, tt.wantBlocks, blocks)
+		})
+	}
+}
+
+func TestValidateVolumeCapabilities(t *testing.T) {
+	block := &csi.VolumeCapability_Block{Block: &csi.VolumeCapability_BlockVolume{}}
+	tests := map[string]struct {
+		capabilities []*csi.VolumeCapability
+		wantCode     codes.Code
+	}{
+		"single node block": {
+			capabilities: []*csi.VolumeCapability{{
+				AccessType: block,
+				AccessMode: &csi.VolumeCapability_AccessMode{Mode: csi.VolumeCapability_AccessMode_SINGLE_NODE_WRITER},
+			}},
+		},
+		"no capabilities": {
+			wantCode: codes.InvalidArgument,
+		},
+		"single node mount": {
+			capabilities: []*csi.VolumeCapability{{
+				AccessType: &csi.VolumeCapability_Mount{Mount: &csi.VolumeCapability_MountVolume{FsType: "xfs"}},
+				AccessMode: &csi.VolumeCapability_AccessMode{Mode: csi.VolumeCapability_AccessMode_SINGLE_NODE_WRITER},
+			}},
+		},
+		"no access type": {
+			capabilities: []*csi.VolumeCapability{{
+				AccessMode: &csi.VolumeCapability_AccessMode{Mode: csi.VolumeCapability_AccessMode_SINGLE_NODE_WRITER},
+			}},
+			wantCode: codes.InvalidArgument,
+		},
+		"multi node access mode": {
+			capabilities: []*csi.VolumeCapability{{
+				AccessType: block,
+				AccessMode: &csi.VolumeCapability_AccessMode{Mode: csi.VolumeCapability_AccessMode_MULTI_NODE_MULTI_WRITER},
+			}},
+			wantCode: codes.InvalidArgument,
+		},
+	}
+
+	for testName, tt := range tests {
+		t.Run(testName, func(t *testing.T) {
+			err := validateVolumeCapabilities(tt.capabilities)
+			require.Equal(t, tt.wantCode, status.Code(err))
+		})
+	}
+}
+
+func TestControllerPublishVolumeToTwoNodes(t *testing.T) {
+	const otherNodeID = "nqn.2014-08.org.nvmexpress:uuid:0a1b2c3d-4e5f-4a6b-8c7d-9e0f1a2b3c4d"
+	capability := &csi.VolumeCapability{
+		AccessType: &csi.VolumeCapability_Block{Block: &csi.VolumeCapability_BlockVolume{}},
+		AccessMode: &csi.VolumeCapability_AccessMode{Mode: csi.VolumeCapability_AccessMode_SINGLE_NODE_WRITER},
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	driver, node := newTestDriver(t)
+	created, err := driver.CreateVolume(ctx, &csi.CreateVolumeRequest{
+		Name:               "volume0",
+		VolumeCapabilities: []*csi.VolumeCapability{capability},
+	})
+	require.NoError(t, err)
+	volumeID := created.GetVolume().GetVolumeId()
+	ids, err := nvme.IdentifiersFromVolumeID(volumeID)
+	require.NoError(t, err)
+
+	published, err := driver.ControllerPublishVolume(ctx, &csi.ControllerPublishVolumeRequest{
+		VolumeId:         volumeID,
+		NodeId:           testNodeID,
+		VolumeCapability: capability,
+	})
+	require.NoError(t, err)
+	nqn := published.GetPublishContext()[publishContextNqn]
+
+	_, err = driver.ControllerPublishVolume(ctx, &csi.ControllerPublishVolumeRequest{
+		VolumeId:         volumeID,
+		NodeId:           otherNodeID,
+		VolumeCapability: capability,
+	})
+	require.Equal(t, codes.FailedPrecondition, status.Code(err))
+
+	_, err = driver.ControllerUnpublishVolume(ctx, &csi.ControllerUnpublishVolumeRequest{
+		VolumeId: volumeID,
+		NodeId:   otherNodeID,
+	})
+	require.NoError(t, err)
+	require.True(t, node.server.ExposedNamespace(nqn, ids.NGUID.String()))
+
+	_, err = driver.ControllerUnpublishVolume(ctx, &csi.ControllerUnpublishVolumeRequest{
+		VolumeId: volumeID,
+		NodeId:   testNodeID,
+	})
+	require.NoError(t, err)
+	require.False(t, node.server.ExposedNamespace(nqn, ids.NGUID.String()))
+}
@@ -0,0 +1,220 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (C) 2024 Intel Corporation
+
+// Package csi implements a reference CSI driver exposing OPI storage to
+// hosts over nvme/tcp
+package csi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"net/url"
+	"os"
+
+	"github.com/container-storage-interface/spec/lib/go/csi"
+	"github.com/google/uuid"
+	"github.com/opiproject/godpu/storage/backend"
+	"github.com/opiproject/godpu/storage/frontend"
+	"github.com/opiproject/godpu/storage/nvme"
+	"google.golang.org/grpc"
+)
+
+const (
+	// DefaultName is the name the driver registers with by default
+	DefaultName = "csi.godpu.opiproject.org"
+	// DefaultBlockSize is the block size of created volumes in bytes
+	DefaultBlockSize = 512
+	// DefaultVolumeSize is the size of volumes created without capacity range
+	DefaultVolumeSize = 1 << 30
+	// DefaultTargetPort is the nvme/tcp port of exposed volumes
+	DefaultTargetPort = 4420
+)
+
+// Options configures the driver
+type Options struct {
+	// Name of the driver, DefaultName if empty
+	Name string
+	// Version reported by the identity service
+	Version string
+	// NodeID is the host nqn of the node the node service runs on. It is
+	// empty if the driver only runs the controller service.
+	NodeID string
+	// TargetAddr is the ip address hosts connect to for exposed volumes
+	TargetAddr net.IP
+	// TargetPort is the nvme/tcp port hosts connect to, DefaultTargetPort if zero
+	TargetPort uint16
+	// BlockSize of created volumes, DefaultBlockSize if zero
+	BlockSize int64
+	// DefaultVolumeSize is used if no capacity is requested, DefaultVolumeSize if zero
+	DefaultVolumeSize int64
+	// MaxVolumesPerNode is reported to the container orchestrator, zero is unlimited
+	MaxVolumesPerNode int64
+}
+
+// Fabrics connects the node to nvme over fabrics subsystems
+type Fabrics interface {
+	Connect(ctx context.Context, conn FabricsConnection) error
+	Disconnect(ctx context.Context, nqn string) error
+}
+
+// FabricsConnection describes an nvme over fabrics connection of the node
+type FabricsConnection struct {
+	Transport string
+	TrAddr    string
+	TrSvcID   string
+	Nqn       string
+	Hostnqn   string
+}
+
+// DeviceResolver finds the block device of an nvme namespace on the node,
+// it is implemented by nvme.Resolver
+type DeviceResolver interface {
+	WaitForDevice(ctx context.Context, id string) (*nvme.Device, error)
+}
+
+// Mounter mounts staged volumes and publishes them to workloads
+type Mounter interface {
+	// FormatAndMount creates a filesystem on the device unless it already
+	// has one and mounts it
+	FormatAndMount(device, target, fsType string, options []string) error
+	BindMount(source, target string) error
+	Unmount(target string) error
+	IsMountPoint(target string) (bool, error)
+}
+
+// Driver implements the CSI identity, controller and node services on top
+// of OPI storage. Volumes are malloc volumes exposed to nodes over nvme/tcp.
+type Driver struct {
+	csi.UnimplementedIdentityServer
+	csi.UnimplementedControllerServer
+	csi.UnimplementedNodeServer
+
+	opts     Options
+	backend  *backend.Client
+	frontend *frontend.Client
+	fabrics  Fabrics
+	resolver DeviceResolver
+	mounter  Mounter
+}
+
+// New creates a driver managing storage of the OPI server at the given
+// address. tls is in client_cert:client_key:ca_cert format and insecure
+// connections are used if it is empty.
+func New(addr string, tls string, opts Options) (*Driver, error) {
+	backendClient, err := backend.New(addr, tls)
+	if err != nil {
+		return nil, err
+	}
+	frontendClient, err := frontend.New(addr, tls)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewWithArgs(
+		opts,
+		backendClient,
+		frontendClient,
+		NewKernelFabrics(),
+		nvme.NewResolver(""),
+		NewMounter(),
+	)
+}
+
+// NewWithArgs creates a driver with non-default members
+func NewWithArgs(
+	opts Options,
+	backendClient *backend.Client,
+	frontendClient *frontend.Client,
+	fabrics Fabrics,
+	resolver DeviceResolver,
+	mounter Mounter,
+) (*Driver, error) {
+	if backendClient == nil || frontendClient == nil {
+		return nil, errors.New("storage clients are required")
+	}
+	if opts.NodeID != "" {
+		if err := nvme.ValidateNQN(opts.NodeID); err != nil {
+			return nil, fmt.Errorf("node id has to be a host nqn: %w", err)
+		}
+	}
+	if opts.Name == "" {
+		opts.Name = DefaultName
+	}
+	if opts.TargetPort == 0 {
+		opts.TargetPort = DefaultTargetPort
+	}
+	if opts.BlockSize == 0 {
+		opts.BlockSize = DefaultBlockSize
+	}
+	if opts.DefaultVolumeSize == 0 {
+		opts.DefaultVolumeSize = DefaultVolumeSize
+	}
+	if opts.BlockSize < 0 || opts.DefaultVolumeSize < 0 || opts.MaxVolumesPerNode < 0 {
+		return nil, errors.New("sizes and limits cannot be negative")
+	}
+
+	return &Driver{
+		opts:     opts,
+		backend:  backendClient,
+		frontend: frontendClient,
+		fabrics:  fabrics,
+		resolver: resolver,
+		mounter:  mounter,
+	}, nil
+}
+
+// Run serves the CSI services on endpoint until ctx is done. endpoint is
+// either unix:///path/to/socket or tcp://host:port.
+func (d *Driver) Run(ctx context.Context, endpoint string) error {
+	listener, err := listen(endpoint)
+	if err != nil {
+		return err
+	}
+
+	server := grpc.NewServer()
+	csi.RegisterIdentityServer(server, d)
+	csi.RegisterControllerServer(server, d)
+	if d.opts.NodeID != "" {
+		csi.RegisterNodeServer(server, d)
+	}
+
+	go func() {
+		<-ctx.Done()
+		server.GracefulStop()
+	}()
+
+	log.Printf("CSI driver %v serving on %v", d.opts.Name, endpoint)
+	return server.Serve(listener)
+}
+
+func listen(endpoint string) (net.Listener, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("invalid endpoint %q: %w", endpoint, err)
+	}
+
+	switch u.Scheme {
+	case "unix":
+		path := u.Path
+		if u.Host != "" {
+			path = u.Host + path
+		}
+		if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return nil, err
+		}
+		return net.Listen("unix", path)
+	case "tcp":
+		return net.Listen("tcp", u.Host)
+	default:
+		return nil, fmt.Errorf("unsupported endpoint scheme %q, expected unix or tcp", u.Scheme)
+	}
+}
+
+// resourceID derives a deterministic OPI resource id, so retried CSI calls
+// address the same resources
+func resourceID(kind string, name string) string {
+	return "csi-" + uuid.NewSHA1(uuid.NameSpaceOID, []byte(kind+":"+name)).String()
+}
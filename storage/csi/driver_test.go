@@ -0,0 +1,209 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (C) 2024 Intel Corporation
+
+// Package csi implements a reference CSI driver exposing OPI storage to
+// hosts over nvme/tcp
+package csi
+
+import (
+	"context"
+	"net"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/kubernetes-csi/csi-test/v5/pkg/sanity"
+	"github.com/opiproject/godpu/storage/backend"
+	"github.com/opiproject/godpu/storage/frontend"
+	"github.com/opiproject/godpu/storage/nvme"
+	"github.com/opiproject/godpu/testing/mock-server/server"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+)
+
+const testNodeID = "nqn.2014-08.org.nvmexpress:uuid:4f4e8d1c-2a3b-4c5d-8e9f-0a1b2c3d4e5f"
+
+// fakeNode connects to subsystems of the mock OPI server and records bind
+// mounts instead of performing them
+type fakeNode struct {
+	server *server.InMemory
+	devDir string
+
+	mu        sync.Mutex
+	connected map[string]bool
+	mounts    map[string]string
+}
+
+func (n *fakeNode) Connect(_ context.Context, conn FabricsConnection) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	n.connected[conn.Nqn] = true
+	return nil
+}
+
+func (n *fakeNode) Disconnect(_ context.Context, nqn string) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	delete(n.connected, nqn)
+	return nil
+}
+
+func (n *fakeNode) WaitForDevice(_ context.Context, id string) (*nvme.Device, error) {
+	nguid, err := nvme.ParseNGUID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	for nqn := range n.connected {
+		if n.server.ExposedNamespace(nqn, nguid.String()) {
+			return &nvme.Device{Path: filepath.Join(n.devDir, id), NGUID: nguid}, nil
+		}
+	}
+	return nil, nvme.ErrDeviceNotFound
+}
+
+func (n *fakeNode) FormatAndMount(device, target, _ string, _ []string) error {
+	return n.BindMount(device, target)
+}
+
+func (n *fakeNode) BindMount(source, target string) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	n.mounts[target] = source
+	return nil
+}
+
+func (n *fakeNode) Unmount(target string) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	delete(n.mounts, target)
+	return nil
+}
+
+func (n *fakeNode) IsMountPoint(target string) (bool, error) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if _, err := os.Stat(target); err != nil {
+		return false, err
+	}
+	_, ok := n.mounts[target]
+	return ok, nil
+}
+
+func startMockServer(t *testing.T) (*server.InMemory, string) {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	opiServer := server.NewInMemory()
+	grpcServer := grpc.NewServer()
+	opiServer.Register(grpcServer)
+	go func() { _ = grpcServer.Serve(listener) }()
+	t.Cleanup(grpcServer.Stop)
+
+	return opiServer, listener.Addr().String()
+}
+
+func newTestDriver(t *testing.T) (*Driver, *fakeNode) {
+	t.Helper()
+
+	opiServer, addr := startMockServer(t)
+	backendClient, err := backend.New(addr, "")
+	require.NoError(t, err)
+	frontendClient, err := frontend.New(addr, "")
+	require.NoError(t, err)
+
+	node := &fakeNode{
+		server:    opiServer,
+		devDir:    t.TempDir(),
+		connected: map[string]bool{},
+		mounts:    map[string]string{},
+	}
+	driver, err := NewWithArgs(
+		Options{
+			NodeID:     testNodeID,
+			TargetAddr: net.ParseIP("127.0.0.1"),
+		},
+		backendClient,
+		frontendClient,
+		node,
+		node,
+		node,
+	)
+	require.NoError(t, err)
+
+	return driver, node
+}
+
+func TestSanity(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping CSI sanity suite in short mode")
+	}
+
+	driver, node := newTestDriver(t)
+	endpoint := filepath.Join(t.TempDir(), "csi.sock")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- driver.Run(ctx, "unix://"+endpoint) }()
+	t.Cleanup(func() {
+		cancel()
+		require.NoError(t, <-done)
+	})
+
+	config := sanity.NewTestConfig()
+	config.Address = endpoint
+	config.TargetPath = filepath.Join(t.TempDir(), "target")
+	config.StagingPath = filepath.Join(t.TempDir(), "staging")
+	config.TestVolumeSize = 64 << 20
+	config.IdempotentCount = 2
+	sanity.Test(t, config)
+
+	require.Empty(t, node.mounts)
+	require.Empty(t, node.connected)
+}
+
+func TestNewWithArgs(t *testing.T) {
+	tests := map[string]struct {
+		opts    Options
+		wantErr bool
+	}{
+		"defaults": {
+			opts: Options{},
+		},
+		"node id is host nqn": {
+			opts: Options{NodeID: testNodeID},
+		},
+		"node id is not an nqn": {
+			opts:    Options{NodeID: "node0"},
+			wantErr: true,
+		},
+		"negative block size": {
+			opts:    Options{BlockSize: -512},
+			wantErr: true,
+		},
+	}
+
+	for testName, tt := range tests {
+		t.Run(testName, func(t *testing.T) {
+			driver, err := NewWithArgs(tt.opts, &backend.Client{}, &frontend.Client{}, nil, nil, nil)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, DefaultName, driver.opts.Name)
+			require.Equal(t, int64(DefaultBlockSize), driver.opts.BlockSize)
+			require.Equal(t, uint16(DefaultTargetPort), driver.opts.TargetPort)
+		})
+	}
+}
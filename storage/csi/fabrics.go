@@ -0,0 +1,112 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (C) 2024 Intel Corporation
+
+// Package csi implements a reference CSI driver exposing OPI storage to
+// hosts over nvme/tcp
+package csi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/opiproject/godpu/storage/nvme"
+)
+
+// kernelFabrics connects to subsystems using the nvme fabrics driver of
+// the linux kernel
+type kernelFabrics struct {
+	device    string
+	sysfsRoot string
+}
+
+// NewKernelFabrics creates Fabrics using the nvme fabrics driver of the
+// linux kernel
+func NewKernelFabrics() Fabrics {
+	return &kernelFabrics{
+		device:    "/dev/nvme-fabrics",
+		sysfsRoot: nvme.DefaultSysfsRoot,
+	}
+}
+
+// Connect connects to the subsystem unless a controller of the subsystem
+// is already connected
+func (f *kernelFabrics) Connect(_ context.Context, conn FabricsConnection) error {
+	controllers, err := f.controllers(conn.Nqn)
+	if err != nil {
+		return err
+	}
+	if len(controllers) != 0 {
+		return nil
+	}
+
+	options := fmt.Sprintf("transport=%v,traddr=%v,trsvcid=%v,nqn=%v",
+		conn.Transport, conn.TrAddr, conn.TrSvcID, conn.Nqn)
+	if conn.Hostnqn != "" {
+		options += ",hostnqn=" + conn.Hostnqn
+	}
+
+	file, err := os.OpenFile(f.device, os.O_RDWR, 0)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	_, err = file.WriteString(options)
+	return err
+}
+
+// Disconnect deletes all controllers connected to the subsystem
+func (f *kernelFabrics) Disconnect(_ context.Context, nqn string) error {
+	controllers, err := f.controllers(nqn)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, controller := range controllers {
+		err := writeSysfsAttribute(filepath.Join(controller, "delete_controller"), "1")
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// controllers returns the sysfs directories of the fabrics controllers
+// connected to the subsystem
+func (f *kernelFabrics) controllers(nqn string) ([]string, error) {
+	paths, err := filepath.Glob(filepath.Join(f.sysfsRoot, "class", "nvme-fabrics", "ctl", "nvme*"))
+	if err != nil {
+		return nil, err
+	}
+
+	controllers := []string{}
+	for _, path := range paths {
+		subsysnqn, err := os.ReadFile(filepath.Join(path, "subsysnqn"))
+		if err != nil {
+			continue
+		}
+		if strings.TrimSpace(string(subsysnqn)) == nqn {
+			controllers = append(controllers, path)
+		}
+	}
+
+	return controllers, nil
+}
+
+// writeSysfsAttribute writes to an existing sysfs attribute
+func writeSysfsAttribute(path string, value string) error {
+	file, err := os.OpenFile(path, os.O_WRONLY, 0)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	_, err = file.WriteString(value)
+	return err
+}
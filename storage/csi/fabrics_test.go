@@ -0,0 +1,64 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (C) 2024 Intel Corporation
+
+// Package csi implements a reference CSI driver exposing OPI storage to
+// hosts over nvme/tcp
+package csi
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func writeSysfsFile(t *testing.T, path string, content string) {
+	t.Helper()
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+}
+
+func TestKernelFabrics(t *testing.T) {
+	root := t.TempDir()
+	device := filepath.Join(root, "nvme-fabrics")
+	writeSysfsFile(t, device, "")
+	controller := filepath.Join(root, "class", "nvme-fabrics", "ctl", "nvme0")
+	writeSysfsFile(t, filepath.Join(controller, "subsysnqn"), "nqn.2022-09.io.opiproject:connected\n")
+	writeSysfsFile(t, filepath.Join(controller, "delete_controller"), "")
+
+	fabrics := &kernelFabrics{device: device, sysfsRoot: root}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	conn := FabricsConnection{
+		Transport: "tcp",
+		TrAddr:    "127.0.0.1",
+		TrSvcID:   "4420",
+		Nqn:       "nqn.2022-09.io.opiproject:volume0",
+		Hostnqn:   testNodeID,
+	}
+	require.NoError(t, fabrics.Connect(ctx, conn))
+	written, err := os.ReadFile(device)
+	require.NoError(t, err)
+	require.Equal(t,
+		"transport=tcp,traddr=127.0.0.1,trsvcid=4420,nqn=nqn.2022-09.io.opiproject:volume0,hostnqn="+testNodeID,
+		string(written))
+
+	// already connected subsystems are not connected again
+	require.NoError(t, os.Truncate(device, 0))
+	conn.Nqn = "nqn.2022-09.io.opiproject:connected"
+	require.NoError(t, fabrics.Connect(ctx, conn))
+	written, err = os.ReadFile(device)
+	require.NoError(t, err)
+	require.Empty(t, written)
+
+	require.NoError(t, fabrics.Disconnect(ctx, conn.Nqn))
+	deleted, err := os.ReadFile(filepath.Join(controller, "delete_controller"))
+	require.NoError(t, err)
+	require.Equal(t, "1", string(deleted))
+
+	require.NoError(t, fabrics.Disconnect(ctx, "nqn.2022-09.io.opiproject:missing"))
+}
@@ -0,0 +1,100 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (C) 2024 Intel Corporation
+
+//go:build linux
+
+// Package csi implements a reference CSI driver exposing OPI storage to
+// hosts over nvme/tcp
+package csi
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"syscall"
+)
+
+// defaultFsType is the filesystem created if none is requested
+const defaultFsType = "ext4"
+
+type mounter struct {
+	mountInfo string
+}
+
+// NewMounter creates a Mounter using the mount system calls
+func NewMounter() Mounter {
+	return &mounter{mountInfo: "/proc/self/mountinfo"}
+}
+
+func (m *mounter) FormatAndMount(device, target, fsType string, options []string) error {
+	if fsType == "" {
+		fsType = defaultFsType
+	}
+
+	// blkid exits with 2 if the device has no filesystem
+	output, err := exec.Command("blkid", "-p", "-s", "TYPE", "-o", "value", device).Output()
+	var exitErr *exec.ExitError
+	if err != nil && (!errors.As(err, &exitErr) || exitErr.ExitCode() != 2) {
+		return fmt.Errorf("failed to probe %v: %w", device, err)
+	}
+
+	switch existing := strings.TrimSpace(string(output)); existing {
+	case "":
+		if output, err := exec.Command("mkfs."+fsType, device).CombinedOutput(); err != nil {
+			return fmt.Errorf("failed to create %v filesystem: %w: %s", fsType, err, output)
+		}
+	case fsType:
+	default:
+		return fmt.Errorf("device %v has %v filesystem, requested %v", device, existing, fsType)
+	}
+
+	args := []string{"-t", fsType}
+	if len(options) != 0 {
+		args = append(args, "-o", strings.Join(options, ","))
+	}
+	args = append(args, device, target)
+	if output, err := exec.Command("mount", args...).CombinedOutput(); err != nil {
+		return fmt.Errorf("%w: %s", err, output)
+	}
+
+	return nil
+}
+
+func (m *mounter) BindMount(source, target string) error {
+	return syscall.Mount(source, target, "", syscall.MS_BIND, "")
+}
+
+func (m *mounter) Unmount(target string) error {
+	return syscall.Unmount(target, 0)
+}
+
+func (m *mounter) IsMountPoint(target string) (bool, error) {
+	if _, err := os.Stat(target); err != nil {
+		return false, err
+	}
+	target, err := filepath.EvalSymlinks(target)
+	if err != nil {
+		return false, err
+	}
+
+	file, err := os.Open(m.mountInfo)
+	if err != nil {
+		return false, err
+	}
+	defer file.Close()
+
+	// the mount point is the fifth field of a mountinfo line
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) > 4 && fields[4] == target {
+			return true, nil
+		}
+	}
+
+	return false, scanner.Err()
+}
@@ -0,0 +1,35 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (C) 2024 Intel Corporation
+
+//go:build !linux
+
+// Package csi implements a reference CSI driver exposing OPI storage to
+// hosts over nvme/tcp
+package csi
+
+import "errors"
+
+var errMountUnsupported = errors.New("mounting is only supported on linux")
+
+type mounter struct{}
+
+// NewMounter creates a Mounter, which fails on platforms other than linux
+func NewMounter() Mounter {
+	return &mounter{}
+}
+
+func (m *mounter) FormatAndMount(_, _, _ string, _ []string) error {
+	return errMountUnsupported
+}
+
+func (m *mounter) BindMount(_, _ string) error {
+	return errMountUnsupported
+}
+
+func (m *mounter) Unmount(_ string) error {
+	return errMountUnsupported
+}
+
+func (m *mounter) IsMountPoint(_ string) (bool, error) {
+	return false, errMountUnsupported
+}
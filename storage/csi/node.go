@@ -0,0 +1,248 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (C) 2024 Intel Corporation
+
+// Package csi implements a reference CSI driver exposing OPI storage to
+// hosts over nvme/tcp
+package csi
+
+import (
+	"context"
+	"errors"
+	"os"
+
+	"github.com/container-storage-interface/spec/lib/go/csi"
+	"github.com/opiproject/godpu/storage/nvme"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// NodeStageVolume connects the node to the subsystem exposing the volume
+// and waits for its block device to show up. Filesystem volumes are
+// formatted if needed and mounted to the staging path.
+func (d *Driver) NodeStageVolume(
+	ctx context.Context,
+	req *csi.NodeStageVolumeRequest,
+) (*csi.NodeStageVolumeResponse, error) {
+	switch {
+	case req.GetVolumeId() == "":
+		return nil, status.Error(codes.InvalidArgument, "volume id is required")
+	case req.GetStagingTargetPath() == "":
+		return nil, status.Error(codes.InvalidArgument, "staging target path is required")
+	case req.GetVolumeCapability() == nil:
+		return nil, status.Error(codes.InvalidArgument, "volume capability is required")
+	}
+	if err := validateVolumeCapabilities([]*csi.VolumeCapability{req.GetVolumeCapability()}); err != nil {
+		return nil, err
+	}
+
+	publishContext := req.GetPublishContext()
+	conn := FabricsConnection{
+		Transport: publishContext[publishContextTransport],
+		TrAddr:    publishContext[publishContextTrAddr],
+		TrSvcID:   publishContext[publishContextTrSvcID],
+		Nqn:       publishContext[publishContextNqn],
+		Hostnqn:   d.opts.NodeID,
+	}
+	if conn.Transport == "" || conn.TrAddr == "" || conn.TrSvcID == "" || conn.Nqn == "" {
+		return nil, status.Error(codes.InvalidArgument, "publish context is missing connection details")
+	}
+
+	if err := d.fabrics.Connect(ctx, conn); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to connect to %v: %v", conn.Nqn, err)
+	}
+	device, err := d.resolveDevice(ctx, req.GetVolumeId())
+	if err != nil {
+		return nil, err
+	}
+
+	mount := req.GetVolumeCapability().GetMount()
+	if mount == nil {
+		return &csi.NodeStageVolumeResponse{}, nil
+	}
+	staging := req.GetStagingTargetPath()
+	mounted, err := d.mounter.IsMountPoint(staging)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	if mounted {
+		return &csi.NodeStageVolumeResponse{}, nil
+	}
+	if err := os.MkdirAll(staging, 0o750); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to create staging path: %v", err)
+	}
+	if err := d.mounter.FormatAndMount(device, staging, mount.GetFsType(), mount.GetMountFlags()); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to mount %v: %v", device, err)
+	}
+
+	return &csi.NodeStageVolumeResponse{}, nil
+}
+
+// NodeUnstageVolume disconnects the node from the subsystem exposing the
+// volume. Unstaging a volume which is not staged succeeds.
+func (d *Driver) NodeUnstageVolume(
+	ctx context.Context,
+	req *csi.NodeUnstageVolumeRequest,
+) (*csi.NodeUnstageVolumeResponse, error) {
+	switch {
+	case req.GetVolumeId() == "":
+		return nil, status.Error(codes.InvalidArgument, "volume id is required")
+	case req.GetStagingTargetPath() == "":
+		return nil, status.Error(codes.InvalidArgument, "staging target path is required")
+	}
+
+	staging := req.GetStagingTargetPath()
+	mounted, err := d.mounter.IsMountPoint(staging)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	if mounted {
+		if err := d.mounter.Unmount(staging); err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to unmount %v: %v", staging, err)
+		}
+	}
+
+	nqn := volumeNQNPrefix + req.GetVolumeId()
+	if err := d.fabrics.Disconnect(ctx, nqn); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to disconnect from %v: %v", nqn, err)
+	}
+
+	return &csi.NodeUnstageVolumeResponse{}, nil
+}
+
+// NodePublishVolume bind mounts the block device or the staged filesystem
+// of a volume to the target path
+func (d *Driver) NodePublishVolume(
+	ctx context.Context,
+	req *csi.NodePublishVolumeRequest,
+) (*csi.NodePublishVolumeResponse, error) {
+	switch {
+	case req.GetVolumeId() == "":
+		return nil, status.Error(codes.InvalidArgument, "volume id is required")
+	case req.GetTargetPath() == "":
+		return nil, status.Error(codes.InvalidArgument, "target path is required")
+	case req.GetVolumeCapability() == nil:
+		return nil, status.Error(codes.InvalidArgument, "volume capability is required")
+	case req.GetReadonly():
+		return nil, status.Error(codes.InvalidArgument, "readonly volumes are not supported")
+	}
+	if err := validateVolumeCapabilities([]*csi.VolumeCapability{req.GetVolumeCapability()}); err != nil {
+		return nil, err
+	}
+
+	isBlock := req.GetVolumeCapability().GetBlock() != nil
+	if !isBlock && req.GetStagingTargetPath() == "" {
+		return nil, status.Error(codes.InvalidArgument, "staging target path is required")
+	}
+
+	target := req.GetTargetPath()
+	mounted, err := d.mounter.IsMountPoint(target)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	if mounted {
+		return &csi.NodePublishVolumeResponse{}, nil
+	}
+
+	source := req.GetStagingTargetPath()
+	if isBlock {
+		source, err = d.resolveDevice(ctx, req.GetVolumeId())
+		if err != nil {
+			return nil, err
+		}
+		// block devices are bind mounted to a file
+		err = createFile(target)
+	} else {
+		err = os.MkdirAll(target, 0o750)
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to create target path: %v", err)
+	}
+	if err := d.mounter.BindMount(source, target); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to mount %v: %v", source, err)
+	}
+
+	return &csi.NodePublishVolumeResponse{}, nil
+}
+
+// NodeUnpublishVolume unmounts the volume from the target path and removes
+// the target path
+func (d *Driver) NodeUnpublishVolume(
+	_ context.Context,
+	req *csi.NodeUnpublishVolumeRequest,
+) (*csi.NodeUnpublishVolumeResponse, error) {
+	switch {
+	case req.GetVolumeId() == "":
+		return nil, status.Error(codes.InvalidArgument, "volume id is required")
+	case req.GetTargetPath() == "":
+		return nil, status.Error(codes.InvalidArgument, "target path is required")
+	}
+
+	target := req.GetTargetPath()
+	mounted, err := d.mounter.IsMountPoint(target)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	if mounted {
+		if err := d.mounter.Unmount(target); err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to unmount %v: %v", target, err)
+		}
+	}
+	if err := os.Remove(target); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, status.Errorf(codes.Internal, "failed to remove target path: %v", err)
+	}
+
+	return &csi.NodeUnpublishVolumeResponse{}, nil
+}
+
+// NodeGetCapabilities reports that volumes are staged before publishing
+func (d *Driver) NodeGetCapabilities(
+	_ context.Context,
+	_ *csi.NodeGetCapabilitiesRequest,
+) (*csi.NodeGetCapabilitiesResponse, error) {
+	return &csi.NodeGetCapabilitiesResponse{
+		Capabilities: []*csi.NodeServiceCapability{
+			{
+				Type: &csi.NodeServiceCapability_Rpc{
+					Rpc: &csi.NodeServiceCapability_RPC{
+						Type: csi.NodeServiceCapability_RPC_STAGE_UNSTAGE_VOLUME,
+					},
+				},
+			},
+		},
+	}, nil
+}
+
+// NodeGetInfo returns the host nqn of the node as node id
+func (d *Driver) NodeGetInfo(
+	_ context.Context,
+	_ *csi.NodeGetInfoRequest,
+) (*csi.NodeGetInfoResponse, error) {
+	return &csi.NodeGetInfoResponse{
+		NodeId:            d.opts.NodeID,
+		MaxVolumesPerNode: d.opts.MaxVolumesPerNode,
+	}, nil
+}
+
+// resolveDevice finds the block device of a volume by the nguid derived
+// from the volume id
+func (d *Driver) resolveDevice(ctx context.Context, volumeID string) (string, error) {
+	nguid, err := nvme.NGUIDFromVolumeID(volumeID)
+	if err != nil {
+		return "", status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	device, err := d.resolver.WaitForDevice(ctx, nguid.String())
+	if err != nil {
+		return "", status.Errorf(codes.NotFound, "no device for volume %v: %v", volumeID, err)
+	}
+
+	return device.Path, nil
+}
+
+func createFile(path string) error {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_RDONLY, 0o600)
+	if err != nil {
+		return err
+	}
+	return file.Close()
+}
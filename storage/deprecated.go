@@ -0,0 +1,107 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2022-2023 Dell Inc, or its subsidiaries.
+// Copyright (C) 2024 Intel Corporation
+
+// Package storage implements the go library for OPI to be used in storage, for example, CSI drivers
+package storage
+
+import (
+	"context"
+	"time"
+)
+
+const (
+	defaultAddress = "localhost:50051"
+	defaultTimeout = time.Second
+)
+
+func withDefaultClient(f func(ctx context.Context, c *CSIClient) error) error {
+	c, err := NewCSIClient(defaultAddress, "")
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+	defer cancel()
+
+	return f(ctx, c)
+}
+
+// NvmeControllerConnect Connects to remote Nvme controller
+//
+// Deprecated: Use CSIClient.NvmeControllerConnect instead.
+func NvmeControllerConnect(id string, trAddr string, subnqn string, trSvcID int64, hostnqn string) error {
+	return withDefaultClient(func(ctx context.Context, c *CSIClient) error {
+		return c.NvmeControllerConnect(ctx, id, trAddr, subnqn, trSvcID, hostnqn)
+	})
+}
+
+// NvmeControllerList lists all the connections to the remote Nvme controller
+//
+// Deprecated: Use CSIClient.NvmeControllerList instead.
+func NvmeControllerList() ([]NvmeConnection, error) {
+	connections := []NvmeConnection{}
+	err := withDefaultClient(func(ctx context.Context, c *CSIClient) error {
+		var err error
+		connections, err = c.NvmeControllerList(ctx)
+		return err
+	})
+	return connections, err
+}
+
+// NvmeControllerGet lists the connection to the remote Nvme controller corresponding to the given ID
+//
+// Deprecated: Use CSIClient.NvmeControllerGet instead.
+func NvmeControllerGet(id string) (string, error) {
+	nqn := ""
+	err := withDefaultClient(func(ctx context.Context, c *CSIClient) error {
+		var err error
+		nqn, err = c.NvmeControllerGet(ctx, id)
+		return err
+	})
+	return nqn, err
+}
+
+// NvmeControllerDisconnect disconnects remote Nvme controller connection
+//
+// Deprecated: Use CSIClient.NvmeControllerDisconnect instead.
+func NvmeControllerDisconnect(id string) error {
+	return withDefaultClient(func(ctx context.Context, c *CSIClient) error {
+		return c.NvmeControllerDisconnect(ctx, id)
+	})
+}
+
+// ExposeRemoteNvme creates a new Nvme Subsystem and Nvme controller
+//
+// Deprecated: Use CSIClient.ExposeRemoteNvme instead.
+func ExposeRemoteNvme(subsystemNQN string, maxNamespaces int64) (string, string, error) {
+	subsystemID, controllerID := "", ""
+	err := withDefaultClient(func(ctx context.Context, c *CSIClient) error {
+		var err error
+		subsystemID, controllerID, err = c.ExposeRemoteNvme(ctx, subsystemNQN, maxNamespaces)
+		return err
+	})
+	return subsystemID, controllerID, err
+}
+
+// CreateNvmeNamespace Creates a new Nvme namespace
+//
+// Deprecated: Use CSIClient.CreateNvmeNamespace instead.
+func CreateNvmeNamespace(id string, subSystemID string, nguid string, hostID int32) (string, error) {
+	name := ""
+	err := withDefaultClient(func(ctx context.Context, c *CSIClient) error {
+		var err error
+		name, err = c.CreateNvmeNamespace(ctx, id, subSystemID, nguid, hostID)
+		return err
+	})
+	return name, err
+}
+
+// DeleteNvmeNamespace deletes the Nvme namespace
+//
+// Deprecated: Use CSIClient.DeleteNvmeNamespace instead.
+func DeleteNvmeNamespace(id string) error {
+	return withDefaultClient(func(ctx context.Context, c *CSIClient) error {
+		return c.DeleteNvmeNamespace(ctx, id)
+	})
+}
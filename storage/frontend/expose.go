@@ -0,0 +1,361 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (C) 2024 Intel Corporation
+
+// Package frontend implements the go library for OPI frontend storage
+package frontend
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/opiproject/godpu/storage/nvme"
+	pb "github.com/opiproject/opi-api/storage/v1alpha1/gen/go"
+	"go.einride.tech/aip/resourcename"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// rollbackTimeout limits the time spent on deleting partially exposed resources
+const rollbackTimeout = 10 * time.Second
+
+// rollbackFunc deletes a resource created while exposing a volume
+type rollbackFunc func(ctx context.Context) error
+
+// ExposeVolumeSpec describes how a volume is exposed to hosts over nvme.
+// Resource ids are required, they make exposing idempotent.
+type ExposeVolumeSpec struct {
+	SubsystemID   string
+	Nqn           string
+	Hostnqn       string
+	Psk           []byte
+	MaxNamespaces int64
+
+	NamespaceID string
+	Volume      string
+	Identifiers nvme.NamespaceIdentifiers
+
+	ControllerID string
+	// Controller defines the transport and endpoint of the controller
+	Controller *pb.NvmeControllerSpec
+}
+
+// ExposedVolume contains the resources exposing a volume to hosts
+type ExposedVolume struct {
+	Subsystem  *pb.NvmeSubsystem
+	Namespace  *pb.NvmeNamespace
+	Controller *pb.NvmeController
+}
+
+// ConflictError is returned if a resource with the requested id already
+// exists, but has a spec different from the requested one
+type ConflictError struct {
+	Name  string
+	Field string
+}
+
+func (e *ConflictError) Error() string {
+	return fmt.Sprintf("%v already exists with different %v", e.Name, e.Field)
+}
+
+// SubsystemName returns the resource name of an nvme subsystem
+func SubsystemName(subsystemID string) string {
+	return resourcename.Join("nvmeSubsystems", subsystemID)
+}
+
+// NamespaceName returns the resource name of an nvme namespace
+func NamespaceName(subsystemID, namespaceID string) string {
+	return resourcename.Join(
+		"nvmeSubsystems", subsystemID,
+		"nvmeNamespaces", namespaceID,
+	)
+}
+
+// ControllerName returns the resource name of an nvme controller
+func ControllerName(subsystemID, controllerID string) string {
+	return resourcename.Join(
+		"nvmeSubsystems", subsystemID,
+		"nvmeControllers", controllerID,
+	)
+}
+
+// ExposeVolume creates or reuses the nvme subsystem, namespace and controller
+// required to expose a volume to hosts. If a step fails, the resources
+// created by this call are deleted in reverse order. Existing resources are
+// reused only if they match the spec, otherwise a *ConflictError is
+// returned. Max namespaces and namespace identifiers left zero in the spec
+// match any value.
+func (c *Client) ExposeVolume(
+	ctx context.Context,
+	spec ExposeVolumeSpec,
+) (*ExposedVolume, error) {
+	if err := validateExposeVolumeSpec(spec); err != nil {
+		return nil, err
+	}
+
+	conn, connClose, err := c.connector.NewConn()
+	if err != nil {
+		return nil, err
+	}
+	defer connClose()
+
+	client := c.createFrontendNvmeClient(conn)
+	exposed := &ExposedVolume{}
+	var rollback []rollbackFunc
+
+	exposed.Subsystem, err = exposeNvmeSubsystem(ctx, client, spec, &rollback)
+	if err == nil {
+		exposed.Namespace, err = exposeNvmeNamespace(ctx, client, spec, &rollback)
+	}
+	if err == nil {
+		exposed.Controller, err = exposeNvmeController(ctx, client, spec, &rollback)
+	}
+	if err != nil {
+		return nil, errors.Join(err, rollbackExposedVolume(ctx, rollback))
+	}
+
+	return exposed, nil
+}
+
+// UnexposeVolume deletes the nvme namespace of an exposed volume. The
+// controller and subsystem are deleted once no other namespace is left in
+// the subsystem, also if ExposeVolume reused rather than created them, since
+// no record of who created them is kept. Callers exposing volumes through a
+// subsystem which has to outlive them should delete the namespace only.
+// Missing resources are ignored.
+func (c *Client) UnexposeVolume(
+	ctx context.Context,
+	spec ExposeVolumeSpec,
+) error {
+	if spec.SubsystemID == "" || spec.NamespaceID == "" || spec.ControllerID == "" {
+		return errors.New("subsystem, namespace and controller ids are required")
+	}
+
+	conn, connClose, err := c.connector.NewConn()
+	if err != nil {
+		return err
+	}
+	defer connClose()
+
+	client := c.createFrontendNvmeClient(conn)
+	_, err = client.DeleteNvmeNamespace(
+		ctx,
+		&pb.DeleteNvmeNamespaceRequest{
+			Name:         NamespaceName(spec.SubsystemID, spec.NamespaceID),
+			AllowMissing: true,
+		})
+	if err != nil {
+		return err
+	}
+
+	subsystem := SubsystemName(spec.SubsystemID)
+	namespaces, err := client.ListNvmeNamespaces(
+		ctx,
+		&pb.ListNvmeNamespacesRequest{
+			Parent: subsystem,
+		})
+	switch {
+	case status.Code(err) == codes.NotFound:
+		return nil
+	case err != nil:
+		return err
+	case len(namespaces.NvmeNamespaces) != 0:
+		return nil
+	}
+
+	_, err = client.DeleteNvmeController(
+		ctx,
+		&pb.DeleteNvmeControllerRequest{
+			Name:         ControllerName(spec.SubsystemID, spec.ControllerID),
+			AllowMissing: true,
+		})
+	if err != nil {
+		return err
+	}
+
+	_, err = client.DeleteNvmeSubsystem(
+		ctx,
+		&pb.DeleteNvmeSubsystemRequest{
+			Name:         subsystem,
+			AllowMissing: true,
+		})
+
+	return err
+}
+
+func validateExposeVolumeSpec(spec ExposeVolumeSpec) error {
+	if spec.SubsystemID == "" || spec.NamespaceID == "" || spec.ControllerID == "" {
+		return errors.New("subsystem, namespace and controller ids are required")
+	}
+	if spec.Volume == "" {
+		return errors.New("volume is required")
+	}
+	if spec.Controller == nil {
+		return errors.New("controller spec is required")
+	}
+	if err := nvme.ValidateNQN(spec.Nqn); err != nil {
+		return err
+	}
+	if spec.Hostnqn != "" {
+		if err := nvme.ValidateNQN(spec.Hostnqn); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func exposeNvmeSubsystem(
+	ctx context.Context,
+	client pb.FrontendNvmeServiceClient,
+	spec ExposeVolumeSpec,
+	rollback *[]rollbackFunc,
+) (*pb.NvmeSubsystem, error) {
+	name := SubsystemName(spec.SubsystemID)
+	subsystem, err := client.GetNvmeSubsystem(ctx, &pb.GetNvmeSubsystemRequest{Name: name})
+	if err == nil {
+		switch {
+		case subsystem.GetSpec().GetNqn() != spec.Nqn:
+			return nil, &ConflictError{Name: name, Field: "nqn"}
+		case subsystem.GetSpec().GetHostnqn() != spec.Hostnqn:
+			return nil, &ConflictError{Name: name, Field: "hostnqn"}
+		case !bytes.Equal(subsystem.GetSpec().GetPsk(), spec.Psk):
+			return nil, &ConflictError{Name: name, Field: "psk"}
+		case spec.MaxNamespaces != 0 && subsystem.GetSpec().GetMaxNamespaces() != spec.MaxNamespaces:
+			return nil, &ConflictError{Name: name, Field: "max_namespaces"}
+		}
+		return subsystem, nil
+	}
+	if status.Code(err) != codes.NotFound {
+		return nil, err
+	}
+
+	subsystem, err = client.CreateNvmeSubsystem(
+		ctx,
+		&pb.CreateNvmeSubsystemRequest{
+			NvmeSubsystemId: spec.SubsystemID,
+			NvmeSubsystem: &pb.NvmeSubsystem{
+				Spec: &pb.NvmeSubsystemSpec{
+					Nqn:           spec.Nqn,
+					Hostnqn:       spec.Hostnqn,
+					Psk:           spec.Psk,
+					MaxNamespaces: spec.MaxNamespaces,
+				},
+			},
+		})
+	if err != nil {
+		return nil, err
+	}
+	*rollback = append(*rollback, func(ctx context.Context) error {
+		_, err := client.DeleteNvmeSubsystem(ctx, &pb.DeleteNvmeSubsystemRequest{Name: subsystem.Name, AllowMissing: true})
+		return err
+	})
+
+	return subsystem, nil
+}
+
+func exposeNvmeNamespace(
+	ctx context.Context,
+	client pb.FrontendNvmeServiceClient,
+	spec ExposeVolumeSpec,
+	rollback *[]rollbackFunc,
+) (*pb.NvmeNamespace, error) {
+	name := NamespaceName(spec.SubsystemID, spec.NamespaceID)
+	namespace, err := client.GetNvmeNamespace(ctx, &pb.GetNvmeNamespaceRequest{Name: name})
+	if err == nil {
+		want := newNvmeNamespaceSpec(spec.Volume, spec.Identifiers)
+		switch {
+		case namespace.GetSpec().GetVolumeNameRef() != want.VolumeNameRef:
+			return nil, &ConflictError{Name: name, Field: "volume"}
+		case want.Nguid != "" && !strings.EqualFold(namespace.GetSpec().GetNguid(), want.Nguid):
+			return nil, &ConflictError{Name: name, Field: "nguid"}
+		case want.Eui64 != 0 && namespace.GetSpec().GetEui64() != want.Eui64:
+			return nil, &ConflictError{Name: name, Field: "eui64"}
+		case want.Uuid != "" && !strings.EqualFold(namespace.GetSpec().GetUuid(), want.Uuid):
+			return nil, &ConflictError{Name: name, Field: "uuid"}
+		}
+		return namespace, nil
+	}
+	if status.Code(err) != codes.NotFound {
+		return nil, err
+	}
+
+	namespace, err = client.CreateNvmeNamespace(
+		ctx,
+		&pb.CreateNvmeNamespaceRequest{
+			Parent:          SubsystemName(spec.SubsystemID),
+			NvmeNamespaceId: spec.NamespaceID,
+			NvmeNamespace: &pb.NvmeNamespace{
+				Spec: newNvmeNamespaceSpec(spec.Volume, spec.Identifiers),
+			},
+		})
+	if err != nil {
+		return nil, err
+	}
+	*rollback = append(*rollback, func(ctx context.Context) error {
+		_, err := client.DeleteNvmeNamespace(ctx, &pb.DeleteNvmeNamespaceRequest{Name: namespace.Name, AllowMissing: true})
+		return err
+	})
+
+	return namespace, nil
+}
+
+func exposeNvmeController(
+	ctx context.Context,
+	client pb.FrontendNvmeServiceClient,
+	spec ExposeVolumeSpec,
+	rollback *[]rollbackFunc,
+) (*pb.NvmeController, error) {
+	name := ControllerName(spec.SubsystemID, spec.ControllerID)
+	controller, err := client.GetNvmeController(ctx, &pb.GetNvmeControllerRequest{Name: name})
+	if err == nil {
+		if controller.GetSpec().GetTrtype() != spec.Controller.GetTrtype() {
+			return nil, &ConflictError{Name: name, Field: "trtype"}
+		}
+		if !proto.Equal(controller.GetSpec().GetFabricsId(), spec.Controller.GetFabricsId()) ||
+			!proto.Equal(controller.GetSpec().GetPcieId(), spec.Controller.GetPcieId()) {
+			return nil, &ConflictError{Name: name, Field: "endpoint"}
+		}
+		return controller, nil
+	}
+	if status.Code(err) != codes.NotFound {
+		return nil, err
+	}
+
+	controller, err = client.CreateNvmeController(
+		ctx,
+		&pb.CreateNvmeControllerRequest{
+			Parent:           SubsystemName(spec.SubsystemID),
+			NvmeControllerId: spec.ControllerID,
+			NvmeController: &pb.NvmeController{
+				Spec: proto.Clone(spec.Controller).(*pb.NvmeControllerSpec),
+			},
+		})
+	if err != nil {
+		return nil, err
+	}
+	*rollback = append(*rollback, func(ctx context.Context) error {
+		_, err := client.DeleteNvmeController(ctx, &pb.DeleteNvmeControllerRequest{Name: controller.Name, AllowMissing: true})
+		return err
+	})
+
+	return controller, nil
+}
+
+func rollbackExposedVolume(ctx context.Context, rollback []rollbackFunc) error {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), rollbackTimeout)
+	defer cancel()
+
+	var errs []error
+	for i := len(rollback) - 1; i >= 0; i-- {
+		if err := rollback[i](ctx); err != nil {
+			errs = append(errs, fmt.Errorf("failed to roll back exposed volume: %w", err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,351 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (C) 2024 Intel Corporation
+
+// Package frontend implements the go library for OPI frontend storage
+package frontend
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/opiproject/godpu/mocks"
+	"github.com/opiproject/godpu/storage/nvme"
+	pb "github.com/opiproject/opi-api/storage/v1alpha1/gen/go"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+func TestExposeVolume(t *testing.T) {
+	testSpec := ExposeVolumeSpec{
+		SubsystemID:  "subsys0",
+		Nqn:          "nqn.2022-09.io.spdk:opitest0",
+		NamespaceID:  "ns0",
+		Volume:       "Malloc0",
+		ControllerID: "ctrl0",
+		Controller: &pb.NvmeControllerSpec{
+			Trtype: pb.NvmeTransportType_NVME_TRANSPORT_TYPE_TCP,
+			Endpoint: &pb.NvmeControllerSpec_FabricsId{
+				FabricsId: &pb.FabricsEndpoint{
+					Traddr:  "127.0.0.1",
+					Trsvcid: "4420",
+					Adrfam:  pb.NvmeAddressFamily_NVME_ADDRESS_FAMILY_IPV4,
+				},
+			},
+		},
+	}
+	testSubsystem := &pb.NvmeSubsystem{
+		Name: "nvmeSubsystems/subsys0",
+		Spec: &pb.NvmeSubsystemSpec{Nqn: testSpec.Nqn},
+	}
+	testNamespace := &pb.NvmeNamespace{
+		Name: "nvmeSubsystems/subsys0/nvmeNamespaces/ns0",
+		Spec: &pb.NvmeNamespaceSpec{VolumeNameRef: testSpec.Volume},
+	}
+	testController := &pb.NvmeController{
+		Name: "nvmeSubsystems/subsys0/nvmeControllers/ctrl0",
+		Spec: proto.Clone(testSpec.Controller).(*pb.NvmeControllerSpec),
+	}
+	testIdentifiers, err := nvme.IdentifiersFromVolumeID("volume0")
+	require.NoError(t, err)
+	notFound := status.Error(codes.NotFound, "not found")
+
+	tests := map[string]struct {
+		giveSpec              ExposeVolumeSpec
+		giveExisting          bool
+		giveConflictSubsystem *pb.NvmeSubsystemSpec
+		giveConflictNamespace *pb.NvmeNamespaceSpec
+		giveConflictAddr      bool
+		giveControllerErr     error
+		wantErr               error
+		wantConnCreated       bool
+		wantExposed           *ExposedVolume
+		wantRollback          bool
+	}{
+		"create all resources": {
+			giveSpec:        testSpec,
+			wantErr:         nil,
+			wantConnCreated: true,
+			wantExposed: &ExposedVolume{
+				Subsystem:  testSubsystem,
+				Namespace:  testNamespace,
+				Controller: testController,
+			},
+		},
+		"reuse existing resources": {
+			giveSpec:        testSpec,
+			giveExisting:    true,
+			wantErr:         nil,
+			wantConnCreated: true,
+			wantExposed: &ExposedVolume{
+				Subsystem:  testSubsystem,
+				Namespace:  testNamespace,
+				Controller: testController,
+			},
+		},
+		"controller err rolls back": {
+			giveSpec:          testSpec,
+			giveControllerErr: errors.New("Some client error"),
+			wantErr:           errors.New("Some client error"),
+			wantConnCreated:   true,
+			wantExposed:       nil,
+			wantRollback:      true,
+		},
+		"conflicting subsystem": {
+			giveSpec:              testSpec,
+			giveConflictSubsystem: &pb.NvmeSubsystemSpec{Nqn: "nqn.2022-09.io.spdk:other"},
+			wantErr:               &ConflictError{Name: "nvmeSubsystems/subsys0", Field: "nqn"},
+			wantConnCreated:       true,
+			wantExposed:           nil,
+		},
+		"conflicting subsystem hostnqn": {
+			giveSpec:              testSpec,
+			giveConflictSubsystem: &pb.NvmeSubsystemSpec{Nqn: testSpec.Nqn, Hostnqn: "nqn.2014-08.org.nvmexpress:uuid:other"},
+			wantErr:               &ConflictError{Name: "nvmeSubsystems/subsys0", Field: "hostnqn"},
+			wantConnCreated:       true,
+			wantExposed:           nil,
+		},
+		"conflicting subsystem psk": {
+			giveSpec:              testSpec,
+			giveConflictSubsystem: &pb.NvmeSubsystemSpec{Nqn: testSpec.Nqn, Psk: []byte("other psk")},
+			wantErr:               &ConflictError{Name: "nvmeSubsystems/subsys0", Field: "psk"},
+			wantConnCreated:       true,
+			wantExposed:           nil,
+		},
+		"conflicting subsystem max namespaces": {
+			giveSpec: func() ExposeVolumeSpec {
+				spec := testSpec
+				spec.MaxNamespaces = 1
+				return spec
+			}(),
+			giveConflictSubsystem: &pb.NvmeSubsystemSpec{Nqn: testSpec.Nqn, MaxNamespaces: 32},
+			wantErr:               &ConflictError{Name: "nvmeSubsystems/subsys0", Field: "max_namespaces"},
+			wantConnCreated:       true,
+			wantExposed:           nil,
+		},
+		"conflicting namespace nguid": {
+			giveSpec: func() ExposeVolumeSpec {
+				spec := testSpec
+				spec.Identifiers = testIdentifiers
+				return spec
+			}(),
+			giveConflictNamespace: &pb.NvmeNamespaceSpec{VolumeNameRef: testSpec.Volume, Nguid: "0123456789abcdef0123456789abcdef"},
+			wantErr:               &ConflictError{Name: "nvmeSubsystems/subsys0/nvmeNamespaces/ns0", Field: "nguid"},
+			wantConnCreated:       true,
+			wantExposed:           nil,
+		},
+		"conflicting controller endpoint": {
+			giveSpec:         testSpec,
+			giveConflictAddr: true,
+			wantErr:          &ConflictError{Name: "nvmeSubsystems/subsys0/nvmeControllers/ctrl0", Field: "endpoint"},
+			wantConnCreated:  true,
+			wantExposed:      nil,
+		},
+		"missing ids": {
+			giveSpec:        ExposeVolumeSpec{Nqn: testSpec.Nqn, Volume: "Malloc0"},
+			wantErr:         errors.New("subsystem, namespace and controller ids are required"),
+			wantConnCreated: false,
+			wantExposed:     nil,
+		},
+	}
+
+	for testName, tt := range tests {
+		t.Run(testName, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
+
+			mockClient := mocks.NewFrontendNvmeServiceClient(t)
+			switch {
+			case tt.giveConflictSubsystem != nil:
+				mockClient.EXPECT().GetNvmeSubsystem(ctx, &pb.GetNvmeSubsystemRequest{Name: testSubsystem.Name}).
+					Return(&pb.NvmeSubsystem{Name: testSubsystem.Name, Spec: tt.giveConflictSubsystem}, nil)
+			case tt.giveConflictNamespace != nil:
+				mockClient.EXPECT().GetNvmeSubsystem(ctx, &pb.GetNvmeSubsystemRequest{Name: testSubsystem.Name}).
+					Return(proto.Clone(testSubsystem).(*pb.NvmeSubsystem), nil)
+				mockClient.EXPECT().GetNvmeNamespace(ctx, &pb.GetNvmeNamespaceRequest{Name: testNamespace.Name}).
+					Return(&pb.NvmeNamespace{Name: testNamespace.Name, Spec: tt.giveConflictNamespace}, nil)
+			case tt.giveConflictAddr:
+				mockClient.EXPECT().GetNvmeSubsystem(ctx, &pb.GetNvmeSubsystemRequest{Name: testSubsystem.Name}).
+					Return(proto.Clone(testSubsystem).(*pb.NvmeSubsystem), nil)
+				mockClient.EXPECT().GetNvmeNamespace(ctx, &pb.GetNvmeNamespaceRequest{Name: testNamespace.Name}).
+					Return(proto.Clone(testNamespace).(*pb.NvmeNamespace), nil)
+				otherController := proto.Clone(testController).(*pb.NvmeController)
+				otherController.GetSpec().GetFabricsId().Traddr = "127.0.0.2"
+				mockClient.EXPECT().GetNvmeController(ctx, &pb.GetNvmeControllerRequest{Name: testController.Name}).
+					Return(otherController, nil)
+			case tt.giveExisting:
+				mockClient.EXPECT().GetNvmeSubsystem(ctx, &pb.GetNvmeSubsystemRequest{Name: testSubsystem.Name}).
+					Return(proto.Clone(testSubsystem).(*pb.NvmeSubsystem), nil)
+				mockClient.EXPECT().GetNvmeNamespace(ctx, &pb.GetNvmeNamespaceRequest{Name: testNamespace.Name}).
+					Return(proto.Clone(testNamespace).(*pb.NvmeNamespace), nil)
+				mockClient.EXPECT().GetNvmeController(ctx, &pb.GetNvmeControllerRequest{Name: testController.Name}).
+					Return(proto.Clone(testController).(*pb.NvmeController), nil)
+			case tt.wantConnCreated:
+				mockClient.EXPECT().GetNvmeSubsystem(ctx, &pb.GetNvmeSubsystemRequest{Name: testSubsystem.Name}).
+					Return(nil, notFound)
+				mockClient.EXPECT().CreateNvmeSubsystem(ctx, &pb.CreateNvmeSubsystemRequest{
+					NvmeSubsystemId: "subsys0",
+					NvmeSubsystem:   &pb.NvmeSubsystem{Spec: &pb.NvmeSubsystemSpec{Nqn: testSpec.Nqn}},
+				}).Return(proto.Clone(testSubsystem).(*pb.NvmeSubsystem), nil)
+				mockClient.EXPECT().GetNvmeNamespace(ctx, &pb.GetNvmeNamespaceRequest{Name: testNamespace.Name}).
+					Return(nil, notFound)
+				mockClient.EXPECT().CreateNvmeNamespace(ctx, &pb.CreateNvmeNamespaceRequest{
+					Parent:          testSubsystem.Name,
+					NvmeNamespaceId: "ns0",
+					NvmeNamespace:   &pb.NvmeNamespace{Spec: &pb.NvmeNamespaceSpec{VolumeNameRef: testSpec.Volume}},
+				}).Return(proto.Clone(testNamespace).(*pb.NvmeNamespace), nil)
+				mockClient.EXPECT().GetNvmeController(ctx, &pb.GetNvmeControllerRequest{Name: testController.Name}).
+					Return(nil, notFound)
+				var toReturn *pb.NvmeController
+				if tt.giveControllerErr == nil {
+					toReturn = proto.Clone(testController).(*pb.NvmeController)
+				}
+				mockClient.EXPECT().CreateNvmeController(ctx, &pb.CreateNvmeControllerRequest{
+					Parent:           testSubsystem.Name,
+					NvmeControllerId: "ctrl0",
+					NvmeController:   &pb.NvmeController{Spec: proto.Clone(testSpec.Controller).(*pb.NvmeControllerSpec)},
+				}).Return(toReturn, tt.giveControllerErr)
+			}
+			rollbackOrder := []string{}
+			if tt.wantRollback {
+				mockClient.EXPECT().DeleteNvmeNamespace(mock.Anything, &pb.DeleteNvmeNamespaceRequest{
+					Name: testNamespace.Name, AllowMissing: true,
+				}).Run(func(context.Context, *pb.DeleteNvmeNamespaceRequest, ...grpc.CallOption) {
+					rollbackOrder = append(rollbackOrder, testNamespace.Name)
+				}).Return(&emptypb.Empty{}, nil)
+				mockClient.EXPECT().DeleteNvmeSubsystem(mock.Anything, &pb.DeleteNvmeSubsystemRequest{
+					Name: testSubsystem.Name, AllowMissing: true,
+				}).Run(func(context.Context, *pb.DeleteNvmeSubsystemRequest, ...grpc.CallOption) {
+					rollbackOrder = append(rollbackOrder, testSubsystem.Name)
+				}).Return(&emptypb.Empty{}, nil)
+			}
+
+			connClosed := false
+			mockConn := mocks.NewConnector(t)
+			if tt.wantConnCreated {
+				mockConn.EXPECT().NewConn().Return(
+					&grpc.ClientConn{},
+					func() { connClosed = true },
+					nil,
+				)
+			}
+
+			c, _ := NewWithArgs(
+				mockConn,
+				func(grpc.ClientConnInterface) pb.FrontendNvmeServiceClient {
+					return mockClient
+				},
+				pb.NewFrontendVirtioBlkServiceClient,
+			)
+
+			exposed, err := c.ExposeVolume(ctx, tt.giveSpec)
+
+			if tt.wantErr == nil {
+				require.NoError(t, err)
+			} else {
+				require.EqualError(t, err, tt.wantErr.Error())
+			}
+			if tt.wantExposed == nil {
+				require.Nil(t, exposed)
+			} else {
+				require.True(t, proto.Equal(tt.wantExposed.Subsystem, exposed.Subsystem))
+				require.True(t, proto.Equal(tt.wantExposed.Namespace, exposed.Namespace))
+				require.True(t, proto.Equal(tt.wantExposed.Controller, exposed.Controller))
+			}
+			if tt.wantRollback {
+				require.Equal(t, []string{testNamespace.Name, testSubsystem.Name}, rollbackOrder)
+			}
+			require.Equal(t, tt.wantConnCreated, connClosed)
+		})
+	}
+}
+
+func TestUnexposeVolume(t *testing.T) {
+	testSpec := ExposeVolumeSpec{
+		SubsystemID:  "subsys0",
+		NamespaceID:  "ns0",
+		ControllerID: "ctrl0",
+	}
+
+	tests := map[string]struct {
+		giveNamespacesLeft []*pb.NvmeNamespace
+		giveListErr        error
+		wantErr            error
+		wantSubsystemGone  bool
+	}{
+		"last namespace deletes shared controller and subsystem": {
+			giveNamespacesLeft: nil,
+			wantErr:            nil,
+			wantSubsystemGone:  true,
+		},
+		"other namespaces left": {
+			giveNamespacesLeft: []*pb.NvmeNamespace{{Name: "nvmeSubsystems/subsys0/nvmeNamespaces/ns1"}},
+			wantErr:            nil,
+			wantSubsystemGone:  false,
+		},
+		"subsystem missing": {
+			giveListErr:       status.Error(codes.NotFound, "not found"),
+			wantErr:           nil,
+			wantSubsystemGone: false,
+		},
+		"list err": {
+			giveListErr:       errors.New("Some client error"),
+			wantErr:           errors.New("Some client error"),
+			wantSubsystemGone: false,
+		},
+	}
+
+	for testName, tt := range tests {
+		t.Run(testName, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
+
+			mockClient := mocks.NewFrontendNvmeServiceClient(t)
+			mockClient.EXPECT().DeleteNvmeNamespace(ctx, &pb.DeleteNvmeNamespaceRequest{
+				Name: "nvmeSubsystems/subsys0/nvmeNamespaces/ns0", AllowMissing: true,
+			}).Return(&emptypb.Empty{}, nil)
+			var listResponse *pb.ListNvmeNamespacesResponse
+			if tt.giveListErr == nil {
+				listResponse = &pb.ListNvmeNamespacesResponse{NvmeNamespaces: tt.giveNamespacesLeft}
+			}
+			mockClient.EXPECT().ListNvmeNamespaces(ctx, &pb.ListNvmeNamespacesRequest{
+				Parent: "nvmeSubsystems/subsys0",
+			}).Return(listResponse, tt.giveListErr)
+			if tt.wantSubsystemGone {
+				mockClient.EXPECT().DeleteNvmeController(ctx, &pb.DeleteNvmeControllerRequest{
+					Name: "nvmeSubsystems/subsys0/nvmeControllers/ctrl0", AllowMissing: true,
+				}).Return(&emptypb.Empty{}, nil)
+				mockClient.EXPECT().DeleteNvmeSubsystem(ctx, &pb.DeleteNvmeSubsystemRequest{
+					Name: "nvmeSubsystems/subsys0", AllowMissing: true,
+				}).Return(&emptypb.Empty{}, nil)
+			}
+
+			connClosed := false
+			mockConn := mocks.NewConnector(t)
+			mockConn.EXPECT().NewConn().Return(
+				&grpc.ClientConn{},
+				func() { connClosed = true },
+				nil,
+			)
+
+			c, _ := NewWithArgs(
+				mockConn,
+				func(grpc.ClientConnInterface) pb.FrontendNvmeServiceClient {
+					return mockClient
+				},
+				pb.NewFrontendVirtioBlkServiceClient,
+			)
+
+			err := c.UnexposeVolume(ctx, testSpec)
+
+			require.Equal(t, tt.wantErr, err)
+			require.True(t, connClosed)
+		})
+	}
+}
@@ -5,11 +5,15 @@
 package frontend
 
 import (
+	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"net"
 
+	"github.com/opiproject/godpu/storage/nvme"
 	pb "github.com/opiproject/opi-api/storage/v1alpha1/gen/go"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
 	"google.golang.org/protobuf/types/known/wrapperspb"
 )
 
@@ -19,6 +23,51 @@ func (c *Client) CreateNvmeTCPController(
 	id, subsystem string,
 	ip net.IP,
 	port uint16,
+) (*pb.NvmeController, error) {
+	return c.createNvmeFabricsController(
+		ctx, id, subsystem, pb.NvmeTransportType_NVME_TRANSPORT_TYPE_TCP, ip, port, nil)
+}
+
+// CreateNvmeTCPTLSController creates an nvme TCP controller which requires
+// a secure channel established with the given TLS pre-shared key.
+// OPI carries the key in the subsystem spec, so it is set on the parent
+// subsystem before the controller is created and cleared again if the
+// controller cannot be created. A *ConflictError is returned if the
+// subsystem already has a different key, since changing it would re-key
+// every other controller of the subsystem.
+func (c *Client) CreateNvmeTCPTLSController(
+	ctx context.Context,
+	id, subsystem string,
+	ip net.IP,
+	port uint16,
+	psk []byte,
+) (*pb.NvmeController, error) {
+	if len(psk) == 0 {
+		return nil, errors.New("empty psk is not allowed for TLS controller")
+	}
+
+	return c.createNvmeFabricsController(
+		ctx, id, subsystem, pb.NvmeTransportType_NVME_TRANSPORT_TYPE_TCP, ip, port, psk)
+}
+
+// CreateNvmeRdmaController creates an nvme RDMA controller
+func (c *Client) CreateNvmeRdmaController(
+	ctx context.Context,
+	id, subsystem string,
+	ip net.IP,
+	port uint16,
+) (*pb.NvmeController, error) {
+	return c.createNvmeFabricsController(
+		ctx, id, subsystem, pb.NvmeTransportType_NVME_TRANSPORT_TYPE_RDMA, ip, port, nil)
+}
+
+func (c *Client) createNvmeFabricsController(
+	ctx context.Context,
+	id, subsystem string,
+	trtype pb.NvmeTransportType,
+	ip net.IP,
+	port uint16,
+	psk []byte,
 ) (*pb.NvmeController, error) {
 	conn, connClose, err := c.connector.NewConn()
 	if err != nil {
@@ -26,17 +75,20 @@ func (c *Client) CreateNvmeTCPController(
 	}
 	defer connClose()
 
-	var adrfam pb.NvmeAddressFamily
-	switch {
-	case ip.To4() != nil:
-		adrfam = pb.NvmeAddressFamily_NVME_ADDRESS_FAMILY_IPV4
-	case ip.To16() != nil:
-		adrfam = pb.NvmeAddressFamily_NVME_ADDRESS_FAMILY_IPV6
-	default:
-		return nil, fmt.Errorf("invalid ip address format: %v", ip)
+	adrfam, err := nvme.AddressFamily(ip)
+	if err != nil {
+		return nil, err
 	}
 
 	client := c.createFrontendNvmeClient(conn)
+	pskSet := false
+	if len(psk) != 0 {
+		pskSet, err = setNvmeSubsystemPsk(ctx, client, subsystem, psk)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	response, err := client.CreateNvmeController(
 		ctx,
 		&pb.CreateNvmeControllerRequest{
@@ -44,7 +96,7 @@ func (c *Client) CreateNvmeTCPController(
 			NvmeControllerId: id,
 			NvmeController: &pb.NvmeController{
 				Spec: &pb.NvmeControllerSpec{
-					Trtype: pb.NvmeTransportType_NVME_TRANSPORT_TYPE_TCP,
+					Trtype: trtype,
 					Endpoint: &pb.NvmeControllerSpec_FabricsId{
 						FabricsId: &pb.FabricsEndpoint{
 							Traddr:  ip.String(),
@@ -55,10 +107,57 @@ func (c *Client) CreateNvmeTCPController(
 				},
 			},
 		})
+	if err != nil && pskSet {
+		if restoreErr := updateNvmeSubsystemPsk(ctx, client, subsystem, nil); restoreErr != nil {
+			err = errors.Join(err, fmt.Errorf("failed to clear psk of %v: %w", subsystem, restoreErr))
+		}
+	}
 
 	return response, err
 }
 
+// setNvmeSubsystemPsk sets the psk of a subsystem which has none and reports
+// whether it was changed
+func setNvmeSubsystemPsk(
+	ctx context.Context,
+	client pb.FrontendNvmeServiceClient,
+	subsystem string,
+	psk []byte,
+) (bool, error) {
+	current, err := client.GetNvmeSubsystem(ctx, &pb.GetNvmeSubsystemRequest{Name: subsystem})
+	if err != nil {
+		return false, err
+	}
+	currentPsk := current.GetSpec().GetPsk()
+	if bytes.Equal(currentPsk, psk) {
+		return false, nil
+	}
+	if len(currentPsk) != 0 {
+		return false, &ConflictError{Name: subsystem, Field: "psk"}
+	}
+	return true, updateNvmeSubsystemPsk(ctx, client, subsystem, psk)
+}
+
+func updateNvmeSubsystemPsk(
+	ctx context.Context,
+	client pb.FrontendNvmeServiceClient,
+	subsystem string,
+	psk []byte,
+) error {
+	_, err := client.UpdateNvmeSubsystem(
+		ctx,
+		&pb.UpdateNvmeSubsystemRequest{
+			NvmeSubsystem: &pb.NvmeSubsystem{
+				Name: subsystem,
+				Spec: &pb.NvmeSubsystemSpec{
+					Psk: psk,
+				},
+			},
+			UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"spec.psk"}},
+		})
+	return err
+}
+
 // CreateNvmePcieController creates an nvme PCIe controller
 func (c *Client) CreateNvmePcieController(
 	ctx context.Context,
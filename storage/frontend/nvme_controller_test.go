@@ -17,6 +17,7 @@ import (
 	"google.golang.org/grpc"
 	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
 	"google.golang.org/protobuf/types/known/wrapperspb"
 )
 
@@ -161,6 +162,331 @@ func TestCreateNvmeTCPController(t *testing.T) {
 	}
 }
 
+func TestCreateNvmeRdmaController(t *testing.T) {
+	controllerID := "nvmerdma0"
+	subsystemName := "subsysRdma0Name"
+	ipAddr := net.ParseIP("10.10.10.1")
+	testRdmaController := &pb.NvmeController{
+		Spec: &pb.NvmeControllerSpec{
+			Trtype: pb.NvmeTransportType_NVME_TRANSPORT_TYPE_RDMA,
+			Endpoint: &pb.NvmeControllerSpec_FabricsId{
+				FabricsId: &pb.FabricsEndpoint{
+					Traddr:  ipAddr.String(),
+					Trsvcid: "4420",
+					Adrfam:  pb.NvmeAddressFamily_NVME_ADDRESS_FAMILY_IPV4,
+				},
+			},
+		},
+	}
+
+	tests := map[string]struct {
+		giveClientErr    error
+		giveConnectorErr error
+		giveIP           net.IP
+		wantErr          error
+		wantRequest      *pb.CreateNvmeControllerRequest
+		wantResponse     *pb.NvmeController
+		wantConnClosed   bool
+	}{
+		"successful call": {
+			giveConnectorErr: nil,
+			giveClientErr:    nil,
+			giveIP:           ipAddr,
+			wantErr:          nil,
+			wantRequest: &pb.CreateNvmeControllerRequest{
+				Parent:           subsystemName,
+				NvmeControllerId: controllerID,
+				NvmeController:   proto.Clone(testRdmaController).(*pb.NvmeController),
+			},
+			wantResponse:   proto.Clone(testRdmaController).(*pb.NvmeController),
+			wantConnClosed: true,
+		},
+		"client err": {
+			giveConnectorErr: nil,
+			giveClientErr:    errors.New("Some client error"),
+			giveIP:           ipAddr,
+			wantErr:          errors.New("Some client error"),
+			wantRequest: &pb.CreateNvmeControllerRequest{
+				Parent:           subsystemName,
+				NvmeControllerId: controllerID,
+				NvmeController:   proto.Clone(testRdmaController).(*pb.NvmeController),
+			},
+			wantResponse:   nil,
+			wantConnClosed: true,
+		},
+		"connector err": {
+			giveConnectorErr: errors.New("Some conn error"),
+			giveClientErr:    nil,
+			giveIP:           ipAddr,
+			wantErr:          errors.New("Some conn error"),
+			wantRequest:      nil,
+			wantResponse:     nil,
+			wantConnClosed:   false,
+		},
+		"invalid address": {
+			giveConnectorErr: nil,
+			giveClientErr:    nil,
+			giveIP:           net.IP{},
+			wantErr:          errors.New("invalid ip address format: <nil>"),
+			wantRequest:      nil,
+			wantResponse:     nil,
+			wantConnClosed:   true,
+		},
+	}
+
+	for testName, tt := range tests {
+		t.Run(testName, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
+
+			mockClient := mocks.NewFrontendNvmeServiceClient(t)
+			if tt.wantRequest != nil {
+				toReturn := proto.Clone(tt.wantResponse).(*pb.NvmeController)
+				mockClient.EXPECT().CreateNvmeController(ctx, tt.wantRequest).
+					Return(toReturn, tt.giveClientErr)
+			}
+
+			connClosed := false
+			mockConn := mocks.NewConnector(t)
+			mockConn.EXPECT().NewConn().Return(
+				&grpc.ClientConn{},
+				func() { connClosed = true },
+				tt.giveConnectorErr,
+			)
+
+			c, _ := NewWithArgs(
+				mockConn,
+				func(grpc.ClientConnInterface) pb.FrontendNvmeServiceClient {
+					return mockClient
+				},
+				pb.NewFrontendVirtioBlkServiceClient,
+			)
+
+			response, err := c.CreateNvmeRdmaController(
+				ctx,
+				controllerID,
+				subsystemName,
+				tt.giveIP,
+				4420,
+			)
+
+			require.Equal(t, tt.wantErr, err)
+			require.True(t, proto.Equal(response, tt.wantResponse))
+			require.Equal(t, tt.wantConnClosed, connClosed)
+		})
+	}
+}
+
+func TestCreateNvmeTCPTLSController(t *testing.T) {
+	controllerID := "nvmetls0"
+	subsystemName := "subsysTls0Name"
+	ipAddr := net.ParseIP("127.0.0.1")
+	testPsk := []byte("NVMeTLSkey-1:01:MDAxMTIyMzM0NDU1NjY3Nzg4OTlhYWJiY2NkZGVlZmZwJEiQ:")
+	testTLSController := &pb.NvmeController{
+		Spec: &pb.NvmeControllerSpec{
+			Trtype: pb.NvmeTransportType_NVME_TRANSPORT_TYPE_TCP,
+			Endpoint: &pb.NvmeControllerSpec_FabricsId{
+				FabricsId: &pb.FabricsEndpoint{
+					Traddr:  ipAddr.String(),
+					Trsvcid: "4420",
+					Adrfam:  pb.NvmeAddressFamily_NVME_ADDRESS_FAMILY_IPV4,
+				},
+			},
+		},
+	}
+	testUpdateSubsystemRequest := &pb.UpdateNvmeSubsystemRequest{
+		NvmeSubsystem: &pb.NvmeSubsystem{
+			Name: subsystemName,
+			Spec: &pb.NvmeSubsystemSpec{
+				Psk: testPsk,
+			},
+		},
+		UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"spec.psk"}},
+	}
+	testRestoreSubsystemRequest := &pb.UpdateNvmeSubsystemRequest{
+		NvmeSubsystem: &pb.NvmeSubsystem{
+			Name: subsystemName,
+			Spec: &pb.NvmeSubsystemSpec{},
+		},
+		UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"spec.psk"}},
+	}
+
+	tests := map[string]struct {
+		giveSubsystemPsk []byte
+		giveGetErr       error
+		giveUpdateErr    error
+		giveClientErr    error
+		giveConnectorErr error
+		givePsk          []byte
+		wantErr          error
+		wantGet          bool
+		wantUpdate       *pb.UpdateNvmeSubsystemRequest
+		wantRestore      *pb.UpdateNvmeSubsystemRequest
+		wantRequest      *pb.CreateNvmeControllerRequest
+		wantResponse     *pb.NvmeController
+		wantConnClosed   bool
+		wantConnCreated  bool
+	}{
+		"successful call": {
+			givePsk:    testPsk,
+			wantErr:    nil,
+			wantGet:    true,
+			wantUpdate: proto.Clone(testUpdateSubsystemRequest).(*pb.UpdateNvmeSubsystemRequest),
+			wantRequest: &pb.CreateNvmeControllerRequest{
+				Parent:           subsystemName,
+				NvmeControllerId: controllerID,
+				NvmeController:   proto.Clone(testTLSController).(*pb.NvmeController),
+			},
+			wantResponse:    proto.Clone(testTLSController).(*pb.NvmeController),
+			wantConnClosed:  true,
+			wantConnCreated: true,
+		},
+		"subsystem update err": {
+			giveUpdateErr:   errors.New("Some update error"),
+			givePsk:         testPsk,
+			wantErr:         errors.New("Some update error"),
+			wantGet:         true,
+			wantUpdate:      proto.Clone(testUpdateSubsystemRequest).(*pb.UpdateNvmeSubsystemRequest),
+			wantRequest:     nil,
+			wantResponse:    nil,
+			wantConnClosed:  true,
+			wantConnCreated: true,
+		},
+		"client err": {
+			giveClientErr: errors.New("Some client error"),
+			givePsk:       testPsk,
+			wantErr:       errors.New("Some client error"),
+			wantGet:       true,
+			wantUpdate:    proto.Clone(testUpdateSubsystemRequest).(*pb.UpdateNvmeSubsystemRequest),
+			wantRestore:   proto.Clone(testRestoreSubsystemRequest).(*pb.UpdateNvmeSubsystemRequest),
+			wantRequest: &pb.CreateNvmeControllerRequest{
+				Parent:           subsystemName,
+				NvmeControllerId: controllerID,
+				NvmeController:   proto.Clone(testTLSController).(*pb.NvmeController),
+			},
+			wantResponse:    nil,
+			wantConnClosed:  true,
+			wantConnCreated: true,
+		},
+		"same psk already set": {
+			giveSubsystemPsk: testPsk,
+			givePsk:          testPsk,
+			wantErr:          nil,
+			wantGet:          true,
+			wantUpdate:       nil,
+			wantRequest: &pb.CreateNvmeControllerRequest{
+				Parent:           subsystemName,
+				NvmeControllerId: controllerID,
+				NvmeController:   proto.Clone(testTLSController).(*pb.NvmeController),
+			},
+			wantResponse:    proto.Clone(testTLSController).(*pb.NvmeController),
+			wantConnClosed:  true,
+			wantConnCreated: true,
+		},
+		"different psk already set": {
+			giveSubsystemPsk: []byte("NVMeTLSkey-1:01:other:"),
+			givePsk:          testPsk,
+			wantErr:          &ConflictError{Name: subsystemName, Field: "psk"},
+			wantGet:          true,
+			wantUpdate:       nil,
+			wantRequest:      nil,
+			wantResponse:     nil,
+			wantConnClosed:   true,
+			wantConnCreated:  true,
+		},
+		"get subsystem err": {
+			giveGetErr:      errors.New("Some get error"),
+			givePsk:         testPsk,
+			wantErr:         errors.New("Some get error"),
+			wantGet:         true,
+			wantUpdate:      nil,
+			wantRequest:     nil,
+			wantResponse:    nil,
+			wantConnClosed:  true,
+			wantConnCreated: true,
+		},
+		"connector err": {
+			giveConnectorErr: errors.New("Some conn error"),
+			givePsk:          testPsk,
+			wantErr:          errors.New("Some conn error"),
+			wantUpdate:       nil,
+			wantRequest:      nil,
+			wantResponse:     nil,
+			wantConnClosed:   false,
+			wantConnCreated:  true,
+		},
+		"empty psk": {
+			givePsk:         nil,
+			wantErr:         errors.New("empty psk is not allowed for TLS controller"),
+			wantUpdate:      nil,
+			wantRequest:     nil,
+			wantResponse:    nil,
+			wantConnClosed:  false,
+			wantConnCreated: false,
+		},
+	}
+
+	for testName, tt := range tests {
+		t.Run(testName, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
+
+			mockClient := mocks.NewFrontendNvmeServiceClient(t)
+			if tt.wantGet {
+				mockClient.EXPECT().GetNvmeSubsystem(ctx, &pb.GetNvmeSubsystemRequest{Name: subsystemName}).
+					Return(&pb.NvmeSubsystem{
+						Name: subsystemName,
+						Spec: &pb.NvmeSubsystemSpec{Psk: tt.giveSubsystemPsk},
+					}, tt.giveGetErr)
+			}
+			if tt.wantRestore != nil {
+				mockClient.EXPECT().UpdateNvmeSubsystem(ctx, tt.wantRestore).
+					Return(&pb.NvmeSubsystem{Name: subsystemName}, nil)
+			}
+			if tt.wantUpdate != nil {
+				mockClient.EXPECT().UpdateNvmeSubsystem(ctx, tt.wantUpdate).
+					Return(&pb.NvmeSubsystem{Name: subsystemName}, tt.giveUpdateErr)
+			}
+			if tt.wantRequest != nil {
+				toReturn := proto.Clone(tt.wantResponse).(*pb.NvmeController)
+				mockClient.EXPECT().CreateNvmeController(ctx, tt.wantRequest).
+					Return(toReturn, tt.giveClientErr)
+			}
+
+			connClosed := false
+			mockConn := mocks.NewConnector(t)
+			if tt.wantConnCreated {
+				mockConn.EXPECT().NewConn().Return(
+					&grpc.ClientConn{},
+					func() { connClosed = true },
+					tt.giveConnectorErr,
+				)
+			}
+
+			c, _ := NewWithArgs(
+				mockConn,
+				func(grpc.ClientConnInterface) pb.FrontendNvmeServiceClient {
+					return mockClient
+				},
+				pb.NewFrontendVirtioBlkServiceClient,
+			)
+
+			response, err := c.CreateNvmeTCPTLSController(
+				ctx,
+				controllerID,
+				subsystemName,
+				ipAddr,
+				4420,
+				tt.givePsk,
+			)
+
+			require.Equal(t, tt.wantErr, err)
+			require.True(t, proto.Equal(response, tt.wantResponse))
+			require.Equal(t, tt.wantConnClosed, connClosed)
+		})
+	}
+}
+
 func TestCreateNvmePcieController(t *testing.T) {
 	controllerID := "nvmepcie0"
 	subsystemName := "subsysPcie0Name"
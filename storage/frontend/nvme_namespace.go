@@ -7,6 +7,8 @@ package frontend
 import (
 	"context"
 
+	"github.com/google/uuid"
+	"github.com/opiproject/godpu/storage/nvme"
 	pb "github.com/opiproject/opi-api/storage/v1alpha1/gen/go"
 )
 
@@ -14,6 +16,16 @@ import (
 func (c *Client) CreateNvmeNamespace(
 	ctx context.Context,
 	id, subsystem, volume string,
+) (*pb.NvmeNamespace, error) {
+	return c.CreateNvmeNamespaceWithIdentifiers(ctx, id, subsystem, volume, nvme.NamespaceIdentifiers{})
+}
+
+// CreateNvmeNamespaceWithIdentifiers creates an nvme namespace reported to
+// hosts with the given identifiers
+func (c *Client) CreateNvmeNamespaceWithIdentifiers(
+	ctx context.Context,
+	id, subsystem, volume string,
+	ids nvme.NamespaceIdentifiers,
 ) (*pb.NvmeNamespace, error) {
 	conn, connClose, err := c.connector.NewConn()
 	if err != nil {
@@ -28,9 +40,7 @@ func (c *Client) CreateNvmeNamespace(
 			Parent:          subsystem,
 			NvmeNamespaceId: id,
 			NvmeNamespace: &pb.NvmeNamespace{
-				Spec: &pb.NvmeNamespaceSpec{
-					VolumeNameRef: volume,
-				},
+				Spec: newNvmeNamespaceSpec(volume, ids),
 			},
 		})
 
@@ -59,3 +69,20 @@ func (c *Client) DeleteNvmeNamespace(
 
 	return err
 }
+
+func newNvmeNamespaceSpec(volume string, ids nvme.NamespaceIdentifiers) *pb.NvmeNamespaceSpec {
+	spec := &pb.NvmeNamespaceSpec{
+		VolumeNameRef: volume,
+	}
+	if !ids.NGUID.IsZero() {
+		spec.Nguid = ids.NGUID.String()
+	}
+	if !ids.EUI64.IsZero() {
+		spec.Eui64 = ids.EUI64.Int64()
+	}
+	if ids.UUID != uuid.Nil {
+		spec.Uuid = ids.UUID.String()
+	}
+
+	return spec
+}
@@ -11,6 +11,7 @@ import (
 	"time"
 
 	"github.com/opiproject/godpu/mocks"
+	"github.com/opiproject/godpu/storage/nvme"
 	pb "github.com/opiproject/opi-api/storage/v1alpha1/gen/go"
 	"github.com/stretchr/testify/require"
 	"google.golang.org/grpc"
@@ -107,6 +108,52 @@ func TestCreateNvmeNamespace(t *testing.T) {
 	}
 }
 
+func TestCreateNvmeNamespaceWithIdentifiers(t *testing.T) {
+	namespaceID := "namespace0"
+	volume := "vol0"
+	subsystem := "subsys0Name"
+	ids, _ := nvme.IdentifiersFromVolumeID(volume)
+	testNamespace := &pb.NvmeNamespace{
+		Spec: &pb.NvmeNamespaceSpec{
+			VolumeNameRef: volume,
+			Nguid:         ids.NGUID.String(),
+			Eui64:         ids.EUI64.Int64(),
+			Uuid:          ids.UUID.String(),
+		},
+	}
+
+	mockClient := mocks.NewFrontendNvmeServiceClient(t)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	mockClient.EXPECT().CreateNvmeNamespace(ctx, &pb.CreateNvmeNamespaceRequest{
+		Parent:          subsystem,
+		NvmeNamespaceId: namespaceID,
+		NvmeNamespace:   proto.Clone(testNamespace).(*pb.NvmeNamespace),
+	}).Return(proto.Clone(testNamespace).(*pb.NvmeNamespace), nil)
+
+	connClosed := false
+	mockConn := mocks.NewConnector(t)
+	mockConn.EXPECT().NewConn().Return(
+		&grpc.ClientConn{},
+		func() { connClosed = true },
+		nil,
+	)
+
+	c, _ := NewWithArgs(
+		mockConn,
+		func(grpc.ClientConnInterface) pb.FrontendNvmeServiceClient {
+			return mockClient
+		},
+		pb.NewFrontendVirtioBlkServiceClient,
+	)
+
+	response, err := c.CreateNvmeNamespaceWithIdentifiers(ctx, namespaceID, subsystem, volume, ids)
+
+	require.NoError(t, err)
+	require.True(t, proto.Equal(response, testNamespace))
+	require.True(t, connClosed)
+}
+
 func TestDeleteNvmeNamespace(t *testing.T) {
 	testNamespaceName := "name"
 	testRequest := &pb.DeleteNvmeNamespaceRequest{
@@ -6,8 +6,13 @@ package frontend
 
 import (
 	"context"
+	"fmt"
 
+	"github.com/opiproject/godpu/storage/nvme"
 	pb "github.com/opiproject/opi-api/storage/v1alpha1/gen/go"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
 )
 
 // CreateNvmeSubsystem creates an nvme subsystem
@@ -15,6 +20,15 @@ func (c *Client) CreateNvmeSubsystem(
 	ctx context.Context,
 	id, nqn, hostnqn string,
 ) (*pb.NvmeSubsystem, error) {
+	if err := nvme.ValidateNQN(nqn); err != nil {
+		return nil, err
+	}
+	if hostnqn != "" {
+		if err := nvme.ValidateNQN(hostnqn); err != nil {
+			return nil, err
+		}
+	}
+
 	conn, connClose, err := c.connector.NewConn()
 	if err != nil {
 		return nil, err
@@ -59,3 +73,137 @@ func (c *Client) DeleteNvmeSubsystem(
 
 	return err
 }
+
+// ListNvmeSubsystemHosts returns the host nqns allowed to connect to an nvme
+// subsystem. allowAnyHost is true if access to the subsystem is not restricted.
+func (c *Client) ListNvmeSubsystemHosts(
+	ctx context.Context,
+	name string,
+) (hosts []string, allowAnyHost bool, err error) {
+	conn, connClose, err := c.connector.NewConn()
+	if err != nil {
+		return nil, false, err
+	}
+	defer connClose()
+
+	client := c.createFrontendNvmeClient(conn)
+	subsystem, err := client.GetNvmeSubsystem(
+		ctx,
+		&pb.GetNvmeSubsystemRequest{
+			Name: name,
+		})
+	if err != nil {
+		return nil, false, err
+	}
+
+	hostnqn := subsystem.GetSpec().GetHostnqn()
+	if hostnqn == "" {
+		return []string{}, true, nil
+	}
+
+	return []string{hostnqn}, false, nil
+}
+
+// AddNvmeSubsystemHost allows a host to connect to an nvme subsystem.
+// OPI subsystems carry a single allowed host, so adding a host to a subsystem
+// which already restricts access to another host fails.
+func (c *Client) AddNvmeSubsystemHost(
+	ctx context.Context,
+	name, hostnqn string,
+) (*pb.NvmeSubsystem, error) {
+	if err := nvme.ValidateNQN(hostnqn); err != nil {
+		return nil, err
+	}
+
+	return c.updateNvmeSubsystemHost(ctx, name, func(current string) (string, error) {
+		if current != "" && current != hostnqn {
+			return "", fmt.Errorf("subsystem %v already restricts access to host %v", name, current)
+		}
+		return hostnqn, nil
+	})
+}
+
+// RemoveNvmeSubsystemHost revokes access of a host to an nvme subsystem.
+// OPI subsystems carry a single allowed host and removing it would make the
+// subsystem accessible by any host, so it fails with FailedPrecondition.
+// SetNvmeSubsystemAllowAnyHost has to be used to open up access explicitly.
+func (c *Client) RemoveNvmeSubsystemHost(
+	ctx context.Context,
+	name, hostnqn string,
+) (*pb.NvmeSubsystem, error) {
+	if err := nvme.ValidateNQN(hostnqn); err != nil {
+		return nil, err
+	}
+
+	return c.updateNvmeSubsystemHost(ctx, name, func(current string) (string, error) {
+		if current != hostnqn {
+			return "", fmt.Errorf("host %v is not allowed on subsystem %v", hostnqn, name)
+		}
+		return "", status.Errorf(codes.FailedPrecondition,
+			"removing the only allowed host %v would allow any host on subsystem %v", hostnqn, name)
+	})
+}
+
+// SetNvmeSubsystemAllowAnyHost toggles unrestricted host access to an nvme
+// subsystem. Allowing any host drops the allowed host. Access can only be
+// restricted by adding an allowed host, so disallowing any host fails with
+// FailedPrecondition on a subsystem without one.
+func (c *Client) SetNvmeSubsystemAllowAnyHost(
+	ctx context.Context,
+	name string,
+	allowAnyHost bool,
+) (*pb.NvmeSubsystem, error) {
+	return c.updateNvmeSubsystemHost(ctx, name, func(current string) (string, error) {
+		if allowAnyHost {
+			return "", nil
+		}
+		if current == "" {
+			return "", status.Errorf(codes.FailedPrecondition,
+				"subsystem %v has no allowed host to restrict access to", name)
+		}
+		return current, nil
+	})
+}
+
+func (c *Client) updateNvmeSubsystemHost(
+	ctx context.Context,
+	name string,
+	newHostnqn func(current string) (string, error),
+) (*pb.NvmeSubsystem, error) {
+	conn, connClose, err := c.connector.NewConn()
+	if err != nil {
+		return nil, err
+	}
+	defer connClose()
+
+	client := c.createFrontendNvmeClient(conn)
+	subsystem, err := client.GetNvmeSubsystem(
+		ctx,
+		&pb.GetNvmeSubsystemRequest{
+			Name: name,
+		})
+	if err != nil {
+		return nil, err
+	}
+
+	current := subsystem.GetSpec().GetHostnqn()
+	hostnqn, err := newHostnqn(current)
+	if err != nil {
+		return nil, err
+	}
+	if hostnqn == current {
+		return subsystem, nil
+	}
+
+	return client.UpdateNvmeSubsystem(
+		ctx,
+		&pb.UpdateNvmeSubsystemRequest{
+			NvmeSubsystem: &pb.NvmeSubsystem{
+				Name: name,
+				Spec: &pb.NvmeSubsystemSpec{
+					Hostnqn: hostnqn,
+				},
+			},
+			UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"spec.hostnqn"}},
+		})
+}
@@ -14,14 +14,17 @@ import (
 	pb "github.com/opiproject/opi-api/storage/v1alpha1/gen/go"
 	"github.com/stretchr/testify/require"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
 )
 
 func TestCreateNvmeSubsystem(t *testing.T) {
 	subsystemID := "subsys0"
-	nqn := "nqn"
-	hostnqn := "hostnqn"
+	nqn := "nqn.2022-09.io.spdk:opitest0"
+	hostnqn := "nqn.2014-08.org.nvmexpress:uuid:feb98abe-d51f-40c8-b348-2753f3571d3c"
 	testSubsystem := &pb.NvmeSubsystem{
 		Spec: &pb.NvmeSubsystemSpec{
 			Nqn:     nqn,
@@ -106,6 +109,53 @@ func TestCreateNvmeSubsystem(t *testing.T) {
 	}
 }
 
+func TestCreateNvmeSubsystemInvalidNqn(t *testing.T) {
+	tests := map[string]struct {
+		giveNqn     string
+		giveHostnqn string
+		wantErr     error
+	}{
+		"invalid nqn": {
+			giveNqn:     "nqn",
+			giveHostnqn: "",
+			wantErr:     errors.New(`invalid nqn format "nqn", expected nqn.yyyy-mm.reverse-domain[:identifier]`),
+		},
+		"empty nqn": {
+			giveNqn:     "",
+			giveHostnqn: "",
+			wantErr:     errors.New("empty nqn is not allowed"),
+		},
+		"invalid hostnqn": {
+			giveNqn:     "nqn.2022-09.io.spdk:opitest0",
+			giveHostnqn: "nqn.2014-08.org.nvmexpress:uuid:invalid",
+			wantErr:     errors.New(`invalid uuid in nqn "nqn.2014-08.org.nvmexpress:uuid:invalid"`),
+		},
+	}
+
+	for testName, tt := range tests {
+		t.Run(testName, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
+
+			mockClient := mocks.NewFrontendNvmeServiceClient(t)
+			mockConn := mocks.NewConnector(t)
+
+			c, _ := NewWithArgs(
+				mockConn,
+				func(grpc.ClientConnInterface) pb.FrontendNvmeServiceClient {
+					return mockClient
+				},
+				pb.NewFrontendVirtioBlkServiceClient,
+			)
+
+			response, err := c.CreateNvmeSubsystem(ctx, "subsys0", tt.giveNqn, tt.giveHostnqn)
+
+			require.Equal(t, tt.wantErr, err)
+			require.Nil(t, response)
+		})
+	}
+}
+
 func TestDeleteNvmeSubsystem(t *testing.T) {
 	testSubsystemName := "name"
 	testRequest := &pb.DeleteNvmeSubsystemRequest{
@@ -176,3 +226,319 @@ func TestDeleteNvmeSubsystem(t *testing.T) {
 		})
 	}
 }
+
+func TestListNvmeSubsystemHosts(t *testing.T) {
+	testSubsystemName := "subsys0Name"
+	testHostnqn := "nqn.2014-08.org.nvmexpress:uuid:feb98abe-d51f-40c8-b348-2753f3571d3c"
+
+	tests := map[string]struct {
+		giveClientErr    error
+		giveConnectorErr error
+		giveSubsystem    *pb.NvmeSubsystem
+		wantErr          error
+		wantHosts        []string
+		wantAllowAnyHost bool
+		wantConnClosed   bool
+	}{
+		"restricted subsystem": {
+			giveSubsystem: &pb.NvmeSubsystem{
+				Name: testSubsystemName,
+				Spec: &pb.NvmeSubsystemSpec{Hostnqn: testHostnqn},
+			},
+			wantErr:          nil,
+			wantHosts:        []string{testHostnqn},
+			wantAllowAnyHost: false,
+			wantConnClosed:   true,
+		},
+		"unrestricted subsystem": {
+			giveSubsystem: &pb.NvmeSubsystem{
+				Name: testSubsystemName,
+				Spec: &pb.NvmeSubsystemSpec{},
+			},
+			wantErr:          nil,
+			wantHosts:        []string{},
+			wantAllowAnyHost: true,
+			wantConnClosed:   true,
+		},
+		"client err": {
+			giveClientErr:    errors.New("Some client error"),
+			giveSubsystem:    nil,
+			wantErr:          errors.New("Some client error"),
+			wantHosts:        nil,
+			wantAllowAnyHost: false,
+			wantConnClosed:   true,
+		},
+		"connector err": {
+			giveConnectorErr: errors.New("Some conn error"),
+			wantErr:          errors.New("Some conn error"),
+			wantHosts:        nil,
+			wantAllowAnyHost: false,
+			wantConnClosed:   false,
+		},
+	}
+
+	for testName, tt := range tests {
+		t.Run(testName, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
+
+			mockClient := mocks.NewFrontendNvmeServiceClient(t)
+			if tt.giveConnectorErr == nil {
+				mockClient.EXPECT().GetNvmeSubsystem(ctx, &pb.GetNvmeSubsystemRequest{Name: testSubsystemName}).
+					Return(tt.giveSubsystem, tt.giveClientErr)
+			}
+
+			connClosed := false
+			mockConn := mocks.NewConnector(t)
+			mockConn.EXPECT().NewConn().Return(
+				&grpc.ClientConn{},
+				func() { connClosed = true },
+				tt.giveConnectorErr,
+			)
+
+			c, _ := NewWithArgs(
+				mockConn,
+				func(grpc.ClientConnInterface) pb.FrontendNvmeServiceClient {
+					return mockClient
+				},
+				pb.NewFrontendVirtioBlkServiceClient,
+			)
+
+			hosts, allowAnyHost, err := c.ListNvmeSubsystemHosts(ctx, testSubsystemName)
+
+			require.Equal(t, tt.wantErr, err)
+			require.Equal(t, tt.wantHosts, hosts)
+			require.Equal(t, tt.wantAllowAnyHost, allowAnyHost)
+			require.Equal(t, tt.wantConnClosed, connClosed)
+		})
+	}
+}
+
+func TestNvmeSubsystemHostAccess(t *testing.T) {
+	testSubsystemName := "subsys0Name"
+	testHostnqn := "nqn.2014-08.org.nvmexpress:uuid:feb98abe-d51f-40c8-b348-2753f3571d3c"
+	otherHostnqn := "nqn.2014-08.org.nvmexpress:uuid:1b4e28ba-2fa1-11d2-883f-0016d3cca427"
+	updateRequest := func(hostnqn string) *pb.UpdateNvmeSubsystemRequest {
+		return &pb.UpdateNvmeSubsystemRequest{
+			NvmeSubsystem: &pb.NvmeSubsystem{
+				Name: testSubsystemName,
+				Spec: &pb.NvmeSubsystemSpec{Hostnqn: hostnqn},
+			},
+			UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"spec.hostnqn"}},
+		}
+	}
+	subsystem := func(hostnqn string) *pb.NvmeSubsystem {
+		return &pb.NvmeSubsystem{
+			Name: testSubsystemName,
+			Spec: &pb.NvmeSubsystemSpec{Nqn: "nqn.2022-09.io.spdk:opitest0", Hostnqn: hostnqn},
+		}
+	}
+
+	tests := map[string]struct {
+		call             func(ctx context.Context, c *Client) (*pb.NvmeSubsystem, error)
+		giveConnectorErr error
+		giveGetErr       error
+		giveCurrentHost  string
+		giveUpdateErr    error
+		wantGet          bool
+		wantUpdate       *pb.UpdateNvmeSubsystemRequest
+		wantErr          error
+		wantResponse     *pb.NvmeSubsystem
+		wantConnCreated  bool
+		wantConnClosed   bool
+	}{
+		"add host to unrestricted subsystem": {
+			call: func(ctx context.Context, c *Client) (*pb.NvmeSubsystem, error) {
+				return c.AddNvmeSubsystemHost(ctx, testSubsystemName, testHostnqn)
+			},
+			giveCurrentHost: "",
+			wantGet:         true,
+			wantUpdate:      updateRequest(testHostnqn),
+			wantErr:         nil,
+			wantResponse:    subsystem(testHostnqn),
+			wantConnCreated: true,
+			wantConnClosed:  true,
+		},
+		"add already allowed host": {
+			call: func(ctx context.Context, c *Client) (*pb.NvmeSubsystem, error) {
+				return c.AddNvmeSubsystemHost(ctx, testSubsystemName, testHostnqn)
+			},
+			giveCurrentHost: testHostnqn,
+			wantGet:         true,
+			wantUpdate:      nil,
+			wantErr:         nil,
+			wantResponse:    subsystem(testHostnqn),
+			wantConnCreated: true,
+			wantConnClosed:  true,
+		},
+		"add second host": {
+			call: func(ctx context.Context, c *Client) (*pb.NvmeSubsystem, error) {
+				return c.AddNvmeSubsystemHost(ctx, testSubsystemName, otherHostnqn)
+			},
+			giveCurrentHost: testHostnqn,
+			wantGet:         true,
+			wantUpdate:      nil,
+			wantErr:         errors.New("subsystem subsys0Name already restricts access to host " + testHostnqn),
+			wantResponse:    nil,
+			wantConnCreated: true,
+			wantConnClosed:  true,
+		},
+		"add invalid host nqn": {
+			call: func(ctx context.Context, c *Client) (*pb.NvmeSubsystem, error) {
+				return c.AddNvmeSubsystemHost(ctx, testSubsystemName, "hostnqn")
+			},
+			wantGet:         false,
+			wantUpdate:      nil,
+			wantErr:         errors.New(`invalid nqn format "hostnqn", expected nqn.yyyy-mm.reverse-domain[:identifier]`),
+			wantResponse:    nil,
+			wantConnCreated: false,
+			wantConnClosed:  false,
+		},
+		"add host get err": {
+			call: func(ctx context.Context, c *Client) (*pb.NvmeSubsystem, error) {
+				return c.AddNvmeSubsystemHost(ctx, testSubsystemName, testHostnqn)
+			},
+			giveGetErr:      errors.New("Some client error"),
+			wantGet:         true,
+			wantUpdate:      nil,
+			wantErr:         errors.New("Some client error"),
+			wantResponse:    nil,
+			wantConnCreated: true,
+			wantConnClosed:  true,
+		},
+		"add host update err": {
+			call: func(ctx context.Context, c *Client) (*pb.NvmeSubsystem, error) {
+				return c.AddNvmeSubsystemHost(ctx, testSubsystemName, testHostnqn)
+			},
+			giveUpdateErr:   errors.New("Some update error"),
+			wantGet:         true,
+			wantUpdate:      updateRequest(testHostnqn),
+			wantErr:         errors.New("Some update error"),
+			wantResponse:    nil,
+			wantConnCreated: true,
+			wantConnClosed:  true,
+		},
+		"add host connector err": {
+			call: func(ctx context.Context, c *Client) (*pb.NvmeSubsystem, error) {
+				return c.AddNvmeSubsystemHost(ctx, testSubsystemName, testHostnqn)
+			},
+			giveConnectorErr: errors.New("Some conn error"),
+			wantGet:          false,
+			wantUpdate:       nil,
+			wantErr:          errors.New("Some conn error"),
+			wantResponse:     nil,
+			wantConnCreated:  true,
+			wantConnClosed:   false,
+		},
+		"remove only allowed host": {
+			call: func(ctx context.Context, c *Client) (*pb.NvmeSubsystem, error) {
+				return c.RemoveNvmeSubsystemHost(ctx, testSubsystemName, testHostnqn)
+			},
+			giveCurrentHost: testHostnqn,
+			wantGet:         true,
+			wantUpdate:      nil,
+			wantErr: status.Error(codes.FailedPrecondition,
+				"removing the only allowed host "+testHostnqn+" would allow any host on subsystem subsys0Name"),
+			wantResponse:    nil,
+			wantConnCreated: true,
+			wantConnClosed:  true,
+		},
+		"remove not allowed host": {
+			call: func(ctx context.Context, c *Client) (*pb.NvmeSubsystem, error) {
+				return c.RemoveNvmeSubsystemHost(ctx, testSubsystemName, otherHostnqn)
+			},
+			giveCurrentHost: testHostnqn,
+			wantGet:         true,
+			wantUpdate:      nil,
+			wantErr:         errors.New("host " + otherHostnqn + " is not allowed on subsystem subsys0Name"),
+			wantResponse:    nil,
+			wantConnCreated: true,
+			wantConnClosed:  true,
+		},
+		"allow any host": {
+			call: func(ctx context.Context, c *Client) (*pb.NvmeSubsystem, error) {
+				return c.SetNvmeSubsystemAllowAnyHost(ctx, testSubsystemName, true)
+			},
+			giveCurrentHost: testHostnqn,
+			wantGet:         true,
+			wantUpdate:      updateRequest(""),
+			wantErr:         nil,
+			wantResponse:    subsystem(""),
+			wantConnCreated: true,
+			wantConnClosed:  true,
+		},
+		"disallow any host without allowed host": {
+			call: func(ctx context.Context, c *Client) (*pb.NvmeSubsystem, error) {
+				return c.SetNvmeSubsystemAllowAnyHost(ctx, testSubsystemName, false)
+			},
+			giveCurrentHost: "",
+			wantGet:         true,
+			wantUpdate:      nil,
+			wantErr:         status.Error(codes.FailedPrecondition, "subsystem subsys0Name has no allowed host to restrict access to"),
+			wantResponse:    nil,
+			wantConnCreated: true,
+			wantConnClosed:  true,
+		},
+		"disallow any host on restricted subsystem": {
+			call: func(ctx context.Context, c *Client) (*pb.NvmeSubsystem, error) {
+				return c.SetNvmeSubsystemAllowAnyHost(ctx, testSubsystemName, false)
+			},
+			giveCurrentHost: testHostnqn,
+			wantGet:         true,
+			wantUpdate:      nil,
+			wantErr:         nil,
+			wantResponse:    subsystem(testHostnqn),
+			wantConnCreated: true,
+			wantConnClosed:  true,
+		},
+	}
+
+	for testName, tt := range tests {
+		t.Run(testName, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
+
+			mockClient := mocks.NewFrontendNvmeServiceClient(t)
+			if tt.wantGet {
+				toReturn := subsystem(tt.giveCurrentHost)
+				if tt.giveGetErr != nil {
+					toReturn = nil
+				}
+				mockClient.EXPECT().GetNvmeSubsystem(ctx, &pb.GetNvmeSubsystemRequest{Name: testSubsystemName}).
+					Return(toReturn, tt.giveGetErr)
+			}
+			if tt.wantUpdate != nil {
+				var toReturn *pb.NvmeSubsystem
+				if tt.giveUpdateErr == nil {
+					toReturn = proto.Clone(tt.wantResponse).(*pb.NvmeSubsystem)
+				}
+				mockClient.EXPECT().UpdateNvmeSubsystem(ctx, tt.wantUpdate).
+					Return(toReturn, tt.giveUpdateErr)
+			}
+
+			connClosed := false
+			mockConn := mocks.NewConnector(t)
+			if tt.wantConnCreated {
+				mockConn.EXPECT().NewConn().Return(
+					&grpc.ClientConn{},
+					func() { connClosed = true },
+					tt.giveConnectorErr,
+				)
+			}
+
+			c, _ := NewWithArgs(
+				mockConn,
+				func(grpc.ClientConnInterface) pb.FrontendNvmeServiceClient {
+					return mockClient
+				},
+				pb.NewFrontendVirtioBlkServiceClient,
+			)
+
+			response, err := tt.call(ctx, c)
+
+			require.Equal(t, tt.wantErr, err)
+			require.True(t, proto.Equal(response, tt.wantResponse))
+			require.Equal(t, tt.wantConnClosed, connClosed)
+		})
+	}
+}
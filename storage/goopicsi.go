@@ -1,6 +1,6 @@
 // SPDX-License-Identifier: Apache-2.0
 // Copyright (c) 2022-2023 Dell Inc, or its subsidiaries.
-// Copyright (C) 2023 Intel Corporation
+// Copyright (C) 2023-2024 Intel Corporation
 
 // Package storage implements the go library for OPI to be used in storage, for example, CSI drivers
 package storage
@@ -10,82 +10,198 @@ import (
 	"errors"
 	"fmt"
 	"log"
+	"net"
+	"strconv"
 	"strings"
-	"time"
 
 	"github.com/google/uuid"
 
+	grpcOpi "github.com/opiproject/godpu/grpc"
+	"github.com/opiproject/godpu/storage/backend"
+	"github.com/opiproject/godpu/storage/frontend"
+	"github.com/opiproject/godpu/storage/nvme"
 	pb "github.com/opiproject/opi-api/storage/v1alpha1/gen/go"
-	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
-var (
-	conn    *grpc.ClientConn
-	address = "localhost:50051"
-)
+// CSIClient is used by CSI drivers to manage storage on OPI server.
+// It keeps no connection state, so it is safe for concurrent use.
+type CSIClient struct {
+	connector                grpcOpi.Connector
+	createNvmeClient         backend.CreateNvmeClient
+	createFrontendNvmeClient frontend.CreateFrontendNvmeClient
+	createNullClient         backend.CreateNullClient
+}
+
+// NewCSIClient creates a new instance of CSIClient for the OPI server at
+// the given address. tls is in client_cert:client_key:ca_cert format and
+// insecure connections are used if it is empty.
+func NewCSIClient(addr string, tls string) (*CSIClient, error) {
+	connector, err := grpcOpi.New(addr, tls)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewCSIClientWithArgs(
+		connector,
+		pb.NewNvmeRemoteControllerServiceClient,
+		pb.NewFrontendNvmeServiceClient,
+		pb.NewNullVolumeServiceClient,
+	)
+}
+
+// NewCSIClientWithArgs creates a new instance of CSIClient with non-default members
+func NewCSIClientWithArgs(
+	connector grpcOpi.Connector,
+	createNvmeClient backend.CreateNvmeClient,
+	createFrontendNvmeClient frontend.CreateFrontendNvmeClient,
+	createNullClient backend.CreateNullClient,
+) (*CSIClient, error) {
+	if connector == nil {
+		return nil, errors.New("grpc connector is nil")
+	}
+
+	return &CSIClient{
+		connector:                connector,
+		createNvmeClient:         createNvmeClient,
+		createFrontendNvmeClient: createFrontendNvmeClient,
+		createNullClient:         createNullClient,
+	}, nil
+}
 
 // NvmeConnection defines remote Nvme connection
 type NvmeConnection struct {
-	id     string
-	subnqn string
-	traddr string
+	// ID is the name of the remote Nvme controller
+	ID string
+	// Subnqn and Traddr are taken from the first path of the controller
+	Subnqn string
+	Traddr string
+	// Paths contains all paths of the controller
+	Paths []*pb.NvmePath
+}
+
+// NvmeConnectPath defines a path to a remote Nvme controller
+type NvmeConnectPath struct {
+	TrAddr  string
+	TrSvcID int64
+}
+
+// NvmeConnectOptions defines options of a connection to a remote Nvme
+// controller. Zero values keep the defaults: a single TCP path without
+// multipath and TLS.
+type NvmeConnectOptions struct {
+	// Transport is either TCP or RDMA
+	Transport pb.NvmeTransportType
+	// Multipath has to be enabled to connect several paths
+	Multipath pb.NvmeMultipath
+	// Psk enables TLS secure channel for TCP transport
+	Psk []byte
 }
 
 // NvmeControllerConnect Connects to remote Nvme controller
-func NvmeControllerConnect(id string, trAddr string, subnqn string, trSvcID int64, hostnqn string) error {
-	if conn == nil {
-		err := dialConnection()
-		if err != nil {
+func (c *CSIClient) NvmeControllerConnect(
+	ctx context.Context,
+	id string,
+	trAddr string,
+	subnqn string,
+	trSvcID int64,
+	hostnqn string,
+) error {
+	return c.NvmeControllerConnectWithOptions(
+		ctx,
+		id,
+		subnqn,
+		hostnqn,
+		[]NvmeConnectPath{{TrAddr: trAddr, TrSvcID: trSvcID}},
+		NvmeConnectOptions{},
+	)
+}
+
+// NvmeControllerConnectWithOptions connects to remote Nvme controller over
+// all given paths. The address family of each path is detected from its
+// address. Nothing is changed if the controller is already connected.
+func (c *CSIClient) NvmeControllerConnectWithOptions(
+	ctx context.Context,
+	id string,
+	subnqn string,
+	hostnqn string,
+	paths []NvmeConnectPath,
+	opts NvmeConnectOptions,
+) error {
+	if err := nvme.ValidateNQN(subnqn); err != nil {
+		return err
+	}
+	if hostnqn != "" {
+		if err := nvme.ValidateNQN(hostnqn); err != nil {
 			return err
 		}
 	}
+	opts, err := validateNvmeConnectOptions(paths, opts)
+	if err != nil {
+		return err
+	}
 
-	client := pb.NewNvmeRemoteControllerServiceClient(conn)
-	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
-	defer cancel()
+	conn, connClose, err := c.connector.NewConn()
+	if err != nil {
+		return err
+	}
+	defer connClose()
 
+	client := c.createNvmeClient(conn)
 	data, err := client.GetNvmeRemoteController(ctx, &pb.GetNvmeRemoteControllerRequest{Name: id})
-	if err != nil {
+	if err != nil && status.Code(err) != codes.NotFound {
 		log.Println(err)
+		return err
 	}
-	log.Println(data)
+	log.Println(data.GetName())
 
 	// we will connect if there is no connection established
-	if data == nil { // This means we are unable to get a connection with this ID
-		request := &pb.CreateNvmeRemoteControllerRequest{NvmeRemoteControllerId: id, NvmeRemoteController: &pb.NvmeRemoteController{
+	if data == nil { // This means there is no controller with this ID
+		controller := &pb.NvmeRemoteController{
 			Name:      id,
-			Multipath: pb.NvmeMultipath_NVME_MULTIPATH_DISABLE,
-		}}
+			Multipath: opts.Multipath,
+		}
+		if len(opts.Psk) != 0 {
+			controller.Tcp = &pb.TcpController{Psk: opts.Psk}
+		}
+		request := &pb.CreateNvmeRemoteControllerRequest{NvmeRemoteControllerId: id, NvmeRemoteController: controller}
 		response, err := client.CreateNvmeRemoteController(ctx, request)
 		if err != nil {
 			log.Printf("could not connect to Remote Nvme controller: %v", err)
 			return err
 		}
-		log.Printf("Connected: %v", response)
-
-		pathResponse, err := client.CreateNvmePath(ctx, &pb.CreateNvmePathRequest{
-			Parent:     response.Name,
-			NvmePathId: nvmeControllerToPathResourceID(id),
-			NvmePath: &pb.NvmePath{
-				Traddr: trAddr,
-				Trtype: pb.NvmeTransportType_NVME_TRANSPORT_TYPE_TCP,
-				Fabrics: &pb.FabricsPath{
-					Subnqn:  subnqn,
-					Trsvcid: trSvcID,
-					Hostnqn: hostnqn,
-					Adrfam:  pb.NvmeAddressFamily_NVME_ADDRESS_FAMILY_IPV4,
+		log.Printf("Connected: %v", response.GetName())
+
+		var created []string
+		for i, path := range paths {
+			pathResponse, err := client.CreateNvmePath(ctx, &pb.CreateNvmePathRequest{
+				Parent:     response.Name,
+				NvmePathId: nvmeControllerToPathResourceID(id, i),
+				NvmePath: &pb.NvmePath{
+					Traddr: path.TrAddr,
+					Trtype: opts.Transport,
+					Fabrics: &pb.FabricsPath{
+						Subnqn:  subnqn,
+						Trsvcid: path.TrSvcID,
+						Hostnqn: hostnqn,
+						Adrfam:  nvmeAddressFamily(path.TrAddr),
+					},
 				},
-			},
-		})
-		if err != nil {
-			log.Printf("could not connect to Remote Nvme path: %v", err)
-			_, _ = client.DeleteNvmeRemoteController(ctx, &pb.DeleteNvmeRemoteControllerRequest{
-				Name: response.Name,
 			})
-			return err
+			if err != nil {
+				log.Printf("could not connect to Remote Nvme path: %v", err)
+				for _, name := range created {
+					_, _ = client.DeleteNvmePath(ctx, &pb.DeleteNvmePathRequest{Name: name})
+				}
+				_, _ = client.DeleteNvmeRemoteController(ctx, &pb.DeleteNvmeRemoteControllerRequest{
+					Name: response.Name,
+				})
+				return err
+			}
+			log.Printf("Connected: %v", pathResponse)
+			created = append(created, pathResponse.GetName())
 		}
-		log.Printf("Connected: %v", pathResponse)
 
 		return nil
 	}
@@ -95,85 +211,112 @@ func NvmeControllerConnect(id string, trAddr string, subnqn string, trSvcID int6
 }
 
 // NvmeControllerList lists all the connections to the remote Nvme controller
-func NvmeControllerList() ([]NvmeConnection, error) {
-	if conn == nil {
-		err := dialConnection()
+func (c *CSIClient) NvmeControllerList(ctx context.Context) ([]NvmeConnection, error) {
+	conn, connClose, err := c.connector.NewConn()
+	if err != nil {
+		return []NvmeConnection{}, err
+	}
+	defer connClose()
+
+	client := c.createNvmeClient(conn)
+	var controllers []*pb.NvmeRemoteController
+	pageToken := ""
+	for {
+		response, err := client.ListNvmeRemoteControllers(ctx, &pb.ListNvmeRemoteControllersRequest{PageToken: pageToken})
 		if err != nil {
+			log.Printf("could not list the connections to Remote Nvme controller: %v", err)
 			return []NvmeConnection{}, err
 		}
+		controllers = append(controllers, response.NvmeRemoteControllers...)
+
+		pageToken = response.NextPageToken
+		if pageToken == "" {
+			break
+		}
 	}
 
-	client := pb.NewNvmeRemoteControllerServiceClient(conn)
-	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
-	defer cancel()
+	nvmeConnections := make([]NvmeConnection, 0, len(controllers))
+	for _, controller := range controllers {
+		paths, err := listNvmePaths(ctx, client, controller.Name)
+		if err != nil {
+			log.Printf("could not list the paths of Remote Nvme controller %v: %v", controller.Name, err)
+			return []NvmeConnection{}, err
+		}
 
-	response, err := client.ListNvmeRemoteControllers(ctx, &pb.ListNvmeRemoteControllersRequest{})
-	if err != nil {
-		log.Printf("could not list the connections to Remote Nvme controller: %v", err)
-		return []NvmeConnection{}, err
-	}
-	nvmeConnections := make([]NvmeConnection, 0)
-	for range response.NvmeRemoteControllers {
-		nvmeConnections = append(nvmeConnections, NvmeConnection{
-			id: "",
-			// TODO: fetch Nvme paths to fill when OPI API is extended with List/Get calls
-			subnqn: "",
-			traddr: "",
-		})
+		connection := NvmeConnection{
+			ID:    controller.Name,
+			Paths: paths,
+		}
+		if len(paths) != 0 {
+			connection.Subnqn = paths[0].GetFabrics().GetSubnqn()
+			connection.Traddr = paths[0].GetTraddr()
+		}
+		nvmeConnections = append(nvmeConnections, connection)
 	}
 	return nvmeConnections, nil
 }
 
 // NvmeControllerGet lists the connection to the remote Nvme controller corresponding to the given ID
-func NvmeControllerGet(id string) (string, error) {
-	if conn == nil {
-		err := dialConnection()
-		if err != nil {
-			return "", err
-		}
+// and returns its subsystem NQN taken from the first path, empty if the controller has no paths
+func (c *CSIClient) NvmeControllerGet(ctx context.Context, id string) (string, error) {
+	conn, connClose, err := c.connector.NewConn()
+	if err != nil {
+		return "", err
 	}
+	defer connClose()
 
-	client := pb.NewNvmeRemoteControllerServiceClient(conn)
-	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
-	defer cancel()
-
-	_, err := client.GetNvmeRemoteController(ctx, &pb.GetNvmeRemoteControllerRequest{Name: id})
+	client := c.createNvmeClient(conn)
+	_, err = client.GetNvmeRemoteController(ctx, &pb.GetNvmeRemoteControllerRequest{Name: id})
 	if err != nil {
 		log.Printf("could not list the connection to Remote Nvme controller corresponding to the given ID: %v", err)
 		return "", err
 	}
-	// TODO: fetch nqn in Nvme path when OPI API is extended with List/Get calls
-	return "", err
+
+	paths, err := listNvmePaths(ctx, client, id)
+	if err != nil {
+		log.Printf("could not list the paths of Remote Nvme controller %v: %v", id, err)
+		return "", err
+	}
+	if len(paths) == 0 {
+		return "", nil
+	}
+	return paths[0].GetFabrics().GetSubnqn(), nil
 }
 
 // NvmeControllerDisconnect disconnects remote Nvme controller connection
-func NvmeControllerDisconnect(id string) error {
-	if conn == nil {
-		err := dialConnection()
-		if err != nil {
-			return err
-		}
+// including all its paths
+func (c *CSIClient) NvmeControllerDisconnect(ctx context.Context, id string) error {
+	conn, connClose, err := c.connector.NewConn()
+	if err != nil {
+		return err
 	}
+	defer connClose()
 
-	client := pb.NewNvmeRemoteControllerServiceClient(conn)
-	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
-	defer cancel()
-
+	client := c.createNvmeClient(conn)
 	data, err := client.GetNvmeRemoteController(ctx, &pb.GetNvmeRemoteControllerRequest{Name: id})
 	if err != nil {
 		log.Println(err)
 		return err
 	}
-	log.Println(data)
+	log.Println(data.GetName())
 
 	// we will disconnect if there is a connection
 	if data != nil {
-		_, err := client.DeleteNvmePath(ctx, &pb.DeleteNvmePathRequest{
-			Name: nvmeControllerToPathResourceID(id),
-		})
-		if err != nil {
-			log.Printf("could not disconnect Remote Nvme path: %v", err)
-			return err
+		pathNames := []string{nvmeControllerToPathResourceID(id, 0)}
+		if paths, err := listNvmePaths(ctx, client, id); err == nil && len(paths) != 0 {
+			pathNames = pathNames[:0]
+			for _, path := range paths {
+				pathNames = append(pathNames, path.Name)
+			}
+		}
+		for _, name := range pathNames {
+			_, err := client.DeleteNvmePath(ctx, &pb.DeleteNvmePathRequest{
+				Name: name,
+			})
+			if err != nil {
+				log.Printf("could not disconnect Remote Nvme path: %v", err)
+				return err
+			}
 		}
 
 		response, err := client.DeleteNvmeRemoteController(ctx, &pb.DeleteNvmeRemoteControllerRequest{Name: id})
@@ -185,33 +328,42 @@ func NvmeControllerDisconnect(id string) error {
 		return nil
 	}
 	log.Printf("Remote Nvme controller disconnected successfully")
-	defer disconnectConnection()
 	return nil
 }
 
-// ExposeRemoteNvme creates a new Nvme Subsystem and Nvme controller. Default value of MaxNamespaces is 32 incase the parameter is not assigned any value
-func ExposeRemoteNvme(subsystemNQN string, maxNamespaces int64) (string, string, error) {
-	if conn == nil {
-		err := dialConnection()
-		if err != nil {
-			return "", "", err
-		}
+// ExposeRemoteNvme creates a new Nvme Subsystem and Nvme controller. Default value of MaxNamespaces is 32 incase the parameter is not assigned any value.
+// The ids of the subsystem and controller are derived from subsystemNQN, so
+// repeated calls return the existing resources. A *frontend.ConflictError is
+// returned if they exist with a different spec. A maxNamespaces of 0 matches
+// whatever value the server filled in when the resources were created.
+func (c *CSIClient) ExposeRemoteNvme(ctx context.Context, subsystemNQN string, maxNamespaces int64) (string, string, error) {
+	if err := nvme.ValidateNQN(subsystemNQN); err != nil {
+		return "", "", err
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
-	defer cancel()
-
-	client := pb.NewFrontendNvmeServiceClient(conn)
-	subsystemID := uuid.New().String()
-	data1, err := client.GetNvmeSubsystem(ctx, &pb.GetNvmeSubsystemRequest{Name: subsystemID})
+	conn, connClose, err := c.connector.NewConn()
 	if err != nil {
-		log.Printf("No existing Nvme Subsystem found with subsystemID: %v", subsystemID)
+		return "", "", err
 	}
-
-	if data1 == nil {
+	defer connClose()
+
+	client := c.createFrontendNvmeClient(conn)
+	subsystemID := deterministicID("subsystem", subsystemNQN)
+	subsystemName := frontend.SubsystemName(subsystemID)
+	subsystem, err := client.GetNvmeSubsystem(ctx, &pb.GetNvmeSubsystemRequest{Name: subsystemName})
+	switch {
+	case err == nil:
+		if subsystem.GetSpec().GetNqn() != subsystemNQN {
+			return "", "", &frontend.ConflictError{Name: subsystemName, Field: "nqn"}
+		}
+		if maxNamespaces != 0 && subsystem.GetSpec().GetMaxNamespaces() != maxNamespaces {
+			return "", "", &frontend.ConflictError{Name: subsystemName, Field: "max_namespaces"}
+		}
+		log.Printf("Nvme Subsystem is already present with the subsytemID: %v", subsystemID)
+	case status.Code(err) == codes.NotFound:
 		response1, err := client.CreateNvmeSubsystem(ctx, &pb.CreateNvmeSubsystemRequest{
+			NvmeSubsystemId: subsystemID,
 			NvmeSubsystem: &pb.NvmeSubsystem{
-				Name: subsystemID,
 				Spec: &pb.NvmeSubsystemSpec{
 					Nqn:           subsystemNQN,
 					MaxNamespaces: maxNamespaces,
@@ -223,22 +375,26 @@ func ExposeRemoteNvme(subsystemNQN string, maxNamespaces int64) (string, string,
 			return "", "", err
 		}
 		log.Printf("Nvme Subsytem created: %v", response1)
-	} else {
-		log.Printf("Nvme Subsystem is already present with the subsytemID: %v", subsystemID)
-	}
-
-	controllerID := uuid.New().String()
-	data2, err := client.GetNvmeController(ctx, &pb.GetNvmeControllerRequest{Name: controllerID})
-	if err != nil {
-		log.Printf("No existing Nvme Controller found with controllerID: %v", controllerID)
+	default:
+		log.Printf("could not get Nvme Subsystem with subsystemID %v: %v", subsystemID, err)
+		return "", "", err
 	}
 
-	// Default value of MaxNamespaces is 32 incase the parameter is not assigned any value
-	if data2 == nil {
+	controllerID := deterministicID("controller", subsystemNQN)
+	controllerName := frontend.ControllerName(subsystemID, controllerID)
+	controller, err := client.GetNvmeController(ctx, &pb.GetNvmeControllerRequest{Name: controllerName})
+	switch {
+	case err == nil:
+		if maxNamespaces != 0 && controller.GetSpec().GetMaxNamespaces() != int32(maxNamespaces) {
+			return subsystemID, "", &frontend.ConflictError{Name: controllerName, Field: "max_namespaces"}
+		}
+		log.Printf("Nvme Controller is already present with the controllerID: %v", controllerID)
+	case status.Code(err) == codes.NotFound:
+		// Default value of MaxNamespaces is 32 incase the parameter is not assigned any value
 		response2, err := client.CreateNvmeController(ctx, &pb.CreateNvmeControllerRequest{
-			Parent: subsystemID,
+			Parent:           subsystemName,
+			NvmeControllerId: controllerID,
 			NvmeController: &pb.NvmeController{
-				Name: controllerID,
 				Spec: &pb.NvmeControllerSpec{
 					MaxNamespaces: int32(maxNamespaces),
 				},
@@ -249,25 +405,37 @@ func ExposeRemoteNvme(subsystemNQN string, maxNamespaces int64) (string, string,
 			return subsystemID, "", err
 		}
 		log.Printf("Nvme Controller created: %v", response2)
-		return subsystemID, controllerID, nil
+	default:
+		log.Printf("could not get Nvme Controller with controllerID %v: %v", controllerID, err)
+		return subsystemID, "", err
 	}
-	log.Printf("Nvme Controller is already present with the controllerID: %v", controllerID)
+
 	return subsystemID, controllerID, nil
 }
 
-// CreateNvmeNamespace Creates a new Nvme namespace
-func CreateNvmeNamespace(id string, subSystemID string, nguid string, hostID int32) (string, error) {
-	if conn == nil {
-		err := dialConnection()
-		if err != nil {
-			return "", err
-		}
+// CreateNvmeNamespace Creates a new Nvme namespace in the subsystem with the
+// given id. The namespace id is derived from subSystemID and nguid if id is
+// empty. An existing namespace is returned if it refers to the same volume,
+// otherwise a *frontend.ConflictError is returned.
+func (c *CSIClient) CreateNvmeNamespace(
+	ctx context.Context,
+	id string,
+	subSystemID string,
+	nguid string,
+	hostID int32,
+) (string, error) {
+	parsedNguid, err := nvme.ParseNGUID(nguid)
+	if err != nil {
+		return "", err
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
-	defer cancel()
+	conn, connClose, err := c.connector.NewConn()
+	if err != nil {
+		return "", err
+	}
+	defer connClose()
 
-	client1 := pb.NewNullVolumeServiceClient(conn)
+	client1 := c.createNullClient(conn)
 	response, err := client1.ListNullVolumes(ctx, &pb.ListNullVolumesRequest{})
 
 	if err != nil {
@@ -278,8 +446,8 @@ func CreateNvmeNamespace(id string, subSystemID string, nguid string, hostID int
 	volumeData := response.NullVolumes
 	volumeID := ""
 	for _, data := range volumeData {
-		uuid := strings.ReplaceAll(data.Uuid, "-", "")
-		if uuid == nguid {
+		volumeNguid, err := nvme.ParseNGUID(data.Uuid)
+		if err == nil && volumeNguid == parsedNguid {
 			volumeID = data.Name
 		}
 	}
@@ -287,11 +455,31 @@ func CreateNvmeNamespace(id string, subSystemID string, nguid string, hostID int
 		return "", errors.New("volume ID not found")
 	}
 
-	client2 := pb.NewFrontendNvmeServiceClient(conn)
+	if id == "" {
+		id = deterministicID("namespace", subSystemID, parsedNguid.String())
+	}
+	name := frontend.NamespaceName(subSystemID, id)
+	client2 := c.createFrontendNvmeClient(conn)
+	namespace, err := client2.GetNvmeNamespace(ctx, &pb.GetNvmeNamespaceRequest{Name: name})
+	switch {
+	case err == nil:
+		if namespace.GetSpec().GetVolumeNameRef() != volumeID {
+			return "", &frontend.ConflictError{Name: name, Field: "volume"}
+		}
+		if hostID != 0 && namespace.GetSpec().GetHostNsid() != hostID {
+			return "", &frontend.ConflictError{Name: name, Field: "host_nsid"}
+		}
+		log.Printf("Nvme Namespace is already present: %v", namespace.Name)
+		return namespace.Name, nil
+	case status.Code(err) != codes.NotFound:
+		log.Println(err)
+		return "", err
+	}
+
 	resp, err := client2.CreateNvmeNamespace(ctx, &pb.CreateNvmeNamespaceRequest{
-		Parent: subSystemID,
+		Parent:          frontend.SubsystemName(subSystemID),
+		NvmeNamespaceId: id,
 		NvmeNamespace: &pb.NvmeNamespace{
-			Name: id,
 			Spec: &pb.NvmeNamespaceSpec{
 				VolumeNameRef: volumeID,
 				HostNsid:      hostID,
@@ -307,17 +495,14 @@ func CreateNvmeNamespace(id string, subSystemID string, nguid string, hostID int
 }
 
 // DeleteNvmeNamespace deletes the Nvme namespace
-func DeleteNvmeNamespace(id string) error {
-	if conn == nil {
-		err := dialConnection()
-		if err != nil {
-			return err
-		}
+func (c *CSIClient) DeleteNvmeNamespace(ctx context.Context, id string) error {
+	conn, connClose, err := c.connector.NewConn()
+	if err != nil {
+		return err
 	}
+	defer connClose()
 
-	client := pb.NewFrontendNvmeServiceClient(conn)
-	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
-	defer cancel()
+	client := c.createFrontendNvmeClient(conn)
 
 	resp, err := client.DeleteNvmeNamespace(ctx, &pb.DeleteNvmeNamespaceRequest{Name: id})
 	if err != nil {
@@ -331,31 +516,81 @@ func DeleteNvmeNamespace(id string) error {
 // GenerateHostNQN generates a new hostNQN
 func GenerateHostNQN() string {
 	// Sample of Nvme Qualified Name in UUID-based format - nqn.2014-08.org.nvmexpress:uuid:a11a1111-11a1-111a-a111-1a111aaa1a11
-	nqnConst := "nqn.2014-08.org.nvmexpress:uuid:"
-	nqnUUID := uuid.New().String()
+	return nvme.NewUUIDNQN(uuid.New()).String()
+}
+
+func listNvmePaths(
+	ctx context.Context,
+	client pb.NvmeRemoteControllerServiceClient,
+	controller string,
+) ([]*pb.NvmePath, error) {
+	var paths []*pb.NvmePath
+	pageToken := ""
+	for {
+		response, err := client.ListNvmePaths(ctx, &pb.ListNvmePathsRequest{
+			Parent:    controller,
+			PageToken: pageToken,
+		})
+		if err != nil {
+			return nil, err
+		}
+		paths = append(paths, response.NvmePaths...)
 
-	hostNQN := fmt.Sprintf("%s%s", nqnConst, nqnUUID)
-	return hostNQN
+		pageToken = response.NextPageToken
+		if pageToken == "" {
+			return paths, nil
+		}
+	}
 }
 
-func dialConnection() error {
-	var err error
-	conn, err = grpc.NewClient(address, grpc.WithTransportCredentials(insecure.NewCredentials()))
-	if err != nil {
-		log.Printf("Failed to connect: %v", err)
-		return err
+func validateNvmeConnectOptions(paths []NvmeConnectPath, opts NvmeConnectOptions) (NvmeConnectOptions, error) {
+	if len(paths) == 0 {
+		return opts, errors.New("at least one path is required")
 	}
-	return nil
+
+	switch opts.Transport {
+	case pb.NvmeTransportType_NVME_TRANSPORT_TYPE_UNSPECIFIED:
+		opts.Transport = pb.NvmeTransportType_NVME_TRANSPORT_TYPE_TCP
+	case pb.NvmeTransportType_NVME_TRANSPORT_TYPE_TCP, pb.NvmeTransportType_NVME_TRANSPORT_TYPE_RDMA:
+	default:
+		return opts, fmt.Errorf("not supported transport %v, only TCP and RDMA are allowed", opts.Transport)
+	}
+
+	if opts.Multipath == pb.NvmeMultipath_NVME_MULTIPATH_UNSPECIFIED {
+		opts.Multipath = pb.NvmeMultipath_NVME_MULTIPATH_DISABLE
+	}
+	if len(paths) > 1 && opts.Multipath == pb.NvmeMultipath_NVME_MULTIPATH_DISABLE {
+		return opts, errors.New("multipath has to be enabled to connect several paths")
+	}
+
+	if len(opts.Psk) != 0 && opts.Transport != pb.NvmeTransportType_NVME_TRANSPORT_TYPE_TCP {
+		return opts, errors.New("psk is supported only for TCP transport")
+	}
+
+	return opts, nil
 }
 
-func disconnectConnection() {
-	err := conn.Close()
+// nvmeAddressFamily detects the address family of trAddr. IPv4 is assumed
+// for addresses which are not IPs.
+func nvmeAddressFamily(trAddr string) pb.NvmeAddressFamily {
+	adrfam, err := nvme.AddressFamily(net.ParseIP(trAddr))
 	if err != nil {
-		log.Fatalf("Failed to close connection: %v", err)
+		return pb.NvmeAddressFamily_NVME_ADDRESS_FAMILY_IPV4
 	}
-	log.Println("GRPC connection closed successfully")
+	return adrfam
 }
 
-func nvmeControllerToPathResourceID(resourceID string) string {
-	return resourceID + "path"
+// deterministicID derives a resource id from the parts identifying the
+// resource, so retries address the resource created by a previous call
+func deterministicID(kind string, parts ...string) string {
+	return uuid.NewSHA1(uuid.NameSpaceOID, []byte(kind+":"+strings.Join(parts, "/"))).String()
+}
+
+// nvmeControllerToPathResourceID returns the id of the path with the given
+// index. The first path keeps the id used before several paths were supported.
+func nvmeControllerToPathResourceID(resourceID string, index int) string {
+	if index == 0 {
+		return resourceID + "path"
+	}
+	return resourceID + "path" + strconv.Itoa(index)
 }
@@ -5,17 +5,28 @@
 package storage
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"net"
 	"os"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/opiproject/godpu/mocks"
+	"github.com/opiproject/godpu/storage/backend"
+	"github.com/opiproject/godpu/storage/frontend"
+	"github.com/opiproject/godpu/storage/nvme"
 	"github.com/opiproject/godpu/testing/mock-server/server"
 	"github.com/opiproject/godpu/testing/mock-server/stub"
 	pb "github.com/opiproject/opi-api/storage/v1alpha1/gen/go"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -86,9 +97,14 @@ func (suite *GoopcsiTestSuite) TestExposeRemoteNvme() {
 
 func (suite *GoopcsiTestSuite) TestCreateNvmeNamespace() {
 	// scenario: when volume ID not found
-	resp, err := CreateNvmeNamespace("1", "nqn", "nguid", 1)
+	resp, err := CreateNvmeNamespace("1", "nqn", "0123456789abcdef0123456789abcdef", 1)
 	assert.Error(suite.T(), err)
 	assert.Empty(suite.T(), resp, "CreateNvmeNamespace failed with invalid volume ID")
+
+	// scenario: when nguid is malformed
+	resp, err = CreateNvmeNamespace("1", "nqn", "nguid", 1)
+	assert.Error(suite.T(), err)
+	assert.Empty(suite.T(), resp, "CreateNvmeNamespace failed with invalid nguid")
 }
 
 func (suite *GoopcsiTestSuite) TestNvmeControllerDisconnect() {
@@ -99,21 +115,61 @@ func (suite *GoopcsiTestSuite) TestNvmeControllerDisconnect() {
 
 func (suite *GoopcsiTestSuite) TestNvmeControllerConnect() {
 	// scenario: when connection already exists
-	err := NvmeControllerConnect("12", "", "", 44565, "")
+	err := NvmeControllerConnect("12", "", "nqn.2022-09.io.spdk:test", 44565, "")
+	assert.NoError(suite.T(), err)
+
+	// scenario: when nqn is malformed
+	err = NvmeControllerConnect("12", "", "", 44565, "")
+	assert.Error(suite.T(), err)
+}
+
+func (suite *GoopcsiTestSuite) TestNvmeControllerConnectWithOptions() {
+	client, err := NewCSIClient("localhost:50051", "")
+	assert.NoError(suite.T(), err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	// scenario: new connection over several IPv6 paths
+	err = client.NvmeControllerConnectWithOptions(ctx, "nvme6", "nqn.2022-09.io.spdk:test", "",
+		[]NvmeConnectPath{{TrAddr: "fd00::1", TrSvcID: 4420}, {TrAddr: "fd00::2", TrSvcID: 4420}},
+		NvmeConnectOptions{
+			Multipath: pb.NvmeMultipath_NVME_MULTIPATH_MULTIPATH,
+			Psk:       []byte("NVMeTLSkey-1:01:MDAxMTIyMzM0NDU1NjY3Nzg4OTlhYWJiY2NkZGVlZmZwJEiQ:"),
+		})
 	assert.NoError(suite.T(), err)
+
+	// scenario: several paths without multipath
+	err = client.NvmeControllerConnectWithOptions(ctx, "nvme6", "nqn.2022-09.io.spdk:test", "",
+		[]NvmeConnectPath{{TrAddr: "fd00::1", TrSvcID: 4420}, {TrAddr: "fd00::2", TrSvcID: 4420}},
+		NvmeConnectOptions{})
+	assert.Error(suite.T(), err)
 }
 
 func (suite *GoopcsiTestSuite) TestNvmeControllerList() {
 	resp, err := NvmeControllerList()
 	assert.NoError(suite.T(), err)
 	assert.NotNil(suite.T(), resp, "ListControllers success")
+	assert.Len(suite.T(), resp, 2)
+
+	assert.Equal(suite.T(), "12", resp[0].ID)
+	assert.Equal(suite.T(), "nqn.2022-09.io.spdk:test", resp[0].Subnqn)
+	assert.Equal(suite.T(), "127.0.0.1", resp[0].Traddr)
+	assert.Len(suite.T(), resp[0].Paths, 2)
+	assert.Equal(suite.T(), "127.0.0.2", resp[0].Paths[1].GetTraddr())
+
+	// scenario: controller without paths
+	assert.Equal(suite.T(), "13", resp[1].ID)
+	assert.Empty(suite.T(), resp[1].Subnqn)
+	assert.Empty(suite.T(), resp[1].Traddr)
+	assert.Empty(suite.T(), resp[1].Paths)
 }
 
 func (suite *GoopcsiTestSuite) TestNvmeControllerGet() {
 	// positive scenario
 	resp, err := NvmeControllerGet("12")
 	assert.NoError(suite.T(), err)
-	assert.NotNil(suite.T(), resp, "GetController success")
+	assert.Equal(suite.T(), "nqn.2022-09.io.spdk:test", resp, "GetController success")
 
 	// negative scenario
 	resp, err = NvmeControllerGet("invalid")
@@ -134,6 +190,414 @@ func (suite *GoopcsiTestSuite) TestDeleteNvmeNamespace() {
 func (suite *GoopcsiTestSuite) TestGenerateHostNQN() {
 	hostNQN := GenerateHostNQN()
 	assert.NotNil(suite.T(), hostNQN, "GenerateHostNQN success")
+	assert.NoError(suite.T(), nvme.ValidateNQN(hostNQN), "GenerateHostNQN is well-formed")
+}
+
+func (suite *GoopcsiTestSuite) TestCSIClient() {
+	client, err := NewCSIClient("localhost:50051", "")
+	assert.NoError(suite.T(), err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	err = client.NvmeControllerConnect(ctx, "12", "", "nqn.2022-09.io.spdk:test", 44565, "")
+	assert.NoError(suite.T(), err, "NvmeControllerConnect success")
+
+	resp, err := client.NvmeControllerList(ctx)
+	assert.NoError(suite.T(), err, "NvmeControllerList success")
+	assert.NotNil(suite.T(), resp, "NvmeControllerList success")
+
+	_, err = client.NvmeControllerGet(ctx, "invalid")
+	assert.Error(suite.T(), err, "NvmeControllerGet failed")
+
+	err = client.DeleteNvmeNamespace(ctx, "1")
+	assert.NoError(suite.T(), err, "DeleteNvmeNamespace success")
+
+	// scenario: canceled context is respected
+	canceledCtx, cancelNow := context.WithCancel(context.Background())
+	cancelNow()
+	err = client.DeleteNvmeNamespace(canceledCtx, "1")
+	assert.Error(suite.T(), err, "DeleteNvmeNamespace failed with canceled context")
+}
+
+func (suite *GoopcsiTestSuite) TestRemoteNvmeNamespaces() {
+	client, err := backend.New("localhost:50051", "")
+	assert.NoError(suite.T(), err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	list, err := client.ListNvmeNamespaces(ctx, "nvmeRemoteControllers/12", 0, "")
+	assert.NoError(suite.T(), err, "ListNvmeNamespaces success")
+	assert.Len(suite.T(), list.GetNvmeRemoteNamespaces(), 2)
+
+	namespace, err := client.GetNvmeNamespace(ctx, "nvmeRemoteControllers/12/nvmeRemoteNamespaces/1")
+	assert.NoError(suite.T(), err, "GetNvmeNamespace success")
+	assert.Equal(suite.T(), int32(1), namespace.GetNsid())
+
+	// negative scenario
+	_, err = client.GetNvmeNamespace(ctx, "nvmeRemoteControllers/12/nvmeRemoteNamespaces/invalid")
+	assert.Error(suite.T(), err, "GetNvmeNamespace failed")
+}
+
+func TestNewCSIClient(t *testing.T) {
+	client, err := NewCSIClient("", "")
+	assert.Error(t, err)
+	assert.Nil(t, client)
+
+	client, err = NewCSIClientWithArgs(nil, pb.NewNvmeRemoteControllerServiceClient, pb.NewFrontendNvmeServiceClient, pb.NewNullVolumeServiceClient)
+	assert.Error(t, err)
+	assert.Nil(t, client)
+
+	client, err = NewCSIClient("localhost:50051", "")
+	assert.NoError(t, err)
+	assert.NotNil(t, client)
+}
+
+func TestValidateNvmeConnectOptions(t *testing.T) {
+	onePath := []NvmeConnectPath{{TrAddr: "10.0.0.1", TrSvcID: 4420}}
+	twoPaths := []NvmeConnectPath{{TrAddr: "10.0.0.1", TrSvcID: 4420}, {TrAddr: "fd00::1", TrSvcID: 4420}}
+
+	tests := map[string]struct {
+		paths    []NvmeConnectPath
+		opts     NvmeConnectOptions
+		wantOpts NvmeConnectOptions
+		wantErr  bool
+	}{
+		"defaults": {
+			paths: onePath,
+			opts:  NvmeConnectOptions{},
+			wantOpts: NvmeConnectOptions{
+				Transport: pb.NvmeTransportType_NVME_TRANSPORT_TYPE_TCP,
+				Multipath: pb.NvmeMultipath_NVME_MULTIPATH_DISABLE,
+			},
+			wantErr: false,
+		},
+		"rdma multipath": {
+			paths: twoPaths,
+			opts: NvmeConnectOptions{
+				Transport: pb.NvmeTransportType_NVME_TRANSPORT_TYPE_RDMA,
+				Multipath: pb.NvmeMultipath_NVME_MULTIPATH_FAILOVER,
+			},
+			wantOpts: NvmeConnectOptions{
+				Transport: pb.NvmeTransportType_NVME_TRANSPORT_TYPE_RDMA,
+				Multipath: pb.NvmeMultipath_NVME_MULTIPATH_FAILOVER,
+			},
+			wantErr: false,
+		},
+		"no paths": {
+			paths:   nil,
+			wantErr: true,
+		},
+		"several paths without multipath": {
+			paths:   twoPaths,
+			wantErr: true,
+		},
+		"pcie transport": {
+			paths:   onePath,
+			opts:    NvmeConnectOptions{Transport: pb.NvmeTransportType_NVME_TRANSPORT_TYPE_PCIE},
+			wantErr: true,
+		},
+		"psk with rdma": {
+			paths: onePath,
+			opts: NvmeConnectOptions{
+				Transport: pb.NvmeTransportType_NVME_TRANSPORT_TYPE_RDMA,
+				Psk:       []byte("key"),
+			},
+			wantErr: true,
+		},
+	}
+
+	for testName, tt := range tests {
+		t.Run(testName, func(t *testing.T) {
+			opts, err := validateNvmeConnectOptions(tt.paths, tt.opts)
+			assert.Equal(t, tt.wantErr, err != nil)
+			if !tt.wantErr {
+				assert.Equal(t, tt.wantOpts, opts)
+			}
+		})
+	}
+}
+
+func TestNvmeAddressFamily(t *testing.T) {
+	assert.Equal(t, pb.NvmeAddressFamily_NVME_ADDRESS_FAMILY_IPV4, nvmeAddressFamily("10.0.0.1"))
+	assert.Equal(t, pb.NvmeAddressFamily_NVME_ADDRESS_FAMILY_IPV6, nvmeAddressFamily("fd00::1"))
+	assert.Equal(t, pb.NvmeAddressFamily_NVME_ADDRESS_FAMILY_IPV4, nvmeAddressFamily(""))
+}
+
+func newTestCSIClient(t *testing.T) (*CSIClient, *mocks.FrontendNvmeServiceClient, *mocks.NullVolumeServiceClient) {
+	mockFrontendClient := mocks.NewFrontendNvmeServiceClient(t)
+	mockNullClient := mocks.NewNullVolumeServiceClient(t)
+	mockConn := mocks.NewConnector(t)
+	mockConn.EXPECT().NewConn().Return(&grpc.ClientConn{}, func() {}, nil)
+
+	c, _ := NewCSIClientWithArgs(
+		mockConn,
+		pb.NewNvmeRemoteControllerServiceClient,
+		func(grpc.ClientConnInterface) pb.FrontendNvmeServiceClient {
+			return mockFrontendClient
+		},
+		func(grpc.ClientConnInterface) pb.NullVolumeServiceClient {
+			return mockNullClient
+		},
+	)
+
+	return c, mockFrontendClient, mockNullClient
+}
+
+func TestCSIClientNvmeControllerConnect(t *testing.T) {
+	nqn := "nqn.2022-09.io.spdk:test"
+
+	tests := map[string]struct {
+		giveGetErr error
+		wantCreate bool
+		wantErr    error
+	}{
+		"missing controller is created": {
+			giveGetErr: status.Error(codes.NotFound, "not found"),
+			wantCreate: true,
+			wantErr:    nil,
+		},
+		"existing controller is kept": {
+			giveGetErr: nil,
+			wantCreate: false,
+			wantErr:    nil,
+		},
+		"get err": {
+			giveGetErr: status.Error(codes.Unavailable, "unavailable"),
+			wantCreate: false,
+			wantErr:    status.Error(codes.Unavailable, "unavailable"),
+		},
+	}
+
+	for testName, tt := range tests {
+		t.Run(testName, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
+
+			mockClient := mocks.NewNvmeRemoteControllerServiceClient(t)
+			var toReturn *pb.NvmeRemoteController
+			if tt.giveGetErr == nil {
+				toReturn = &pb.NvmeRemoteController{Name: "nvme0"}
+			}
+			mockClient.EXPECT().GetNvmeRemoteController(ctx, &pb.GetNvmeRemoteControllerRequest{Name: "nvme0"}).
+				Return(toReturn, tt.giveGetErr)
+			if tt.wantCreate {
+				mockClient.EXPECT().CreateNvmeRemoteController(ctx, &pb.CreateNvmeRemoteControllerRequest{
+					NvmeRemoteControllerId: "nvme0",
+					NvmeRemoteController: &pb.NvmeRemoteController{
+						Name:      "nvme0",
+						Multipath: pb.NvmeMultipath_NVME_MULTIPATH_DISABLE,
+					},
+				}).Return(&pb.NvmeRemoteController{Name: "nvme0"}, nil)
+				mockClient.EXPECT().CreateNvmePath(ctx, mock.Anything).
+					Return(&pb.NvmePath{Name: "nvme0path"}, nil)
+			}
+
+			mockConn := mocks.NewConnector(t)
+			mockConn.EXPECT().NewConn().Return(&grpc.ClientConn{}, func() {}, nil)
+
+			c, _ := NewCSIClientWithArgs(
+				mockConn,
+				func(grpc.ClientConnInterface) pb.NvmeRemoteControllerServiceClient {
+					return mockClient
+				},
+				pb.NewFrontendNvmeServiceClient,
+				pb.NewNullVolumeServiceClient,
+			)
+
+			err := c.NvmeControllerConnect(ctx, "nvme0", "127.0.0.1", nqn, 4420, "")
+
+			require.Equal(t, tt.wantErr, err)
+		})
+	}
+}
+
+func TestCSIClientExposeRemoteNvme(t *testing.T) {
+	nqn := "nqn.2022-09.io.spdk:test"
+	subsystemID := deterministicID("subsystem", nqn)
+	controllerID := deterministicID("controller", nqn)
+	subsystemName := frontend.SubsystemName(subsystemID)
+	controllerName := frontend.ControllerName(subsystemID, controllerID)
+	notFoundErr := status.Error(codes.NotFound, "not found")
+
+	tests := map[string]struct {
+		giveMaxNamespaces int64
+		giveSubsystem     *pb.NvmeSubsystem
+		giveSubsystemErr  error
+		giveController    *pb.NvmeController
+		giveControllerErr error
+		wantCreate        bool
+		wantErr           error
+	}{
+		"create missing resources": {
+			giveMaxNamespaces: 10,
+			giveSubsystemErr:  notFoundErr,
+			giveControllerErr: notFoundErr,
+			wantCreate:        true,
+			wantErr:           nil,
+		},
+		"reuse existing resources": {
+			giveMaxNamespaces: 10,
+			giveSubsystem: &pb.NvmeSubsystem{
+				Name: subsystemName,
+				Spec: &pb.NvmeSubsystemSpec{Nqn: nqn, MaxNamespaces: 10},
+			},
+			giveController: &pb.NvmeController{
+				Name: controllerName,
+				Spec: &pb.NvmeControllerSpec{MaxNamespaces: 10},
+			},
+			wantCreate: false,
+			wantErr:    nil,
+		},
+		"reuse existing resources with default max namespaces": {
+			giveMaxNamespaces: 0,
+			giveSubsystem: &pb.NvmeSubsystem{
+				Name: subsystemName,
+				Spec: &pb.NvmeSubsystemSpec{Nqn: nqn, MaxNamespaces: 32},
+			},
+			giveController: &pb.NvmeController{
+				Name: controllerName,
+				Spec: &pb.NvmeControllerSpec{MaxNamespaces: 32},
+			},
+			wantCreate: false,
+			wantErr:    nil,
+		},
+		"subsystem conflict": {
+			giveMaxNamespaces: 10,
+			giveSubsystem: &pb.NvmeSubsystem{
+				Name: subsystemName,
+				Spec: &pb.NvmeSubsystemSpec{Nqn: nqn, MaxNamespaces: 32},
+			},
+			wantErr: &frontend.ConflictError{Name: subsystemName, Field: "max_namespaces"},
+		},
+		"controller conflict": {
+			giveMaxNamespaces: 10,
+			giveSubsystem: &pb.NvmeSubsystem{
+				Name: subsystemName,
+				Spec: &pb.NvmeSubsystemSpec{Nqn: nqn, MaxNamespaces: 10},
+			},
+			giveController: &pb.NvmeController{
+				Name: controllerName,
+				Spec: &pb.NvmeControllerSpec{MaxNamespaces: 32},
+			},
+			wantErr: &frontend.ConflictError{Name: controllerName, Field: "max_namespaces"},
+		},
+		"get subsystem err": {
+			giveMaxNamespaces: 10,
+			giveSubsystemErr:  errors.New("Some client error"),
+			wantErr:           errors.New("Some client error"),
+		},
+	}
+
+	for testName, tt := range tests {
+		t.Run(testName, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
+
+			c, mockClient, _ := newTestCSIClient(t)
+			mockClient.EXPECT().GetNvmeSubsystem(ctx, &pb.GetNvmeSubsystemRequest{Name: subsystemName}).
+				Return(tt.giveSubsystem, tt.giveSubsystemErr)
+			mockClient.EXPECT().GetNvmeController(ctx, &pb.GetNvmeControllerRequest{Name: controllerName}).
+				Return(tt.giveController, tt.giveControllerErr).Maybe()
+			if tt.wantCreate {
+				mockClient.EXPECT().CreateNvmeSubsystem(ctx, &pb.CreateNvmeSubsystemRequest{
+					NvmeSubsystemId: subsystemID,
+					NvmeSubsystem: &pb.NvmeSubsystem{
+						Spec: &pb.NvmeSubsystemSpec{Nqn: nqn, MaxNamespaces: tt.giveMaxNamespaces},
+					},
+				}).Return(&pb.NvmeSubsystem{Name: subsystemName}, nil)
+				mockClient.EXPECT().CreateNvmeController(ctx, &pb.CreateNvmeControllerRequest{
+					Parent:           subsystemName,
+					NvmeControllerId: controllerID,
+					NvmeController: &pb.NvmeController{
+						Spec: &pb.NvmeControllerSpec{MaxNamespaces: int32(tt.giveMaxNamespaces)},
+					},
+				}).Return(&pb.NvmeController{Name: controllerName}, nil)
+			}
+
+			gotSubsystemID, gotControllerID, err := c.ExposeRemoteNvme(ctx, nqn, tt.giveMaxNamespaces)
+
+			require.Equal(t, tt.wantErr, err)
+			if tt.wantErr == nil {
+				require.Equal(t, subsystemID, gotSubsystemID)
+				require.Equal(t, controllerID, gotControllerID)
+			}
+		})
+	}
+}
+
+func TestCSIClientCreateNvmeNamespace(t *testing.T) {
+	nguid := "0123456789abcdef0123456789abcdef"
+	parsedNguid, _ := nvme.ParseNGUID(nguid)
+	namespaceID := deterministicID("namespace", "subsys0", parsedNguid.String())
+	namespaceName := frontend.NamespaceName("subsys0", namespaceID)
+	volumes := &pb.ListNullVolumesResponse{
+		NullVolumes: []*pb.NullVolume{{Name: "nullVolumes/null0", Uuid: nguid}},
+	}
+
+	tests := map[string]struct {
+		giveNamespace    *pb.NvmeNamespace
+		giveNamespaceErr error
+		wantCreate       bool
+		wantName         string
+		wantErr          error
+	}{
+		"create missing namespace": {
+			giveNamespaceErr: status.Error(codes.NotFound, "not found"),
+			wantCreate:       true,
+			wantName:         namespaceName,
+			wantErr:          nil,
+		},
+		"reuse existing namespace": {
+			giveNamespace: &pb.NvmeNamespace{
+				Name: namespaceName,
+				Spec: &pb.NvmeNamespaceSpec{VolumeNameRef: "nullVolumes/null0", HostNsid: 1},
+			},
+			wantName: namespaceName,
+			wantErr:  nil,
+		},
+		"volume conflict": {
+			giveNamespace: &pb.NvmeNamespace{
+				Name: namespaceName,
+				Spec: &pb.NvmeNamespaceSpec{VolumeNameRef: "nullVolumes/null1", HostNsid: 1},
+			},
+			wantErr: &frontend.ConflictError{Name: namespaceName, Field: "volume"},
+		},
+		"host nsid conflict": {
+			giveNamespace: &pb.NvmeNamespace{
+				Name: namespaceName,
+				Spec: &pb.NvmeNamespaceSpec{VolumeNameRef: "nullVolumes/null0", HostNsid: 2},
+			},
+			wantErr: &frontend.ConflictError{Name: namespaceName, Field: "host_nsid"},
+		},
+	}
+
+	for testName, tt := range tests {
+		t.Run(testName, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
+
+			c, mockClient, mockNullClient := newTestCSIClient(t)
+			mockNullClient.EXPECT().ListNullVolumes(ctx, &pb.ListNullVolumesRequest{}).Return(volumes, nil)
+			mockClient.EXPECT().GetNvmeNamespace(ctx, &pb.GetNvmeNamespaceRequest{Name: namespaceName}).
+				Return(tt.giveNamespace, tt.giveNamespaceErr)
+			if tt.wantCreate {
+				mockClient.EXPECT().CreateNvmeNamespace(ctx, &pb.CreateNvmeNamespaceRequest{
+					Parent:          frontend.SubsystemName("subsys0"),
+					NvmeNamespaceId: namespaceID,
+					NvmeNamespace: &pb.NvmeNamespace{
+						Spec: &pb.NvmeNamespaceSpec{VolumeNameRef: "nullVolumes/null0", HostNsid: 1},
+					},
+				}).Return(&pb.NvmeNamespace{Name: namespaceName}, nil)
+			}
+
+			name, err := c.CreateNvmeNamespace(ctx, "", "subsys0", nguid, 1)
+
+			require.Equal(t, tt.wantErr, err)
+			require.Equal(t, tt.wantName, name)
+		})
+	}
 }
 
 func TestGoopcsiTestSuite(t *testing.T) {
@@ -0,0 +1,51 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (C) 2024 Intel Corporation
+
+// Package middleend implements the go library for OPI middleend storage
+package middleend
+
+import (
+	grpcOpi "github.com/opiproject/godpu/grpc"
+	pb "github.com/opiproject/opi-api/storage/v1alpha1/gen/go"
+	"google.golang.org/grpc"
+)
+
+// CreateEncryptionClient defines the function type used to retrieve MiddleendEncryptionServiceClient
+type CreateEncryptionClient func(cc grpc.ClientConnInterface) pb.MiddleendEncryptionServiceClient
+
+// CreateQosClient defines the function type used to retrieve MiddleendQosVolumeServiceClient
+type CreateQosClient func(cc grpc.ClientConnInterface) pb.MiddleendQosVolumeServiceClient
+
+// Client is used for managing storage devices on OPI server
+type Client struct {
+	connector              grpcOpi.Connector
+	createEncryptionClient CreateEncryptionClient
+	createQosClient        CreateQosClient
+}
+
+// New creates a new instance of Client
+func New(addr string, tls string) (*Client, error) {
+	connector, err := grpcOpi.New(addr, tls)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewWithArgs(
+		connector,
+		pb.NewMiddleendEncryptionServiceClient,
+		pb.NewMiddleendQosVolumeServiceClient,
+	)
+}
+
+// NewWithArgs creates a new instance of Client with non-default members
+func NewWithArgs(
+	connector grpcOpi.Connector,
+	createEncryptionClient CreateEncryptionClient,
+	createQosClient CreateQosClient,
+) (*Client, error) {
+	return &Client{
+		connector:              connector,
+		createEncryptionClient: createEncryptionClient,
+		createQosClient:        createQosClient,
+	}, nil
+}
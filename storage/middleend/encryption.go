@@ -0,0 +1,200 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (C) 2024 Intel Corporation
+
+// Package middleend implements the go library for OPI middleend storage
+package middleend
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	pb "github.com/opiproject/opi-api/storage/v1alpha1/gen/go"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+)
+
+// xtsKeySizes maps AES-XTS ciphers to their key sizes in bytes. XTS keys
+// consist of two AES keys of the cipher size.
+var xtsKeySizes = map[pb.EncryptionType]int{
+	pb.EncryptionType_ENCRYPTION_TYPE_AES_XTS_128: 2 * 128 / 8,
+	pb.EncryptionType_ENCRYPTION_TYPE_AES_XTS_192: 2 * 192 / 8,
+	pb.EncryptionType_ENCRYPTION_TYPE_AES_XTS_256: 2 * 256 / 8,
+}
+
+// EncryptionKeySize returns the key size in bytes required by an AES-XTS
+// cipher
+func EncryptionKeySize(cipher pb.EncryptionType) (int, error) {
+	size, ok := xtsKeySizes[cipher]
+	if !ok {
+		return 0, fmt.Errorf("unsupported cipher %v, only AES-XTS ciphers are allowed", cipher)
+	}
+
+	return size, nil
+}
+
+// CreateEncryptedVolume creates an encrypted volume on top of a volume.
+// The key is never included into returned errors.
+func (c *Client) CreateEncryptedVolume(
+	ctx context.Context,
+	id string,
+	volume string,
+	cipher pb.EncryptionType,
+	key []byte,
+) (*pb.EncryptedVolume, error) {
+	if volume == "" {
+		return nil, errors.New("volume is required")
+	}
+	if err := validateEncryptionKey(cipher, key); err != nil {
+		return nil, err
+	}
+
+	conn, connClose, err := c.connector.NewConn()
+	if err != nil {
+		return nil, err
+	}
+	defer connClose()
+
+	client := c.createEncryptionClient(conn)
+	return client.CreateEncryptedVolume(
+		ctx,
+		&pb.CreateEncryptedVolumeRequest{
+			EncryptedVolumeId: id,
+			EncryptedVolume: &pb.EncryptedVolume{
+				VolumeNameRef: volume,
+				Key:           key,
+				Cipher:        cipher,
+			},
+		})
+}
+
+// DeleteEncryptedVolume deletes an encrypted volume
+func (c *Client) DeleteEncryptedVolume(
+	ctx context.Context,
+	name string,
+	allowMissing bool,
+) error {
+	conn, connClose, err := c.connector.NewConn()
+	if err != nil {
+		return err
+	}
+	defer connClose()
+
+	client := c.createEncryptionClient(conn)
+	_, err = client.DeleteEncryptedVolume(
+		ctx,
+		&pb.DeleteEncryptedVolumeRequest{
+			Name:         name,
+			AllowMissing: allowMissing,
+		})
+
+	return err
+}
+
+// GetEncryptedVolume gets an encrypted volume
+func (c *Client) GetEncryptedVolume(
+	ctx context.Context,
+	name string,
+) (*pb.EncryptedVolume, error) {
+	conn, connClose, err := c.connector.NewConn()
+	if err != nil {
+		return nil, err
+	}
+	defer connClose()
+
+	client := c.createEncryptionClient(conn)
+	return client.GetEncryptedVolume(
+		ctx,
+		&pb.GetEncryptedVolumeRequest{
+			Name: name,
+		})
+}
+
+// ListEncryptedVolumes lists a page of encrypted volumes
+func (c *Client) ListEncryptedVolumes(
+	ctx context.Context,
+	parent string,
+	pageSize int32,
+	pageToken string,
+) (*pb.ListEncryptedVolumesResponse, error) {
+	conn, connClose, err := c.connector.NewConn()
+	if err != nil {
+		return nil, err
+	}
+	defer connClose()
+
+	client := c.createEncryptionClient(conn)
+	return client.ListEncryptedVolumes(
+		ctx,
+		&pb.ListEncryptedVolumesRequest{
+			Parent:    parent,
+			PageSize:  pageSize,
+			PageToken: pageToken,
+		})
+}
+
+// UpdateEncryptedVolume updates the fields of an encrypted volume listed in
+// updateMask. All fields are updated if updateMask is empty. A new key is
+// validated against the cipher of the volume.
+func (c *Client) UpdateEncryptedVolume(
+	ctx context.Context,
+	volume *pb.EncryptedVolume,
+	updateMask []string,
+	allowMissing bool,
+) (*pb.EncryptedVolume, error) {
+	if len(volume.GetKey()) != 0 {
+		if err := validateEncryptionKey(volume.GetCipher(), volume.GetKey()); err != nil {
+			return nil, err
+		}
+	}
+
+	conn, connClose, err := c.connector.NewConn()
+	if err != nil {
+		return nil, err
+	}
+	defer connClose()
+
+	client := c.createEncryptionClient(conn)
+	return client.UpdateEncryptedVolume(
+		ctx,
+		&pb.UpdateEncryptedVolumeRequest{
+			EncryptedVolume: volume,
+			UpdateMask:      &fieldmaskpb.FieldMask{Paths: updateMask},
+			AllowMissing:    allowMissing,
+		})
+}
+
+// StatsEncryptedVolume gets io statistics of an encrypted volume
+func (c *Client) StatsEncryptedVolume(
+	ctx context.Context,
+	name string,
+) (*pb.VolumeStats, error) {
+	conn, connClose, err := c.connector.NewConn()
+	if err != nil {
+		return nil, err
+	}
+	defer connClose()
+
+	client := c.createEncryptionClient(conn)
+	response, err := client.StatsEncryptedVolume(
+		ctx,
+		&pb.StatsEncryptedVolumeRequest{
+			Name: name,
+		})
+	if err != nil {
+		return nil, err
+	}
+
+	return response.GetStats(), nil
+}
+
+func validateEncryptionKey(cipher pb.EncryptionType, key []byte) error {
+	size, err := EncryptionKeySize(cipher)
+	if err != nil {
+		return err
+	}
+	if len(key) != size {
+		return fmt.Errorf("invalid key size %v for cipher %v, expected %v bytes", len(key), cipher, size)
+	}
+
+	return nil
+}
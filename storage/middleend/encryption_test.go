@@ -0,0 +1,532 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (C) 2024 Intel Corporation
+
+// Package middleend implements the go library for OPI middleend storage
+package middleend
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/opiproject/godpu/mocks"
+	pb "github.com/opiproject/opi-api/storage/v1alpha1/gen/go"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+)
+
+var testEncryptedVolume = &pb.EncryptedVolume{
+	Name:          "encryptedVolumes/enc0",
+	VolumeNameRef: "Malloc0",
+	Key:           []byte("0123456789abcdef0123456789abcdef"),
+	Cipher:        pb.EncryptionType_ENCRYPTION_TYPE_AES_XTS_128,
+}
+
+func TestCreateEncryptedVolume(t *testing.T) {
+	testRequest := &pb.CreateEncryptedVolumeRequest{
+		EncryptedVolumeId: "enc0",
+		EncryptedVolume: &pb.EncryptedVolume{
+			VolumeNameRef: testEncryptedVolume.VolumeNameRef,
+			Key:           testEncryptedVolume.Key,
+			Cipher:        testEncryptedVolume.Cipher,
+		},
+	}
+
+	tests := map[string]struct {
+		giveCipher       pb.EncryptionType
+		giveKey          []byte
+		giveClientErr    error
+		giveConnectorErr error
+		wantErr          error
+		wantRequest      *pb.CreateEncryptedVolumeRequest
+		wantResponse     *pb.EncryptedVolume
+		wantConnClosed   bool
+	}{
+		"successful call": {
+			giveCipher:       testEncryptedVolume.Cipher,
+			giveKey:          testEncryptedVolume.Key,
+			giveConnectorErr: nil,
+			giveClientErr:    nil,
+			wantErr:          nil,
+			wantRequest:      proto.Clone(testRequest).(*pb.CreateEncryptedVolumeRequest),
+			wantResponse:     proto.Clone(testEncryptedVolume).(*pb.EncryptedVolume),
+			wantConnClosed:   true,
+		},
+		"client err": {
+			giveCipher:       testEncryptedVolume.Cipher,
+			giveKey:          testEncryptedVolume.Key,
+			giveConnectorErr: nil,
+			giveClientErr:    errors.New("Some client error"),
+			wantErr:          errors.New("Some client error"),
+			wantRequest:      proto.Clone(testRequest).(*pb.CreateEncryptedVolumeRequest),
+			wantResponse:     nil,
+			wantConnClosed:   true,
+		},
+		"connector err": {
+			giveCipher:       testEncryptedVolume.Cipher,
+			giveKey:          testEncryptedVolume.Key,
+			giveConnectorErr: errors.New("Some conn error"),
+			giveClientErr:    nil,
+			wantErr:          errors.New("Some conn error"),
+			wantRequest:      nil,
+			wantResponse:     nil,
+			wantConnClosed:   false,
+		},
+		"invalid key size": {
+			giveCipher:     pb.EncryptionType_ENCRYPTION_TYPE_AES_XTS_256,
+			giveKey:        testEncryptedVolume.Key,
+			wantErr:        errors.New("invalid key size 32 for cipher ENCRYPTION_TYPE_AES_XTS_256, expected 64 bytes"),
+			wantRequest:    nil,
+			wantResponse:   nil,
+			wantConnClosed: false,
+		},
+		"unsupported cipher": {
+			giveCipher:     pb.EncryptionType_ENCRYPTION_TYPE_AES_CBC_128,
+			giveKey:        testEncryptedVolume.Key,
+			wantErr:        errors.New("unsupported cipher ENCRYPTION_TYPE_AES_CBC_128, only AES-XTS ciphers are allowed"),
+			wantRequest:    nil,
+			wantResponse:   nil,
+			wantConnClosed: false,
+		},
+	}
+
+	for testName, tt := range tests {
+		t.Run(testName, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
+
+			connClosed := false
+			mockClient := mocks.NewMiddleendEncryptionServiceClient(t)
+			mockConn := mocks.NewConnector(t)
+			mockConn.EXPECT().NewConn().Return(
+				&grpc.ClientConn{},
+				func() { connClosed = true },
+				tt.giveConnectorErr,
+			).Maybe()
+			if tt.wantRequest != nil {
+				toReturn := proto.Clone(tt.wantResponse).(*pb.EncryptedVolume)
+				mockClient.EXPECT().CreateEncryptedVolume(ctx, tt.wantRequest).
+					Return(toReturn, tt.giveClientErr)
+			}
+
+			c, _ := NewWithArgs(
+				mockConn,
+				func(grpc.ClientConnInterface) pb.MiddleendEncryptionServiceClient {
+					return mockClient
+				},
+				pb.NewMiddleendQosVolumeServiceClient,
+			)
+
+			response, err := c.CreateEncryptedVolume(
+				ctx,
+				"enc0",
+				testEncryptedVolume.VolumeNameRef,
+				tt.giveCipher,
+				tt.giveKey,
+			)
+
+			require.Equal(t, tt.wantErr, err)
+			require.True(t, proto.Equal(response, tt.wantResponse))
+			require.Equal(t, tt.wantConnClosed, connClosed)
+		})
+	}
+}
+
+func TestDeleteEncryptedVolume(t *testing.T) {
+	testRequest := &pb.DeleteEncryptedVolumeRequest{
+		Name:         testEncryptedVolume.Name,
+		AllowMissing: true,
+	}
+
+	tests := map[string]struct {
+		giveClientErr    error
+		giveConnectorErr error
+		wantErr          error
+		wantRequest      *pb.DeleteEncryptedVolumeRequest
+		wantConnClosed   bool
+	}{
+		"successful call": {
+			giveConnectorErr: nil,
+			giveClientErr:    nil,
+			wantErr:          nil,
+			wantRequest:      proto.Clone(testRequest).(*pb.DeleteEncryptedVolumeRequest),
+			wantConnClosed:   true,
+		},
+		"client err": {
+			giveConnectorErr: nil,
+			giveClientErr:    errors.New("Some client error"),
+			wantErr:          errors.New("Some client error"),
+			wantRequest:      proto.Clone(testRequest).(*pb.DeleteEncryptedVolumeRequest),
+			wantConnClosed:   true,
+		},
+		"connector err": {
+			giveConnectorErr: errors.New("Some conn error"),
+			giveClientErr:    nil,
+			wantErr:          errors.New("Some conn error"),
+			wantRequest:      nil,
+			wantConnClosed:   false,
+		},
+	}
+
+	for testName, tt := range tests {
+		t.Run(testName, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
+
+			mockClient := mocks.NewMiddleendEncryptionServiceClient(t)
+			if tt.wantRequest != nil {
+				mockClient.EXPECT().DeleteEncryptedVolume(ctx, tt.wantRequest).
+					Return(&emptypb.Empty{}, tt.giveClientErr)
+			}
+
+			connClosed := false
+			mockConn := mocks.NewConnector(t)
+			mockConn.EXPECT().NewConn().Return(
+				&grpc.ClientConn{},
+				func() { connClosed = true },
+				tt.giveConnectorErr,
+			)
+
+			c, _ := NewWithArgs(
+				mockConn,
+				func(grpc.ClientConnInterface) pb.MiddleendEncryptionServiceClient {
+					return mockClient
+				},
+				pb.NewMiddleendQosVolumeServiceClient,
+			)
+
+			err := c.DeleteEncryptedVolume(ctx, testEncryptedVolume.Name, true)
+
+			require.Equal(t, tt.wantErr, err)
+			require.Equal(t, tt.wantConnClosed, connClosed)
+		})
+	}
+}
+
+func TestGetEncryptedVolume(t *testing.T) {
+	testRequest := &pb.GetEncryptedVolumeRequest{
+		Name: testEncryptedVolume.Name,
+	}
+
+	tests := map[string]struct {
+		giveClientErr    error
+		giveConnectorErr error
+		wantErr          error
+		wantRequest      *pb.GetEncryptedVolumeRequest
+		wantResponse     *pb.EncryptedVolume
+		wantConnClosed   bool
+	}{
+		"successful call": {
+			giveConnectorErr: nil,
+			giveClientErr:    nil,
+			wantErr:          nil,
+			wantRequest:      proto.Clone(testRequest).(*pb.GetEncryptedVolumeRequest),
+			wantResponse:     proto.Clone(testEncryptedVolume).(*pb.EncryptedVolume),
+			wantConnClosed:   true,
+		},
+		"client err": {
+			giveConnectorErr: nil,
+			giveClientErr:    errors.New("Some client error"),
+			wantErr:          errors.New("Some client error"),
+			wantRequest:      proto.Clone(testRequest).(*pb.GetEncryptedVolumeRequest),
+			wantResponse:     nil,
+			wantConnClosed:   true,
+		},
+		"connector err": {
+			giveConnectorErr: errors.New("Some conn error"),
+			giveClientErr:    nil,
+			wantErr:          errors.New("Some conn error"),
+			wantRequest:      nil,
+			wantResponse:     nil,
+			wantConnClosed:   false,
+		},
+	}
+
+	for testName, tt := range tests {
+		t.Run(testName, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
+
+			mockClient := mocks.NewMiddleendEncryptionServiceClient(t)
+			if tt.wantRequest != nil {
+				toReturn := proto.Clone(tt.wantResponse).(*pb.EncryptedVolume)
+				mockClient.EXPECT().GetEncryptedVolume(ctx, tt.wantRequest).
+					Return(toReturn, tt.giveClientErr)
+			}
+
+			connClosed := false
+			mockConn := mocks.NewConnector(t)
+			mockConn.EXPECT().NewConn().Return(
+				&grpc.ClientConn{},
+				func() { connClosed = true },
+				tt.giveConnectorErr,
+			)
+
+			c, _ := NewWithArgs(
+				mockConn,
+				func(grpc.ClientConnInterface) pb.MiddleendEncryptionServiceClient {
+					return mockClient
+				},
+				pb.NewMiddleendQosVolumeServiceClient,
+			)
+
+			response, err := c.GetEncryptedVolume(ctx, testEncryptedVolume.Name)
+
+			require.Equal(t, tt.wantErr, err)
+			require.True(t, proto.Equal(response, tt.wantResponse))
+			require.Equal(t, tt.wantConnClosed, connClosed)
+		})
+	}
+}
+
+func TestListEncryptedVolumes(t *testing.T) {
+	testRequest := &pb.ListEncryptedVolumesRequest{
+		Parent:    "volumes",
+		PageSize:  10,
+		PageToken: "token",
+	}
+	testResponse := &pb.ListEncryptedVolumesResponse{
+		EncryptedVolumes: []*pb.EncryptedVolume{testEncryptedVolume},
+		NextPageToken:    "next",
+	}
+
+	tests := map[string]struct {
+		giveClientErr    error
+		giveConnectorErr error
+		wantErr          error
+		wantRequest      *pb.ListEncryptedVolumesRequest
+		wantResponse     *pb.ListEncryptedVolumesResponse
+		wantConnClosed   bool
+	}{
+		"successful call": {
+			giveConnectorErr: nil,
+			giveClientErr:    nil,
+			wantErr:          nil,
+			wantRequest:      proto.Clone(testRequest).(*pb.ListEncryptedVolumesRequest),
+			wantResponse:     proto.Clone(testResponse).(*pb.ListEncryptedVolumesResponse),
+			wantConnClosed:   true,
+		},
+		"client err": {
+			giveConnectorErr: nil,
+			giveClientErr:    errors.New("Some client error"),
+			wantErr:          errors.New("Some client error"),
+			wantRequest:      proto.Clone(testRequest).(*pb.ListEncryptedVolumesRequest),
+			wantResponse:     nil,
+			wantConnClosed:   true,
+		},
+		"connector err": {
+			giveConnectorErr: errors.New("Some conn error"),
+			giveClientErr:    nil,
+			wantErr:          errors.New("Some conn error"),
+			wantRequest:      nil,
+			wantResponse:     nil,
+			wantConnClosed:   false,
+		},
+	}
+
+	for testName, tt := range tests {
+		t.Run(testName, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
+
+			mockClient := mocks.NewMiddleendEncryptionServiceClient(t)
+			if tt.wantRequest != nil {
+				toReturn := proto.Clone(tt.wantResponse).(*pb.ListEncryptedVolumesResponse)
+				mockClient.EXPECT().ListEncryptedVolumes(ctx, tt.wantRequest).
+					Return(toReturn, tt.giveClientErr)
+			}
+
+			connClosed := false
+			mockConn := mocks.NewConnector(t)
+			mockConn.EXPECT().NewConn().Return(
+				&grpc.ClientConn{},
+				func() { connClosed = true },
+				tt.giveConnectorErr,
+			)
+
+			c, _ := NewWithArgs(
+				mockConn,
+				func(grpc.ClientConnInterface) pb.MiddleendEncryptionServiceClient {
+					return mockClient
+				},
+				pb.NewMiddleendQosVolumeServiceClient,
+			)
+
+			response, err := c.ListEncryptedVolumes(ctx, "volumes", 10, "token")
+
+			require.Equal(t, tt.wantErr, err)
+			require.True(t, proto.Equal(response, tt.wantResponse))
+			require.Equal(t, tt.wantConnClosed, connClosed)
+		})
+	}
+}
+
+func TestUpdateEncryptedVolume(t *testing.T) {
+	testRequest := &pb.UpdateEncryptedVolumeRequest{
+		EncryptedVolume: proto.Clone(testEncryptedVolume).(*pb.EncryptedVolume),
+		UpdateMask:      &fieldmaskpb.FieldMask{Paths: []string{"key"}},
+		AllowMissing:    false,
+	}
+
+	tests := map[string]struct {
+		giveClientErr    error
+		giveConnectorErr error
+		wantErr          error
+		wantRequest      *pb.UpdateEncryptedVolumeRequest
+		wantResponse     *pb.EncryptedVolume
+		wantConnClosed   bool
+	}{
+		"successful call": {
+			giveConnectorErr: nil,
+			giveClientErr:    nil,
+			wantErr:          nil,
+			wantRequest:      proto.Clone(testRequest).(*pb.UpdateEncryptedVolumeRequest),
+			wantResponse:     proto.Clone(testEncryptedVolume).(*pb.EncryptedVolume),
+			wantConnClosed:   true,
+		},
+		"client err": {
+			giveConnectorErr: nil,
+			giveClientErr:    errors.New("Some client error"),
+			wantErr:          errors.New("Some client error"),
+			wantRequest:      proto.Clone(testRequest).(*pb.UpdateEncryptedVolumeRequest),
+			wantResponse:     nil,
+			wantConnClosed:   true,
+		},
+		"connector err": {
+			giveConnectorErr: errors.New("Some conn error"),
+			giveClientErr:    nil,
+			wantErr:          errors.New("Some conn error"),
+			wantRequest:      nil,
+			wantResponse:     nil,
+			wantConnClosed:   false,
+		},
+	}
+
+	for testName, tt := range tests {
+		t.Run(testName, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
+
+			mockClient := mocks.NewMiddleendEncryptionServiceClient(t)
+			if tt.wantRequest != nil {
+				toReturn := proto.Clone(tt.wantResponse).(*pb.EncryptedVolume)
+				mockClient.EXPECT().UpdateEncryptedVolume(ctx, tt.wantRequest).
+					Return(toReturn, tt.giveClientErr)
+			}
+
+			connClosed := false
+			mockConn := mocks.NewConnector(t)
+			mockConn.EXPECT().NewConn().Return(
+				&grpc.ClientConn{},
+				func() { connClosed = true },
+				tt.giveConnectorErr,
+			)
+
+			c, _ := NewWithArgs(
+				mockConn,
+				func(grpc.ClientConnInterface) pb.MiddleendEncryptionServiceClient {
+					return mockClient
+				},
+				pb.NewMiddleendQosVolumeServiceClient,
+			)
+
+			response, err := c.UpdateEncryptedVolume(
+				ctx,
+				proto.Clone(testEncryptedVolume).(*pb.EncryptedVolume),
+				[]string{"key"},
+				false,
+			)
+
+			require.Equal(t, tt.wantErr, err)
+			require.True(t, proto.Equal(response, tt.wantResponse))
+			require.Equal(t, tt.wantConnClosed, connClosed)
+		})
+	}
+}
+
+func TestStatsEncryptedVolume(t *testing.T) {
+	testRequest := &pb.StatsEncryptedVolumeRequest{
+		Name: testEncryptedVolume.Name,
+	}
+	testStats := &pb.VolumeStats{
+		ReadBytesCount: 4096,
+		ReadOpsCount:   8,
+	}
+
+	tests := map[string]struct {
+		giveClientErr    error
+		giveConnectorErr error
+		wantErr          error
+		wantRequest      *pb.StatsEncryptedVolumeRequest
+		wantResponse     *pb.VolumeStats
+		wantConnClosed   bool
+	}{
+		"successful call": {
+			giveConnectorErr: nil,
+			giveClientErr:    nil,
+			wantErr:          nil,
+			wantRequest:      proto.Clone(testRequest).(*pb.StatsEncryptedVolumeRequest),
+			wantResponse:     proto.Clone(testStats).(*pb.VolumeStats),
+			wantConnClosed:   true,
+		},
+		"client err": {
+			giveConnectorErr: nil,
+			giveClientErr:    errors.New("Some client error"),
+			wantErr:          errors.New("Some client error"),
+			wantRequest:      proto.Clone(testRequest).(*pb.StatsEncryptedVolumeRequest),
+			wantResponse:     nil,
+			wantConnClosed:   true,
+		},
+		"connector err": {
+			giveConnectorErr: errors.New("Some conn error"),
+			giveClientErr:    nil,
+			wantErr:          errors.New("Some conn error"),
+			wantRequest:      nil,
+			wantResponse:     nil,
+			wantConnClosed:   false,
+		},
+	}
+
+	for testName, tt := range tests {
+		t.Run(testName, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
+
+			mockClient := mocks.NewMiddleendEncryptionServiceClient(t)
+			if tt.wantRequest != nil {
+				var toReturn *pb.StatsEncryptedVolumeResponse
+				if tt.giveClientErr == nil {
+					toReturn = &pb.StatsEncryptedVolumeResponse{Stats: proto.Clone(tt.wantResponse).(*pb.VolumeStats)}
+				}
+				mockClient.EXPECT().StatsEncryptedVolume(ctx, tt.wantRequest).
+					Return(toReturn, tt.giveClientErr)
+			}
+
+			connClosed := false
+			mockConn := mocks.NewConnector(t)
+			mockConn.EXPECT().NewConn().Return(
+				&grpc.ClientConn{},
+				func() { connClosed = true },
+				tt.giveConnectorErr,
+			)
+
+			c, _ := NewWithArgs(
+				mockConn,
+				func(grpc.ClientConnInterface) pb.MiddleendEncryptionServiceClient {
+					return mockClient
+				},
+				pb.NewMiddleendQosVolumeServiceClient,
+			)
+
+			response, err := c.StatsEncryptedVolume(ctx, testEncryptedVolume.Name)
+
+			require.Equal(t, tt.wantErr, err)
+			require.True(t, proto.Equal(response, tt.wantResponse))
+			require.Equal(t, tt.wantConnClosed, connClosed)
+		})
+	}
+}
@@ -0,0 +1,253 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (C) 2024 Intel Corporation
+
+// Package middleend implements the go library for OPI middleend storage
+package middleend
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	pb "github.com/opiproject/opi-api/storage/v1alpha1/gen/go"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+)
+
+// CreateQosVolume creates a QoS volume limiting iops and bandwidth of
+// a volume
+func (c *Client) CreateQosVolume(
+	ctx context.Context,
+	id string,
+	volume string,
+	limits *pb.Limits,
+) (*pb.QosVolume, error) {
+	if volume == "" {
+		return nil, errors.New("volume is required")
+	}
+	if err := ValidateQosLimits(limits); err != nil {
+		return nil, err
+	}
+
+	conn, connClose, err := c.connector.NewConn()
+	if err != nil {
+		return nil, err
+	}
+	defer connClose()
+
+	client := c.createQosClient(conn)
+	return client.CreateQosVolume(
+		ctx,
+		&pb.CreateQosVolumeRequest{
+			QosVolumeId: id,
+			QosVolume: &pb.QosVolume{
+				VolumeNameRef: volume,
+				Limits:        limits,
+			},
+		})
+}
+
+// DeleteQosVolume deletes a QoS volume
+func (c *Client) DeleteQosVolume(
+	ctx context.Context,
+	name string,
+	allowMissing bool,
+) error {
+	conn, connClose, err := c.connector.NewConn()
+	if err != nil {
+		return err
+	}
+	defer connClose()
+
+	client := c.createQosClient(conn)
+	_, err = client.DeleteQosVolume(
+		ctx,
+		&pb.DeleteQosVolumeRequest{
+			Name:         name,
+			AllowMissing: allowMissing,
+		})
+
+	return err
+}
+
+// GetQosVolume gets a QoS volume
+func (c *Client) GetQosVolume(
+	ctx context.Context,
+	name string,
+) (*pb.QosVolume, error) {
+	conn, connClose, err := c.connector.NewConn()
+	if err != nil {
+		return nil, err
+	}
+	defer connClose()
+
+	client := c.createQosClient(conn)
+	return client.GetQosVolume(
+		ctx,
+		&pb.GetQosVolumeRequest{
+			Name: name,
+		})
+}
+
+// ListQosVolumes lists a page of QoS volumes
+func (c *Client) ListQosVolumes(
+	ctx context.Context,
+	parent string,
+	pageSize int32,
+	pageToken string,
+) (*pb.ListQosVolumesResponse, error) {
+	conn, connClose, err := c.connector.NewConn()
+	if err != nil {
+		return nil, err
+	}
+	defer connClose()
+
+	client := c.createQosClient(conn)
+	return client.ListQosVolumes(
+		ctx,
+		&pb.ListQosVolumesRequest{
+			Parent:    parent,
+			PageSize:  pageSize,
+			PageToken: pageToken,
+		})
+}
+
+// UpdateQosVolume updates the fields of a QoS volume listed in updateMask.
+// All fields are updated if updateMask is empty. Limits can be changed
+// while the volume is in use. Only the limits listed in updateMask are
+// validated since the others keep their current values.
+func (c *Client) UpdateQosVolume(
+	ctx context.Context,
+	volume *pb.QosVolume,
+	updateMask []string,
+	allowMissing bool,
+) (*pb.QosVolume, error) {
+	if len(updateMask) == 0 && volume.GetLimits() != nil {
+		if err := ValidateQosLimits(volume.GetLimits()); err != nil {
+			return nil, err
+		}
+	}
+	if len(updateMask) != 0 {
+		if err := validateMaskedQosLimits(volume.GetLimits(), updateMask); err != nil {
+			return nil, err
+		}
+	}
+
+	conn, connClose, err := c.connector.NewConn()
+	if err != nil {
+		return nil, err
+	}
+	defer connClose()
+
+	client := c.createQosClient(conn)
+	return client.UpdateQosVolume(
+		ctx,
+		&pb.UpdateQosVolumeRequest{
+			QosVolume:    volume,
+			UpdateMask:   &fieldmaskpb.FieldMask{Paths: updateMask},
+			AllowMissing: allowMissing,
+		})
+}
+
+// StatsQosVolume gets io statistics of a QoS volume
+func (c *Client) StatsQosVolume(
+	ctx context.Context,
+	name string,
+) (*pb.VolumeStats, error) {
+	conn, connClose, err := c.connector.NewConn()
+	if err != nil {
+		return nil, err
+	}
+	defer connClose()
+
+	client := c.createQosClient(conn)
+	response, err := client.StatsQosVolume(
+		ctx,
+		&pb.StatsQosVolumeRequest{
+			Name: name,
+		})
+	if err != nil {
+		return nil, err
+	}
+
+	return response.GetStats(), nil
+}
+
+// ValidateQosLimits checks that QoS limits are not negative and that no
+// min limit exceeds the corresponding max limit. At least one limit has
+// to be set.
+func ValidateQosLimits(limits *pb.Limits) error {
+	if limits == nil {
+		return errors.New("limits are required")
+	}
+
+	minValues := qosLimitValues(limits.GetMin())
+	maxValues := qosLimitValues(limits.GetMax())
+	anySet := false
+	for i, name := range qosLimitNames {
+		if minValues[i] < 0 {
+			return fmt.Errorf("min %v cannot be negative: %v", name, minValues[i])
+		}
+		if maxValues[i] < 0 {
+			return fmt.Errorf("max %v cannot be negative: %v", name, maxValues[i])
+		}
+		if maxValues[i] != 0 && minValues[i] > maxValues[i] {
+			return fmt.Errorf("min %v %v exceeds max %v", name, minValues[i], maxValues[i])
+		}
+		anySet = anySet || minValues[i] != 0 || maxValues[i] != 0
+	}
+	if !anySet {
+		return errors.New("at least one limit has to be set")
+	}
+
+	return nil
+}
+
+// validateMaskedQosLimits checks the QoS limits listed in updateMask. A min
+// limit is compared to its max limit only if both are updated.
+func validateMaskedQosLimits(limits *pb.Limits, updateMask []string) error {
+	updated := map[string]bool{}
+	for _, path := range updateMask {
+		if path == "*" || path == "limits" {
+			return ValidateQosLimits(limits)
+		}
+		updated[path] = true
+	}
+
+	minValues := qosLimitValues(limits.GetMin())
+	maxValues := qosLimitValues(limits.GetMax())
+	for i, name := range qosLimitNames {
+		updatedMin := updated["limits.min"] || updated["limits.min."+name]
+		updatedMax := updated["limits.max"] || updated["limits.max."+name]
+		if updatedMin && minValues[i] < 0 {
+			return fmt.Errorf("min %v cannot be negative: %v", name, minValues[i])
+		}
+		if updatedMax && maxValues[i] < 0 {
+			return fmt.Errorf("max %v cannot be negative: %v", name, maxValues[i])
+		}
+		if updatedMin && updatedMax && maxValues[i] != 0 && minValues[i] > maxValues[i] {
+			return fmt.Errorf("min %v %v exceeds max %v", name, minValues[i], maxValues[i])
+		}
+	}
+
+	return nil
+}
+
+var qosLimitNames = []string{
+	"rd_iops_kiops",
+	"wr_iops_kiops",
+	"rw_iops_kiops",
+	"rd_bandwidth_mbs",
+	"wr_bandwidth_mbs",
+	"rw_bandwidth_mbs",
+}
+
+func qosLimitValues(limit *pb.QosLimit) []int64 {
+	return []int64{
+		limit.GetRdIopsKiops(),
+		limit.GetWrIopsKiops(),
+		limit.GetRwIopsKiops(),
+		limit.GetRdBandwidthMbs(),
+		limit.GetWrBandwidthMbs(),
+		limit.GetRwBandwidthMbs(),
+	}
+}
@@ -0,0 +1,627 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (C) 2024 Intel Corporation
+
+// Package middleend implements the go library for OPI middleend storage
+package middleend
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/opiproject/godpu/mocks"
+	pb "github.com/opiproject/opi-api/storage/v1alpha1/gen/go"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+)
+
+var testQosVolume = &pb.QosVolume{
+	Name:          "qosVolumes/qos0",
+	VolumeNameRef: "Malloc0",
+	Limits: &pb.Limits{
+		Max: &pb.QosLimit{
+			RdIopsKiops:    10,
+			RwBandwidthMbs: 100,
+		},
+	},
+}
+
+func TestCreateQosVolume(t *testing.T) {
+	testRequest := &pb.CreateQosVolumeRequest{
+		QosVolumeId: "qos0",
+		QosVolume: &pb.QosVolume{
+			VolumeNameRef: testQosVolume.VolumeNameRef,
+			Limits:        testQosVolume.Limits,
+		},
+	}
+
+	tests := map[string]struct {
+		giveLimits       *pb.Limits
+		giveClientErr    error
+		giveConnectorErr error
+		wantErr          error
+		wantRequest      *pb.CreateQosVolumeRequest
+		wantResponse     *pb.QosVolume
+		wantConnClosed   bool
+	}{
+		"successful call": {
+			giveLimits:       testQosVolume.Limits,
+			giveConnectorErr: nil,
+			giveClientErr:    nil,
+			wantErr:          nil,
+			wantRequest:      proto.Clone(testRequest).(*pb.CreateQosVolumeRequest),
+			wantResponse:     proto.Clone(testQosVolume).(*pb.QosVolume),
+			wantConnClosed:   true,
+		},
+		"client err": {
+			giveLimits:       testQosVolume.Limits,
+			giveConnectorErr: nil,
+			giveClientErr:    errors.New("Some client error"),
+			wantErr:          errors.New("Some client error"),
+			wantRequest:      proto.Clone(testRequest).(*pb.CreateQosVolumeRequest),
+			wantResponse:     nil,
+			wantConnClosed:   true,
+		},
+		"connector err": {
+			giveLimits:       testQosVolume.Limits,
+			giveConnectorErr: errors.New("Some conn error"),
+			giveClientErr:    nil,
+			wantErr:          errors.New("Some conn error"),
+			wantRequest:      nil,
+			wantResponse:     nil,
+			wantConnClosed:   false,
+		},
+		"min exceeds max": {
+			giveLimits: &pb.Limits{
+				Min: &pb.QosLimit{RdIopsKiops: 20},
+				Max: &pb.QosLimit{RdIopsKiops: 10},
+			},
+			wantErr:        errors.New("min rd_iops_kiops 20 exceeds max 10"),
+			wantRequest:    nil,
+			wantResponse:   nil,
+			wantConnClosed: false,
+		},
+		"negative limit": {
+			giveLimits: &pb.Limits{
+				Max: &pb.QosLimit{WrBandwidthMbs: -1},
+			},
+			wantErr:        errors.New("max wr_bandwidth_mbs cannot be negative: -1"),
+			wantRequest:    nil,
+			wantResponse:   nil,
+			wantConnClosed: false,
+		},
+		"no limits": {
+			giveLimits:     &pb.Limits{},
+			wantErr:        errors.New("at least one limit has to be set"),
+			wantRequest:    nil,
+			wantResponse:   nil,
+			wantConnClosed: false,
+		},
+	}
+
+	for testName, tt := range tests {
+		t.Run(testName, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
+
+			connClosed := false
+			mockClient := mocks.NewMiddleendQosVolumeServiceClient(t)
+			mockConn := mocks.NewConnector(t)
+			mockConn.EXPECT().NewConn().Return(
+				&grpc.ClientConn{},
+				func() { connClosed = true },
+				tt.giveConnectorErr,
+			).Maybe()
+			if tt.wantRequest != nil {
+				toReturn := proto.Clone(tt.wantResponse).(*pb.QosVolume)
+				mockClient.EXPECT().CreateQosVolume(ctx, tt.wantRequest).
+					Return(toReturn, tt.giveClientErr)
+			}
+
+			c, _ := NewWithArgs(
+				mockConn,
+				pb.NewMiddleendEncryptionServiceClient,
+				func(grpc.ClientConnInterface) pb.MiddleendQosVolumeServiceClient {
+					return mockClient
+				},
+			)
+
+			response, err := c.CreateQosVolume(
+				ctx,
+				"qos0",
+				testQosVolume.VolumeNameRef,
+				tt.giveLimits,
+			)
+
+			require.Equal(t, tt.wantErr, err)
+			require.True(t, proto.Equal(response, tt.wantResponse))
+			require.Equal(t, tt.wantConnClosed, connClosed)
+		})
+	}
+}
+
+func TestDeleteQosVolume(t *testing.T) {
+	testRequest := &pb.DeleteQosVolumeRequest{
+		Name:         testQosVolume.Name,
+		AllowMissing: true,
+	}
+
+	tests := map[string]struct {
+		giveClientErr    error
+		giveConnectorErr error
+		wantErr          error
+		wantRequest      *pb.DeleteQosVolumeRequest
+		wantConnClosed   bool
+	}{
+		"successful call": {
+			giveConnectorErr: nil,
+			giveClientErr:    nil,
+			wantErr:          nil,
+			wantRequest:      proto.Clone(testRequest).(*pb.DeleteQosVolumeRequest),
+			wantConnClosed:   true,
+		},
+		"client err": {
+			giveConnectorErr: nil,
+			giveClientErr:    errors.New("Some client error"),
+			wantErr:          errors.New("Some client error"),
+			wantRequest:      proto.Clone(testRequest).(*pb.DeleteQosVolumeRequest),
+			wantConnClosed:   true,
+		},
+		"connector err": {
+			giveConnectorErr: errors.New("Some conn error"),
+			giveClientErr:    nil,
+			wantErr:          errors.New("Some conn error"),
+			wantRequest:      nil,
+			wantConnClosed:   false,
+		},
+	}
+
+	for testName, tt := range tests {
+		t.Run(testName, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
+
+			mockClient := mocks.NewMiddleendQosVolumeServiceClient(t)
+			if tt.wantRequest != nil {
+				mockClient.EXPECT().DeleteQosVolume(ctx, tt.wantRequest).
+					Return(&emptypb.Empty{}, tt.giveClientErr)
+			}
+
+			connClosed := false
+			mockConn := mocks.NewConnector(t)
+			mockConn.EXPECT().NewConn().Return(
+				&grpc.ClientConn{},
+				func() { connClosed = true },
+				tt.giveConnectorErr,
+			)
+
+			c, _ := NewWithArgs(
+				mockConn,
+				pb.NewMiddleendEncryptionServiceClient,
+				func(grpc.ClientConnInterface) pb.MiddleendQosVolumeServiceClient {
+					return mockClient
+				},
+			)
+
+			err := c.DeleteQosVolume(ctx, testQosVolume.Name, true)
+
+			require.Equal(t, tt.wantErr, err)
+			require.Equal(t, tt.wantConnClosed, connClosed)
+		})
+	}
+}
+
+func TestGetQosVolume(t *testing.T) {
+	testRequest := &pb.GetQosVolumeRequest{
+		Name: testQosVolume.Name,
+	}
+
+	tests := map[string]struct {
+		giveClientErr    error
+		giveConnectorErr error
+		wantErr          error
+		wantRequest      *pb.GetQosVolumeRequest
+		wantResponse     *pb.QosVolume
+		wantConnClosed   bool
+	}{
+		"successful call": {
+			giveConnectorErr: nil,
+			giveClientErr:    nil,
+			wantErr:          nil,
+			wantRequest:      proto.Clone(testRequest).(*pb.GetQosVolumeRequest),
+			wantResponse:     proto.Clone(testQosVolume).(*pb.QosVolume),
+			wantConnClosed:   true,
+		},
+		"client err": {
+			giveConnectorErr: nil,
+			giveClientErr:    errors.New("Some client error"),
+			wantErr:          errors.New("Some client error"),
+			wantRequest:      proto.Clone(testRequest).(*pb.GetQosVolumeRequest),
+			wantResponse:     nil,
+			wantConnClosed:   true,
+		},
+		"connector err": {
+			giveConnectorErr: errors.New("Some conn error"),
+			giveClientErr:    nil,
+			wantErr:          errors.New("Some conn error"),
+			wantRequest:      nil,
+			wantResponse:     nil,
+			wantConnClosed:   false,
+		},
+	}
+
+	for testName, tt := range tests {
+		t.Run(testName, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
+
+			mockClient := mocks.NewMiddleendQosVolumeServiceClient(t)
+			if tt.wantRequest != nil {
+				toReturn := proto.Clone(tt.wantResponse).(*pb.QosVolume)
+				mockClient.EXPECT().GetQosVolume(ctx, tt.wantRequest).
+					Return(toReturn, tt.giveClientErr)
+			}
+
+			connClosed := false
+			mockConn := mocks.NewConnector(t)
+			mockConn.EXPECT().NewConn().Return(
+				&grpc.ClientConn{},
+				func() { connClosed = true },
+				tt.giveConnectorErr,
+			)
+
+			c, _ := NewWithArgs(
+				mockConn,
+				pb.NewMiddleendEncryptionServiceClient,
+				func(grpc.ClientConnInterface) pb.MiddleendQosVolumeServiceClient {
+					return mockClient
+				},
+			)
+
+			response, err := c.GetQosVolume(ctx, testQosVolume.Name)
+
+			require.Equal(t, tt.wantErr, err)
+			require.True(t, proto.Equal(response, tt.wantResponse))
+			require.Equal(t, tt.wantConnClosed, connClosed)
+		})
+	}
+}
+
+func TestListQosVolumes(t *testing.T) {
+	testRequest := &pb.ListQosVolumesRequest{
+		Parent:    "volumes",
+		PageSize:  10,
+		PageToken: "token",
+	}
+	testResponse := &pb.ListQosVolumesResponse{
+		QosVolumes:    []*pb.QosVolume{testQosVolume},
+		NextPageToken: "next",
+	}
+
+	tests := map[string]struct {
+		giveClientErr    error
+		giveConnectorErr error
+		wantErr          error
+		wantRequest      *pb.ListQosVolumesRequest
+		wantResponse     *pb.ListQosVolumesResponse
+		wantConnClosed   bool
+	}{
+		"successful call": {
+			giveConnectorErr: nil,
+			giveClientErr:    nil,
+			wantErr:          nil,
+			wantRequest:      proto.Clone(testRequest).(*pb.ListQosVolumesRequest),
+			wantResponse:     proto.Clone(testResponse).(*pb.ListQosVolumesResponse),
+			wantConnClosed:   true,
+		},
+		"client err": {
+			giveConnectorErr: nil,
+			giveClientErr:    errors.New("Some client error"),
+			wantErr:          errors.New("Some client error"),
+			wantRequest:      proto.Clone(testRequest).(*pb.ListQosVolumesRequest),
+			wantResponse:     nil,
+			wantConnClosed:   true,
+		},
+		"connector err": {
+			giveConnectorErr: errors.New("Some conn error"),
+			giveClientErr:    nil,
+			wantErr:          errors.New("Some conn error"),
+			wantRequest:      nil,
+			wantResponse:     nil,
+			wantConnClosed:   false,
+		},
+	}
+
+	for testName, tt := range tests {
+		t.Run(testName, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
+
+			mockClient := mocks.NewMiddleendQosVolumeServiceClient(t)
+			if tt.wantRequest != nil {
+				toReturn := proto.Clone(tt.wantResponse).(*pb.ListQosVolumesResponse)
+				mockClient.EXPECT().ListQosVolumes(ctx, tt.wantRequest).
+					Return(toReturn, tt.giveClientErr)
+			}
+
+			connClosed := false
+			mockConn := mocks.NewConnector(t)
+			mockConn.EXPECT().NewConn().Return(
+				&grpc.ClientConn{},
+				func() { connClosed = true },
+				tt.giveConnectorErr,
+			)
+
+			c, _ := NewWithArgs(
+				mockConn,
+				pb.NewMiddleendEncryptionServiceClient,
+				func(grpc.ClientConnInterface) pb.MiddleendQosVolumeServiceClient {
+					return mockClient
+				},
+			)
+
+			response, err := c.ListQosVolumes(ctx, "volumes", 10, "token")
+
+			require.Equal(t, tt.wantErr, err)
+			require.True(t, proto.Equal(response, tt.wantResponse))
+			require.Equal(t, tt.wantConnClosed, connClosed)
+		})
+	}
+}
+
+func TestUpdateQosVolume(t *testing.T) {
+	testMask := []string{"limits.max.rd_iops_kiops"}
+	testRequest := &pb.UpdateQosVolumeRequest{
+		QosVolume:    proto.Clone(testQosVolume).(*pb.QosVolume),
+		UpdateMask:   &fieldmaskpb.FieldMask{Paths: testMask},
+		AllowMissing: false,
+	}
+	testClearedVolume := &pb.QosVolume{
+		Name:   testQosVolume.Name,
+		Limits: &pb.Limits{Max: &pb.QosLimit{}},
+	}
+
+	tests := map[string]struct {
+		giveVolume       *pb.QosVolume
+		giveMask         []string
+		giveClientErr    error
+		giveConnectorErr error
+		wantErr          error
+		wantRequest      *pb.UpdateQosVolumeRequest
+		wantResponse     *pb.QosVolume
+		wantConnClosed   bool
+	}{
+		"successful call": {
+			giveVolume:       testQosVolume,
+			giveMask:         testMask,
+			giveConnectorErr: nil,
+			giveClientErr:    nil,
+			wantErr:          nil,
+			wantRequest:      proto.Clone(testRequest).(*pb.UpdateQosVolumeRequest),
+			wantResponse:     proto.Clone(testQosVolume).(*pb.QosVolume),
+			wantConnClosed:   true,
+		},
+		"client err": {
+			giveVolume:       testQosVolume,
+			giveMask:         testMask,
+			giveConnectorErr: nil,
+			giveClientErr:    errors.New("Some client error"),
+			wantErr:          errors.New("Some client error"),
+			wantRequest:      proto.Clone(testRequest).(*pb.UpdateQosVolumeRequest),
+			wantResponse:     nil,
+			wantConnClosed:   true,
+		},
+		"connector err": {
+			giveVolume:       testQosVolume,
+			giveMask:         testMask,
+			giveConnectorErr: errors.New("Some conn error"),
+			giveClientErr:    nil,
+			wantErr:          errors.New("Some conn error"),
+			wantRequest:      nil,
+			wantResponse:     nil,
+			wantConnClosed:   false,
+		},
+		"clear masked limit": {
+			giveVolume:       testClearedVolume,
+			giveMask:         testMask,
+			giveConnectorErr: nil,
+			giveClientErr:    nil,
+			wantErr:          nil,
+			wantRequest: &pb.UpdateQosVolumeRequest{
+				QosVolume:  proto.Clone(testClearedVolume).(*pb.QosVolume),
+				UpdateMask: &fieldmaskpb.FieldMask{Paths: testMask},
+			},
+			wantResponse:   proto.Clone(testClearedVolume).(*pb.QosVolume),
+			wantConnClosed: true,
+		},
+		"ignore limits not in mask": {
+			giveVolume: &pb.QosVolume{
+				Name: testQosVolume.Name,
+				Limits: &pb.Limits{
+					Min: &pb.QosLimit{RdIopsKiops: 20, WrBandwidthMbs: -1},
+					Max: &pb.QosLimit{RdIopsKiops: 10},
+				},
+			},
+			giveMask:         testMask,
+			giveConnectorErr: nil,
+			giveClientErr:    nil,
+			wantErr:          nil,
+			wantRequest: &pb.UpdateQosVolumeRequest{
+				QosVolume: &pb.QosVolume{
+					Name: testQosVolume.Name,
+					Limits: &pb.Limits{
+						Min: &pb.QosLimit{RdIopsKiops: 20, WrBandwidthMbs: -1},
+						Max: &pb.QosLimit{RdIopsKiops: 10},
+					},
+				},
+				UpdateMask: &fieldmaskpb.FieldMask{Paths: testMask},
+			},
+			wantResponse:   proto.Clone(testQosVolume).(*pb.QosVolume),
+			wantConnClosed: true,
+		},
+		"negative masked limit": {
+			giveVolume: &pb.QosVolume{
+				Name:   testQosVolume.Name,
+				Limits: &pb.Limits{Max: &pb.QosLimit{RdIopsKiops: -1}},
+			},
+			giveMask:       testMask,
+			wantErr:        errors.New("max rd_iops_kiops cannot be negative: -1"),
+			wantRequest:    nil,
+			wantResponse:   nil,
+			wantConnClosed: false,
+		},
+		"masked min exceeds max": {
+			giveVolume: &pb.QosVolume{
+				Name: testQosVolume.Name,
+				Limits: &pb.Limits{
+					Min: &pb.QosLimit{RdIopsKiops: 20},
+					Max: &pb.QosLimit{RdIopsKiops: 10},
+				},
+			},
+			giveMask:       []string{"limits.min", "limits.max.rd_iops_kiops"},
+			wantErr:        errors.New("min rd_iops_kiops 20 exceeds max 10"),
+			wantRequest:    nil,
+			wantResponse:   nil,
+			wantConnClosed: false,
+		},
+		"clear all limits": {
+			giveVolume: &pb.QosVolume{
+				Name:   testQosVolume.Name,
+				Limits: &pb.Limits{},
+			},
+			giveMask:       []string{"limits"},
+			wantErr:        errors.New("at least one limit has to be set"),
+			wantRequest:    nil,
+			wantResponse:   nil,
+			wantConnClosed: false,
+		},
+	}
+
+	for testName, tt := range tests {
+		t.Run(testName, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
+
+			mockClient := mocks.NewMiddleendQosVolumeServiceClient(t)
+			if tt.wantRequest != nil {
+				toReturn := proto.Clone(tt.wantResponse).(*pb.QosVolume)
+				mockClient.EXPECT().UpdateQosVolume(ctx, tt.wantRequest).
+					Return(toReturn, tt.giveClientErr)
+			}
+
+			connClosed := false
+			mockConn := mocks.NewConnector(t)
+			mockConn.EXPECT().NewConn().Return(
+				&grpc.ClientConn{},
+				func() { connClosed = true },
+				tt.giveConnectorErr,
+			).Maybe()
+
+			c, _ := NewWithArgs(
+				mockConn,
+				pb.NewMiddleendEncryptionServiceClient,
+				func(grpc.ClientConnInterface) pb.MiddleendQosVolumeServiceClient {
+					return mockClient
+				},
+			)
+
+			response, err := c.UpdateQosVolume(
+				ctx,
+				proto.Clone(tt.giveVolume).(*pb.QosVolume),
+				tt.giveMask,
+				false,
+			)
+
+			require.Equal(t, tt.wantErr, err)
+			require.True(t, proto.Equal(response, tt.wantResponse))
+			require.Equal(t, tt.wantConnClosed, connClosed)
+		})
+	}
+}
+
+func TestStatsQosVolume(t *testing.T) {
+	testRequest := &pb.StatsQosVolumeRequest{
+		Name: testQosVolume.Name,
+	}
+	testStats := &pb.VolumeStats{
+		ReadBytesCount: 4096,
+		ReadOpsCount:   8,
+	}
+
+	tests := map[string]struct {
+		giveClientErr    error
+		giveConnectorErr error
+		wantErr          error
+		wantRequest      *pb.StatsQosVolumeRequest
+		wantResponse     *pb.VolumeStats
+		wantConnClosed   bool
+	}{
+		"successful call": {
+			giveConnectorErr: nil,
+			giveClientErr:    nil,
+			wantErr:          nil,
+			wantRequest:      proto.Clone(testRequest).(*pb.StatsQosVolumeRequest),
+			wantResponse:     proto.Clone(testStats).(*pb.VolumeStats),
+			wantConnClosed:   true,
+		},
+		"client err": {
+			giveConnectorErr: nil,
+			giveClientErr:    errors.New("Some client error"),
+			wantErr:          errors.New("Some client error"),
+			wantRequest:      proto.Clone(testRequest).(*pb.StatsQosVolumeRequest),
+			wantResponse:     nil,
+			wantConnClosed:   true,
+		},
+		"connector err": {
+			giveConnectorErr: errors.New("Some conn error"),
+			giveClientErr:    nil,
+			wantErr:          errors.New("Some conn error"),
+			wantRequest:      nil,
+			wantResponse:     nil,
+			wantConnClosed:   false,
+		},
+	}
+
+	for testName, tt := range tests {
+		t.Run(testName, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
+
+			mockClient := mocks.NewMiddleendQosVolumeServiceClient(t)
+			if tt.wantRequest != nil {
+				var toReturn *pb.StatsQosVolumeResponse
+				if tt.giveClientErr == nil {
+					toReturn = &pb.StatsQosVolumeResponse{Stats: proto.Clone(tt.wantResponse).(*pb.VolumeStats)}
+				}
+				mockClient.EXPECT().StatsQosVolume(ctx, tt.wantRequest).
+					Return(toReturn, tt.giveClientErr)
+			}
+
+			connClosed := false
+			mockConn := mocks.NewConnector(t)
+			mockConn.EXPECT().NewConn().Return(
+				&grpc.ClientConn{},
+				func() { connClosed = true },
+				tt.giveConnectorErr,
+			)
+
+			c, _ := NewWithArgs(
+				mockConn,
+				pb.NewMiddleendEncryptionServiceClient,
+				func(grpc.ClientConnInterface) pb.MiddleendQosVolumeServiceClient {
+					return mockClient
+				},
+			)
+
+			response, err := c.StatsQosVolume(ctx, testQosVolume.Name)
+
+			require.Equal(t, tt.wantErr, err)
+			require.True(t, proto.Equal(response, tt.wantResponse))
+			require.Equal(t, tt.wantConnClosed, connClosed)
+		})
+	}
+}
@@ -0,0 +1,25 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (C) 2024 Intel Corporation
+
+// Package nvme implements nvme addressing and identifier helpers for OPI storage clients
+package nvme
+
+import (
+	"fmt"
+	"net"
+
+	pb "github.com/opiproject/opi-api/storage/v1alpha1/gen/go"
+)
+
+// AddressFamily returns the nvme address family corresponding to the ip
+func AddressFamily(ip net.IP) (pb.NvmeAddressFamily, error) {
+	switch {
+	case ip.To4() != nil:
+		return pb.NvmeAddressFamily_NVME_ADDRESS_FAMILY_IPV4, nil
+	case ip.To16() != nil:
+		return pb.NvmeAddressFamily_NVME_ADDRESS_FAMILY_IPV6, nil
+	default:
+		return pb.NvmeAddressFamily_NVME_ADDRESS_FAMILY_UNSPECIFIED,
+			fmt.Errorf("invalid ip address format: %v", ip)
+	}
+}
@@ -0,0 +1,52 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (C) 2024 Intel Corporation
+
+// Package nvme implements nvme addressing and identifier helpers for OPI storage clients
+package nvme
+
+import (
+	"errors"
+	"net"
+	"testing"
+
+	pb "github.com/opiproject/opi-api/storage/v1alpha1/gen/go"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAddressFamily(t *testing.T) {
+	tests := map[string]struct {
+		giveIP     net.IP
+		wantAdrfam pb.NvmeAddressFamily
+		wantErr    error
+	}{
+		"ipv4 address": {
+			giveIP:     net.ParseIP("127.0.0.1"),
+			wantAdrfam: pb.NvmeAddressFamily_NVME_ADDRESS_FAMILY_IPV4,
+			wantErr:    nil,
+		},
+		"ipv4-mapped ipv6 address": {
+			giveIP:     net.ParseIP("::ffff:10.0.0.1"),
+			wantAdrfam: pb.NvmeAddressFamily_NVME_ADDRESS_FAMILY_IPV4,
+			wantErr:    nil,
+		},
+		"ipv6 address": {
+			giveIP:     net.ParseIP("fe80::1"),
+			wantAdrfam: pb.NvmeAddressFamily_NVME_ADDRESS_FAMILY_IPV6,
+			wantErr:    nil,
+		},
+		"invalid address": {
+			giveIP:     net.IP{},
+			wantAdrfam: pb.NvmeAddressFamily_NVME_ADDRESS_FAMILY_UNSPECIFIED,
+			wantErr:    errors.New("invalid ip address format: <nil>"),
+		},
+	}
+
+	for testName, tt := range tests {
+		t.Run(testName, func(t *testing.T) {
+			adrfam, err := AddressFamily(tt.giveIP)
+
+			require.Equal(t, tt.wantErr, err)
+			require.Equal(t, tt.wantAdrfam, adrfam)
+		})
+	}
+}
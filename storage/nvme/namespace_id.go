@@ -0,0 +1,164 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (C) 2024 Intel Corporation
+
+// Package nvme implements nvme addressing and identifier helpers for OPI storage clients
+package nvme
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// volumeIDNamespace is the name space used to derive namespace uuids from volume ids
+var volumeIDNamespace = uuid.MustParse("6f1c9a5e-0c4b-4f4e-9a59-5e6b1e0f2d7a")
+
+// NGUID is a namespace globally unique identifier
+type NGUID [16]byte
+
+// EUI64 is an IEEE extended unique identifier of a namespace
+type EUI64 [8]byte
+
+// NamespaceIdentifiers are the identifiers reported to hosts for a namespace.
+// Zero values are left for the server to assign.
+type NamespaceIdentifiers struct {
+	NGUID NGUID
+	EUI64 EUI64
+	UUID  uuid.UUID
+}
+
+// IdentifiersFromVolumeID derives deterministic namespace identifiers from
+// a volume id, so the same volume is always exposed with the same identity
+func IdentifiersFromVolumeID(volumeID string) (NamespaceIdentifiers, error) {
+	nguid, err := NGUIDFromVolumeID(volumeID)
+	if err != nil {
+		return NamespaceIdentifiers{}, err
+	}
+	eui64, err := EUI64FromVolumeID(volumeID)
+	if err != nil {
+		return NamespaceIdentifiers{}, err
+	}
+	id, err := UUIDFromVolumeID(volumeID)
+	if err != nil {
+		return NamespaceIdentifiers{}, err
+	}
+
+	return NamespaceIdentifiers{NGUID: nguid, EUI64: eui64, UUID: id}, nil
+}
+
+// ParseNGUID parses an NGUID given as 32 hex digits, optionally separated by
+// dashes in uuid layout
+func ParseNGUID(s string) (NGUID, error) {
+	var nguid NGUID
+	if err := decodeIdentifier(strings.ReplaceAll(s, "-", ""), nguid[:]); err != nil {
+		return NGUID{}, fmt.Errorf("invalid nguid %q: %w", s, err)
+	}
+	return nguid, nil
+}
+
+// NGUIDFromVolumeID derives a deterministic NGUID from a volume id
+func NGUIDFromVolumeID(volumeID string) (NGUID, error) {
+	sum, err := volumeIDSum(volumeID)
+	if err != nil {
+		return NGUID{}, err
+	}
+
+	var nguid NGUID
+	copy(nguid[:], sum[:len(nguid)])
+	return nguid, nil
+}
+
+// String returns the NGUID as 32 lowercase hex digits
+func (n NGUID) String() string {
+	return hex.EncodeToString(n[:])
+}
+
+// IsZero returns true if no identifier is set
+func (n NGUID) IsZero() bool {
+	return n == NGUID{}
+}
+
+// ParseEUI64 parses an EUI-64 given as 16 hex digits, optionally separated
+// by dashes or colons
+func ParseEUI64(s string) (EUI64, error) {
+	var eui64 EUI64
+	digits := strings.NewReplacer("-", "", ":", "").Replace(s)
+	if err := decodeIdentifier(digits, eui64[:]); err != nil {
+		return EUI64{}, fmt.Errorf("invalid eui64 %q: %w", s, err)
+	}
+	return eui64, nil
+}
+
+// EUI64FromVolumeID derives a deterministic EUI-64 from a volume id
+func EUI64FromVolumeID(volumeID string) (EUI64, error) {
+	sum, err := volumeIDSum(volumeID)
+	if err != nil {
+		return EUI64{}, err
+	}
+
+	var eui64 EUI64
+	copy(eui64[:], sum[len(NGUID{}):])
+	return eui64, nil
+}
+
+// Int64 returns the EUI-64 in the representation used by OPI
+func (e EUI64) Int64() int64 {
+	return int64(binary.BigEndian.Uint64(e[:]))
+}
+
+// String returns the EUI-64 as 16 lowercase hex digits
+func (e EUI64) String() string {
+	return hex.EncodeToString(e[:])
+}
+
+// IsZero returns true if no identifier is set
+func (e EUI64) IsZero() bool {
+	return e == EUI64{}
+}
+
+// ParseUUID parses a namespace uuid in canonical textual form
+func ParseUUID(s string) (uuid.UUID, error) {
+	id, err := uuid.Parse(s)
+	if err != nil || len(s) != 36 {
+		return uuid.Nil, fmt.Errorf("invalid uuid %q", s)
+	}
+	if id == uuid.Nil {
+		return uuid.Nil, fmt.Errorf("invalid uuid %q: all zeros", s)
+	}
+	return id, nil
+}
+
+// UUIDFromVolumeID derives a deterministic namespace uuid from a volume id
+func UUIDFromVolumeID(volumeID string) (uuid.UUID, error) {
+	if volumeID == "" {
+		return uuid.Nil, errors.New("empty volume id is not allowed")
+	}
+	return uuid.NewSHA1(volumeIDNamespace, []byte(volumeID)), nil
+}
+
+func volumeIDSum(volumeID string) ([sha256.Size]byte, error) {
+	if volumeID == "" {
+		return [sha256.Size]byte{}, errors.New("empty volume id is not allowed")
+	}
+	return sha256.Sum256([]byte(volumeID)), nil
+}
+
+func decodeIdentifier(digits string, out []byte) error {
+	if len(digits) != 2*len(out) {
+		return fmt.Errorf("expected %d hex digits", 2*len(out))
+	}
+	if _, err := hex.Decode(out, []byte(digits)); err != nil {
+		return errors.New("not a hex string")
+	}
+	for _, b := range out {
+		if b != 0 {
+			return nil
+		}
+	}
+	return errors.New("all zeros")
+}
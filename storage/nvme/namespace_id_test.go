@@ -0,0 +1,136 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (C) 2024 Intel Corporation
+
+// Package nvme implements nvme addressing and identifier helpers for OPI storage clients
+package nvme
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseNGUID(t *testing.T) {
+	tests := map[string]struct {
+		giveNguid string
+		wantNguid string
+		wantErr   bool
+	}{
+		"hex digits": {
+			giveNguid: "0123456789ABCDEF0123456789abcdef",
+			wantNguid: "0123456789abcdef0123456789abcdef",
+			wantErr:   false,
+		},
+		"uuid layout": {
+			giveNguid: "feb98abe-d51f-40c8-b348-2753f3571d3c",
+			wantNguid: "feb98abed51f40c8b3482753f3571d3c",
+			wantErr:   false,
+		},
+		"too short": {
+			giveNguid: "0123456789abcdef",
+			wantErr:   true,
+		},
+		"not hex": {
+			giveNguid: "nguid",
+			wantErr:   true,
+		},
+		"all zeros": {
+			giveNguid: "00000000000000000000000000000000",
+			wantErr:   true,
+		},
+	}
+
+	for testName, tt := range tests {
+		t.Run(testName, func(t *testing.T) {
+			nguid, err := ParseNGUID(tt.giveNguid)
+
+			require.Equal(t, tt.wantErr, err != nil)
+			if !tt.wantErr {
+				require.Equal(t, tt.wantNguid, nguid.String())
+			}
+		})
+	}
+}
+
+func TestParseEUI64(t *testing.T) {
+	tests := map[string]struct {
+		giveEui64 string
+		wantInt64 int64
+		wantErr   bool
+	}{
+		"hex digits": {
+			giveEui64: "0000000000000102",
+			wantInt64: 0x102,
+			wantErr:   false,
+		},
+		"colon separated": {
+			giveEui64: "00:00:00:00:00:00:01:02",
+			wantInt64: 0x102,
+			wantErr:   false,
+		},
+		"too long": {
+			giveEui64: "000000000000000102",
+			wantErr:   true,
+		},
+		"all zeros": {
+			giveEui64: "0000000000000000",
+			wantErr:   true,
+		},
+	}
+
+	for testName, tt := range tests {
+		t.Run(testName, func(t *testing.T) {
+			eui64, err := ParseEUI64(tt.giveEui64)
+
+			require.Equal(t, tt.wantErr, err != nil)
+			if !tt.wantErr {
+				require.Equal(t, tt.wantInt64, eui64.Int64())
+			}
+		})
+	}
+}
+
+func TestParseUUID(t *testing.T) {
+	_, err := ParseUUID("feb98abe-d51f-40c8-b348-2753f3571d3c")
+	require.NoError(t, err)
+
+	_, err = ParseUUID("feb98abed51f40c8b3482753f3571d3c")
+	require.Error(t, err)
+
+	_, err = ParseUUID(uuid.Nil.String())
+	require.Error(t, err)
+}
+
+func TestIdentifiersFromVolumeID(t *testing.T) {
+	nguid, err := NGUIDFromVolumeID("vol0")
+	require.NoError(t, err)
+	sameNguid, _ := NGUIDFromVolumeID("vol0")
+	otherNguid, _ := NGUIDFromVolumeID("vol1")
+	require.Equal(t, nguid, sameNguid)
+	require.NotEqual(t, nguid, otherNguid)
+
+	eui64, err := EUI64FromVolumeID("vol0")
+	require.NoError(t, err)
+	sameEui64, _ := EUI64FromVolumeID("vol0")
+	require.Equal(t, eui64, sameEui64)
+	require.NotZero(t, eui64.Int64())
+
+	id, err := UUIDFromVolumeID("vol0")
+	require.NoError(t, err)
+	sameID, _ := UUIDFromVolumeID("vol0")
+	require.Equal(t, id, sameID)
+
+	ids, err := IdentifiersFromVolumeID("vol0")
+	require.NoError(t, err)
+	require.Equal(t, NamespaceIdentifiers{NGUID: nguid, EUI64: eui64, UUID: id}, ids)
+
+	_, err = NGUIDFromVolumeID("")
+	require.Error(t, err)
+	_, err = EUI64FromVolumeID("")
+	require.Error(t, err)
+	_, err = UUIDFromVolumeID("")
+	require.Error(t, err)
+	_, err = IdentifiersFromVolumeID("")
+	require.Error(t, err)
+}
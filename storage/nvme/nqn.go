@@ -0,0 +1,98 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (C) 2024 Intel Corporation
+
+// Package nvme implements nvme addressing and identifier helpers for OPI storage clients
+package nvme
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// MaxNQNLength is the maximum length of an nvme qualified name in bytes
+const MaxNQNLength = 223
+
+// UUIDNQNPrefix is the prefix of nvme qualified names in uuid-based format
+const UUIDNQNPrefix = "nqn.2014-08.org.nvmexpress:uuid:"
+
+var nqnRegexp = regexp.MustCompile(
+	`^nqn\.([0-9]{4}-(?:0[1-9]|1[0-2]))\.` +
+		`([a-zA-Z0-9](?:[a-zA-Z0-9-]*[a-zA-Z0-9])?(?:\.[a-zA-Z0-9](?:[a-zA-Z0-9-]*[a-zA-Z0-9])?)*)` +
+		`(?::(.+))?$`,
+)
+
+// NQN is a parsed nvme qualified name
+type NQN struct {
+	// Date is the yyyy-mm part of the name
+	Date string
+	// Domain is the reverse domain name of the naming authority
+	Domain string
+	// Identifier is the optional string following the domain
+	Identifier string
+	// UUID is set for names in uuid-based format
+	UUID uuid.UUID
+}
+
+// ParseNQN parses a well-formed nvme qualified name either in
+// nqn.yyyy-mm.reverse-domain[:identifier] or in uuid-based format
+func ParseNQN(nqn string) (NQN, error) {
+	if nqn == "" {
+		return NQN{}, errors.New("empty nqn is not allowed")
+	}
+	if len(nqn) > MaxNQNLength {
+		return NQN{}, fmt.Errorf("nqn %q exceeds %d bytes", nqn, MaxNQNLength)
+	}
+
+	if strings.HasPrefix(nqn, UUIDNQNPrefix) {
+		id := strings.TrimPrefix(nqn, UUIDNQNPrefix)
+		parsed, err := uuid.Parse(id)
+		if err != nil || len(id) != 36 {
+			return NQN{}, fmt.Errorf("invalid uuid in nqn %q", nqn)
+		}
+		return NewUUIDNQN(parsed), nil
+	}
+
+	match := nqnRegexp.FindStringSubmatch(nqn)
+	if match == nil {
+		return NQN{}, fmt.Errorf("invalid nqn format %q, expected nqn.yyyy-mm.reverse-domain[:identifier]", nqn)
+	}
+
+	return NQN{
+		Date:       match[1],
+		Domain:     match[2],
+		Identifier: match[3],
+	}, nil
+}
+
+// ValidateNQN checks that nqn is a well-formed nvme qualified name
+func ValidateNQN(nqn string) error {
+	_, err := ParseNQN(nqn)
+	return err
+}
+
+// NewUUIDNQN creates an nvme qualified name in uuid-based format
+func NewUUIDNQN(id uuid.UUID) NQN {
+	return NQN{
+		Date:       "2014-08",
+		Domain:     "org.nvmexpress",
+		Identifier: "uuid:" + id.String(),
+		UUID:       id,
+	}
+}
+
+// IsUUIDBased returns true if the name is in uuid-based format
+func (n NQN) IsUUIDBased() bool {
+	return n.UUID != uuid.Nil
+}
+
+// String returns the textual representation of the name
+func (n NQN) String() string {
+	if n.Identifier == "" {
+		return fmt.Sprintf("nqn.%s.%s", n.Date, n.Domain)
+	}
+	return fmt.Sprintf("nqn.%s.%s:%s", n.Date, n.Domain, n.Identifier)
+}
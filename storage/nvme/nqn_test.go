@@ -0,0 +1,122 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (C) 2024 Intel Corporation
+
+// Package nvme implements nvme addressing and identifier helpers for OPI storage clients
+package nvme
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateNQN(t *testing.T) {
+	tests := map[string]struct {
+		giveNqn string
+		wantErr bool
+	}{
+		"domain-based nqn": {
+			giveNqn: "nqn.2016-06.io.spdk:cnode1",
+			wantErr: false,
+		},
+		"domain-based nqn without identifier": {
+			giveNqn: "nqn.2014-08.org.nvmexpress.discovery",
+			wantErr: false,
+		},
+		"uuid-based nqn": {
+			giveNqn: "nqn.2014-08.org.nvmexpress:uuid:feb98abe-d51f-40c8-b348-2753f3571d3c",
+			wantErr: false,
+		},
+		"empty nqn": {
+			giveNqn: "",
+			wantErr: true,
+		},
+		"missing prefix": {
+			giveNqn: "iqn.2016-06.io.spdk:cnode1",
+			wantErr: true,
+		},
+		"invalid month": {
+			giveNqn: "nqn.2016-13.io.spdk:cnode1",
+			wantErr: true,
+		},
+		"missing domain": {
+			giveNqn: "nqn.2016-06.:cnode1",
+			wantErr: true,
+		},
+		"invalid uuid": {
+			giveNqn: "nqn.2014-08.org.nvmexpress:uuid:not-a-uuid",
+			wantErr: true,
+		},
+		"too long": {
+			giveNqn: "nqn.2016-06.io.spdk:" + strings.Repeat("a", MaxNQNLength),
+			wantErr: true,
+		},
+	}
+
+	for testName, tt := range tests {
+		t.Run(testName, func(t *testing.T) {
+			err := ValidateNQN(tt.giveNqn)
+
+			require.Equal(t, tt.wantErr, err != nil)
+		})
+	}
+}
+
+func TestParseNQN(t *testing.T) {
+	testUUID := uuid.MustParse("feb98abe-d51f-40c8-b348-2753f3571d3c")
+
+	tests := map[string]struct {
+		giveNqn string
+		wantNqn NQN
+		wantErr error
+	}{
+		"domain-based nqn": {
+			giveNqn: "nqn.2016-06.io.spdk:cnode1",
+			wantNqn: NQN{Date: "2016-06", Domain: "io.spdk", Identifier: "cnode1"},
+			wantErr: nil,
+		},
+		"uuid-based nqn": {
+			giveNqn: "nqn.2014-08.org.nvmexpress:uuid:feb98abe-d51f-40c8-b348-2753f3571d3c",
+			wantNqn: NQN{
+				Date:       "2014-08",
+				Domain:     "org.nvmexpress",
+				Identifier: "uuid:feb98abe-d51f-40c8-b348-2753f3571d3c",
+				UUID:       testUUID,
+			},
+			wantErr: nil,
+		},
+		"empty nqn": {
+			giveNqn: "",
+			wantNqn: NQN{},
+			wantErr: errors.New("empty nqn is not allowed"),
+		},
+		"invalid nqn": {
+			giveNqn: "nqn",
+			wantNqn: NQN{},
+			wantErr: errors.New(`invalid nqn format "nqn", expected nqn.yyyy-mm.reverse-domain[:identifier]`),
+		},
+	}
+
+	for testName, tt := range tests {
+		t.Run(testName, func(t *testing.T) {
+			nqn, err := ParseNQN(tt.giveNqn)
+
+			require.Equal(t, tt.wantErr, err)
+			require.Equal(t, tt.wantNqn, nqn)
+			if err == nil {
+				require.Equal(t, tt.giveNqn, nqn.String())
+				require.Equal(t, tt.wantNqn.UUID != uuid.Nil, nqn.IsUUIDBased())
+			}
+		})
+	}
+}
+
+func TestNewUUIDNQN(t *testing.T) {
+	nqn := NewUUIDNQN(uuid.New())
+
+	require.True(t, nqn.IsUUIDBased())
+	require.NoError(t, ValidateNQN(nqn.String()))
+}
@@ -0,0 +1,51 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (C) 2024 Intel Corporation
+
+// Package nvme implements nvme addressing and identifier helpers for OPI storage clients
+package nvme
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+)
+
+// ReadPskFile reads a TLS pre-shared key stored in the file at the given path.
+// Like ssh private keys, the file must not be accessible by group or others.
+func ReadPskFile(path string) ([]byte, error) {
+	psk, err := ReadSecretFile(path, "psk")
+	if err != nil {
+		return nil, err
+	}
+
+	trimmed := bytes.TrimSpace(psk)
+	if len(trimmed) == 0 {
+		return nil, errors.New("psk file is empty")
+	}
+
+	return trimmed, nil
+}
+
+// ReadSecretFile reads a secret of the given kind, e.g. a key, stored in the
+// regular file at the given path. The file must not be accessible by group
+// or others. The content is returned as is.
+func ReadSecretFile(path string, kind string) ([]byte, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %v file: %w", kind, err)
+	}
+	if !info.Mode().IsRegular() {
+		return nil, fmt.Errorf("%v file %v is not a regular file", kind, path)
+	}
+	if info.Mode().Perm()&0o077 != 0 {
+		return nil, fmt.Errorf("%v file %v must not be accessible by group or others, permissions are %v", kind, path, info.Mode().Perm())
+	}
+
+	secret, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %v file: %w", kind, err)
+	}
+
+	return secret, nil
+}
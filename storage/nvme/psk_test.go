@@ -0,0 +1,69 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (C) 2024 Intel Corporation
+
+// Package nvme implements nvme addressing and identifier helpers for OPI storage clients
+package nvme
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadPskFile(t *testing.T) {
+	tests := map[string]struct {
+		giveContent string
+		givePerm    os.FileMode
+		want        []byte
+		wantErr     string
+	}{
+		"trims whitespace": {
+			giveContent: "NVMeTLSkey-1:01:key:\n",
+			givePerm:    0o600,
+			want:        []byte("NVMeTLSkey-1:01:key:"),
+		},
+		"accessible by others": {
+			giveContent: "NVMeTLSkey-1:01:key:",
+			givePerm:    0o644,
+			wantErr:     "must not be accessible by group or others",
+		},
+		"empty": {
+			giveContent: " \n",
+			givePerm:    0o600,
+			wantErr:     "psk file is empty",
+		},
+	}
+
+	for testName, tt := range tests {
+		t.Run(testName, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "psk")
+			require.NoError(t, os.WriteFile(path, []byte(tt.giveContent), tt.givePerm))
+			require.NoError(t, os.Chmod(path, tt.givePerm))
+
+			psk, err := ReadPskFile(path)
+			if tt.wantErr != "" {
+				require.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, psk)
+		})
+	}
+
+	_, err := ReadPskFile(filepath.Join(t.TempDir(), "missing"))
+	require.ErrorIs(t, err, os.ErrNotExist)
+}
+
+func TestReadSecretFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "key")
+	require.NoError(t, os.WriteFile(path, []byte("\x00key\n"), 0o600))
+
+	key, err := ReadSecretFile(path, "key")
+	require.NoError(t, err)
+	require.Equal(t, []byte("\x00key\n"), key)
+
+	_, err = ReadSecretFile(t.TempDir(), "key")
+	require.ErrorContains(t, err, "is not a regular file")
+}
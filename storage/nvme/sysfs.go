@@ -0,0 +1,249 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (C) 2024 Intel Corporation
+
+// Package nvme implements nvme addressing and identifier helpers for OPI storage clients
+package nvme
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DefaultSysfsRoot is the mount point of sysfs on linux hosts
+const DefaultSysfsRoot = "/sys"
+
+// ControllerStateLive is the state of a controller ready for io
+const ControllerStateLive = "live"
+
+// devicePollInterval is the interval sysfs is scanned while waiting for a device
+const devicePollInterval = 100 * time.Millisecond
+
+// ErrDeviceNotFound is returned if no nvme device matches an identifier
+var ErrDeviceNotFound = errors.New("nvme device not found")
+
+// namespaceEntryRegexp matches namespaces in a controller directory. With
+// native multipath the entries are hidden paths named
+// nvme<subsystem>c<controller>n<nsid> of the nvme<subsystem>n<nsid> device.
+var namespaceEntryRegexp = regexp.MustCompile(`^nvme([0-9]+)(?:c[0-9]+)?n([0-9]+)$`)
+
+// Controller is an nvme controller of the host
+type Controller struct {
+	// Name is the kernel name of the controller, e.g. nvme0
+	Name      string
+	Subsysnqn string
+	// Transport is one of pcie, tcp, rdma, fc or loop
+	Transport string
+	// State is live if the controller is ready for io
+	State string
+	// Address is the transport address, e.g. traddr=10.0.0.1,trsvcid=4420
+	Address string
+	Serial  string
+}
+
+// Device is an nvme namespace block device of the host
+type Device struct {
+	// Path of the block device, e.g. /dev/nvme0n1
+	Path  string
+	NGUID NGUID
+	UUID  uuid.UUID
+	// Controllers the namespace is reachable through, more than one with
+	// native multipath
+	Controllers []Controller
+}
+
+// Live returns true if the device is reachable through a live controller
+func (d *Device) Live() bool {
+	for _, controller := range d.Controllers {
+		if controller.State == ControllerStateLive {
+			return true
+		}
+	}
+	return false
+}
+
+// Resolver finds the block devices of nvme namespaces by scanning sysfs
+type Resolver struct {
+	root string
+}
+
+// NewResolver creates a resolver scanning the sysfs mounted at root,
+// DefaultSysfsRoot if empty
+func NewResolver(root string) *Resolver {
+	if root == "" {
+		root = DefaultSysfsRoot
+	}
+	return &Resolver{root: root}
+}
+
+// ListDevices returns all nvme namespace block devices of the host
+func (r *Resolver) ListDevices() ([]Device, error) {
+	controllerDirs, err := filepath.Glob(filepath.Join(r.root, "class", "nvme", "nvme*"))
+	if err != nil {
+		return nil, err
+	}
+
+	devices := map[string]*Device{}
+	for _, dir := range controllerDirs {
+		entries, err := os.ReadDir(dir)
+		if errors.Is(err, os.ErrNotExist) {
+			// controller was removed while scanning
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		controller := r.readController(dir)
+
+		for _, entry := range entries {
+			match := namespaceEntryRegexp.FindStringSubmatch(entry.Name())
+			if match == nil {
+				continue
+			}
+			name := "nvme" + match[1] + "n" + match[2]
+			device, ok := devices[name]
+			if !ok {
+				device = r.readDevice(name)
+				devices[name] = device
+			}
+			device.Controllers = append(device.Controllers, controller)
+		}
+	}
+
+	result := make([]Device, 0, len(devices))
+	for _, device := range devices {
+		result = append(result, *device)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Path < result[j].Path })
+
+	return result, nil
+}
+
+// FindDevice returns the device matching id. id is either a subsystem nqn,
+// the NGUID or uuid of the namespace or the serial number of a controller.
+// An error is returned if id matches more than one device.
+func (r *Resolver) FindDevice(id string) (*Device, error) {
+	if id == "" {
+		return nil, errors.New("empty device id is not allowed")
+	}
+
+	devices, err := r.ListDevices()
+	if err != nil {
+		return nil, err
+	}
+
+	match := deviceMatcher(id)
+	var found []Device
+	for _, device := range devices {
+		if match(&device) {
+			found = append(found, device)
+		}
+	}
+
+	switch len(found) {
+	case 0:
+		return nil, fmt.Errorf("%w: %v", ErrDeviceNotFound, id)
+	case 1:
+		return &found[0], nil
+	default:
+		return nil, fmt.Errorf("%v matches %d nvme devices", id, len(found))
+	}
+}
+
+// WaitForDevice waits until the device matching id is reachable through a
+// live controller. See FindDevice for the supported ids.
+func (r *Resolver) WaitForDevice(ctx context.Context, id string) (*Device, error) {
+	ticker := time.NewTicker(devicePollInterval)
+	defer ticker.Stop()
+
+	for {
+		device, err := r.FindDevice(id)
+		switch {
+		case err == nil && device.Live():
+			return device, nil
+		case err != nil && !errors.Is(err, ErrDeviceNotFound):
+			return nil, err
+		}
+
+		select {
+		case <-ctx.Done():
+			if err == nil {
+				err = fmt.Errorf("nvme device %v has no live controller", device.Path)
+			}
+			return nil, fmt.Errorf("%w: %w", err, ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
+
+func (r *Resolver) readController(dir string) Controller {
+	return Controller{
+		Name:      filepath.Base(dir),
+		Subsysnqn: readAttribute(dir, "subsysnqn"),
+		Transport: readAttribute(dir, "transport"),
+		State:     readAttribute(dir, "state"),
+		Address:   readAttribute(dir, "address"),
+		Serial:    readAttribute(dir, "serial"),
+	}
+}
+
+func (r *Resolver) readDevice(name string) *Device {
+	dir := filepath.Join(r.root, "class", "block", name)
+	device := &Device{Path: filepath.Join("/dev", name)}
+	// devices without identifiers report all zeros, which fail to parse
+	if nguid, err := ParseNGUID(readAttribute(dir, "nguid")); err == nil {
+		device.NGUID = nguid
+	}
+	if id, err := ParseUUID(readAttribute(dir, "uuid")); err == nil {
+		device.UUID = id
+	}
+
+	return device
+}
+
+// deviceMatcher returns a function matching devices by the kind of id
+func deviceMatcher(id string) func(*Device) bool {
+	if ValidateNQN(id) == nil {
+		return func(d *Device) bool {
+			for _, controller := range d.Controllers {
+				if controller.Subsysnqn == id {
+					return true
+				}
+			}
+			return false
+		}
+	}
+
+	nguid, nguidErr := ParseNGUID(id)
+	namespaceUUID, uuidErr := ParseUUID(id)
+	return func(d *Device) bool {
+		if nguidErr == nil && d.NGUID == nguid {
+			return true
+		}
+		if uuidErr == nil && d.UUID == namespaceUUID {
+			return true
+		}
+		for _, controller := range d.Controllers {
+			if controller.Serial == id {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+func readAttribute(dir string, name string) string {
+	value, err := os.ReadFile(filepath.Join(dir, name))
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(value))
+}
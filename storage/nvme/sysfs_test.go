@@ -0,0 +1,251 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (C) 2024 Intel Corporation
+
+// Package nvme implements nvme addressing and identifier helpers for OPI storage clients
+package nvme
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testNGUID = "4d4f4e0a1b2c3d4e5f60718293a4b5c6"
+	testUUID  = "feb98abe-d51f-40c8-b348-2753f3571d3c"
+	testNqn   = "nqn.2022-09.io.spdk:opitest1"
+	testNqn2  = "nqn.2022-09.io.spdk:opitest2"
+)
+
+type fakeSysfs struct {
+	t    *testing.T
+	root string
+}
+
+func newFakeSysfs(t *testing.T) *fakeSysfs {
+	return &fakeSysfs{t: t, root: t.TempDir()}
+}
+
+func (f *fakeSysfs) write(path string, value string) {
+	f.t.Helper()
+	path = filepath.Join(f.root, path)
+	require.NoError(f.t, os.MkdirAll(filepath.Dir(path), 0o755))
+	require.NoError(f.t, os.WriteFile(path, []byte(value+"\n"), 0o600))
+}
+
+func (f *fakeSysfs) addController(name string, attributes map[string]string, namespaces ...string) {
+	f.t.Helper()
+	for attribute, value := range attributes {
+		f.write(filepath.Join("class", "nvme", name, attribute), value)
+	}
+	for _, namespace := range namespaces {
+		require.NoError(f.t, os.MkdirAll(filepath.Join(f.root, "class", "nvme", name, namespace), 0o755))
+	}
+}
+
+func (f *fakeSysfs) addNamespace(name string, nguid string, id string) {
+	f.t.Helper()
+	f.write(filepath.Join("class", "block", name, "nguid"), nguid)
+	f.write(filepath.Join("class", "block", name, "uuid"), id)
+}
+
+// newTestSysfs creates a local pcie ssd, a multipath nvme/tcp namespace
+// and a subsystem with two namespaces
+func newTestSysfs(t *testing.T) *fakeSysfs {
+	sysfs := newFakeSysfs(t)
+	sysfs.addController("nvme0", map[string]string{
+		"subsysnqn": "nqn.2014.08.org.nvmexpress:80868086PHLJ0000000001",
+		"transport": "pcie",
+		"state":     "live",
+		"address":   "0000:3b:00.0",
+		"serial":    "PHLJ000000000    ",
+	}, "nvme0n1", "power")
+	sysfs.addNamespace("nvme0n1", "0000000000000000000000000000000a", "00000000-0000-0000-0000-000000000000")
+
+	sysfs.addController("nvme1", map[string]string{
+		"subsysnqn": testNqn,
+		"transport": "tcp",
+		"state":     "connecting",
+		"address":   "traddr=10.0.0.1,trsvcid=4420",
+		"serial":    "SPDK00000000000001",
+	}, "nvme1c1n1")
+	sysfs.addController("nvme2", map[string]string{
+		"subsysnqn": testNqn,
+		"transport": "tcp",
+		"state":     "live",
+		"address":   "traddr=10.0.0.2,trsvcid=4420",
+		"serial":    "SPDK00000000000001",
+	}, "nvme1c2n1")
+	sysfs.addNamespace("nvme1n1", testNGUID, testUUID)
+
+	sysfs.addController("nvme3", map[string]string{
+		"subsysnqn": testNqn2,
+		"transport": "rdma",
+		"state":     "live",
+		"address":   "traddr=10.0.0.3,trsvcid=4420",
+		"serial":    "SPDK00000000000002",
+	}, "nvme3n1", "nvme3n2")
+	sysfs.addNamespace("nvme3n1", "00000000000000000000000000000031", "00000000-0000-0000-0000-000000000031")
+	sysfs.addNamespace("nvme3n2", "00000000000000000000000000000032", "00000000-0000-0000-0000-000000000032")
+
+	return sysfs
+}
+
+func TestResolverListDevices(t *testing.T) {
+	sysfs := newTestSysfs(t)
+
+	devices, err := NewResolver(sysfs.root).ListDevices()
+	require.NoError(t, err)
+
+	paths := []string{}
+	for _, device := range devices {
+		paths = append(paths, device.Path)
+	}
+	require.Equal(t, []string{"/dev/nvme0n1", "/dev/nvme1n1", "/dev/nvme3n1", "/dev/nvme3n2"}, paths)
+
+	require.Equal(t, []Controller{{
+		Name:      "nvme0",
+		Subsysnqn: "nqn.2014.08.org.nvmexpress:80868086PHLJ0000000001",
+		Transport: "pcie",
+		State:     "live",
+		Address:   "0000:3b:00.0",
+		Serial:    "PHLJ000000000",
+	}}, devices[0].Controllers)
+	require.Equal(t, uuid.Nil, devices[0].UUID)
+
+	multipath := devices[1]
+	require.Equal(t, testNGUID, multipath.NGUID.String())
+	require.Equal(t, testUUID, multipath.UUID.String())
+	require.Len(t, multipath.Controllers, 2)
+	require.Equal(t, "nvme1", multipath.Controllers[0].Name)
+	require.Equal(t, "nvme2", multipath.Controllers[1].Name)
+	require.True(t, multipath.Live())
+}
+
+func TestResolverListDevicesEmpty(t *testing.T) {
+	devices, err := NewResolver(t.TempDir()).ListDevices()
+	require.NoError(t, err)
+	require.Empty(t, devices)
+}
+
+func TestResolverFindDevice(t *testing.T) {
+	tests := map[string]struct {
+		giveID   string
+		wantPath string
+		wantErr  error
+	}{
+		"by nqn": {
+			giveID:   testNqn,
+			wantPath: "/dev/nvme1n1",
+		},
+		"by nguid": {
+			giveID:   testNGUID,
+			wantPath: "/dev/nvme1n1",
+		},
+		"by nguid in uuid layout": {
+			giveID:   "00000000-0000-0000-0000-000000000032",
+			wantPath: "/dev/nvme3n2",
+		},
+		"by uuid": {
+			giveID:   testUUID,
+			wantPath: "/dev/nvme1n1",
+		},
+		"by serial": {
+			giveID:   "PHLJ000000000",
+			wantPath: "/dev/nvme0n1",
+		},
+		"not found": {
+			giveID:  "nqn.2022-09.io.spdk:missing",
+			wantErr: ErrDeviceNotFound,
+		},
+		"nqn of subsystem with several namespaces": {
+			giveID:  testNqn2,
+			wantErr: errors.New("nqn.2022-09.io.spdk:opitest2 matches 2 nvme devices"),
+		},
+		"empty id": {
+			giveID:  "",
+			wantErr: errors.New("empty device id is not allowed"),
+		},
+	}
+
+	resolver := NewResolver(newTestSysfs(t).root)
+	for testName, tt := range tests {
+		t.Run(testName, func(t *testing.T) {
+			device, err := resolver.FindDevice(tt.giveID)
+			if tt.wantErr != nil {
+				require.Error(t, err)
+				if errors.Is(tt.wantErr, ErrDeviceNotFound) {
+					require.ErrorIs(t, err, ErrDeviceNotFound)
+				} else {
+					require.EqualError(t, err, tt.wantErr.Error())
+				}
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.wantPath, device.Path)
+		})
+	}
+}
+
+func TestResolverWaitForDevice(t *testing.T) {
+	sysfs := newFakeSysfs(t)
+	resolver := NewResolver(sysfs.root)
+
+	go func() {
+		time.Sleep(2 * devicePollInterval)
+		sysfs.addController("nvme4", map[string]string{
+			"subsysnqn": testNqn,
+			"transport": "tcp",
+			"state":     "live",
+		}, "nvme4n1")
+		sysfs.addNamespace("nvme4n1", testNGUID, testUUID)
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	device, err := resolver.WaitForDevice(ctx, testUUID)
+	require.NoError(t, err)
+	require.Equal(t, "/dev/nvme4n1", device.Path)
+	require.Equal(t, "tcp", device.Controllers[0].Transport)
+}
+
+func TestResolverWaitForDeviceTimeout(t *testing.T) {
+	sysfs := newFakeSysfs(t)
+	sysfs.addController("nvme0", map[string]string{
+		"subsysnqn": testNqn,
+		"state":     "connecting",
+	}, "nvme0n1")
+	resolver := NewResolver(sysfs.root)
+
+	tests := map[string]struct {
+		giveID  string
+		wantErr error
+	}{
+		"missing device": {
+			giveID:  testNqn2,
+			wantErr: ErrDeviceNotFound,
+		},
+		"no live controller": {
+			giveID: testNqn,
+		},
+	}
+
+	for testName, tt := range tests {
+		t.Run(testName, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 3*devicePollInterval)
+			defer cancel()
+
+			_, err := resolver.WaitForDevice(ctx, tt.giveID)
+			require.ErrorIs(t, err, context.DeadlineExceeded)
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+			}
+		})
+	}
+}
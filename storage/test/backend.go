@@ -10,10 +10,9 @@ import (
 	"fmt"
 	"log"
 	"net"
-	"path"
 	"time"
 
-	"github.com/google/uuid"
+	"github.com/opiproject/godpu/storage/nvme"
 	pb "github.com/opiproject/opi-api/storage/v1alpha1/gen/go"
 
 	"google.golang.org/grpc"
@@ -22,92 +21,154 @@ import (
 
 // DoBackend executes the back end code
 func DoBackend(ctx context.Context, conn grpc.ClientConnInterface) error {
+	return RunBackend(ctx, conn, DefaultFixtures(), NewReport(false))
+}
+
+// RunBackend executes the back end code with fixtures and records every step
+// in report
+func RunBackend(ctx context.Context, conn grpc.ClientConnInterface, fixtures *Fixtures, report *Report) error {
 	nvme := pb.NewNvmeRemoteControllerServiceClient(conn)
 	null := pb.NewNullVolumeServiceClient(conn)
 	aio := pb.NewAioVolumeServiceClient(conn)
 
-	err := executeNvmeRemoteController(ctx, nvme)
-	if err != nil {
+	err := executeNvmeRemoteController(ctx, nvme, fixtures, report)
+	if report.Fatal(err) {
 		return err
 	}
-	err = executeNvmePath(ctx, nvme, false)
-	if err != nil {
+	err = executeNvmePath(ctx, nvme, false, fixtures, report)
+	if report.Fatal(err) {
 		return err
 	}
-	err = executeNvmePath(ctx, nvme, true)
-	if err != nil {
+	if fixtures.Target.TLS {
+		err = executeNvmePath(ctx, nvme, true, fixtures, report)
+		if report.Fatal(err) {
+			return err
+		}
+	}
+	err = executeNullVolume(ctx, null, fixtures, report)
+	if report.Fatal(err) {
 		return err
 	}
-	err = executeNullVolume(ctx, null)
-	if err != nil {
+	err = executeAioVolume(ctx, aio, fixtures, report)
+	if report.Fatal(err) {
 		return err
 	}
-	err = executeAioVolume(ctx, aio)
-	if err != nil {
+	for _, checks := range []*resourceChecks{
+		nvmeRemoteControllerChecks(nvme),
+		nullVolumeChecks(null),
+		aioVolumeChecks(aio, fixtures),
+	} {
+		err = executeErrorChecks(ctx, checks, fixtures, report)
+		if report.Fatal(err) {
+			return err
+		}
+	}
+	for _, checks := range []*resourceChecks{
+		nvmeRemoteControllerChecks(nvme),
+		nullVolumeChecks(null),
+	} {
+		err = executePaginationChecks(ctx, checks, fixtures, report)
+		if report.Fatal(err) {
+			return err
+		}
+	}
+	err = executeNvmeRemoteControllerFieldMask(ctx, nvme, fixtures, report)
+	if report.Fatal(err) {
+		return err
+	}
+	err = executeNullVolumeFieldMask(ctx, null, fixtures, report)
+	if report.Fatal(err) {
 		return err
 	}
 	return nil
 }
 
-func executeNvmeRemoteController(ctx context.Context, c4 pb.NvmeRemoteControllerServiceClient) error {
-	log.Printf("=======================================")
-	log.Printf("Testing NewNvmeRemoteControllerServiceClient")
-	log.Printf("=======================================")
+func executeNvmeRemoteController(ctx context.Context, c4 pb.NvmeRemoteControllerServiceClient, f *Fixtures, r *Report) (err error) {
+	r.Begin("NvmeRemoteController")
+	defer r.Cleanup(ctx, &err)
 
 	// testing with and without {resource}_id field
-	for _, resourceID := range []string{"opi-nvme8", ""} {
-		rr0, err := c4.CreateNvmeRemoteController(ctx, &pb.CreateNvmeRemoteControllerRequest{
-			NvmeRemoteControllerId: resourceID,
-			NvmeRemoteController: &pb.NvmeRemoteController{
-				Multipath: pb.NvmeMultipath_NVME_MULTIPATH_MULTIPATH,
-				Tcp: &pb.TcpController{
-					Hdgst: false,
-					Ddgst: false,
-				},
-			}})
+	for _, resourceID := range []string{f.ID("opi-nvme8"), ""} {
+		var rr0 *pb.NvmeRemoteController
+		err := r.Step(caseName("CreateNvmeRemoteController", resourceID), func() (err error) {
+			rr0, err = c4.CreateNvmeRemoteController(ctx, &pb.CreateNvmeRemoteControllerRequest{
+				NvmeRemoteControllerId: resourceID,
+				NvmeRemoteController: &pb.NvmeRemoteController{
+					Multipath: pb.NvmeMultipath_NVME_MULTIPATH_MULTIPATH,
+					Tcp: &pb.TcpController{
+						Hdgst: false,
+						Ddgst: false,
+					},
+				}})
+			if err != nil {
+				return err
+			}
+			r.Track(rr0.Name, func(ctx context.Context) error {
+				_, err := c4.DeleteNvmeRemoteController(ctx, &pb.DeleteNvmeRemoteControllerRequest{Name: rr0.Name, AllowMissing: true})
+				return err
+			})
+			log.Printf("Created Nvme controller: %v", rr0)
+			return verifyResourceName(rr0.Name, resourceID, resourceIDToRemoteControllerName)
+		})
 		if err != nil {
 			return err
 		}
-		// verify
-		newResourceID := resourceID
-		if resourceID == "" {
-			parsed, err := uuid.Parse(path.Base(rr0.Name))
+		err = r.Step(caseName("ResetNvmeRemoteController", resourceID), func() error {
+			rr2, err := c4.ResetNvmeRemoteController(ctx, &pb.ResetNvmeRemoteControllerRequest{Name: rr0.Name})
 			if err != nil {
 				return err
 			}
-			newResourceID = parsed.String()
-		}
-		fullname := resourceIDToRemoteControllerName(newResourceID)
-		if rr0.Name != fullname {
-			return fmt.Errorf("server filled value '%s' is not matching user requested '%s'", rr0.Name, fullname)
-		}
-		log.Printf("Created Nvme controller: %v", rr0)
-		// continue
-		rr2, err := c4.ResetNvmeRemoteController(ctx, &pb.ResetNvmeRemoteControllerRequest{Name: rr0.Name})
-		if err != nil {
+			log.Printf("Reset Nvme: %v", rr2)
+			return nil
+		})
+		if r.Fatal(err) {
 			return err
 		}
-		log.Printf("Reset Nvme: %v", rr2)
-		rr3, err := c4.ListNvmeRemoteControllers(ctx, &pb.ListNvmeRemoteControllersRequest{})
-		if err != nil {
+		err = r.Step(caseName("ListNvmeRemoteControllers", resourceID), func() error {
+			rr3, err := c4.ListNvmeRemoteControllers(ctx, &pb.ListNvmeRemoteControllersRequest{})
+			if err != nil {
+				return err
+			}
+			log.Printf("List Nvme: %v", rr3)
+			return nil
+		})
+		if r.Fatal(err) {
 			return err
 		}
-		log.Printf("List Nvme: %v", rr3)
-		rr4, err := c4.GetNvmeRemoteController(ctx, &pb.GetNvmeRemoteControllerRequest{Name: rr0.Name})
-		if err != nil {
+		err = r.Step(caseName("GetNvmeRemoteController", resourceID), func() error {
+			rr4, err := c4.GetNvmeRemoteController(ctx, &pb.GetNvmeRemoteControllerRequest{Name: rr0.Name})
+			if err != nil {
+				return err
+			}
+			log.Printf("Got Nvme: %v", rr4)
+			return nil
+		})
+		if r.Fatal(err) {
 			return err
 		}
-		log.Printf("Got Nvme: %v", rr4)
-		rr5, err := c4.StatsNvmeRemoteController(ctx, &pb.StatsNvmeRemoteControllerRequest{Name: rr0.Name})
-		if err != nil {
+		err = r.Step(caseName("StatsNvmeRemoteController", resourceID), func() error {
+			rr5, err := c4.StatsNvmeRemoteController(ctx, &pb.StatsNvmeRemoteControllerRequest{Name: rr0.Name})
+			if err != nil {
+				return err
+			}
+			log.Printf("Stats Nvme: %v", rr5)
+			return nil
+		})
+		if r.Fatal(err) {
 			return err
 		}
-		log.Printf("Stats Nvme: %v", rr5)
-		rr1, err := c4.DeleteNvmeRemoteController(ctx, &pb.DeleteNvmeRemoteControllerRequest{Name: rr0.Name})
+		err = r.Step(caseName("DeleteNvmeRemoteController", resourceID), func() error {
+			rr1, err := c4.DeleteNvmeRemoteController(ctx, &pb.DeleteNvmeRemoteControllerRequest{Name: rr0.Name})
+			if err != nil {
+				return err
+			}
+			r.Untrack(rr0.Name)
+			log.Printf("Deleted Nvme controller: %v -> %v", rr0, rr1)
+			return nil
+		})
 		if err != nil {
 			return err
 		}
-		log.Printf("Deleted Nvme controller: %v -> %v", rr0, rr1)
 
 		// wait for some time for the backend to delete above objects
 		time.Sleep(time.Second)
@@ -115,242 +176,347 @@ func executeNvmeRemoteController(ctx context.Context, c4 pb.NvmeRemoteController
 	return nil
 }
 
-func executeNvmePath(ctx context.Context, c5 pb.NvmeRemoteControllerServiceClient, tlsEnabled bool) error {
-	log.Printf("=======================================")
-	log.Printf("Testing NewNvmePathClient TLS=%v", tlsEnabled)
-	log.Printf("=======================================")
+func executeNvmePath(ctx context.Context, c5 pb.NvmeRemoteControllerServiceClient, tlsEnabled bool, f *Fixtures, r *Report) (err error) {
+	r.Begin(fmt.Sprintf("NvmePath TLS=%v", tlsEnabled))
+	defer r.Cleanup(ctx, &err)
 
-	addr, err := net.LookupIP("spdk")
+	var addr []net.IP
+	var adrfam pb.NvmeAddressFamily
+	err = r.Step("LookupTargetAddress", func() (err error) {
+		addr, err = net.LookupIP(f.Target.Addr)
+		if err != nil {
+			return err
+		}
+		adrfam, err = nvme.AddressFamily(addr[0])
+		return err
+	})
 	if err != nil {
 		return err
 	}
 
-	port := 4444
+	port := f.Target.Port
 	psk := []byte{}
 	if tlsEnabled {
-		port = 5555
-		psk = []byte("NVMeTLSkey-1:01:MDAxMTIyMzM0NDU1NjY3Nzg4OTlhYWJiY2NkZGVlZmZwJEiQ:")
-	}
-
-	ctrlrResourceID := "opi-nvme8"
-	rr0, err := c5.CreateNvmeRemoteController(ctx, &pb.CreateNvmeRemoteControllerRequest{
-		NvmeRemoteControllerId: ctrlrResourceID,
-		NvmeRemoteController: &pb.NvmeRemoteController{
-			Multipath: pb.NvmeMultipath_NVME_MULTIPATH_MULTIPATH,
-			Tcp: &pb.TcpController{
-				Hdgst: false,
-				Ddgst: false,
-				Psk:   psk,
-			},
-		}})
-	if err != nil {
-		return err
+		port = f.Target.TLSPort
+		psk = f.Target.Psk
 	}
-	log.Printf("Created Nvme controller: %v", rr0)
 
-	for _, resourceID := range []string{"opi-nvme8-path", ""} {
-		np0, err := c5.CreateNvmePath(ctx, &pb.CreateNvmePathRequest{
-			Parent:     rr0.Name,
-			NvmePathId: resourceID,
-			NvmePath: &pb.NvmePath{
-				Trtype: pb.NvmeTransportType_NVME_TRANSPORT_TYPE_TCP,
-				Traddr: addr[0].String(),
-				Fabrics: &pb.FabricsPath{
-					Adrfam:  pb.NvmeAddressFamily_NVME_ADDRESS_FAMILY_IPV4,
-					Trsvcid: int64(port),
-					Subnqn:  "nqn.2016-06.io.spdk:cnode1",
-					Hostnqn: "nqn.2014-08.org.nvmexpress:uuid:feb98abe-d51f-40c8-b348-2753f3571d3c",
+	ctrlrResourceID := f.ID("opi-nvme8")
+	var rr0 *pb.NvmeRemoteController
+	err = r.Step("CreateNvmeRemoteController", func() (err error) {
+		rr0, err = c5.CreateNvmeRemoteController(ctx, &pb.CreateNvmeRemoteControllerRequest{
+			NvmeRemoteControllerId: ctrlrResourceID,
+			NvmeRemoteController: &pb.NvmeRemoteController{
+				Multipath: pb.NvmeMultipath_NVME_MULTIPATH_MULTIPATH,
+				Tcp: &pb.TcpController{
+					Hdgst: false,
+					Ddgst: false,
+					Psk:   psk,
 				},
 			}})
 		if err != nil {
 			return err
 		}
-		log.Printf("Created Nvme path: %v", np0)
+		r.Track(rr0.Name, func(ctx context.Context) error {
+			_, err := c5.DeleteNvmeRemoteController(ctx, &pb.DeleteNvmeRemoteControllerRequest{Name: rr0.Name, AllowMissing: true})
+			return err
+		})
+		log.Printf("Created Nvme controller: %s", rr0.Name)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
 
-		newResourceID := resourceID
-		if resourceID == "" {
-			parsed, err := uuid.Parse(path.Base(np0.Name))
+	for _, resourceID := range []string{f.ID("opi-nvme8-path"), ""} {
+		var np0 *pb.NvmePath
+		err := r.Step(caseName("CreateNvmePath", resourceID), func() (err error) {
+			np0, err = c5.CreateNvmePath(ctx, &pb.CreateNvmePathRequest{
+				Parent:     rr0.Name,
+				NvmePathId: resourceID,
+				NvmePath: &pb.NvmePath{
+					Trtype: pb.NvmeTransportType_NVME_TRANSPORT_TYPE_TCP,
+					Traddr: addr[0].String(),
+					Fabrics: &pb.FabricsPath{
+						Adrfam:  adrfam,
+						Trsvcid: int64(port),
+						Subnqn:  f.Target.Nqn,
+						Hostnqn: f.Hostnqn,
+					},
+				}})
 			if err != nil {
 				return err
 			}
-			newResourceID = parsed.String()
-		}
-		fullname := resourceIDToNvmePathName(ctrlrResourceID, newResourceID)
-		if np0.Name != fullname {
-			return fmt.Errorf("server filled value '%s' is not matching user requested '%s'", np0.Name, fullname)
-		}
-		log.Printf("Created Nvme path: %v", np0)
-		np3, err := c5.UpdateNvmePath(ctx, &pb.UpdateNvmePathRequest{
-			UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"*"}},
-			NvmePath: &pb.NvmePath{
-				Name:   np0.Name,
-				Trtype: pb.NvmeTransportType_NVME_TRANSPORT_TYPE_TCP,
-				Traddr: addr[0].String(),
-				Fabrics: &pb.FabricsPath{
-					Adrfam:  pb.NvmeAddressFamily_NVME_ADDRESS_FAMILY_IPV4,
-					Trsvcid: int64(port),
-					Subnqn:  "nqn.2016-06.io.spdk:cnode1",
-					Hostnqn: "nqn.2014-08.org.nvmexpress:uuid:feb98abe-d51f-40c8-b348-2753f3571d3c",
-				},
-			}})
+			r.Track(np0.Name, func(ctx context.Context) error {
+				_, err := c5.DeleteNvmePath(ctx, &pb.DeleteNvmePathRequest{Name: np0.Name, AllowMissing: true})
+				return err
+			})
+			log.Printf("Created Nvme path: %v", np0)
+			return verifyResourceName(np0.Name, resourceID, func(id string) string {
+				return resourceIDToNvmePathName(ctrlrResourceID, id)
+			})
+		})
 		if err != nil {
 			return err
 		}
-		log.Printf("Updated Nvme path: %v", np3)
-		np4, err := c5.ListNvmePaths(ctx, &pb.ListNvmePathsRequest{Parent: rr0.Name})
-		if err != nil {
+		err = r.Step(caseName("UpdateNvmePath", resourceID), func() error {
+			np3, err := c5.UpdateNvmePath(ctx, &pb.UpdateNvmePathRequest{
+				UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"*"}},
+				NvmePath: &pb.NvmePath{
+					Name:   np0.Name,
+					Trtype: pb.NvmeTransportType_NVME_TRANSPORT_TYPE_TCP,
+					Traddr: addr[0].String(),
+					Fabrics: &pb.FabricsPath{
+						Adrfam:  adrfam,
+						Trsvcid: int64(port),
+						Subnqn:  f.Target.Nqn,
+						Hostnqn: f.Hostnqn,
+					},
+				}})
+			if err != nil {
+				return err
+			}
+			log.Printf("Updated Nvme path: %v", np3)
+			return nil
+		})
+		if r.Fatal(err) {
 			return err
 		}
-		log.Printf("Listed Nvme path: %v", np4)
-		np5, err := c5.GetNvmePath(ctx, &pb.GetNvmePathRequest{Name: np0.Name})
-		if err != nil {
+		err = r.Step(caseName("ListNvmePaths", resourceID), func() error {
+			np4, err := c5.ListNvmePaths(ctx, &pb.ListNvmePathsRequest{Parent: rr0.Name})
+			if err != nil {
+				return err
+			}
+			log.Printf("Listed Nvme path: %v", np4)
+			return nil
+		})
+		if r.Fatal(err) {
 			return err
 		}
-		log.Printf("Got Nvme path: %s", np5.Name)
-		np6, err := c5.StatsNvmePath(ctx, &pb.StatsNvmePathRequest{Name: np0.Name})
-		if err != nil {
+		err = r.Step(caseName("GetNvmePath", resourceID), func() error {
+			np5, err := c5.GetNvmePath(ctx, &pb.GetNvmePathRequest{Name: np0.Name})
+			if err != nil {
+				return err
+			}
+			log.Printf("Got Nvme path: %s", np5.Name)
+			return nil
+		})
+		if r.Fatal(err) {
 			return err
 		}
-		log.Printf("Stats Nvme path: %s", np6.Stats)
-		np1, err := c5.DeleteNvmePath(ctx, &pb.DeleteNvmePathRequest{
-			Name: np0.Name,
+		err = r.Step(caseName("StatsNvmePath", resourceID), func() error {
+			np6, err := c5.StatsNvmePath(ctx, &pb.StatsNvmePathRequest{Name: np0.Name})
+			if err != nil {
+				return err
+			}
+			log.Printf("Stats Nvme path: %s", np6.Stats)
+			return nil
+		})
+		if r.Fatal(err) {
+			return err
+		}
+		err = r.Step(caseName("DeleteNvmePath", resourceID), func() error {
+			np1, err := c5.DeleteNvmePath(ctx, &pb.DeleteNvmePathRequest{
+				Name: np0.Name,
+			})
+			if err != nil {
+				return err
+			}
+			r.Untrack(np0.Name)
+			log.Printf("Deleted Nvme path: %v -> %v", np0, np1)
+			return nil
 		})
 		if err != nil {
 			return err
 		}
-		log.Printf("Deleted Nvme path: %v -> %v", np0, np1)
 
 		// wait for some time for the backend to delete above objects
 		time.Sleep(time.Second)
 	}
 
-	rr1, err := c5.DeleteNvmeRemoteController(ctx, &pb.DeleteNvmeRemoteControllerRequest{Name: rr0.Name})
-	if err != nil {
-		return err
-	}
-	log.Printf("Deleted Nvme controller: %v -> %v", rr0, rr1)
-
-	return nil
+	return r.Step("DeleteNvmeRemoteController", func() error {
+		rr1, err := c5.DeleteNvmeRemoteController(ctx, &pb.DeleteNvmeRemoteControllerRequest{Name: rr0.Name})
+		if err != nil {
+			return err
+		}
+		r.Untrack(rr0.Name)
+		log.Printf("Deleted Nvme controller: %s -> %v", rr0.Name, rr1)
+		return nil
+	})
 }
 
-func executeNullVolume(ctx context.Context, c1 pb.NullVolumeServiceClient) error {
-	log.Printf("=======================================")
-	log.Printf("Testing NewNullVolumeServiceClient")
-	log.Printf("=======================================")
+func executeNullVolume(ctx context.Context, c1 pb.NullVolumeServiceClient, f *Fixtures, r *Report) (err error) {
+	r.Begin("NullVolume")
+	defer r.Cleanup(ctx, &err)
 
 	// testing with and without {resource}_id field
-	for _, resourceID := range []string{"opi-null9", ""} {
-		rs1, err := c1.CreateNullVolume(ctx, &pb.CreateNullVolumeRequest{
-			NullVolumeId: resourceID,
-			NullVolume:   &pb.NullVolume{BlockSize: 512, BlocksCount: 64}})
+	for _, resourceID := range []string{f.ID("opi-null9"), ""} {
+		var rs1 *pb.NullVolume
+		err := r.Step(caseName("CreateNullVolume", resourceID), func() (err error) {
+			rs1, err = c1.CreateNullVolume(ctx, &pb.CreateNullVolumeRequest{
+				NullVolumeId: resourceID,
+				NullVolume:   &pb.NullVolume{BlockSize: 512, BlocksCount: 64}})
+			if err != nil {
+				return err
+			}
+			r.Track(rs1.Name, func(ctx context.Context) error {
+				_, err := c1.DeleteNullVolume(ctx, &pb.DeleteNullVolumeRequest{Name: rs1.Name, AllowMissing: true})
+				return err
+			})
+			log.Printf("Added Null: %v", rs1)
+			return verifyResourceName(rs1.Name, resourceID, resourceIDToVolumeName)
+		})
 		if err != nil {
 			return err
 		}
-		// verify
-		newResourceID := resourceID
-		if resourceID == "" {
-			parsed, err := uuid.Parse(path.Base(rs1.Name))
+		err = r.Step(caseName("UpdateNullVolume", resourceID), func() error {
+			rs3, err := c1.UpdateNullVolume(ctx, &pb.UpdateNullVolumeRequest{
+				UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"*"}},
+				NullVolume: &pb.NullVolume{
+					Name:        rs1.Name,
+					BlockSize:   512,
+					BlocksCount: 128,
+				}})
 			if err != nil {
 				return err
 			}
-			newResourceID = parsed.String()
-		}
-		fullname := resourceIDToVolumeName(newResourceID)
-		if rs1.Name != fullname {
-			return fmt.Errorf("server filled value '%s' is not matching user requested '%s'", rs1.Name, fullname)
-		}
-		log.Printf("Added Null: %v", rs1)
-		// continue
-		rs3, err := c1.UpdateNullVolume(ctx, &pb.UpdateNullVolumeRequest{
-			UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"*"}},
-			NullVolume: &pb.NullVolume{
-				Name:        rs1.Name,
-				BlockSize:   512,
-				BlocksCount: 128,
-			}})
-		if err != nil {
+			log.Printf("Updated Null: %v", rs3)
+			return nil
+		})
+		if r.Fatal(err) {
 			return err
 		}
-		log.Printf("Updated Null: %v", rs3)
-		rs4, err := c1.ListNullVolumes(ctx, &pb.ListNullVolumesRequest{})
-		if err != nil {
+		err = r.Step(caseName("ListNullVolumes", resourceID), func() error {
+			rs4, err := c1.ListNullVolumes(ctx, &pb.ListNullVolumesRequest{})
+			if err != nil {
+				return err
+			}
+			log.Printf("Listed Null: %v", rs4)
+			return nil
+		})
+		if r.Fatal(err) {
 			return err
 		}
-		log.Printf("Listed Null: %v", rs4)
-		rs5, err := c1.GetNullVolume(ctx, &pb.GetNullVolumeRequest{Name: rs1.Name})
-		if err != nil {
+		err = r.Step(caseName("GetNullVolume", resourceID), func() error {
+			rs5, err := c1.GetNullVolume(ctx, &pb.GetNullVolumeRequest{Name: rs1.Name})
+			if err != nil {
+				return err
+			}
+			log.Printf("Got Null: %s", rs5.Name)
+			return nil
+		})
+		if r.Fatal(err) {
 			return err
 		}
-		log.Printf("Got Null: %s", rs5.Name)
-		rs6, err := c1.StatsNullVolume(ctx, &pb.StatsNullVolumeRequest{Name: rs1.Name})
-		if err != nil {
+		err = r.Step(caseName("StatsNullVolume", resourceID), func() error {
+			rs6, err := c1.StatsNullVolume(ctx, &pb.StatsNullVolumeRequest{Name: rs1.Name})
+			if err != nil {
+				return err
+			}
+			log.Printf("Stats Null: %s", rs6.Stats)
+			return nil
+		})
+		if r.Fatal(err) {
 			return err
 		}
-		log.Printf("Stats Null: %s", rs6.Stats)
-		rs2, err := c1.DeleteNullVolume(ctx, &pb.DeleteNullVolumeRequest{Name: rs1.Name})
+		err = r.Step(caseName("DeleteNullVolume", resourceID), func() error {
+			rs2, err := c1.DeleteNullVolume(ctx, &pb.DeleteNullVolumeRequest{Name: rs1.Name})
+			if err != nil {
+				return err
+			}
+			r.Untrack(rs1.Name)
+			log.Printf("Deleted Null: %v -> %v", rs1, rs2)
+			return nil
+		})
 		if err != nil {
 			return err
 		}
-		log.Printf("Deleted Null: %v -> %v", rs1, rs2)
 	}
 	return nil
 }
 
-func executeAioVolume(ctx context.Context, c2 pb.AioVolumeServiceClient) error {
-	log.Printf("=======================================")
-	log.Printf("Testing NewAioVolumeServiceClient")
-	log.Printf("=======================================")
+func executeAioVolume(ctx context.Context, c2 pb.AioVolumeServiceClient, f *Fixtures, r *Report) (err error) {
+	r.Begin("AioVolume")
+	defer r.Cleanup(ctx, &err)
 
 	// testing with and without {resource}_id field
-	for _, resourceID := range []string{"opi-aio4", ""} {
-		ra1, err := c2.CreateAioVolume(ctx, &pb.CreateAioVolumeRequest{
-			AioVolumeId: resourceID,
-			AioVolume:   &pb.AioVolume{BlockSize: 512, BlocksCount: 12, Filename: "/tmp/aio_bdev_file"}})
+	for _, resourceID := range []string{f.ID("opi-aio4"), ""} {
+		var ra1 *pb.AioVolume
+		err := r.Step(caseName("CreateAioVolume", resourceID), func() (err error) {
+			ra1, err = c2.CreateAioVolume(ctx, &pb.CreateAioVolumeRequest{
+				AioVolumeId: resourceID,
+				AioVolume:   &pb.AioVolume{BlockSize: 512, BlocksCount: 12, Filename: f.AioFilename}})
+			if err != nil {
+				return err
+			}
+			r.Track(ra1.Name, func(ctx context.Context) error {
+				_, err := c2.DeleteAioVolume(ctx, &pb.DeleteAioVolumeRequest{Name: ra1.Name, AllowMissing: true})
+				return err
+			})
+			log.Printf("Added Aio: %v", ra1)
+			return verifyResourceName(ra1.Name, resourceID, resourceIDToVolumeName)
+		})
 		if err != nil {
 			return err
 		}
-		// verify
-		newResourceID := resourceID
-		if resourceID == "" {
-			parsed, err := uuid.Parse(path.Base(ra1.Name))
+		err = r.Step(caseName("UpdateAioVolume", resourceID), func() error {
+			ra3, err := c2.UpdateAioVolume(ctx, &pb.UpdateAioVolumeRequest{
+				UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"*"}},
+				AioVolume:  &pb.AioVolume{Name: ra1.Name, Filename: f.AioFilename}})
 			if err != nil {
 				return err
 			}
-			newResourceID = parsed.String()
-		}
-		fullname := resourceIDToVolumeName(newResourceID)
-		if ra1.Name != fullname {
-			return fmt.Errorf("server filled value '%s' is not matching user requested '%s'", ra1.Name, fullname)
-		}
-		log.Printf("Added Aio: %v", ra1)
-		// continue
-		ra3, err := c2.UpdateAioVolume(ctx, &pb.UpdateAioVolumeRequest{
-			UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"*"}},
-			AioVolume:  &pb.AioVolume{Name: ra1.Name, Filename: "/tmp/aio_bdev_file"}})
-		if err != nil {
+			log.Printf("Updated Aio: %v", ra3)
+			return nil
+		})
+		if r.Fatal(err) {
 			return err
 		}
-		log.Printf("Updated Aio: %v", ra3)
-		ra4, err := c2.ListAioVolumes(ctx, &pb.ListAioVolumesRequest{})
-		if err != nil {
+		err = r.Step(caseName("ListAioVolumes", resourceID), func() error {
+			ra4, err := c2.ListAioVolumes(ctx, &pb.ListAioVolumesRequest{})
+			if err != nil {
+				return err
+			}
+			log.Printf("Listed Aio: %v", ra4)
+			return nil
+		})
+		if r.Fatal(err) {
 			return err
 		}
-		log.Printf("Listed Aio: %v", ra4)
-		ra5, err := c2.GetAioVolume(ctx, &pb.GetAioVolumeRequest{Name: ra1.Name})
-		if err != nil {
+		err = r.Step(caseName("GetAioVolume", resourceID), func() error {
+			ra5, err := c2.GetAioVolume(ctx, &pb.GetAioVolumeRequest{Name: ra1.Name})
+			if err != nil {
+				return err
+			}
+			log.Printf("Got Aio: %s", ra5.Name)
+			return nil
+		})
+		if r.Fatal(err) {
 			return err
 		}
-		log.Printf("Got Aio: %s", ra5.Name)
-		ra6, err := c2.StatsAioVolume(ctx, &pb.StatsAioVolumeRequest{Name: ra1.Name})
-		if err != nil {
+		err = r.Step(caseName("StatsAioVolume", resourceID), func() error {
+			ra6, err := c2.StatsAioVolume(ctx, &pb.StatsAioVolumeRequest{Name: ra1.Name})
+			if err != nil {
+				return err
+			}
+			log.Printf("Stats Aio: %s", ra6.Stats)
+			return nil
+		})
+		if r.Fatal(err) {
 			return err
 		}
-		log.Printf("Stats Aio: %s", ra6.Stats)
-		ra2, err := c2.DeleteAioVolume(ctx, &pb.DeleteAioVolumeRequest{Name: ra1.Name})
+		err = r.Step(caseName("DeleteAioVolume", resourceID), func() error {
+			ra2, err := c2.DeleteAioVolume(ctx, &pb.DeleteAioVolumeRequest{Name: ra1.Name})
+			if err != nil {
+				return err
+			}
+			r.Untrack(ra1.Name)
+			log.Printf("Deleted Aio: %v -> %v", ra1, ra2)
+			return nil
+		})
 		if err != nil {
 			return err
 		}
-		log.Printf("Deleted Aio: %v -> %v", ra1, ra2)
 	}
 	return nil
 }
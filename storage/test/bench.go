@@ -0,0 +1,329 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (C) 2024 Intel Corporation
+
+// Package test implements compliance storage tests
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"math"
+	"slices"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"github.com/opiproject/godpu/testing/report"
+	pb "github.com/opiproject/opi-api/storage/v1alpha1/gen/go"
+	"google.golang.org/grpc"
+)
+
+// BenchFormat defines the output format of a benchmark result
+type BenchFormat string
+
+// Enumerates all benchmark result formats
+const (
+	BenchFormatTable BenchFormat = "table"
+	BenchFormatJSON  BenchFormat = "json"
+)
+
+// AllBenchFormats contains all supported benchmark result formats
+var AllBenchFormats = []BenchFormat{
+	BenchFormatTable,
+	BenchFormatJSON,
+}
+
+// AllBenchKinds contains the resource types which can be benchmarked
+var AllBenchKinds = []string{
+	"NvmeSubsystem",
+	"NvmeRemoteController",
+	"NullVolume",
+}
+
+// BenchOptions configures a benchmark
+type BenchOptions struct {
+	// Kinds are the benchmarked resource types, see AllBenchKinds
+	Kinds []string
+	// Concurrency is the number of workers per resource type
+	Concurrency int
+	// Duration is how long the workers create, get, list and delete
+	// resources
+	Duration time.Duration
+	// Resources is the number of resources a worker creates per iteration
+	Resources int
+}
+
+// Validate checks that options can be used to run a benchmark
+func (o *BenchOptions) Validate() error {
+	switch {
+	case len(o.Kinds) == 0:
+		return errors.New("at least one resource type is required")
+	case o.Concurrency <= 0:
+		return fmt.Errorf("invalid concurrency: %v", o.Concurrency)
+	case o.Duration <= 0:
+		return fmt.Errorf("invalid duration: %v", o.Duration)
+	case o.Resources <= 0:
+		return fmt.Errorf("invalid resource count: %v", o.Resources)
+	}
+	for _, kind := range o.Kinds {
+		if !slices.Contains(AllBenchKinds, kind) {
+			return fmt.Errorf("unknown resource type %v, expected one of %v", kind, AllBenchKinds)
+		}
+	}
+	return nil
+}
+
+// BenchRPC is the throughput and latency of an RPC
+type BenchRPC struct {
+	Name   string
+	Calls  int
+	Errors int
+	// Throughput is the number of calls per second
+	Throughput    float64
+	P50, P95, P99 time.Duration
+}
+
+// BenchResult is the result of a benchmark
+type BenchResult struct {
+	Duration time.Duration
+	RPCs     []BenchRPC
+}
+
+// Bench runs concurrent create, get, list and delete loops of the resource
+// types in options and measures the latency of every RPC. Resources use the
+// ids of fixtures and are deleted when the benchmark ends.
+func Bench(ctx context.Context, conn grpc.ClientConnInterface, fixtures *Fixtures, options BenchOptions) (*BenchResult, error) {
+	if err := options.Validate(); err != nil {
+		return nil, err
+	}
+
+	var checks []*resourceChecks
+	for _, kind := range options.Kinds {
+		switch kind {
+		case "NvmeSubsystem":
+			checks = append(checks, nvmeSubsystemChecks(pb.NewFrontendNvmeServiceClient(conn), fixtures))
+		case "NvmeRemoteController":
+			checks = append(checks, nvmeRemoteControllerChecks(pb.NewNvmeRemoteControllerServiceClient(conn)))
+		case "NullVolume":
+			checks = append(checks, nullVolumeChecks(pb.NewNullVolumeServiceClient(conn)))
+		}
+	}
+	return bench(ctx, checks, fixtures, options)
+}
+
+func bench(ctx context.Context, checks []*resourceChecks, f *Fixtures, options BenchOptions) (*BenchResult, error) {
+	runCtx, cancel := context.WithTimeout(ctx, options.Duration)
+	defer cancel()
+
+	type leftover struct {
+		checks *resourceChecks
+		names  []string
+	}
+	recorder := newBenchRecorder()
+	leftovers := make([]leftover, len(checks)*options.Concurrency)
+
+	log.Printf("Benchmarking %v with %d workers each for %v", options.Kinds, options.Concurrency, options.Duration)
+	start := time.Now()
+	var wg sync.WaitGroup
+	for i, c := range checks {
+		for worker := range options.Concurrency {
+			wg.Add(1)
+			go func(index int) {
+				defer wg.Done()
+				leftovers[index] = leftover{
+					checks: c,
+					names:  benchWorker(runCtx, c, f, worker, options.Resources, recorder),
+				}
+			}(i*options.Concurrency + worker)
+		}
+	}
+	wg.Wait()
+	result := recorder.result(time.Since(start))
+
+	// resources of interrupted iterations
+	cleanupCtx, cleanupCancel := context.WithTimeout(context.WithoutCancel(ctx), report.CleanupTimeout)
+	defer cleanupCancel()
+	var errs []error
+	for _, l := range leftovers {
+		for _, name := range l.names {
+			if err := l.checks.delete(cleanupCtx, name, true); err != nil {
+				errs = append(errs, fmt.Errorf("failed to delete %v: %w", name, err))
+			}
+		}
+	}
+	return result, errors.Join(errs...)
+}
+
+// benchWorker creates, gets, lists and deletes resources until ctx is done.
+// It returns the resources which are not deleted.
+func benchWorker(ctx context.Context, c *resourceChecks, f *Fixtures, worker int, resources int, recorder *benchRecorder) []string {
+	var created []string
+	for ctx.Err() == nil {
+		for i := range resources {
+			id := f.ID(fmt.Sprintf("bench-%v-%d-%d", strings.ToLower(c.kind), worker, i))
+			var name string
+			ok := recorder.call(ctx, "Create"+c.kind, func() (err error) {
+				name, err = c.create(ctx, "", id)
+				return err
+			})
+			switch {
+			case ok:
+				created = append(created, name)
+			case ctx.Err() != nil:
+				// the server may have created the resource before the
+				// benchmark ended
+				created = append(created, c.name("", id))
+			}
+		}
+		for _, name := range created {
+			recorder.call(ctx, "Get"+c.kind, func() error {
+				return c.get(ctx, name)
+			})
+		}
+		recorder.call(ctx, "List"+c.kind+"s", func() error {
+			_, _, err := c.list(ctx, 0, "")
+			return err
+		})
+
+		var remaining []string
+		for _, name := range created {
+			ok := recorder.call(ctx, "Delete"+c.kind, func() error {
+				return c.delete(ctx, name, false)
+			})
+			if !ok {
+				remaining = append(remaining, name)
+			}
+		}
+		created = remaining
+	}
+	return created
+}
+
+// benchRecorder collects the latencies of RPCs of all workers
+type benchRecorder struct {
+	mu        sync.Mutex
+	latencies map[string][]time.Duration
+	errors    map[string]int
+}
+
+func newBenchRecorder() *benchRecorder {
+	return &benchRecorder{
+		latencies: map[string][]time.Duration{},
+		errors:    map[string]int{},
+	}
+}
+
+// call runs and measures an RPC. It returns true if the RPC succeeded. RPCs
+// interrupted by the end of the benchmark are not recorded.
+func (b *benchRecorder) call(ctx context.Context, rpc string, fn func() error) bool {
+	start := time.Now()
+	err := fn()
+	latency := time.Since(start)
+	if err != nil && ctx.Err() != nil {
+		return false
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.latencies[rpc] = append(b.latencies[rpc], latency)
+	if err != nil {
+		b.errors[rpc]++
+	}
+	return err == nil
+}
+
+func (b *benchRecorder) result(elapsed time.Duration) *BenchResult {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	result := &BenchResult{Duration: elapsed}
+	for rpc, latencies := range b.latencies {
+		slices.Sort(latencies)
+		result.RPCs = append(result.RPCs, BenchRPC{
+			Name:       rpc,
+			Calls:      len(latencies),
+			Errors:     b.errors[rpc],
+			Throughput: float64(len(latencies)) / elapsed.Seconds(),
+			P50:        percentile(latencies, 50),
+			P95:        percentile(latencies, 95),
+			P99:        percentile(latencies, 99),
+		})
+	}
+	slices.SortFunc(result.RPCs, func(a, b BenchRPC) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+	return result
+}
+
+// percentile returns the nearest-rank percentile p of sorted latencies
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	return sorted[max(rank, 1)-1]
+}
+
+// Write writes the result in the given format
+func (r *BenchResult) Write(w io.Writer, format BenchFormat) error {
+	switch format {
+	case BenchFormatTable:
+		return r.WriteTable(w)
+	case BenchFormatJSON:
+		return r.WriteJSON(w)
+	default:
+		return fmt.Errorf("unknown bench format: %v", format)
+	}
+}
+
+// WriteTable writes the result as a table with a row per RPC
+func (r *BenchResult) WriteTable(w io.Writer) error {
+	table := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(table, "RPC\tCALLS\tERRORS\tCALLS/S\tP50\tP95\tP99")
+	for _, rpc := range r.RPCs {
+		fmt.Fprintf(table, "%v\t%d\t%d\t%.1f\t%v\t%v\t%v\n",
+			rpc.Name, rpc.Calls, rpc.Errors, rpc.Throughput,
+			rpc.P50.Round(time.Microsecond), rpc.P95.Round(time.Microsecond), rpc.P99.Round(time.Microsecond))
+	}
+	return table.Flush()
+}
+
+type jsonBenchResult struct {
+	Duration float64        `json:"duration"`
+	RPCs     []jsonBenchRPC `json:"rpcs"`
+}
+
+type jsonBenchRPC struct {
+	RPC        string  `json:"rpc"`
+	Calls      int     `json:"calls"`
+	Errors     int     `json:"errors"`
+	Throughput float64 `json:"throughput"`
+	P50        float64 `json:"p50"`
+	P95        float64 `json:"p95"`
+	P99        float64 `json:"p99"`
+}
+
+// WriteJSON writes the result as JSON. Durations are in seconds.
+func (r *BenchResult) WriteJSON(w io.Writer) error {
+	result := jsonBenchResult{Duration: r.Duration.Seconds(), RPCs: []jsonBenchRPC{}}
+	for _, rpc := range r.RPCs {
+		result.RPCs = append(result.RPCs, jsonBenchRPC{
+			RPC:        rpc.Name,
+			Calls:      rpc.Calls,
+			Errors:     rpc.Errors,
+			Throughput: rpc.Throughput,
+			P50:        rpc.P50.Seconds(),
+			P95:        rpc.P95.Seconds(),
+			P99:        rpc.P99.Seconds(),
+		})
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(result)
+}
@@ -0,0 +1,127 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (C) 2024 Intel Corporation
+
+// Package test implements compliance storage tests
+package test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPercentile(t *testing.T) {
+	var latencies []time.Duration
+	for i := 1; i <= 100; i++ {
+		latencies = append(latencies, time.Duration(i)*time.Millisecond)
+	}
+	require.Equal(t, 50*time.Millisecond, percentile(latencies, 50))
+	require.Equal(t, 95*time.Millisecond, percentile(latencies, 95))
+	require.Equal(t, 99*time.Millisecond, percentile(latencies, 99))
+	require.Equal(t, time.Millisecond, percentile(latencies[:1], 99))
+	require.Zero(t, percentile(nil, 50))
+}
+
+func TestBenchOptionsValidate(t *testing.T) {
+	valid := BenchOptions{Kinds: []string{"NullVolume"}, Concurrency: 1, Duration: time.Second, Resources: 1}
+	require.NoError(t, valid.Validate())
+
+	tests := map[string]struct {
+		options BenchOptions
+		wantErr string
+	}{
+		"no kinds": {
+			options: BenchOptions{Concurrency: 1, Duration: time.Second, Resources: 1},
+			wantErr: "at least one resource type is required",
+		},
+		"unknown kind": {
+			options: BenchOptions{Kinds: []string{"VirtioBlk"}, Concurrency: 1, Duration: time.Second, Resources: 1},
+			wantErr: "unknown resource type VirtioBlk, expected one of [NvmeSubsystem NvmeRemoteController NullVolume]",
+		},
+		"no concurrency": {
+			options: BenchOptions{Kinds: []string{"NullVolume"}, Duration: time.Second, Resources: 1},
+			wantErr: "invalid concurrency: 0",
+		},
+		"no resources": {
+			options: BenchOptions{Kinds: []string{"NullVolume"}, Concurrency: 1, Duration: time.Second},
+			wantErr: "invalid resource count: 0",
+		},
+	}
+
+	for testName, tt := range tests {
+		t.Run(testName, func(t *testing.T) {
+			require.EqualError(t, tt.options.Validate(), tt.wantErr)
+		})
+	}
+}
+
+func TestBench(t *testing.T) {
+	resources := map[string]bool{}
+	checks := fakePaginationChecks(resources, false, 0)
+	options := BenchOptions{Kinds: []string{"Fake"}, Concurrency: 1, Duration: 20 * time.Millisecond, Resources: 3}
+
+	result, err := bench(context.Background(), []*resourceChecks{checks}, DefaultFixtures(), options)
+	require.NoError(t, err)
+	require.Empty(t, resources)
+
+	var rpcs []string
+	for _, rpc := range result.RPCs {
+		rpcs = append(rpcs, rpc.Name)
+		require.Positive(t, rpc.Calls)
+		require.Zero(t, rpc.Errors)
+		require.Positive(t, rpc.Throughput)
+		require.LessOrEqual(t, rpc.P50, rpc.P95)
+		require.LessOrEqual(t, rpc.P95, rpc.P99)
+	}
+	require.Equal(t, []string{"CreateFake", "DeleteFake", "GetFake", "ListFakes"}, rpcs)
+}
+
+func TestBenchDeletesResourcesOfInterruptedCreate(t *testing.T) {
+	resources := map[string]bool{}
+	checks := fakePaginationChecks(resources, false, 0)
+	create := checks.create
+	checks.create = func(ctx context.Context, parent string, id string) (string, error) {
+		if _, err := create(ctx, parent, id); err != nil {
+			return "", err
+		}
+		<-ctx.Done()
+		return "", ctx.Err()
+	}
+	options := BenchOptions{Kinds: []string{"Fake"}, Concurrency: 1, Duration: 20 * time.Millisecond, Resources: 1}
+
+	_, err := bench(context.Background(), []*resourceChecks{checks}, DefaultFixtures(), options)
+	require.NoError(t, err)
+	require.Empty(t, resources)
+}
+
+func TestBenchResultWrite(t *testing.T) {
+	result := &BenchResult{
+		Duration: 2 * time.Second,
+		RPCs: []BenchRPC{
+			{Name: "CreateNullVolume", Calls: 10, Errors: 1, Throughput: 5, P50: time.Millisecond, P95: 2 * time.Millisecond, P99: 3 * time.Millisecond},
+		},
+	}
+
+	var table bytes.Buffer
+	require.NoError(t, result.Write(&table, BenchFormatTable))
+	require.Equal(t, `RPC               CALLS  ERRORS  CALLS/S  P50  P95  P99
+CreateNullVolume  10     1       5.0      1ms  2ms  3ms
+`, table.String())
+
+	var out bytes.Buffer
+	require.NoError(t, result.Write(&out, BenchFormatJSON))
+	var decoded jsonBenchResult
+	require.NoError(t, json.Unmarshal(out.Bytes(), &decoded))
+	require.Equal(t, jsonBenchResult{
+		Duration: 2,
+		RPCs: []jsonBenchRPC{
+			{RPC: "CreateNullVolume", Calls: 10, Errors: 1, Throughput: 5, P50: 0.001, P95: 0.002, P99: 0.003},
+		},
+	}, decoded)
+
+	require.EqualError(t, result.Write(&bytes.Buffer{}, "csv"), "unknown bench format: csv")
+}
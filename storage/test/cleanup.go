@@ -0,0 +1,354 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (C) 2024 Intel Corporation
+
+// Package test implements compliance storage tests
+package test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"path"
+	"strings"
+
+	pb "github.com/opiproject/opi-api/storage/v1alpha1/gen/go"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// CleanupSuite is the suite deletions of leftover resources are recorded in
+const CleanupSuite = "Cleanup"
+
+// leftoverResource is a resource left behind by earlier runs of the tests
+type leftoverResource struct {
+	name    string
+	cleanup func(ctx context.Context) error
+}
+
+// Cleanup deletes resources left behind by earlier runs of the tests, e.g.
+// after the process was killed. A resource is deleted if its id or the id
+// of its parent starts with prefix, see Fixtures.Prefix. Every deletion is
+// recorded as a step of CleanupSuite in report. Services not implemented by
+// the server are skipped.
+func Cleanup(ctx context.Context, conn grpc.ClientConnInterface, prefix string, report *Report) error {
+	if prefix == "" {
+		return errors.New("empty prefix is not allowed")
+	}
+	report.Begin(CleanupSuite)
+	log.Printf("Deleting resources with prefix %v", prefix)
+
+	finders := []struct {
+		kind string
+		find func(context.Context, grpc.ClientConnInterface, string) ([]leftoverResource, error)
+	}{
+		{"NvmeSubsystem", findNvmeLeftovers},
+		{"VirtioBlk", findVirtioBlkLeftovers},
+		{"VirtioScsiController", findVirtioScsiLeftovers},
+		{"EncryptedVolume", findEncryptedVolumeLeftovers},
+		{"QosVolume", findQosVolumeLeftovers},
+		{"NvmeRemoteController", findNvmeRemoteControllerLeftovers},
+		{"NullVolume", findNullVolumeLeftovers},
+		{"AioVolume", findAioVolumeLeftovers},
+	}
+
+	var errs []error
+	for _, finder := range finders {
+		var leftovers []leftoverResource
+		err := report.Step("find "+finder.kind+" leftovers", func() (err error) {
+			leftovers, err = finder.find(ctx, conn, prefix)
+			return err
+		})
+		if status.Code(err) == codes.Unimplemented {
+			report.Cases[len(report.Cases)-1].Skipped = true
+			continue
+		}
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		for _, leftover := range leftovers {
+			err := report.Step("delete "+leftover.name, func() error {
+				return leftover.cleanup(ctx)
+			})
+			if err != nil {
+				errs = append(errs, fmt.Errorf("failed to delete %v: %w", leftover.name, err))
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// hasPrefix returns true if the id of a resource name starts with prefix
+func hasPrefix(name string, prefix string) bool {
+	return strings.HasPrefix(path.Base(name), prefix)
+}
+
+// listAll calls list until all pages are returned
+func listAll[T any](list func(pageToken string) ([]T, string, error)) ([]T, error) {
+	var all []T
+	pageToken := ""
+	for {
+		items, next, err := list(pageToken)
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, items...)
+		if next == "" {
+			return all, nil
+		}
+		if next == pageToken {
+			return nil, fmt.Errorf("server returned the same page token %v again", next)
+		}
+		pageToken = next
+	}
+}
+
+func findNvmeLeftovers(ctx context.Context, conn grpc.ClientConnInterface, prefix string) ([]leftoverResource, error) {
+	client := pb.NewFrontendNvmeServiceClient(conn)
+	subsystems, err := listAll(func(pageToken string) ([]*pb.NvmeSubsystem, string, error) {
+		response, err := client.ListNvmeSubsystems(ctx, &pb.ListNvmeSubsystemsRequest{PageToken: pageToken})
+		return response.GetNvmeSubsystems(), response.GetNextPageToken(), err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var leftovers []leftoverResource
+	for _, subsystem := range subsystems {
+		parentMatches := hasPrefix(subsystem.Name, prefix)
+
+		namespaces, err := listAll(func(pageToken string) ([]*pb.NvmeNamespace, string, error) {
+			response, err := client.ListNvmeNamespaces(ctx, &pb.ListNvmeNamespacesRequest{Parent: subsystem.Name, PageToken: pageToken})
+			return response.GetNvmeNamespaces(), response.GetNextPageToken(), err
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, namespace := range namespaces {
+			if parentMatches || hasPrefix(namespace.Name, prefix) {
+				leftovers = append(leftovers, leftoverResource{name: namespace.Name, cleanup: func(ctx context.Context) error {
+					_, err := client.DeleteNvmeNamespace(ctx, &pb.DeleteNvmeNamespaceRequest{Name: namespace.Name, AllowMissing: true})
+					return err
+				}})
+			}
+		}
+
+		controllers, err := listAll(func(pageToken string) ([]*pb.NvmeController, string, error) {
+			response, err := client.ListNvmeControllers(ctx, &pb.ListNvmeControllersRequest{Parent: subsystem.Name, PageToken: pageToken})
+			return response.GetNvmeControllers(), response.GetNextPageToken(), err
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, controller := range controllers {
+			if parentMatches || hasPrefix(controller.Name, prefix) {
+				leftovers = append(leftovers, leftoverResource{name: controller.Name, cleanup: func(ctx context.Context) error {
+					_, err := client.DeleteNvmeController(ctx, &pb.DeleteNvmeControllerRequest{Name: controller.Name, AllowMissing: true})
+					return err
+				}})
+			}
+		}
+
+		if parentMatches {
+			leftovers = append(leftovers, leftoverResource{name: subsystem.Name, cleanup: func(ctx context.Context) error {
+				_, err := client.DeleteNvmeSubsystem(ctx, &pb.DeleteNvmeSubsystemRequest{Name: subsystem.Name, AllowMissing: true})
+				return err
+			}})
+		}
+	}
+	return leftovers, nil
+}
+
+func findVirtioBlkLeftovers(ctx context.Context, conn grpc.ClientConnInterface, prefix string) ([]leftoverResource, error) {
+	client := pb.NewFrontendVirtioBlkServiceClient(conn)
+	blks, err := listAll(func(pageToken string) ([]*pb.VirtioBlk, string, error) {
+		response, err := client.ListVirtioBlks(ctx, &pb.ListVirtioBlksRequest{PageToken: pageToken})
+		return response.GetVirtioBlks(), response.GetNextPageToken(), err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var leftovers []leftoverResource
+	for _, blk := range blks {
+		if hasPrefix(blk.Name, prefix) {
+			leftovers = append(leftovers, leftoverResource{name: blk.Name, cleanup: func(ctx context.Context) error {
+				_, err := client.DeleteVirtioBlk(ctx, &pb.DeleteVirtioBlkRequest{Name: blk.Name, AllowMissing: true})
+				return err
+			}})
+		}
+	}
+	return leftovers, nil
+}
+
+func findVirtioScsiLeftovers(ctx context.Context, conn grpc.ClientConnInterface, prefix string) ([]leftoverResource, error) {
+	client := pb.NewFrontendVirtioScsiServiceClient(conn)
+	controllers, err := listAll(func(pageToken string) ([]*pb.VirtioScsiController, string, error) {
+		response, err := client.ListVirtioScsiControllers(ctx, &pb.ListVirtioScsiControllersRequest{Parent: "todo", PageToken: pageToken})
+		return response.GetVirtioScsiControllers(), response.GetNextPageToken(), err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var leftovers []leftoverResource
+	for _, controller := range controllers {
+		parentMatches := hasPrefix(controller.Name, prefix)
+
+		luns, err := listAll(func(pageToken string) ([]*pb.VirtioScsiLun, string, error) {
+			response, err := client.ListVirtioScsiLuns(ctx, &pb.ListVirtioScsiLunsRequest{Parent: controller.Name, PageToken: pageToken})
+			return response.GetVirtioScsiLuns(), response.GetNextPageToken(), err
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, lun := range luns {
+			if parentMatches || hasPrefix(lun.Name, prefix) {
+				leftovers = append(leftovers, leftoverResource{name: lun.Name, cleanup: func(ctx context.Context) error {
+					_, err := client.DeleteVirtioScsiLun(ctx, &pb.DeleteVirtioScsiLunRequest{Name: lun.Name, AllowMissing: true})
+					return err
+				}})
+			}
+		}
+
+		if parentMatches {
+			leftovers = append(leftovers, leftoverResource{name: controller.Name, cleanup: func(ctx context.Context) error {
+				_, err := client.DeleteVirtioScsiController(ctx, &pb.DeleteVirtioScsiControllerRequest{Name: controller.Name, AllowMissing: true})
+				return err
+			}})
+		}
+	}
+	return leftovers, nil
+}
+
+func findEncryptedVolumeLeftovers(ctx context.Context, conn grpc.ClientConnInterface, prefix string) ([]leftoverResource, error) {
+	client := pb.NewMiddleendEncryptionServiceClient(conn)
+	volumes, err := listAll(func(pageToken string) ([]*pb.EncryptedVolume, string, error) {
+		response, err := client.ListEncryptedVolumes(ctx, &pb.ListEncryptedVolumesRequest{Parent: "todo", PageToken: pageToken})
+		return response.GetEncryptedVolumes(), response.GetNextPageToken(), err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var leftovers []leftoverResource
+	for _, volume := range volumes {
+		if hasPrefix(volume.Name, prefix) {
+			leftovers = append(leftovers, leftoverResource{name: volume.Name, cleanup: func(ctx context.Context) error {
+				_, err := client.DeleteEncryptedVolume(ctx, &pb.DeleteEncryptedVolumeRequest{Name: volume.Name, AllowMissing: true})
+				return err
+			}})
+		}
+	}
+	return leftovers, nil
+}
+
+func findQosVolumeLeftovers(ctx context.Context, conn grpc.ClientConnInterface, prefix string) ([]leftoverResource, error) {
+	client := pb.NewMiddleendQosVolumeServiceClient(conn)
+	volumes, err := listAll(func(pageToken string) ([]*pb.QosVolume, string, error) {
+		response, err := client.ListQosVolumes(ctx, &pb.ListQosVolumesRequest{Parent: "todo", PageToken: pageToken})
+		return response.GetQosVolumes(), response.GetNextPageToken(), err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var leftovers []leftoverResource
+	for _, volume := range volumes {
+		if hasPrefix(volume.Name, prefix) {
+			leftovers = append(leftovers, leftoverResource{name: volume.Name, cleanup: func(ctx context.Context) error {
+				_, err := client.DeleteQosVolume(ctx, &pb.DeleteQosVolumeRequest{Name: volume.Name, AllowMissing: true})
+				return err
+			}})
+		}
+	}
+	return leftovers, nil
+}
+
+func findNvmeRemoteControllerLeftovers(ctx context.Context, conn grpc.ClientConnInterface, prefix string) ([]leftoverResource, error) {
+	client := pb.NewNvmeRemoteControllerServiceClient(conn)
+	controllers, err := listAll(func(pageToken string) ([]*pb.NvmeRemoteController, string, error) {
+		response, err := client.ListNvmeRemoteControllers(ctx, &pb.ListNvmeRemoteControllersRequest{PageToken: pageToken})
+		return response.GetNvmeRemoteControllers(), response.GetNextPageToken(), err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var leftovers []leftoverResource
+	for _, controller := range controllers {
+		parentMatches := hasPrefix(controller.Name, prefix)
+
+		paths, err := listAll(func(pageToken string) ([]*pb.NvmePath, string, error) {
+			response, err := client.ListNvmePaths(ctx, &pb.ListNvmePathsRequest{Parent: controller.Name, PageToken: pageToken})
+			return response.GetNvmePaths(), response.GetNextPageToken(), err
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, nvmePath := range paths {
+			if parentMatches || hasPrefix(nvmePath.Name, prefix) {
+				leftovers = append(leftovers, leftoverResource{name: nvmePath.Name, cleanup: func(ctx context.Context) error {
+					_, err := client.DeleteNvmePath(ctx, &pb.DeleteNvmePathRequest{Name: nvmePath.Name, AllowMissing: true})
+					return err
+				}})
+			}
+		}
+
+		if parentMatches {
+			leftovers = append(leftovers, leftoverResource{name: controller.Name, cleanup: func(ctx context.Context) error {
+				_, err := client.DeleteNvmeRemoteController(ctx, &pb.DeleteNvmeRemoteControllerRequest{Name: controller.Name, AllowMissing: true})
+				return err
+			}})
+		}
+	}
+	return leftovers, nil
+}
+
+func findNullVolumeLeftovers(ctx context.Context, conn grpc.ClientConnInterface, prefix string) ([]leftoverResource, error) {
+	client := pb.NewNullVolumeServiceClient(conn)
+	volumes, err := listAll(func(pageToken string) ([]*pb.NullVolume, string, error) {
+		response, err := client.ListNullVolumes(ctx, &pb.ListNullVolumesRequest{PageToken: pageToken})
+		return response.GetNullVolumes(), response.GetNextPageToken(), err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var leftovers []leftoverResource
+	for _, volume := range volumes {
+		if hasPrefix(volume.Name, prefix) {
+			leftovers = append(leftovers, leftoverResource{name: volume.Name, cleanup: func(ctx context.Context) error {
+				_, err := client.DeleteNullVolume(ctx, &pb.DeleteNullVolumeRequest{Name: volume.Name, AllowMissing: true})
+				return err
+			}})
+		}
+	}
+	return leftovers, nil
+}
+
+func findAioVolumeLeftovers(ctx context.Context, conn grpc.ClientConnInterface, prefix string) ([]leftoverResource, error) {
+	client := pb.NewAioVolumeServiceClient(conn)
+	volumes, err := listAll(func(pageToken string) ([]*pb.AioVolume, string, error) {
+		response, err := client.ListAioVolumes(ctx, &pb.ListAioVolumesRequest{PageToken: pageToken})
+		return response.GetAioVolumes(), response.GetNextPageToken(), err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var leftovers []leftoverResource
+	for _, volume := range volumes {
+		if hasPrefix(volume.Name, prefix) {
+			leftovers = append(leftovers, leftoverResource{name: volume.Name, cleanup: func(ctx context.Context) error {
+				_, err := client.DeleteAioVolume(ctx, &pb.DeleteAioVolumeRequest{Name: volume.Name, AllowMissing: true})
+				return err
+			}})
+		}
+	}
+	return leftovers, nil
+}
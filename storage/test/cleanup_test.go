@@ -0,0 +1,115 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (C) 2024 Intel Corporation
+
+// Package test implements compliance storage tests
+package test
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	pb "github.com/opiproject/opi-api/storage/v1alpha1/gen/go"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+func TestListAll(t *testing.T) {
+	tests := map[string]struct {
+		pages   map[string][]string
+		next    map[string]string
+		want    []string
+		wantErr bool
+	}{
+		"single page": {
+			pages: map[string][]string{"": {"a", "b"}},
+			want:  []string{"a", "b"},
+		},
+		"multiple pages": {
+			pages: map[string][]string{"": {"a"}, "1": {"b"}, "2": {"c"}},
+			next:  map[string]string{"": "1", "1": "2"},
+			want:  []string{"a", "b", "c"},
+		},
+		"repeated page token": {
+			pages:   map[string][]string{"": {"a"}, "1": {"b"}},
+			next:    map[string]string{"": "1", "1": "1"},
+			wantErr: true,
+		},
+	}
+
+	for testName, tt := range tests {
+		t.Run(testName, func(t *testing.T) {
+			all, err := listAll(func(pageToken string) ([]string, string, error) {
+				return tt.pages[pageToken], tt.next[pageToken], nil
+			})
+			require.Equal(t, tt.wantErr, err != nil)
+			require.Equal(t, tt.want, all)
+		})
+	}
+}
+
+func TestHasPrefix(t *testing.T) {
+	require.True(t, hasPrefix("nvmeSubsystems/ci-opi-nvme8", "ci-"))
+	require.True(t, hasPrefix("nvmeSubsystems/ci-opi-nvme8/namespaces/ci-opi-ns8", "ci-"))
+	require.False(t, hasPrefix("nvmeSubsystems/ci-opi-nvme8/namespaces/opi-ns8", "ci-"))
+	require.False(t, hasPrefix("ci-volumes/opi-null", "ci-"))
+}
+
+type fakeNullVolumeServer struct {
+	pb.UnimplementedNullVolumeServiceServer
+	volumes []string
+	deleted []string
+}
+
+func (s *fakeNullVolumeServer) ListNullVolumes(_ context.Context, in *pb.ListNullVolumesRequest) (*pb.ListNullVolumesResponse, error) {
+	// one volume per page
+	response := &pb.ListNullVolumesResponse{}
+	for i, name := range s.volumes {
+		if in.PageToken == "" && i == 0 || i > 0 && in.PageToken == s.volumes[i-1] {
+			response.NullVolumes = []*pb.NullVolume{{Name: name}}
+			if i < len(s.volumes)-1 {
+				response.NextPageToken = name
+			}
+		}
+	}
+	return response, nil
+}
+
+func (s *fakeNullVolumeServer) DeleteNullVolume(_ context.Context, in *pb.DeleteNullVolumeRequest) (*emptypb.Empty, error) {
+	s.deleted = append(s.deleted, in.Name)
+	return &emptypb.Empty{}, nil
+}
+
+func TestCleanup(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	server := grpc.NewServer()
+	nullVolumes := &fakeNullVolumeServer{volumes: []string{
+		"volumes/ci-opi-null1", "volumes/opi-null2", "volumes/ci-opi-null3",
+	}}
+	pb.RegisterNullVolumeServiceServer(server, nullVolumes)
+	go func() { _ = server.Serve(listener) }()
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient(listener.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+
+	report := NewReport(true)
+	require.NoError(t, Cleanup(context.Background(), conn, "ci-", report))
+	require.Equal(t, []string{"volumes/ci-opi-null1", "volumes/ci-opi-null3"}, nullVolumes.deleted)
+	require.Zero(t, report.Failures())
+
+	skipped := 0
+	for _, c := range report.Cases {
+		require.Equal(t, CleanupSuite, c.Suite)
+		if c.Skipped {
+			skipped++
+		}
+	}
+	require.Equal(t, 7, skipped)
+
+	require.Error(t, Cleanup(context.Background(), conn, "", NewReport(true)))
+}
@@ -0,0 +1,542 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (C) 2024 Intel Corporation
+
+// Package test implements compliance storage tests
+package test
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	pb "github.com/opiproject/opi-api/storage/v1alpha1/gen/go"
+	"go.einride.tech/aip/resourcename"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+)
+
+// resourceChecks are the RPCs of a resource type called by the negative and
+// pagination tests
+type resourceChecks struct {
+	kind string
+	// parent is created before the tests of a resource with a parent
+	parent *resourceChecks
+	// dependent is created below the resource to check that a resource in
+	// use can not be deleted
+	dependent *resourceChecks
+
+	name   func(parent string, id string) string
+	create func(ctx context.Context, parent string, id string) (string, error)
+	get    func(ctx context.Context, name string) error
+	update func(ctx context.Context, name string, mask *fieldmaskpb.FieldMask) error
+	delete func(ctx context.Context, name string, allowMissing bool) error
+	// list returns a page of resource names, it is only set for resource
+	// types tested by the pagination tests
+	list func(ctx context.Context, pageSize int32, pageToken string) ([]string, string, error)
+}
+
+// id returns the id of a resource created by the negative tests
+func (c *resourceChecks) id(f *Fixtures) string {
+	return f.ID("errors-" + strings.ToLower(c.kind))
+}
+
+// createTracked creates a resource, which is deleted on any exit path
+func (c *resourceChecks) createTracked(ctx context.Context, parent string, f *Fixtures, r *Report) (string, error) {
+	name, err := c.create(ctx, parent, c.id(f))
+	if err != nil {
+		return "", err
+	}
+	r.Track(name, func(ctx context.Context) error {
+		return c.delete(ctx, name, true)
+	})
+	return name, nil
+}
+
+// expectCode checks that an RPC returned the gRPC code want
+func expectCode(err error, want codes.Code) error {
+	got := status.Code(err)
+	switch {
+	case got == want:
+		return nil
+	case err == nil:
+		return fmt.Errorf("expected gRPC code %v, got %v", want, got)
+	default:
+		return fmt.Errorf("expected gRPC code %v, got %v: %v", want, got, status.Convert(err).Message())
+	}
+}
+
+// executeErrorChecks checks that a server returns NotFound for missing
+// resources, AlreadyExists for duplicate ids, InvalidArgument for malformed
+// names and field masks and FailedPrecondition when deleting a resource in use
+func executeErrorChecks(ctx context.Context, c *resourceChecks, f *Fixtures, r *Report) (err error) {
+	r.Begin(c.kind + " errors")
+	defer r.Cleanup(ctx, &err)
+
+	// pre create: parent
+	parent := ""
+	if c.parent != nil {
+		err = r.Step("Create"+c.parent.kind, func() (err error) {
+			parent, err = c.parent.createTracked(ctx, "", f, r)
+			return err
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	missing := c.name(parent, f.ID("errors-missing"))
+	err = r.Step("Get"+c.kind+" of missing resource", func() error {
+		return expectCode(c.get(ctx, missing), codes.NotFound)
+	})
+	if r.Fatal(err) {
+		return err
+	}
+	err = r.Step("Delete"+c.kind+" of missing resource", func() error {
+		return expectCode(c.delete(ctx, missing, false), codes.NotFound)
+	})
+	if r.Fatal(err) {
+		return err
+	}
+	err = r.Step("Delete"+c.kind+" of missing resource with allow_missing", func() error {
+		return expectCode(c.delete(ctx, missing, true), codes.OK)
+	})
+	if r.Fatal(err) {
+		return err
+	}
+	err = r.Step("Get"+c.kind+" with malformed name", func() error {
+		return expectCode(c.get(ctx, c.name(parent, "{malformed}")), codes.InvalidArgument)
+	})
+	if r.Fatal(err) {
+		return err
+	}
+
+	var name string
+	err = r.Step("Create"+c.kind, func() (err error) {
+		name, err = c.createTracked(ctx, parent, f, r)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	err = r.Step("Create"+c.kind+" with duplicate id", func() error {
+		_, err := c.create(ctx, parent, c.id(f))
+		return expectCode(err, codes.AlreadyExists)
+	})
+	if r.Fatal(err) {
+		return err
+	}
+	err = r.Step("Update"+c.kind+" with invalid field mask", func() error {
+		mask := &fieldmaskpb.FieldMask{Paths: []string{"no_such_field"}}
+		return expectCode(c.update(ctx, name, mask), codes.InvalidArgument)
+	})
+	if r.Fatal(err) {
+		return err
+	}
+
+	if c.dependent != nil {
+		var dependent string
+		err = r.Step("Create"+c.dependent.kind, func() (err error) {
+			dependent, err = c.dependent.createTracked(ctx, name, f, r)
+			return err
+		})
+		if err != nil {
+			return err
+		}
+		err = r.Step("Delete"+c.kind+" with "+c.dependent.kind, func() error {
+			return expectCode(c.delete(ctx, name, false), codes.FailedPrecondition)
+		})
+		if err != nil {
+			// the resource may be gone, leave it to cleanup
+			return err
+		}
+		err = r.Step("Delete"+c.dependent.kind, func() error {
+			if err := c.dependent.delete(ctx, dependent, false); err != nil {
+				return err
+			}
+			r.Untrack(dependent)
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	// post cleanup: resource
+	return r.Step("Delete"+c.kind, func() error {
+		if err := c.delete(ctx, name, false); err != nil {
+			return err
+		}
+		r.Untrack(name)
+		return nil
+	})
+}
+
+// topLevelName names resources without parent
+func topLevelName(toName func(string) string) func(string, string) string {
+	return func(_ string, id string) string {
+		return toName(id)
+	}
+}
+
+// childName names resources of a collection below parent
+func childName(collection string) func(string, string) string {
+	return func(parent string, id string) string {
+		return resourcename.Join(parent, collection, id)
+	}
+}
+
+func nvmeSubsystemChecks(c pb.FrontendNvmeServiceClient, f *Fixtures) *resourceChecks {
+	return &resourceChecks{
+		kind:      "NvmeSubsystem",
+		dependent: nvmeControllerChecks(c, f, nil),
+		name:      topLevelName(resourceIDToSubsystemName),
+		create: func(ctx context.Context, _ string, id string) (string, error) {
+			rs, err := c.CreateNvmeSubsystem(ctx, &pb.CreateNvmeSubsystemRequest{
+				NvmeSubsystemId: id,
+				NvmeSubsystem: &pb.NvmeSubsystem{
+					Spec: &pb.NvmeSubsystemSpec{
+						ModelNumber:   "OPI Model",
+						SerialNumber:  "OPI SN",
+						MaxNamespaces: 10,
+						Hostnqn:       f.Hostnqn,
+						Nqn:           f.nqn(id)}}})
+			return rs.GetName(), err
+		},
+		get: func(ctx context.Context, name string) error {
+			_, err := c.GetNvmeSubsystem(ctx, &pb.GetNvmeSubsystemRequest{Name: name})
+			return err
+		},
+		update: func(ctx context.Context, name string, mask *fieldmaskpb.FieldMask) error {
+			_, err := c.UpdateNvmeSubsystem(ctx, &pb.UpdateNvmeSubsystemRequest{
+				UpdateMask:    mask,
+				NvmeSubsystem: &pb.NvmeSubsystem{Name: name}})
+			return err
+		},
+		delete: func(ctx context.Context, name string, allowMissing bool) error {
+			_, err := c.DeleteNvmeSubsystem(ctx, &pb.DeleteNvmeSubsystemRequest{Name: name, AllowMissing: allowMissing})
+			return err
+		},
+		list: func(ctx context.Context, pageSize int32, pageToken string) ([]string, string, error) {
+			response, err := c.ListNvmeSubsystems(ctx, &pb.ListNvmeSubsystemsRequest{PageSize: pageSize, PageToken: pageToken})
+			return resourceNames(response.GetNvmeSubsystems()), response.GetNextPageToken(), err
+		},
+	}
+}
+
+func nvmeControllerChecks(c pb.FrontendNvmeServiceClient, f *Fixtures, parent *resourceChecks) *resourceChecks {
+	return &resourceChecks{
+		kind:   "NvmeController",
+		parent: parent,
+		name:   childName("nvmeControllers"),
+		create: func(ctx context.Context, parent string, id string) (string, error) {
+			rc, err := c.CreateNvmeController(ctx, &pb.CreateNvmeControllerRequest{
+				Parent:           parent,
+				NvmeControllerId: id,
+				NvmeController: &pb.NvmeController{
+					Spec: &pb.NvmeControllerSpec{
+						Endpoint:         f.controllerEndpoint(),
+						Trtype:           pb.NvmeTransportType_NVME_TRANSPORT_TYPE_TCP,
+						MaxNsq:           5,
+						MaxNcq:           6,
+						Sqes:             7,
+						Cqes:             8,
+						NvmeControllerId: proto.Int32(1)}}})
+			return rc.GetName(), err
+		},
+		get: func(ctx context.Context, name string) error {
+			_, err := c.GetNvmeController(ctx, &pb.GetNvmeControllerRequest{Name: name})
+			return err
+		},
+		update: func(ctx context.Context, name string, mask *fieldmaskpb.FieldMask) error {
+			_, err := c.UpdateNvmeController(ctx, &pb.UpdateNvmeControllerRequest{
+				UpdateMask:     mask,
+				NvmeController: &pb.NvmeController{Name: name}})
+			return err
+		},
+		delete: func(ctx context.Context, name string, allowMissing bool) error {
+			_, err := c.DeleteNvmeController(ctx, &pb.DeleteNvmeControllerRequest{Name: name, AllowMissing: allowMissing})
+			return err
+		},
+	}
+}
+
+func nvmeNamespaceChecks(c pb.FrontendNvmeServiceClient, f *Fixtures) *resourceChecks {
+	return &resourceChecks{
+		kind:   "NvmeNamespace",
+		parent: nvmeSubsystemChecks(c, f),
+		name:   childName("nvmeNamespaces"),
+		create: func(ctx context.Context, parent string, id string) (string, error) {
+			rn, err := c.CreateNvmeNamespace(ctx, &pb.CreateNvmeNamespaceRequest{
+				Parent:          parent,
+				NvmeNamespaceId: id,
+				NvmeNamespace: &pb.NvmeNamespace{
+					Spec: &pb.NvmeNamespaceSpec{
+						VolumeNameRef: f.Volume,
+						HostNsid:      1}}})
+			return rn.GetName(), err
+		},
+		get: func(ctx context.Context, name string) error {
+			_, err := c.GetNvmeNamespace(ctx, &pb.GetNvmeNamespaceRequest{Name: name})
+			return err
+		},
+		update: func(ctx context.Context, name string, mask *fieldmaskpb.FieldMask) error {
+			_, err := c.UpdateNvmeNamespace(ctx, &pb.UpdateNvmeNamespaceRequest{
+				UpdateMask:    mask,
+				NvmeNamespace: &pb.NvmeNamespace{Name: name}})
+			return err
+		},
+		delete: func(ctx context.Context, name string, allowMissing bool) error {
+			_, err := c.DeleteNvmeNamespace(ctx, &pb.DeleteNvmeNamespaceRequest{Name: name, AllowMissing: allowMissing})
+			return err
+		},
+	}
+}
+
+func virtioBlkChecks(c pb.FrontendVirtioBlkServiceClient, f *Fixtures) *resourceChecks {
+	return &resourceChecks{
+		kind: "VirtioBlk",
+		name: topLevelName(resourceIDToVolumeName),
+		create: func(ctx context.Context, _ string, id string) (string, error) {
+			rv, err := c.CreateVirtioBlk(ctx, &pb.CreateVirtioBlkRequest{
+				VirtioBlkId: id,
+				VirtioBlk: &pb.VirtioBlk{
+					VolumeNameRef: f.Volume,
+					PcieId:        f.VirtioBlk.endpoint(),
+				}})
+			return rv.GetName(), err
+		},
+		get: func(ctx context.Context, name string) error {
+			_, err := c.GetVirtioBlk(ctx, &pb.GetVirtioBlkRequest{Name: name})
+			return err
+		},
+		update: func(ctx context.Context, name string, mask *fieldmaskpb.FieldMask) error {
+			_, err := c.UpdateVirtioBlk(ctx, &pb.UpdateVirtioBlkRequest{
+				UpdateMask: mask,
+				VirtioBlk:  &pb.VirtioBlk{Name: name}})
+			return err
+		},
+		delete: func(ctx context.Context, name string, allowMissing bool) error {
+			_, err := c.DeleteVirtioBlk(ctx, &pb.DeleteVirtioBlkRequest{Name: name, AllowMissing: allowMissing})
+			return err
+		},
+	}
+}
+
+func virtioScsiControllerChecks(c pb.FrontendVirtioScsiServiceClient, f *Fixtures) *resourceChecks {
+	return &resourceChecks{
+		kind: "VirtioScsiController",
+		name: topLevelName(resourceIDToVolumeName),
+		create: func(ctx context.Context, _ string, id string) (string, error) {
+			rss, err := c.CreateVirtioScsiController(ctx, &pb.CreateVirtioScsiControllerRequest{
+				VirtioScsiControllerId: id,
+				VirtioScsiController: &pb.VirtioScsiController{
+					PcieId: f.VirtioScsi.endpoint(),
+				}})
+			return rss.GetName(), err
+		},
+		get: func(ctx context.Context, name string) error {
+			_, err := c.GetVirtioScsiController(ctx, &pb.GetVirtioScsiControllerRequest{Name: name})
+			return err
+		},
+		update: func(ctx context.Context, name string, mask *fieldmaskpb.FieldMask) error {
+			_, err := c.UpdateVirtioScsiController(ctx, &pb.UpdateVirtioScsiControllerRequest{
+				UpdateMask:           mask,
+				VirtioScsiController: &pb.VirtioScsiController{Name: name}})
+			return err
+		},
+		delete: func(ctx context.Context, name string, allowMissing bool) error {
+			_, err := c.DeleteVirtioScsiController(ctx, &pb.DeleteVirtioScsiControllerRequest{Name: name, AllowMissing: allowMissing})
+			return err
+		},
+	}
+}
+
+func virtioScsiLunChecks(c pb.FrontendVirtioScsiServiceClient, f *Fixtures) *resourceChecks {
+	controller := virtioScsiControllerChecks(c, f)
+	return &resourceChecks{
+		kind:   "VirtioScsiLun",
+		parent: controller,
+		// luns reference their controller instead of being named below it
+		name: topLevelName(resourceIDToVolumeName),
+		create: func(ctx context.Context, _ string, id string) (string, error) {
+			rl, err := c.CreateVirtioScsiLun(ctx, &pb.CreateVirtioScsiLunRequest{
+				VirtioScsiLunId: id,
+				VirtioScsiLun: &pb.VirtioScsiLun{
+					TargetNameRef: controller.id(f),
+					VolumeNameRef: f.Volume,
+				}})
+			return rl.GetName(), err
+		},
+		get: func(ctx context.Context, name string) error {
+			_, err := c.GetVirtioScsiLun(ctx, &pb.GetVirtioScsiLunRequest{Name: name})
+			return err
+		},
+		update: func(ctx context.Context, name string, mask *fieldmaskpb.FieldMask) error {
+			_, err := c.UpdateVirtioScsiLun(ctx, &pb.UpdateVirtioScsiLunRequest{
+				UpdateMask:    mask,
+				VirtioScsiLun: &pb.VirtioScsiLun{Name: name}})
+			return err
+		},
+		delete: func(ctx context.Context, name string, allowMissing bool) error {
+			_, err := c.DeleteVirtioScsiLun(ctx, &pb.DeleteVirtioScsiLunRequest{Name: name, AllowMissing: allowMissing})
+			return err
+		},
+	}
+}
+
+func nvmeRemoteControllerChecks(c pb.NvmeRemoteControllerServiceClient) *resourceChecks {
+	return &resourceChecks{
+		kind: "NvmeRemoteController",
+		name: topLevelName(resourceIDToRemoteControllerName),
+		create: func(ctx context.Context, _ string, id string) (string, error) {
+			rr, err := c.CreateNvmeRemoteController(ctx, &pb.CreateNvmeRemoteControllerRequest{
+				NvmeRemoteControllerId: id,
+				NvmeRemoteController: &pb.NvmeRemoteController{
+					Multipath: pb.NvmeMultipath_NVME_MULTIPATH_MULTIPATH,
+					Tcp:       &pb.TcpController{},
+				}})
+			return rr.GetName(), err
+		},
+		get: func(ctx context.Context, name string) error {
+			_, err := c.GetNvmeRemoteController(ctx, &pb.GetNvmeRemoteControllerRequest{Name: name})
+			return err
+		},
+		update: func(ctx context.Context, name string, mask *fieldmaskpb.FieldMask) error {
+			_, err := c.UpdateNvmeRemoteController(ctx, &pb.UpdateNvmeRemoteControllerRequest{
+				UpdateMask:           mask,
+				NvmeRemoteController: &pb.NvmeRemoteController{Name: name}})
+			return err
+		},
+		delete: func(ctx context.Context, name string, allowMissing bool) error {
+			_, err := c.DeleteNvmeRemoteController(ctx, &pb.DeleteNvmeRemoteControllerRequest{Name: name, AllowMissing: allowMissing})
+			return err
+		},
+		list: func(ctx context.Context, pageSize int32, pageToken string) ([]string, string, error) {
+			response, err := c.ListNvmeRemoteControllers(ctx, &pb.ListNvmeRemoteControllersRequest{PageSize: pageSize, PageToken: pageToken})
+			return resourceNames(response.GetNvmeRemoteControllers()), response.GetNextPageToken(), err
+		},
+	}
+}
+
+func nullVolumeChecks(c pb.NullVolumeServiceClient) *resourceChecks {
+	return &resourceChecks{
+		kind: "NullVolume",
+		name: topLevelName(resourceIDToVolumeName),
+		create: func(ctx context.Context, _ string, id string) (string, error) {
+			rs, err := c.CreateNullVolume(ctx, &pb.CreateNullVolumeRequest{
+				NullVolumeId: id,
+				NullVolume:   &pb.NullVolume{BlockSize: 512, BlocksCount: 64}})
+			return rs.GetName(), err
+		},
+		get: func(ctx context.Context, name string) error {
+			_, err := c.GetNullVolume(ctx, &pb.GetNullVolumeRequest{Name: name})
+			return err
+		},
+		update: func(ctx context.Context, name string, mask *fieldmaskpb.FieldMask) error {
+			_, err := c.UpdateNullVolume(ctx, &pb.UpdateNullVolumeRequest{
+				UpdateMask: mask,
+				NullVolume: &pb.NullVolume{Name: name, BlockSize: 512, BlocksCount: 64}})
+			return err
+		},
+		delete: func(ctx context.Context, name string, allowMissing bool) error {
+			_, err := c.DeleteNullVolume(ctx, &pb.DeleteNullVolumeRequest{Name: name, AllowMissing: allowMissing})
+			return err
+		},
+		list: func(ctx context.Context, pageSize int32, pageToken string) ([]string, string, error) {
+			response, err := c.ListNullVolumes(ctx, &pb.ListNullVolumesRequest{PageSize: pageSize, PageToken: pageToken})
+			return resourceNames(response.GetNullVolumes()), response.GetNextPageToken(), err
+		},
+	}
+}
+
+func aioVolumeChecks(c pb.AioVolumeServiceClient, f *Fixtures) *resourceChecks {
+	return &resourceChecks{
+		kind: "AioVolume",
+		name: topLevelName(resourceIDToVolumeName),
+		create: func(ctx context.Context, _ string, id string) (string, error) {
+			ra, err := c.CreateAioVolume(ctx, &pb.CreateAioVolumeRequest{
+				AioVolumeId: id,
+				AioVolume:   &pb.AioVolume{BlockSize: 512, BlocksCount: 12, Filename: f.AioFilename}})
+			return ra.GetName(), err
+		},
+		get: func(ctx context.Context, name string) error {
+			_, err := c.GetAioVolume(ctx, &pb.GetAioVolumeRequest{Name: name})
+			return err
+		},
+		update: func(ctx context.Context, name string, mask *fieldmaskpb.FieldMask) error {
+			_, err := c.UpdateAioVolume(ctx, &pb.UpdateAioVolumeRequest{
+				UpdateMask: mask,
+				AioVolume:  &pb.AioVolume{Name: name, BlockSize: 512, BlocksCount: 12, Filename: f.AioFilename}})
+			return err
+		},
+		delete: func(ctx context.Context, name string, allowMissing bool) error {
+			_, err := c.DeleteAioVolume(ctx, &pb.DeleteAioVolumeRequest{Name: name, AllowMissing: allowMissing})
+			return err
+		},
+	}
+}
+
+func encryptedVolumeChecks(c pb.MiddleendEncryptionServiceClient, f *Fixtures) *resourceChecks {
+	return &resourceChecks{
+		kind: "EncryptedVolume",
+		name: topLevelName(resourceIDToVolumeName),
+		create: func(ctx context.Context, _ string, id string) (string, error) {
+			rs, err := c.CreateEncryptedVolume(ctx, &pb.CreateEncryptedVolumeRequest{
+				EncryptedVolumeId: id,
+				EncryptedVolume: &pb.EncryptedVolume{
+					VolumeNameRef: f.Volume,
+					Key:           []byte("0123456789abcdef0123456789abcdee"),
+					Cipher:        pb.EncryptionType_ENCRYPTION_TYPE_AES_XTS_128,
+				}})
+			return rs.GetName(), err
+		},
+		get: func(ctx context.Context, name string) error {
+			_, err := c.GetEncryptedVolume(ctx, &pb.GetEncryptedVolumeRequest{Name: name})
+			return err
+		},
+		update: func(ctx context.Context, name string, mask *fieldmaskpb.FieldMask) error {
+			_, err := c.UpdateEncryptedVolume(ctx, &pb.UpdateEncryptedVolumeRequest{
+				UpdateMask:      mask,
+				EncryptedVolume: &pb.EncryptedVolume{Name: name}})
+			return err
+		},
+		delete: func(ctx context.Context, name string, allowMissing bool) error {
+			_, err := c.DeleteEncryptedVolume(ctx, &pb.DeleteEncryptedVolumeRequest{Name: name, AllowMissing: allowMissing})
+			return err
+		},
+	}
+}
+
+func qosVolumeChecks(c pb.MiddleendQosVolumeServiceClient, f *Fixtures) *resourceChecks {
+	return &resourceChecks{
+		kind: "QosVolume",
+		name: topLevelName(resourceIDToVolumeName),
+		create: func(ctx context.Context, _ string, id string) (string, error) {
+			rs, err := c.CreateQosVolume(ctx, &pb.CreateQosVolumeRequest{
+				QosVolumeId: id,
+				QosVolume: &pb.QosVolume{
+					VolumeNameRef: f.Volume,
+					Limits: &pb.Limits{
+						Max: &pb.QosLimit{RwBandwidthMbs: 2},
+					},
+				}})
+			return rs.GetName(), err
+		},
+		get: func(ctx context.Context, name string) error {
+			_, err := c.GetQosVolume(ctx, &pb.GetQosVolumeRequest{Name: name})
+			return err
+		},
+		update: func(ctx context.Context, name string, mask *fieldmaskpb.FieldMask) error {
+			_, err := c.UpdateQosVolume(ctx, &pb.UpdateQosVolumeRequest{
+				UpdateMask: mask,
+				QosVolume:  &pb.QosVolume{Name: name}})
+			return err
+		},
+		delete: func(ctx context.Context, name string, allowMissing bool) error {
+			_, err := c.DeleteQosVolume(ctx, &pb.DeleteQosVolumeRequest{Name: name, AllowMissing: allowMissing})
+			return err
+		},
+	}
+}
@@ -0,0 +1,139 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (C) 2024 Intel Corporation
+
+// Package test implements compliance storage tests
+package test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.einride.tech/aip/resourcename"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+)
+
+func TestExpectCode(t *testing.T) {
+	tests := map[string]struct {
+		err     error
+		want    codes.Code
+		wantErr string
+	}{
+		"expected code": {
+			err:  status.Error(codes.NotFound, "missing"),
+			want: codes.NotFound,
+		},
+		"expected success": {
+			want: codes.OK,
+		},
+		"unexpected code": {
+			err:     status.Error(codes.Internal, "boom"),
+			want:    codes.NotFound,
+			wantErr: "expected gRPC code NotFound, got Internal: boom",
+		},
+		"unexpected success": {
+			want:    codes.AlreadyExists,
+			wantErr: "expected gRPC code AlreadyExists, got OK",
+		},
+		"non gRPC error": {
+			err:     errors.New("boom"),
+			want:    codes.InvalidArgument,
+			wantErr: "expected gRPC code InvalidArgument, got Unknown: boom",
+		},
+	}
+
+	for testName, tt := range tests {
+		t.Run(testName, func(t *testing.T) {
+			err := expectCode(tt.err, tt.want)
+			if tt.wantErr == "" {
+				require.NoError(t, err)
+			} else {
+				require.EqualError(t, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// fakeErrorChecks implements resources in memory. Resources with children
+// can not be deleted.
+func fakeChecks(resources map[string]bool, allowMissingReturnsNotFound bool) *resourceChecks {
+	validate := func(name string) error {
+		if err := resourcename.Validate(name); err != nil {
+			return status.Error(codes.InvalidArgument, err.Error())
+		}
+		return nil
+	}
+	return &resourceChecks{
+		kind: "Fake",
+		name: childName("fakes"),
+		create: func(_ context.Context, parent string, id string) (string, error) {
+			name := resourcename.Join(parent, "fakes", id)
+			if resources[name] {
+				return "", status.Errorf(codes.AlreadyExists, "%v exists", name)
+			}
+			resources[name] = true
+			return name, nil
+		},
+		get: func(_ context.Context, name string) error {
+			if err := validate(name); err != nil {
+				return err
+			}
+			if !resources[name] {
+				return status.Errorf(codes.NotFound, "unable to find %v", name)
+			}
+			return nil
+		},
+		update: func(_ context.Context, _ string, mask *fieldmaskpb.FieldMask) error {
+			return status.Errorf(codes.InvalidArgument, "invalid field mask %v", mask.Paths)
+		},
+		delete: func(_ context.Context, name string, allowMissing bool) error {
+			if !resources[name] {
+				if allowMissing && !allowMissingReturnsNotFound {
+					return nil
+				}
+				return status.Errorf(codes.NotFound, "unable to find %v", name)
+			}
+			for child := range resources {
+				if len(child) > len(name) && child[:len(name)+1] == name+"/" {
+					return status.Errorf(codes.FailedPrecondition, "%v is in use", name)
+				}
+			}
+			delete(resources, name)
+			return nil
+		},
+	}
+}
+
+func TestExecuteErrorChecks(t *testing.T) {
+	tests := map[string]struct {
+		allowMissingReturnsNotFound bool
+		wantErr                     string
+	}{
+		"compliant server": {},
+		"allow_missing not supported": {
+			allowMissingReturnsNotFound: true,
+			wantErr:                     "Fake errors: DeleteFake of missing resource with allow_missing: expected gRPC code OK, got NotFound: unable to find fakes/errors-fake/fakes/errors-missing",
+		},
+	}
+
+	for testName, tt := range tests {
+		t.Run(testName, func(t *testing.T) {
+			resources := map[string]bool{}
+			checks := fakeChecks(resources, tt.allowMissingReturnsNotFound)
+			checks.parent = fakeChecks(resources, false)
+			checks.dependent = fakeChecks(resources, false)
+
+			report := NewReport(true)
+			require.NoError(t, executeErrorChecks(context.Background(), checks, DefaultFixtures(), report))
+			if tt.wantErr == "" {
+				require.NoError(t, report.Err())
+			} else {
+				require.EqualError(t, report.Err(), tt.wantErr)
+			}
+			require.Empty(t, resources)
+		})
+	}
+}
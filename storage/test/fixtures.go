@@ -0,0 +1,167 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (C) 2024 Intel Corporation
+
+// Package test implements compliance storage tests
+package test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+
+	"github.com/opiproject/godpu/storage/nvme"
+	"github.com/opiproject/godpu/testing/report"
+	pb "github.com/opiproject/opi-api/storage/v1alpha1/gen/go"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+// PciFunction is a PCIe function of the DPU resources are exposed on
+type PciFunction struct {
+	Port             int32 `json:"port"`
+	PhysicalFunction int32 `json:"pf"`
+	VirtualFunction  int32 `json:"vf"`
+}
+
+func (p PciFunction) endpoint() *pb.PciEndpoint {
+	return &pb.PciEndpoint{
+		PortId:           wrapperspb.Int32(p.Port),
+		PhysicalFunction: wrapperspb.Int32(p.PhysicalFunction),
+		VirtualFunction:  wrapperspb.Int32(p.VirtualFunction),
+	}
+}
+
+// Target is the remote nvme/tcp subsystem the backend tests connect to
+type Target struct {
+	// Addr is the ip address or host name of the target
+	Addr string `json:"addr"`
+	Port int    `json:"port"`
+	Nqn  string `json:"nqn"`
+	// TLS enables tests of the target over a TLS secure channel on TLSPort
+	TLS     bool `json:"tls"`
+	TLSPort int  `json:"tls_port"`
+	// PskFile is the file the TLS pre-shared key is read from by
+	// ReadPskFile, replacing Psk
+	PskFile string `json:"psk_file"`
+	Psk     []byte `json:"-"`
+}
+
+// ReadPskFile sets Psk to the key in PskFile if it is set
+func (t *Target) ReadPskFile() error {
+	if t.PskFile == "" {
+		return nil
+	}
+	psk, err := nvme.ReadPskFile(t.PskFile)
+	if err != nil {
+		return err
+	}
+	t.Psk = psk
+	return nil
+}
+
+// Fixtures are the resources and addresses used by the compliance tests
+type Fixtures struct {
+	report.ResourceIDs
+	// Volume is an existing volume referenced by frontend and middleend
+	// resources
+	Volume string `json:"volume"`
+	// Hostnqn is the nqn of the host connecting to subsystems
+	Hostnqn string `json:"hostnqn"`
+	// NqnPrefix is prepended to the ids of created subsystems to form
+	// their nqn
+	NqnPrefix string `json:"nqn_prefix"`
+	// ControllerAddr and ControllerPort are the address created nvme
+	// controllers listen on
+	ControllerAddr string      `json:"controller_addr"`
+	ControllerPort int         `json:"controller_port"`
+	VirtioBlk      PciFunction `json:"virtio_blk"`
+	VirtioScsi     PciFunction `json:"virtio_scsi"`
+	AioFilename    string      `json:"aio_filename"`
+	Target         Target      `json:"target"`
+}
+
+// DefaultFixtures returns fixtures matching the spdk target and OPI bridge of
+// docker-compose.yml
+func DefaultFixtures() *Fixtures {
+	return &Fixtures{
+		Volume:         "Malloc1",
+		Hostnqn:        "nqn.2014-08.org.nvmexpress:uuid:feb98abe-d51f-40c8-b348-2753f3571d3c",
+		NqnPrefix:      "nqn.2022-09.io.spdk:",
+		ControllerAddr: "127.0.0.1",
+		ControllerPort: 4421,
+		VirtioBlk:      PciFunction{Port: 0, PhysicalFunction: 1, VirtualFunction: 0},
+		VirtioScsi:     PciFunction{Port: 3, PhysicalFunction: 1, VirtualFunction: 2},
+		AioFilename:    "/tmp/aio_bdev_file",
+		Target: Target{
+			Addr:    "spdk",
+			Port:    4444,
+			Nqn:     "nqn.2016-06.io.spdk:cnode1",
+			TLS:     true,
+			TLSPort: 5555,
+			Psk:     []byte("NVMeTLSkey-1:01:MDAxMTIyMzM0NDU1NjY3Nzg4OTlhYWJiY2NkZGVlZmZwJEiQ:"),
+		},
+	}
+}
+
+// LoadFixtures reads fixtures from a JSON file. Fields missing in the file
+// keep their DefaultFixtures value. The psk is read from the psk file of the
+// target if the file sets one.
+func LoadFixtures(path string) (*Fixtures, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read fixtures: %w", err)
+	}
+
+	fixtures := DefaultFixtures()
+	if err := json.Unmarshal(data, fixtures); err != nil {
+		return nil, fmt.Errorf("failed to parse fixtures %v: %w", path, err)
+	}
+	if err := fixtures.Target.ReadPskFile(); err != nil {
+		return nil, err
+	}
+
+	return fixtures, fixtures.Validate()
+}
+
+// Validate checks that fixtures can be used to run the tests
+func (f *Fixtures) Validate() error {
+	switch {
+	case f.Volume == "":
+		return errors.New("volume is required")
+	case net.ParseIP(f.ControllerAddr) == nil:
+		return fmt.Errorf("invalid controller address: %v", f.ControllerAddr)
+	case f.NqnPrefix == "":
+		return errors.New("nqn prefix is required")
+	case f.Target.Addr == "":
+		return errors.New("target address is required")
+	case f.Target.Nqn == "":
+		return errors.New("target nqn is required")
+	case f.Target.TLS && len(f.Target.Psk) == 0:
+		return errors.New("target psk is required for TLS tests")
+	}
+	for _, port := range []int{f.ControllerPort, f.Target.Port, f.Target.TLSPort} {
+		if port <= 0 || port > 65535 {
+			return fmt.Errorf("invalid port: %v", port)
+		}
+	}
+	return nil
+}
+
+// nqn returns the nqn of a created subsystem
+func (f *Fixtures) nqn(subsystemID string) string {
+	return f.NqnPrefix + subsystemID
+}
+
+// controllerEndpoint returns the fabrics endpoint of created nvme controllers
+func (f *Fixtures) controllerEndpoint() *pb.NvmeControllerSpec_FabricsId {
+	adrfam, _ := nvme.AddressFamily(net.ParseIP(f.ControllerAddr))
+	return &pb.NvmeControllerSpec_FabricsId{
+		FabricsId: &pb.FabricsEndpoint{
+			Traddr:  f.ControllerAddr,
+			Trsvcid: strconv.Itoa(f.ControllerPort),
+			Adrfam:  adrfam,
+		},
+	}
+}
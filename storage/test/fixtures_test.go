@@ -0,0 +1,113 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (C) 2024 Intel Corporation
+
+// Package test implements compliance storage tests
+package test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	pb "github.com/opiproject/opi-api/storage/v1alpha1/gen/go"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadFixtures(t *testing.T) {
+	tests := map[string]struct {
+		giveJSON string
+		want     func(*Fixtures)
+		wantErr  string
+	}{
+		"empty file keeps defaults": {
+			giveJSON: `{}`,
+			want:     func(*Fixtures) {},
+		},
+		"overrides": {
+			giveJSON: `{
+				"prefix": "ci-",
+				"suffix": "-1",
+				"controller_addr": "fd00::1",
+				"virtio_blk": {"port": 1, "pf": 2, "vf": 3},
+				"target": {"addr": "10.0.0.1", "nqn": "nqn.2024-01.io.opi:target", "tls": false}
+			}`,
+			want: func(f *Fixtures) {
+				f.Prefix = "ci-"
+				f.Suffix = "-1"
+				f.ControllerAddr = "fd00::1"
+				f.VirtioBlk = PciFunction{Port: 1, PhysicalFunction: 2, VirtualFunction: 3}
+				f.Target.Addr = "10.0.0.1"
+				f.Target.Nqn = "nqn.2024-01.io.opi:target"
+				f.Target.TLS = false
+			},
+		},
+		"invalid json": {
+			giveJSON: `{"prefix": 1}`,
+			wantErr:  "failed to parse fixtures",
+		},
+		"invalid controller address": {
+			giveJSON: `{"controller_addr": "localhost"}`,
+			wantErr:  "invalid controller address: localhost",
+		},
+		"invalid port": {
+			giveJSON: `{"target": {"port": 70000}}`,
+			wantErr:  "invalid port: 70000",
+		},
+	}
+
+	for testName, tt := range tests {
+		t.Run(testName, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "fixtures.json")
+			require.NoError(t, os.WriteFile(path, []byte(tt.giveJSON), 0o600))
+
+			fixtures, err := LoadFixtures(path)
+			if tt.wantErr != "" {
+				require.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+
+			want := DefaultFixtures()
+			tt.want(want)
+			require.Equal(t, want, fixtures)
+		})
+	}
+}
+
+func TestLoadFixturesPskFile(t *testing.T) {
+	dir := t.TempDir()
+	pskPath := filepath.Join(dir, "psk")
+	require.NoError(t, os.WriteFile(pskPath, []byte("NVMeTLSkey-1:01:file:\n"), 0o600))
+	path := filepath.Join(dir, "fixtures.json")
+
+	require.NoError(t, os.WriteFile(path, []byte(`{"target": {"psk_file": "`+pskPath+`"}}`), 0o600))
+	fixtures, err := LoadFixtures(path)
+	require.NoError(t, err)
+	require.Equal(t, pskPath, fixtures.Target.PskFile)
+	require.Equal(t, []byte("NVMeTLSkey-1:01:file:"), fixtures.Target.Psk)
+
+	missing := filepath.Join(dir, "missing")
+	require.NoError(t, os.WriteFile(path, []byte(`{"target": {"psk_file": "`+missing+`"}}`), 0o600))
+	_, err = LoadFixtures(path)
+	require.ErrorIs(t, err, os.ErrNotExist)
+}
+
+func TestLoadFixturesMissingFile(t *testing.T) {
+	_, err := LoadFixtures(filepath.Join(t.TempDir(), "missing.json"))
+	require.ErrorIs(t, err, os.ErrNotExist)
+}
+
+func TestFixturesIDs(t *testing.T) {
+	fixtures := DefaultFixtures()
+	fixtures.Prefix = "ci-"
+	fixtures.Suffix = "-1"
+
+	id := fixtures.ID("subsystem-test")
+	require.Equal(t, "ci-subsystem-test-1", id)
+	require.Equal(t, "nqn.2022-09.io.spdk:"+id, fixtures.nqn(id))
+
+	fixtures.ControllerAddr = "fd00::1"
+	endpoint := fixtures.controllerEndpoint().FabricsId
+	require.Equal(t, "4421", endpoint.Trsvcid)
+	require.Equal(t, pb.NvmeAddressFamily_NVME_ADDRESS_FAMILY_IPV6, endpoint.Adrfam)
+}
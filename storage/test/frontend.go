@@ -9,16 +9,13 @@ import (
 	"context"
 	"fmt"
 	"log"
-	"path"
 	"time"
 
-	"github.com/google/uuid"
 	pb "github.com/opiproject/opi-api/storage/v1alpha1/gen/go"
 	"google.golang.org/protobuf/proto"
 
 	"google.golang.org/grpc"
 	"google.golang.org/protobuf/types/known/fieldmaskpb"
-	"google.golang.org/protobuf/types/known/wrapperspb"
 )
 
 // FrontendPartition defines frontend API partition type
@@ -43,41 +40,84 @@ func DoFrontend(
 	ctx context.Context,
 	conn grpc.ClientConnInterface,
 	partitionsToTest []FrontendPartition,
+) error {
+	return RunFrontend(ctx, conn, partitionsToTest, DefaultFixtures(), NewReport(false))
+}
+
+// RunFrontend executes the front end code with fixtures and records every
+// step in report
+func RunFrontend(
+	ctx context.Context,
+	conn grpc.ClientConnInterface,
+	partitionsToTest []FrontendPartition,
+	fixtures *Fixtures,
+	report *Report,
 ) error {
 	for _, partition := range partitionsToTest {
 		switch partition {
 		case FrontendPartitionNvme:
 			nvme := pb.NewFrontendNvmeServiceClient(conn)
-			err := executeNvmeSubsystem(ctx, nvme)
-			if err != nil {
+			err := executeNvmeSubsystem(ctx, nvme, fixtures, report)
+			if report.Fatal(err) {
 				return err
 			}
-			err = executeNvmeController(ctx, nvme)
-			if err != nil {
+			err = executeNvmeSubsystemFieldMask(ctx, nvme, fixtures, report)
+			if report.Fatal(err) {
 				return err
 			}
-			err = executeNvmeNamespace(ctx, nvme)
-			if err != nil {
+			err = executeNvmeController(ctx, nvme, fixtures, report)
+			if report.Fatal(err) {
+				return err
+			}
+			err = executeNvmeNamespace(ctx, nvme, fixtures, report)
+			if report.Fatal(err) {
+				return err
+			}
+			for _, checks := range []*resourceChecks{
+				nvmeSubsystemChecks(nvme, fixtures),
+				nvmeControllerChecks(nvme, fixtures, nvmeSubsystemChecks(nvme, fixtures)),
+				nvmeNamespaceChecks(nvme, fixtures),
+			} {
+				err = executeErrorChecks(ctx, checks, fixtures, report)
+				if report.Fatal(err) {
+					return err
+				}
+			}
+			err = executePaginationChecks(ctx, nvmeSubsystemChecks(nvme, fixtures), fixtures, report)
+			if report.Fatal(err) {
 				return err
 			}
 
 		case FrontendPartitionVirtioBlk:
 			blk := pb.NewFrontendVirtioBlkServiceClient(conn)
-			err := executeVirtioBlk(ctx, blk)
-			if err != nil {
+			err := executeVirtioBlk(ctx, blk, fixtures, report)
+			if report.Fatal(err) {
+				return err
+			}
+			err = executeErrorChecks(ctx, virtioBlkChecks(blk, fixtures), fixtures, report)
+			if report.Fatal(err) {
 				return err
 			}
 
 		case FrontendPartitionScsi:
 			scsi := pb.NewFrontendVirtioScsiServiceClient(conn)
-			err := executeVirtioScsiController(ctx, scsi)
-			if err != nil {
+			err := executeVirtioScsiController(ctx, scsi, fixtures, report)
+			if report.Fatal(err) {
 				return err
 			}
-			err = executeVirtioScsiLun(ctx, scsi)
-			if err != nil {
+			err = executeVirtioScsiLun(ctx, scsi, fixtures, report)
+			if report.Fatal(err) {
 				return err
 			}
+			for _, checks := range []*resourceChecks{
+				virtioScsiControllerChecks(scsi, fixtures),
+				virtioScsiLunChecks(scsi, fixtures),
+			} {
+				err = executeErrorChecks(ctx, checks, fixtures, report)
+				if report.Fatal(err) {
+					return err
+				}
+			}
 
 		default:
 			return fmt.Errorf("unknown storage frontend partition: %v", partition)
@@ -87,260 +127,356 @@ func DoFrontend(
 	return nil
 }
 
-func executeVirtioScsiLun(ctx context.Context, c6 pb.FrontendVirtioScsiServiceClient) error {
-	log.Printf("=======================================")
-	log.Printf("Testing VirtioScsiLun")
-	log.Printf("=======================================")
-	const resourceID = "opi-virtio-scsi8"
+func executeVirtioScsiLun(ctx context.Context, c6 pb.FrontendVirtioScsiServiceClient, f *Fixtures, r *Report) (err error) {
+	r.Begin("VirtioScsiLun")
+	defer r.Cleanup(ctx, &err)
+	resourceID := f.ID("opi-virtio-scsi8")
 	// pre create: controller
-	rss1, err := c6.CreateVirtioScsiController(ctx, &pb.CreateVirtioScsiControllerRequest{
-		VirtioScsiControllerId: resourceID,
-		VirtioScsiController: &pb.VirtioScsiController{
-			Name: "",
-			PcieId: &pb.PciEndpoint{
-				PhysicalFunction: wrapperspb.Int32(1),
-				VirtualFunction:  wrapperspb.Int32(2),
-				PortId:           wrapperspb.Int32(3)},
-		}})
-	if err != nil {
-		return err
-	}
-	ctrlrName := resourceIDToVolumeName(resourceID)
-	if rss1.Name != ctrlrName {
-		return fmt.Errorf("server filled value '%s' is not matching user requested '%s'", rss1.Name, ctrlrName)
-	}
-	rl1, err := c6.CreateVirtioScsiLun(ctx, &pb.CreateVirtioScsiLunRequest{VirtioScsiLunId: resourceID, VirtioScsiLun: &pb.VirtioScsiLun{Name: "", TargetNameRef: resourceID, VolumeNameRef: "Malloc1"}})
+	var rss1 *pb.VirtioScsiController
+	err = r.Step("CreateVirtioScsiController", func() (err error) {
+		rss1, err = c6.CreateVirtioScsiController(ctx, &pb.CreateVirtioScsiControllerRequest{
+			VirtioScsiControllerId: resourceID,
+			VirtioScsiController: &pb.VirtioScsiController{
+				Name:   "",
+				PcieId: f.VirtioScsi.endpoint(),
+			}})
+		if err != nil {
+			return err
+		}
+		r.Track(rss1.Name, func(ctx context.Context) error {
+			_, err := c6.DeleteVirtioScsiController(ctx, &pb.DeleteVirtioScsiControllerRequest{Name: rss1.Name, AllowMissing: true})
+			return err
+		})
+		return verifyResourceName(rss1.Name, resourceID, resourceIDToVolumeName)
+	})
 	if err != nil {
 		return err
 	}
-	fullname := resourceIDToVolumeName(resourceID)
-	if rl1.Name != fullname {
-		return fmt.Errorf("server filled value '%s' is not matching user requested '%s'", rl1.Name, fullname)
-	}
-	log.Printf("Added VirtioScsiLun: %v", rl1)
-	rl3, err := c6.UpdateVirtioScsiLun(ctx, &pb.UpdateVirtioScsiLunRequest{
-		UpdateMask:    &fieldmaskpb.FieldMask{Paths: []string{"*"}},
-		VirtioScsiLun: &pb.VirtioScsiLun{Name: rl1.Name, TargetNameRef: resourceID, VolumeNameRef: "Malloc1"}})
+	var rl1 *pb.VirtioScsiLun
+	err = r.Step("CreateVirtioScsiLun", func() (err error) {
+		rl1, err = c6.CreateVirtioScsiLun(ctx, &pb.CreateVirtioScsiLunRequest{VirtioScsiLunId: resourceID, VirtioScsiLun: &pb.VirtioScsiLun{Name: "", TargetNameRef: resourceID, VolumeNameRef: f.Volume}})
+		if err != nil {
+			return err
+		}
+		r.Track(rl1.Name, func(ctx context.Context) error {
+			_, err := c6.DeleteVirtioScsiLun(ctx, &pb.DeleteVirtioScsiLunRequest{Name: rl1.Name, AllowMissing: true})
+			return err
+		})
+		log.Printf("Added VirtioScsiLun: %v", rl1)
+		return verifyResourceName(rl1.Name, resourceID, resourceIDToVolumeName)
+	})
 	if err != nil {
 		return err
 	}
-	log.Printf("Updated VirtioScsiLun: %v", rl3)
-	rl4, err := c6.ListVirtioScsiLuns(ctx, &pb.ListVirtioScsiLunsRequest{Parent: rl1.Name})
-	if err != nil {
+	err = r.Step("UpdateVirtioScsiLun", func() error {
+		rl3, err := c6.UpdateVirtioScsiLun(ctx, &pb.UpdateVirtioScsiLunRequest{
+			UpdateMask:    &fieldmaskpb.FieldMask{Paths: []string{"*"}},
+			VirtioScsiLun: &pb.VirtioScsiLun{Name: rl1.Name, TargetNameRef: resourceID, VolumeNameRef: f.Volume}})
+		if err != nil {
+			return err
+		}
+		log.Printf("Updated VirtioScsiLun: %v", rl3)
+		return nil
+	})
+	if r.Fatal(err) {
 		return err
 	}
-	log.Printf("Listed VirtioScsiLun: %v", rl4)
-	rl5, err := c6.GetVirtioScsiLun(ctx, &pb.GetVirtioScsiLunRequest{Name: rl1.Name})
-	if err != nil {
+	err = r.Step("ListVirtioScsiLuns", func() error {
+		rl4, err := c6.ListVirtioScsiLuns(ctx, &pb.ListVirtioScsiLunsRequest{Parent: rl1.Name})
+		if err != nil {
+			return err
+		}
+		log.Printf("Listed VirtioScsiLun: %v", rl4)
+		return nil
+	})
+	if r.Fatal(err) {
 		return err
 	}
-	log.Printf("Got VirtioScsiLun: %v", rl5.VolumeNameRef)
-	rl6, err := c6.StatsVirtioScsiLun(ctx, &pb.StatsVirtioScsiLunRequest{Name: rl1.Name})
-	if err != nil {
+	err = r.Step("GetVirtioScsiLun", func() error {
+		rl5, err := c6.GetVirtioScsiLun(ctx, &pb.GetVirtioScsiLunRequest{Name: rl1.Name})
+		if err != nil {
+			return err
+		}
+		log.Printf("Got VirtioScsiLun: %v", rl5.VolumeNameRef)
+		return nil
+	})
+	if r.Fatal(err) {
 		return err
 	}
-	log.Printf("Stats VirtioScsiLun: %v", rl6.Stats)
-	rl2, err := c6.DeleteVirtioScsiLun(ctx, &pb.DeleteVirtioScsiLunRequest{Name: rl1.Name})
-	if err != nil {
+	err = r.Step("StatsVirtioScsiLun", func() error {
+		rl6, err := c6.StatsVirtioScsiLun(ctx, &pb.StatsVirtioScsiLunRequest{Name: rl1.Name})
+		if err != nil {
+			return err
+		}
+		log.Printf("Stats VirtioScsiLun: %v", rl6.Stats)
+		return nil
+	})
+	if r.Fatal(err) {
 		return err
 	}
-	log.Printf("Deleted VirtioScsiLun: %v -> %v", rl1, rl2)
-	rss2, err := c6.DeleteVirtioScsiController(ctx, &pb.DeleteVirtioScsiControllerRequest{Name: rl1.Name})
+	err = r.Step("DeleteVirtioScsiLun", func() error {
+		rl2, err := c6.DeleteVirtioScsiLun(ctx, &pb.DeleteVirtioScsiLunRequest{Name: rl1.Name})
+		if err != nil {
+			return err
+		}
+		r.Untrack(rl1.Name)
+		log.Printf("Deleted VirtioScsiLun: %v -> %v", rl1, rl2)
+		return nil
+	})
 	if err != nil {
 		return err
 	}
-	log.Printf("Deleted VirtioScsiController: %v -> %v", rss1, rss2)
-	return err
+	return r.Step("DeleteVirtioScsiController", func() error {
+		rss2, err := c6.DeleteVirtioScsiController(ctx, &pb.DeleteVirtioScsiControllerRequest{Name: rss1.Name})
+		if err != nil {
+			return err
+		}
+		r.Untrack(rss1.Name)
+		log.Printf("Deleted VirtioScsiController: %v -> %v", rss1, rss2)
+		return nil
+	})
 }
 
-func executeVirtioScsiController(ctx context.Context, c5 pb.FrontendVirtioScsiServiceClient) error {
-	log.Printf("=======================================")
-	log.Printf("Testing VirtioScsiController")
-	log.Printf("=======================================")
+func executeVirtioScsiController(ctx context.Context, c5 pb.FrontendVirtioScsiServiceClient, f *Fixtures, r *Report) (err error) {
+	r.Begin("VirtioScsiController")
+	defer r.Cleanup(ctx, &err)
 
 	// testing with and without {resource}_id field
-	for _, resourceID := range []string{"opi-virtio-scsi8", ""} {
-		rss1, err := c5.CreateVirtioScsiController(ctx, &pb.CreateVirtioScsiControllerRequest{
-			VirtioScsiControllerId: resourceID,
-			VirtioScsiController: &pb.VirtioScsiController{
-				Name: "",
-				PcieId: &pb.PciEndpoint{
-					PhysicalFunction: wrapperspb.Int32(1),
-					VirtualFunction:  wrapperspb.Int32(2),
-					PortId:           wrapperspb.Int32(3)},
-			}})
+	for _, resourceID := range []string{f.ID("opi-virtio-scsi8"), ""} {
+		var rss1 *pb.VirtioScsiController
+		err := r.Step(caseName("CreateVirtioScsiController", resourceID), func() (err error) {
+			rss1, err = c5.CreateVirtioScsiController(ctx, &pb.CreateVirtioScsiControllerRequest{
+				VirtioScsiControllerId: resourceID,
+				VirtioScsiController: &pb.VirtioScsiController{
+					Name:   "",
+					PcieId: f.VirtioScsi.endpoint(),
+				}})
+			if err != nil {
+				return err
+			}
+			r.Track(rss1.Name, func(ctx context.Context) error {
+				_, err := c5.DeleteVirtioScsiController(ctx, &pb.DeleteVirtioScsiControllerRequest{Name: rss1.Name, AllowMissing: true})
+				return err
+			})
+			log.Printf("Added VirtioScsiController: %v", rss1)
+			return verifyResourceName(rss1.Name, resourceID, resourceIDToVolumeName)
+		})
 		if err != nil {
 			return err
 		}
-		// verify
-		newResourceID := resourceID
-		if resourceID == "" {
-			parsed, err := uuid.Parse(path.Base(rss1.Name))
+		err = r.Step(caseName("UpdateVirtioScsiController", resourceID), func() error {
+			rss3, err := c5.UpdateVirtioScsiController(ctx, &pb.UpdateVirtioScsiControllerRequest{
+				UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"*"}},
+				VirtioScsiController: &pb.VirtioScsiController{
+					Name:   rss1.Name,
+					PcieId: f.VirtioScsi.endpoint(),
+				}})
 			if err != nil {
 				return err
 			}
-			newResourceID = parsed.String()
-		}
-		fullname := resourceIDToVolumeName(newResourceID)
-		if rss1.Name != fullname {
-			return fmt.Errorf("server filled value '%s' is not matching user requested '%s'", rss1.Name, fullname)
-		}
-		log.Printf("Added VirtioScsiController: %v", rss1)
-		rss3, err := c5.UpdateVirtioScsiController(ctx, &pb.UpdateVirtioScsiControllerRequest{
-			UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"*"}},
-			VirtioScsiController: &pb.VirtioScsiController{
-				Name: rss1.Name,
-				PcieId: &pb.PciEndpoint{
-					PhysicalFunction: wrapperspb.Int32(1),
-					VirtualFunction:  wrapperspb.Int32(2),
-					PortId:           wrapperspb.Int32(3)},
-			}})
-		if err != nil {
+			log.Printf("Updated VirtioScsiController: %v", rss3)
+			return nil
+		})
+		if r.Fatal(err) {
 			return err
 		}
-		log.Printf("Updated VirtioScsiController: %v", rss3)
-		rss4, err := c5.ListVirtioScsiControllers(ctx, &pb.ListVirtioScsiControllersRequest{Parent: "todo"})
-		if err != nil {
+		err = r.Step(caseName("ListVirtioScsiControllers", resourceID), func() error {
+			rss4, err := c5.ListVirtioScsiControllers(ctx, &pb.ListVirtioScsiControllersRequest{Parent: "todo"})
+			if err != nil {
+				return err
+			}
+			log.Printf("Listed VirtioScsiControllers: %s", rss4)
+			return nil
+		})
+		if r.Fatal(err) {
 			return err
 		}
-		log.Printf("Listed VirtioScsiControllers: %s", rss4)
-		rss5, err := c5.GetVirtioScsiController(ctx, &pb.GetVirtioScsiControllerRequest{Name: rss1.Name})
-		if err != nil {
+		err = r.Step(caseName("GetVirtioScsiController", resourceID), func() error {
+			rss5, err := c5.GetVirtioScsiController(ctx, &pb.GetVirtioScsiControllerRequest{Name: rss1.Name})
+			if err != nil {
+				return err
+			}
+			log.Printf("Got VirtioScsiController: %s", rss5.Name)
+			return nil
+		})
+		if r.Fatal(err) {
 			return err
 		}
-		log.Printf("Got VirtioScsiController: %s", rss5.Name)
-		rss6, err := c5.StatsVirtioScsiController(ctx, &pb.StatsVirtioScsiControllerRequest{Name: rss1.Name})
-		if err != nil {
+		err = r.Step(caseName("StatsVirtioScsiController", resourceID), func() error {
+			rss6, err := c5.StatsVirtioScsiController(ctx, &pb.StatsVirtioScsiControllerRequest{Name: rss1.Name})
+			if err != nil {
+				return err
+			}
+			log.Printf("Stats VirtioScsiController: %s", rss6.Stats)
+			return nil
+		})
+		if r.Fatal(err) {
 			return err
 		}
-		log.Printf("Stats VirtioScsiController: %s", rss6.Stats)
-		rss2, err := c5.DeleteVirtioScsiController(ctx, &pb.DeleteVirtioScsiControllerRequest{Name: rss1.Name})
+		err = r.Step(caseName("DeleteVirtioScsiController", resourceID), func() error {
+			rss2, err := c5.DeleteVirtioScsiController(ctx, &pb.DeleteVirtioScsiControllerRequest{Name: rss1.Name})
+			if err != nil {
+				return err
+			}
+			r.Untrack(rss1.Name)
+			log.Printf("Deleted VirtioScsiController: %v -> %v", rss1, rss2)
+			return nil
+		})
 		if err != nil {
 			return err
 		}
-		log.Printf("Deleted VirtioScsiController: %v -> %v", rss1, rss2)
 	}
 	return nil
 }
 
-func executeVirtioBlk(ctx context.Context, c4 pb.FrontendVirtioBlkServiceClient) error {
-	log.Printf("=======================================")
-	log.Printf("Testing VirtioBlk")
-	log.Printf("=======================================")
+func executeVirtioBlk(ctx context.Context, c4 pb.FrontendVirtioBlkServiceClient, f *Fixtures, r *Report) (err error) {
+	r.Begin("VirtioBlk")
+	defer r.Cleanup(ctx, &err)
 
 	// testing with and without {resource}_id field
-	for _, resourceID := range []string{"opi-virtio-blk8", ""} {
-		rv1, err := c4.CreateVirtioBlk(ctx, &pb.CreateVirtioBlkRequest{
-			VirtioBlkId: resourceID,
-			VirtioBlk: &pb.VirtioBlk{
-				Name:          "",
-				VolumeNameRef: "Malloc1",
-				PcieId: &pb.PciEndpoint{
-					PhysicalFunction: wrapperspb.Int32(1),
-					VirtualFunction:  wrapperspb.Int32(0),
-					PortId:           wrapperspb.Int32(0)},
-			}})
+	for _, resourceID := range []string{f.ID("opi-virtio-blk8"), ""} {
+		var rv1 *pb.VirtioBlk
+		err := r.Step(caseName("CreateVirtioBlk", resourceID), func() (err error) {
+			rv1, err = c4.CreateVirtioBlk(ctx, &pb.CreateVirtioBlkRequest{
+				VirtioBlkId: resourceID,
+				VirtioBlk: &pb.VirtioBlk{
+					Name:          "",
+					VolumeNameRef: f.Volume,
+					PcieId:        f.VirtioBlk.endpoint(),
+				}})
+			if err != nil {
+				return err
+			}
+			r.Track(rv1.Name, func(ctx context.Context) error {
+				_, err := c4.DeleteVirtioBlk(ctx, &pb.DeleteVirtioBlkRequest{Name: rv1.Name, AllowMissing: true})
+				return err
+			})
+			log.Printf("Added VirtioBlk: %v", rv1)
+			return verifyResourceName(rv1.Name, resourceID, resourceIDToVolumeName)
+		})
 		if err != nil {
 			return err
 		}
-		// verify
-		newResourceID := resourceID
-		if resourceID == "" {
-			parsed, err := uuid.Parse(path.Base(rv1.Name))
+		// UpdateVirtioBlk is not implemented, so no error here
+		r.OptionalStep(caseName("UpdateVirtioBlk", resourceID), func() error {
+			rv3, err := c4.UpdateVirtioBlk(ctx, &pb.UpdateVirtioBlkRequest{
+				UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"*"}},
+				VirtioBlk:  &pb.VirtioBlk{Name: rv1.Name}})
 			if err != nil {
 				return err
 			}
-			newResourceID = parsed.String()
-		}
-		fullname := resourceIDToVolumeName(newResourceID)
-		if rv1.Name != fullname {
-			return fmt.Errorf("server filled value '%s' is not matching user requested '%s'", rv1.Name, fullname)
-		}
-		log.Printf("Added VirtioBlk: %v", rv1)
-		rv3, err := c4.UpdateVirtioBlk(ctx, &pb.UpdateVirtioBlkRequest{
-			UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"*"}},
-			VirtioBlk:  &pb.VirtioBlk{Name: rv1.Name}})
-		if err != nil {
-			// UpdateVirtioBlk is not implemented, so no error here
-			log.Printf("could not update VirtioBlk: %v", err)
-		}
-		log.Printf("Updated VirtioBlk: %v", rv3)
-		rv4, err := c4.ListVirtioBlks(ctx, &pb.ListVirtioBlksRequest{})
-		if err != nil {
+			log.Printf("Updated VirtioBlk: %v", rv3)
+			return nil
+		})
+		err = r.Step(caseName("ListVirtioBlks", resourceID), func() error {
+			rv4, err := c4.ListVirtioBlks(ctx, &pb.ListVirtioBlksRequest{})
+			if err != nil {
+				return err
+			}
+			log.Printf("Listed VirtioBlks: %v", rv4)
+			return nil
+		})
+		if r.Fatal(err) {
 			return err
 		}
-		log.Printf("Listed VirtioBlks: %v", rv4)
-		rv5, err := c4.GetVirtioBlk(ctx, &pb.GetVirtioBlkRequest{Name: rv1.Name})
-		if err != nil {
+		err = r.Step(caseName("GetVirtioBlk", resourceID), func() error {
+			rv5, err := c4.GetVirtioBlk(ctx, &pb.GetVirtioBlkRequest{Name: rv1.Name})
+			if err != nil {
+				return err
+			}
+			log.Printf("Got VirtioBlk: %v", rv5.Name)
+			return nil
+		})
+		if r.Fatal(err) {
 			return err
 		}
-		log.Printf("Got VirtioBlk: %v", rv5.Name)
-		rv6, err := c4.StatsVirtioBlk(ctx, &pb.StatsVirtioBlkRequest{Name: rv1.Name})
-		if err != nil {
-			// VirtioBlkStats is not implemented, so no error here
-			log.Printf("could not stats VirtioBlk: %v", err)
-		}
-		log.Printf("Stats VirtioBlk: %v", rv6)
-		rv2, err := c4.DeleteVirtioBlk(ctx, &pb.DeleteVirtioBlkRequest{Name: rv1.Name})
+		// VirtioBlkStats is not implemented, so no error here
+		r.OptionalStep(caseName("StatsVirtioBlk", resourceID), func() error {
+			rv6, err := c4.StatsVirtioBlk(ctx, &pb.StatsVirtioBlkRequest{Name: rv1.Name})
+			if err != nil {
+				return err
+			}
+			log.Printf("Stats VirtioBlk: %v", rv6)
+			return nil
+		})
+		err = r.Step(caseName("DeleteVirtioBlk", resourceID), func() error {
+			rv2, err := c4.DeleteVirtioBlk(ctx, &pb.DeleteVirtioBlkRequest{Name: rv1.Name})
+			if err != nil {
+				return err
+			}
+			r.Untrack(rv1.Name)
+			log.Printf("Deleted VirtioBlk: %v -> %v", rv1, rv2)
+			return nil
+		})
 		if err != nil {
 			return err
 		}
-		log.Printf("Deleted VirtioBlk: %v -> %v", rv1, rv2)
 	}
 	return nil
 }
 
-func executeNvmeNamespace(ctx context.Context, c2 pb.FrontendNvmeServiceClient) error {
-	log.Printf("=======================================")
-	log.Printf("Testing NvmeNamespace")
-	log.Printf("=======================================")
+func executeNvmeNamespace(ctx context.Context, c2 pb.FrontendNvmeServiceClient, f *Fixtures, r *Report) (err error) {
+	r.Begin("NvmeNamespace")
+	defer r.Cleanup(ctx, &err)
+	ssResourceID := f.ID("namespace-test-ss")
+	ctrlrResourceID := f.ID("namespace-test-ctrler")
 
 	// pre create: subsystem
-	rs1, err := c2.CreateNvmeSubsystem(ctx, &pb.CreateNvmeSubsystemRequest{
-		NvmeSubsystemId: "namespace-test-ss",
-		NvmeSubsystem: &pb.NvmeSubsystem{
-			Spec: &pb.NvmeSubsystemSpec{
-				ModelNumber:   "OPI Model",
-				SerialNumber:  "OPI SN",
-				MaxNamespaces: 10,
-				Hostnqn:       "nqn.2014-08.org.nvmexpress:uuid:feb98abe-d51f-40c8-b348-2753f3571d3c",
-				Nqn:           "nqn.2022-09.io.spdk:opi1"}}})
+	var rs1 *pb.NvmeSubsystem
+	err = r.Step("CreateNvmeSubsystem", func() (err error) {
+		rs1, err = c2.CreateNvmeSubsystem(ctx, &pb.CreateNvmeSubsystemRequest{
+			NvmeSubsystemId: ssResourceID,
+			NvmeSubsystem: &pb.NvmeSubsystem{
+				Spec: &pb.NvmeSubsystemSpec{
+					ModelNumber:   "OPI Model",
+					SerialNumber:  "OPI SN",
+					MaxNamespaces: 10,
+					Hostnqn:       f.Hostnqn,
+					Nqn:           f.nqn(ssResourceID)}}})
+		if err != nil {
+			return err
+		}
+		r.Track(rs1.Name, func(ctx context.Context) error {
+			_, err := c2.DeleteNvmeSubsystem(ctx, &pb.DeleteNvmeSubsystemRequest{Name: rs1.Name, AllowMissing: true})
+			return err
+		})
+		log.Printf("Added NvmeSubsystem: %v", rs1)
+		return verifyResourceName(rs1.Name, ssResourceID, resourceIDToSubsystemName)
+	})
 	if err != nil {
 		return err
 	}
-	ssName := resourceIDToSubsystemName("namespace-test-ss")
-	if rs1.Name != ssName {
-		return fmt.Errorf("server filled value '%s' is not matching user requested '%s'", rs1.Name, ssName)
-	}
-	log.Printf("Added NvmeSubsystem: %v", rs1)
 
 	// pre create: controller
-	rc1, err := c2.CreateNvmeController(ctx, &pb.CreateNvmeControllerRequest{
-		Parent:           rs1.Name,
-		NvmeControllerId: "namespace-test-ctrler",
-		NvmeController: &pb.NvmeController{
-			Spec: &pb.NvmeControllerSpec{
-				Endpoint: &pb.NvmeControllerSpec_FabricsId{
-					FabricsId: &pb.FabricsEndpoint{
-						Traddr:  "127.0.0.1",
-						Trsvcid: "4421",
-						Adrfam:  pb.NvmeAddressFamily_NVME_ADDRESS_FAMILY_IPV4,
-					},
-				},
-				Trtype:           pb.NvmeTransportType_NVME_TRANSPORT_TYPE_TCP,
-				MaxNsq:           5,
-				MaxNcq:           6,
-				Sqes:             7,
-				Cqes:             8,
-				NvmeControllerId: proto.Int32(1)}}})
+	var rc1 *pb.NvmeController
+	err = r.Step("CreateNvmeController", func() (err error) {
+		rc1, err = c2.CreateNvmeController(ctx, &pb.CreateNvmeControllerRequest{
+			Parent:           rs1.Name,
+			NvmeControllerId: ctrlrResourceID,
+			NvmeController: &pb.NvmeController{
+				Spec: &pb.NvmeControllerSpec{
+					Endpoint:         f.controllerEndpoint(),
+					Trtype:           pb.NvmeTransportType_NVME_TRANSPORT_TYPE_TCP,
+					MaxNsq:           5,
+					MaxNcq:           6,
+					Sqes:             7,
+					Cqes:             8,
+					NvmeControllerId: proto.Int32(1)}}})
+		if err != nil {
+			return err
+		}
+		r.Track(rc1.Name, func(ctx context.Context) error {
+			_, err := c2.DeleteNvmeController(ctx, &pb.DeleteNvmeControllerRequest{Name: rc1.Name, AllowMissing: true})
+			return err
+		})
+		log.Printf("Added NvmeController: %v", rc1)
+		return verifyResourceName(rc1.Name, ctrlrResourceID, func(id string) string {
+			return resourceIDToControllerName(ssResourceID, id)
+		})
+	})
 	if err != nil {
 		return err
 	}
-	ctrlrName := resourceIDToControllerName("namespace-test-ss", "namespace-test-ctrler")
-	if rc1.Name != ctrlrName {
-		return fmt.Errorf("server filled value '%s' is not matching user requested '%s'", rc1.Name, ctrlrName)
-	}
-	log.Printf("Added NvmeController: %v", rc1)
 
 	// wait for some time for the backend to created above objects
 	time.Sleep(time.Second)
@@ -348,271 +484,363 @@ func executeNvmeNamespace(ctx context.Context, c2 pb.FrontendNvmeServiceClient)
 	// NvmeNamespace
 
 	// testing with and without {resource}_id field
-	for _, resourceID := range []string{"namespace-test", ""} {
-		rn1, err := c2.CreateNvmeNamespace(ctx, &pb.CreateNvmeNamespaceRequest{
-			Parent:          rs1.Name,
-			NvmeNamespaceId: resourceID,
-			NvmeNamespace: &pb.NvmeNamespace{
-				Spec: &pb.NvmeNamespaceSpec{
-					VolumeNameRef: "Malloc1",
-					Uuid:          "1b4e28ba-2fa1-11d2-883f-b9a761bde3fb",
-					Nguid:         "1b4e28ba-2fa1-11d2-883f-b9a761bde3fb",
-					Eui64:         1967554867335598546,
-					HostNsid:      1}}})
+	for _, resourceID := range []string{f.ID("namespace-test"), ""} {
+		var rn1 *pb.NvmeNamespace
+		err := r.Step(caseName("CreateNvmeNamespace", resourceID), func() (err error) {
+			rn1, err = c2.CreateNvmeNamespace(ctx, &pb.CreateNvmeNamespaceRequest{
+				Parent:          rs1.Name,
+				NvmeNamespaceId: resourceID,
+				NvmeNamespace: &pb.NvmeNamespace{
+					Spec: &pb.NvmeNamespaceSpec{
+						VolumeNameRef: f.Volume,
+						Uuid:          "1b4e28ba-2fa1-11d2-883f-b9a761bde3fb",
+						Nguid:         "1b4e28ba-2fa1-11d2-883f-b9a761bde3fb",
+						Eui64:         1967554867335598546,
+						HostNsid:      1}}})
+			if err != nil {
+				return err
+			}
+			r.Track(rn1.Name, func(ctx context.Context) error {
+				_, err := c2.DeleteNvmeNamespace(ctx, &pb.DeleteNvmeNamespaceRequest{Name: rn1.Name, AllowMissing: true})
+				return err
+			})
+			log.Printf("Added NvmeNamespace: %v", rn1)
+			return verifyResourceName(rn1.Name, resourceID, func(id string) string {
+				return resourceIDToNamespaceName(ssResourceID, id)
+			})
+		})
 		if err != nil {
 			return err
 		}
-		// verify
-		newResourceID := resourceID
-		if resourceID == "" {
-			parsed, err := uuid.Parse(path.Base(rn1.Name))
+		err = r.Step(caseName("UpdateNvmeNamespace", resourceID), func() error {
+			rn3, err := c2.UpdateNvmeNamespace(ctx, &pb.UpdateNvmeNamespaceRequest{
+				UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"*"}},
+				NvmeNamespace: &pb.NvmeNamespace{
+					Name: rn1.Name,
+					Spec: &pb.NvmeNamespaceSpec{
+						VolumeNameRef: f.Volume,
+						Uuid:          "1b4e28ba-2fa1-11d2-883f-b9a761bde3fb",
+						Nguid:         "1b4e28ba-2fa1-11d2-883f-b9a761bde3fb",
+						Eui64:         1967554867335598546,
+						HostNsid:      1}}})
 			if err != nil {
 				return err
 			}
-			newResourceID = parsed.String()
-		}
-		fullname := resourceIDToNamespaceName("namespace-test-ss", newResourceID)
-		if rn1.Name != fullname {
-			return fmt.Errorf("server filled value '%s' is not matching user requested '%s'", rn1.Name, fullname)
-		}
-		log.Printf("Added NvmeNamespace: %v", rn1)
-		rn3, err := c2.UpdateNvmeNamespace(ctx, &pb.UpdateNvmeNamespaceRequest{
-			UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"*"}},
-			NvmeNamespace: &pb.NvmeNamespace{
-				Name: rn1.Name,
-				Spec: &pb.NvmeNamespaceSpec{
-					VolumeNameRef: "Malloc1",
-					Uuid:          "1b4e28ba-2fa1-11d2-883f-b9a761bde3fb",
-					Nguid:         "1b4e28ba-2fa1-11d2-883f-b9a761bde3fb",
-					Eui64:         1967554867335598546,
-					HostNsid:      1}}})
-		if err != nil {
+			log.Printf("Updated NvmeNamespace: %v", rn3)
+			return nil
+		})
+		if r.Fatal(err) {
 			return err
 		}
-		log.Printf("Updated NvmeNamespace: %v", rn3)
-		rn4, err := c2.ListNvmeNamespaces(ctx, &pb.ListNvmeNamespacesRequest{Parent: rs1.Name})
-		if err != nil {
+		err = r.Step(caseName("ListNvmeNamespaces", resourceID), func() error {
+			rn4, err := c2.ListNvmeNamespaces(ctx, &pb.ListNvmeNamespacesRequest{Parent: rs1.Name})
+			if err != nil {
+				return err
+			}
+			log.Printf("Listed NvmeNamespaces: %v", rn4)
+			return nil
+		})
+		if r.Fatal(err) {
 			return err
 		}
-		log.Printf("Listed NvmeNamespaces: %v", rn4)
-		rn5, err := c2.GetNvmeNamespace(ctx, &pb.GetNvmeNamespaceRequest{Name: rn1.Name})
-		if err != nil {
+		err = r.Step(caseName("GetNvmeNamespace", resourceID), func() error {
+			rn5, err := c2.GetNvmeNamespace(ctx, &pb.GetNvmeNamespaceRequest{Name: rn1.Name})
+			if err != nil {
+				return err
+			}
+			log.Printf("Got NvmeNamespace: %v", rn5.Name)
+			return nil
+		})
+		if r.Fatal(err) {
 			return err
 		}
-		log.Printf("Got NvmeNamespace: %v", rn5.Name)
-		rn6, err := c2.StatsNvmeNamespace(ctx, &pb.StatsNvmeNamespaceRequest{Name: rn1.Name})
-		if err != nil {
+		err = r.Step(caseName("StatsNvmeNamespace", resourceID), func() error {
+			rn6, err := c2.StatsNvmeNamespace(ctx, &pb.StatsNvmeNamespaceRequest{Name: rn1.Name})
+			if err != nil {
+				return err
+			}
+			log.Printf("Stats NvmeNamespace: %v", rn6.Stats)
+			return nil
+		})
+		if r.Fatal(err) {
 			return err
 		}
-		log.Printf("Stats NvmeNamespace: %v", rn6.Stats)
-		rn2, err := c2.DeleteNvmeNamespace(ctx, &pb.DeleteNvmeNamespaceRequest{Name: rn1.Name})
+		err = r.Step(caseName("DeleteNvmeNamespace", resourceID), func() error {
+			rn2, err := c2.DeleteNvmeNamespace(ctx, &pb.DeleteNvmeNamespaceRequest{Name: rn1.Name})
+			if err != nil {
+				return err
+			}
+			r.Untrack(rn1.Name)
+			log.Printf("Deleted NvmeNamespace:  %v -> %v", rn1, rn2)
+			return nil
+		})
 		if err != nil {
 			return err
 		}
-		log.Printf("Deleted NvmeNamespace:  %v -> %v", rn1, rn2)
 	}
 
 	// post cleanup: controller
-	rc2, err := c2.DeleteNvmeController(ctx, &pb.DeleteNvmeControllerRequest{Name: rc1.Name})
+	err = r.Step("DeleteNvmeController", func() error {
+		rc2, err := c2.DeleteNvmeController(ctx, &pb.DeleteNvmeControllerRequest{Name: rc1.Name})
+		if err != nil {
+			return err
+		}
+		r.Untrack(rc1.Name)
+		log.Printf("Deleted NvmeController: %v", rc2)
+		return nil
+	})
 	if err != nil {
 		return err
 	}
-	log.Printf("Deleted NvmeController: %v", rc2)
 
 	// post cleanup: subsystem
-	rs2, err := c2.DeleteNvmeSubsystem(ctx, &pb.DeleteNvmeSubsystemRequest{Name: rs1.Name})
-	if err != nil {
-		return err
-	}
-	log.Printf("Deleted NvmeSubsystem: %v -> %v", rs1, rs2)
-	return nil
+	return r.Step("DeleteNvmeSubsystem", func() error {
+		rs2, err := c2.DeleteNvmeSubsystem(ctx, &pb.DeleteNvmeSubsystemRequest{Name: rs1.Name})
+		if err != nil {
+			return err
+		}
+		r.Untrack(rs1.Name)
+		log.Printf("Deleted NvmeSubsystem: %v -> %v", rs1, rs2)
+		return nil
+	})
 }
 
-func executeNvmeController(ctx context.Context, c2 pb.FrontendNvmeServiceClient) error {
-	log.Printf("=======================================")
-	log.Printf("Testing NvmeController")
-	log.Printf("=======================================")
+func executeNvmeController(ctx context.Context, c2 pb.FrontendNvmeServiceClient, f *Fixtures, r *Report) (err error) {
+	r.Begin("NvmeController")
+	defer r.Cleanup(ctx, &err)
+	ssResourceID := f.ID("controller-test-ss")
+
 	// pre create: subsystem
-	rs1, err := c2.CreateNvmeSubsystem(ctx, &pb.CreateNvmeSubsystemRequest{
-		NvmeSubsystemId: "controller-test-ss",
-		NvmeSubsystem: &pb.NvmeSubsystem{
-			Spec: &pb.NvmeSubsystemSpec{
-				ModelNumber:   "OPI Model",
-				SerialNumber:  "OPI SN",
-				MaxNamespaces: 10,
-				Hostnqn:       "nqn.2014-08.org.nvmexpress:uuid:feb98abe-d51f-40c8-b348-2753f3571d3c",
-				Nqn:           "nqn.2022-09.io.spdk:opi2"}}})
+	var rs1 *pb.NvmeSubsystem
+	err = r.Step("CreateNvmeSubsystem", func() (err error) {
+		rs1, err = c2.CreateNvmeSubsystem(ctx, &pb.CreateNvmeSubsystemRequest{
+			NvmeSubsystemId: ssResourceID,
+			NvmeSubsystem: &pb.NvmeSubsystem{
+				Spec: &pb.NvmeSubsystemSpec{
+					ModelNumber:   "OPI Model",
+					SerialNumber:  "OPI SN",
+					MaxNamespaces: 10,
+					Hostnqn:       f.Hostnqn,
+					Nqn:           f.nqn(ssResourceID)}}})
+		if err != nil {
+			return err
+		}
+		r.Track(rs1.Name, func(ctx context.Context) error {
+			_, err := c2.DeleteNvmeSubsystem(ctx, &pb.DeleteNvmeSubsystemRequest{Name: rs1.Name, AllowMissing: true})
+			return err
+		})
+		log.Printf("Added NvmeSubsystem: %v", rs1)
+		return verifyResourceName(rs1.Name, ssResourceID, resourceIDToSubsystemName)
+	})
 	if err != nil {
 		return err
 	}
-	ssName := resourceIDToSubsystemName("controller-test-ss")
-	if rs1.Name != ssName {
-		return fmt.Errorf("server filled value '%s' is not matching user requested '%s'", rs1.Name, ssName)
-	}
-	log.Printf("Added NvmeSubsystem: %v", rs1)
 
 	// NvmeController
 
 	// testing with and without {resource}_id field
-	for _, resourceID := range []string{"controller-test", ""} {
-		rc1, err := c2.CreateNvmeController(ctx, &pb.CreateNvmeControllerRequest{
-			Parent:           rs1.Name,
-			NvmeControllerId: resourceID,
-			NvmeController: &pb.NvmeController{
-				Spec: &pb.NvmeControllerSpec{
-					Endpoint: &pb.NvmeControllerSpec_FabricsId{
-						FabricsId: &pb.FabricsEndpoint{
-							Traddr:  "127.0.0.1",
-							Trsvcid: "4421",
-							Adrfam:  pb.NvmeAddressFamily_NVME_ADDRESS_FAMILY_IPV4,
-						},
-					},
-					Trtype:           pb.NvmeTransportType_NVME_TRANSPORT_TYPE_TCP,
-					MaxNsq:           5,
-					MaxNcq:           6,
-					Sqes:             7,
-					Cqes:             8,
-					NvmeControllerId: proto.Int32(1)}}})
+	for _, resourceID := range []string{f.ID("controller-test"), ""} {
+		var rc1 *pb.NvmeController
+		err := r.Step(caseName("CreateNvmeController", resourceID), func() (err error) {
+			rc1, err = c2.CreateNvmeController(ctx, &pb.CreateNvmeControllerRequest{
+				Parent:           rs1.Name,
+				NvmeControllerId: resourceID,
+				NvmeController: &pb.NvmeController{
+					Spec: &pb.NvmeControllerSpec{
+						Endpoint:         f.controllerEndpoint(),
+						Trtype:           pb.NvmeTransportType_NVME_TRANSPORT_TYPE_TCP,
+						MaxNsq:           5,
+						MaxNcq:           6,
+						Sqes:             7,
+						Cqes:             8,
+						NvmeControllerId: proto.Int32(1)}}})
+			if err != nil {
+				return err
+			}
+			r.Track(rc1.Name, func(ctx context.Context) error {
+				_, err := c2.DeleteNvmeController(ctx, &pb.DeleteNvmeControllerRequest{Name: rc1.Name, AllowMissing: true})
+				return err
+			})
+			log.Printf("Added NvmeController: %v", rc1)
+			return verifyResourceName(rc1.Name, resourceID, func(id string) string {
+				return resourceIDToControllerName(ssResourceID, id)
+			})
+		})
 		if err != nil {
 			return err
 		}
-		// verify
-		newResourceID := resourceID
-		if resourceID == "" {
-			parsed, err := uuid.Parse(path.Base(rc1.Name))
+		err = r.Step(caseName("UpdateNvmeController", resourceID), func() error {
+			rc3, err := c2.UpdateNvmeController(ctx, &pb.UpdateNvmeControllerRequest{
+				UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"*"}},
+				NvmeController: &pb.NvmeController{
+					Name: rc1.Name,
+					Spec: &pb.NvmeControllerSpec{
+						Endpoint:         f.controllerEndpoint(),
+						Trtype:           pb.NvmeTransportType_NVME_TRANSPORT_TYPE_TCP,
+						MaxNsq:           8,
+						MaxNcq:           7,
+						Sqes:             6,
+						Cqes:             5,
+						NvmeControllerId: proto.Int32(1)}}})
 			if err != nil {
 				return err
 			}
-			newResourceID = parsed.String()
-		}
-		fullname := resourceIDToControllerName("controller-test-ss", newResourceID)
-		if rc1.Name != fullname {
-			return fmt.Errorf("server filled value '%s' is not matching user requested '%s'", rc1.Name, fullname)
-		}
-		log.Printf("Added NvmeController: %v", rc1)
-		rc3, err := c2.UpdateNvmeController(ctx, &pb.UpdateNvmeControllerRequest{
-			UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"*"}},
-			NvmeController: &pb.NvmeController{
-				Name: rc1.Name,
-				Spec: &pb.NvmeControllerSpec{
-					Endpoint: &pb.NvmeControllerSpec_FabricsId{
-						FabricsId: &pb.FabricsEndpoint{
-							Traddr:  "127.0.0.1",
-							Trsvcid: "4421",
-							Adrfam:  pb.NvmeAddressFamily_NVME_ADDRESS_FAMILY_IPV4,
-						},
-					},
-					Trtype:           pb.NvmeTransportType_NVME_TRANSPORT_TYPE_TCP,
-					MaxNsq:           8,
-					MaxNcq:           7,
-					Sqes:             6,
-					Cqes:             5,
-					NvmeControllerId: proto.Int32(1)}}})
-		if err != nil {
+			log.Printf("Updated NvmeController: %v", rc3)
+			return nil
+		})
+		if r.Fatal(err) {
 			return err
 		}
-		log.Printf("Updated NvmeController: %v", rc3)
-
-		rc4, err := c2.ListNvmeControllers(ctx, &pb.ListNvmeControllersRequest{Parent: rs1.Name})
-		if err != nil {
+		err = r.Step(caseName("ListNvmeControllers", resourceID), func() error {
+			rc4, err := c2.ListNvmeControllers(ctx, &pb.ListNvmeControllersRequest{Parent: rs1.Name})
+			if err != nil {
+				return err
+			}
+			log.Printf("Listed NvmeControllers: %s", rc4)
+			return nil
+		})
+		if r.Fatal(err) {
 			return err
 		}
-		log.Printf("Listed NvmeControllers: %s", rc4)
-
-		rc5, err := c2.GetNvmeController(ctx, &pb.GetNvmeControllerRequest{Name: rc1.Name})
-		if err != nil {
+		err = r.Step(caseName("GetNvmeController", resourceID), func() error {
+			rc5, err := c2.GetNvmeController(ctx, &pb.GetNvmeControllerRequest{Name: rc1.Name})
+			if err != nil {
+				return err
+			}
+			log.Printf("Got NvmeController: %s", rc5.Name)
+			return nil
+		})
+		if r.Fatal(err) {
 			return err
 		}
-		log.Printf("Got NvmeController: %s", rc5.Name)
-
-		rc6, err := c2.StatsNvmeController(ctx, &pb.StatsNvmeControllerRequest{Name: rc1.Name})
-		if err != nil {
+		err = r.Step(caseName("StatsNvmeController", resourceID), func() error {
+			rc6, err := c2.StatsNvmeController(ctx, &pb.StatsNvmeControllerRequest{Name: rc1.Name})
+			if err != nil {
+				return err
+			}
+			log.Printf("Stats NvmeController: %s", rc6.Stats)
+			return nil
+		})
+		if r.Fatal(err) {
 			return err
 		}
-		log.Printf("Stats NvmeController: %s", rc6.Stats)
-
-		rc2, err := c2.DeleteNvmeController(ctx, &pb.DeleteNvmeControllerRequest{Name: rc1.Name})
+		err = r.Step(caseName("DeleteNvmeController", resourceID), func() error {
+			rc2, err := c2.DeleteNvmeController(ctx, &pb.DeleteNvmeControllerRequest{Name: rc1.Name})
+			if err != nil {
+				return err
+			}
+			r.Untrack(rc1.Name)
+			log.Printf("Deleted NvmeController: %v -> %v", rc1, rc2)
+			return nil
+		})
 		if err != nil {
 			return err
 		}
-		log.Printf("Deleted NvmeController: %v -> %v", rc1, rc2)
 	}
 
 	// post cleanup: subsystem
-	rs2, err := c2.DeleteNvmeSubsystem(ctx, &pb.DeleteNvmeSubsystemRequest{Name: rs1.Name})
-	if err != nil {
-		return err
-	}
-	log.Printf("Deleted NvmeSubsystem: %v -> %v", rs1, rs2)
-	return nil
+	return r.Step("DeleteNvmeSubsystem", func() error {
+		rs2, err := c2.DeleteNvmeSubsystem(ctx, &pb.DeleteNvmeSubsystemRequest{Name: rs1.Name})
+		if err != nil {
+			return err
+		}
+		r.Untrack(rs1.Name)
+		log.Printf("Deleted NvmeSubsystem: %v -> %v", rs1, rs2)
+		return nil
+	})
 }
 
-func executeNvmeSubsystem(ctx context.Context, c1 pb.FrontendNvmeServiceClient) error {
-	log.Printf("=======================================")
-	log.Printf("Testing NvmeSubsystem")
-	log.Printf("=======================================")
+func executeNvmeSubsystem(ctx context.Context, c1 pb.FrontendNvmeServiceClient, f *Fixtures, r *Report) (err error) {
+	r.Begin("NvmeSubsystem")
+	defer r.Cleanup(ctx, &err)
 
 	// testing with and without {resource}_id field
-	for _, resourceID := range []string{"subsystem-test", ""} {
-		rs1, err := c1.CreateNvmeSubsystem(ctx, &pb.CreateNvmeSubsystemRequest{
-			NvmeSubsystemId: resourceID,
-			NvmeSubsystem: &pb.NvmeSubsystem{
-				Spec: &pb.NvmeSubsystemSpec{
-					ModelNumber:   "OPI Model",
-					SerialNumber:  "OPI SN",
-					MaxNamespaces: 10,
-					Hostnqn:       "nqn.2014-08.org.nvmexpress:uuid:feb98abe-d51f-40c8-b348-2753f3571d3c",
-					Nqn:           "nqn.2022-09.io.spdk:opi3"}}})
+	for _, resourceID := range []string{f.ID("subsystem-test"), ""} {
+		var rs1 *pb.NvmeSubsystem
+		err := r.Step(caseName("CreateNvmeSubsystem", resourceID), func() (err error) {
+			rs1, err = c1.CreateNvmeSubsystem(ctx, &pb.CreateNvmeSubsystemRequest{
+				NvmeSubsystemId: resourceID,
+				NvmeSubsystem: &pb.NvmeSubsystem{
+					Spec: &pb.NvmeSubsystemSpec{
+						ModelNumber:   "OPI Model",
+						SerialNumber:  "OPI SN",
+						MaxNamespaces: 10,
+						Hostnqn:       f.Hostnqn,
+						Nqn:           f.nqn(f.ID("subsystem-test"))}}})
+			if err != nil {
+				return err
+			}
+			r.Track(rs1.Name, func(ctx context.Context) error {
+				_, err := c1.DeleteNvmeSubsystem(ctx, &pb.DeleteNvmeSubsystemRequest{Name: rs1.Name, AllowMissing: true})
+				return err
+			})
+			log.Printf("Added NvmeSubsystem: %v", rs1)
+			return verifyResourceName(rs1.Name, resourceID, resourceIDToSubsystemName)
+		})
 		if err != nil {
 			return err
 		}
-		// verify
-		newResourceID := resourceID
-		if resourceID == "" {
-			parsed, err := uuid.Parse(path.Base(rs1.Name))
+		// UpdateNvmeSubsystem is not implemented, so no error here
+		r.OptionalStep(caseName("UpdateNvmeSubsystem", resourceID), func() error {
+			rs3, err := c1.UpdateNvmeSubsystem(ctx, &pb.UpdateNvmeSubsystemRequest{
+				UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"*"}},
+				NvmeSubsystem: &pb.NvmeSubsystem{
+					Name: rs1.Name,
+					Spec: &pb.NvmeSubsystemSpec{
+						Nqn: f.nqn(f.ID("subsystem-test"))}}})
 			if err != nil {
 				return err
 			}
-			newResourceID = parsed.String()
-		}
-		fullname := resourceIDToSubsystemName(newResourceID)
-		if rs1.Name != fullname {
-			return fmt.Errorf("server filled value '%s' is not matching user requested '%s'", rs1.Name, fullname)
-		}
-		log.Printf("Added NvmeSubsystem: %v", rs1)
-		rs3, err := c1.UpdateNvmeSubsystem(ctx, &pb.UpdateNvmeSubsystemRequest{
-			UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"*"}},
-			NvmeSubsystem: &pb.NvmeSubsystem{
-				Name: rs1.Name,
-				Spec: &pb.NvmeSubsystemSpec{
-					Nqn: "nqn.2022-09.io.spdk:opi3"}}})
-		if err != nil {
-			// UpdateNvmeSubsystem is not implemented, so no error here
-			log.Printf("could not update Nvme subsystem: %v", err)
-		}
-		log.Printf("Updated UpdateNvmeSubsystem: %v", rs3)
-		rs4, err := c1.ListNvmeSubsystems(ctx, &pb.ListNvmeSubsystemsRequest{})
-		if err != nil {
+			log.Printf("Updated UpdateNvmeSubsystem: %v", rs3)
+			return nil
+		})
+		err = r.Step(caseName("ListNvmeSubsystems", resourceID), func() error {
+			rs4, err := c1.ListNvmeSubsystems(ctx, &pb.ListNvmeSubsystemsRequest{})
+			if err != nil {
+				return err
+			}
+			log.Printf("Listed UpdateNvmeSubsystems: %v", rs4)
+			return nil
+		})
+		if r.Fatal(err) {
 			return err
 		}
-		log.Printf("Listed UpdateNvmeSubsystems: %v", rs4)
-		rs5, err := c1.GetNvmeSubsystem(ctx, &pb.GetNvmeSubsystemRequest{Name: rs1.Name})
-		if err != nil {
+		err = r.Step(caseName("GetNvmeSubsystem", resourceID), func() error {
+			rs5, err := c1.GetNvmeSubsystem(ctx, &pb.GetNvmeSubsystemRequest{Name: rs1.Name})
+			if err != nil {
+				return err
+			}
+			log.Printf("Got UpdateNvmeSubsystem: %s", rs5.Spec.Nqn)
+			return nil
+		})
+		if r.Fatal(err) {
 			return err
 		}
-		log.Printf("Got UpdateNvmeSubsystem: %s", rs5.Spec.Nqn)
-		rs6, err := c1.StatsNvmeSubsystem(ctx, &pb.StatsNvmeSubsystemRequest{Name: rs1.Name})
-		if err != nil {
+		err = r.Step(caseName("StatsNvmeSubsystem", resourceID), func() error {
+			rs6, err := c1.StatsNvmeSubsystem(ctx, &pb.StatsNvmeSubsystemRequest{Name: rs1.Name})
+			if err != nil {
+				return err
+			}
+			log.Printf("Stats UpdateNvmeSubsystem: %s", rs6.Stats)
+			return nil
+		})
+		if r.Fatal(err) {
 			return err
 		}
-		log.Printf("Stats UpdateNvmeSubsystem: %s", rs6.Stats)
 
 		// post cleanup: subsystem
-		rs2, err := c1.DeleteNvmeSubsystem(ctx, &pb.DeleteNvmeSubsystemRequest{Name: rs1.Name})
+		err = r.Step(caseName("DeleteNvmeSubsystem", resourceID), func() error {
+			rs2, err := c1.DeleteNvmeSubsystem(ctx, &pb.DeleteNvmeSubsystemRequest{Name: rs1.Name})
+			if err != nil {
+				return err
+			}
+			r.Untrack(rs1.Name)
+			log.Printf("Deleted NvmeSubsystem: %v -> %v", rs1, rs2)
+			return nil
+		})
 		if err != nil {
 			return err
 		}
-		log.Printf("Deleted NvmeSubsystem: %v -> %v", rs1, rs2)
 	}
 	return nil
 }
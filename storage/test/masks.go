@@ -0,0 +1,336 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (C) 2024 Intel Corporation
+
+// Package test implements compliance storage tests
+package test
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	pb "github.com/opiproject/opi-api/storage/v1alpha1/gen/go"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+)
+
+// verifyField checks the value of a field after an update with a partial
+// field mask
+func verifyField[T comparable](path string, got T, want T) error {
+	if got != want {
+		return fmt.Errorf("field %v is %v after update, expected %v", path, got, want)
+	}
+	return nil
+}
+
+// executeNullVolumeFieldMask checks that an update with a partial field mask
+// only changes the masked fields
+func executeNullVolumeFieldMask(ctx context.Context, c1 pb.NullVolumeServiceClient, f *Fixtures, r *Report) (err error) {
+	r.Begin("NullVolume field masks")
+	defer r.Cleanup(ctx, &err)
+
+	verify := func(volume *pb.NullVolume) error {
+		if err := verifyField("blocks_count", volume.BlocksCount, 128); err != nil {
+			return err
+		}
+		return verifyField("block_size", volume.BlockSize, 512)
+	}
+
+	var rs1 *pb.NullVolume
+	err = r.Step("CreateNullVolume", func() (err error) {
+		rs1, err = c1.CreateNullVolume(ctx, &pb.CreateNullVolumeRequest{
+			NullVolumeId: f.ID("mask-null"),
+			NullVolume:   &pb.NullVolume{BlockSize: 512, BlocksCount: 64}})
+		if err != nil {
+			return err
+		}
+		r.Track(rs1.Name, func(ctx context.Context) error {
+			_, err := c1.DeleteNullVolume(ctx, &pb.DeleteNullVolumeRequest{Name: rs1.Name, AllowMissing: true})
+			return err
+		})
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	err = r.Step("UpdateNullVolume with partial field mask", func() error {
+		rs3, err := c1.UpdateNullVolume(ctx, &pb.UpdateNullVolumeRequest{
+			UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"blocks_count"}},
+			NullVolume: &pb.NullVolume{
+				Name:        rs1.Name,
+				BlockSize:   4096,
+				BlocksCount: 128,
+			}})
+		if err != nil {
+			return err
+		}
+		log.Printf("Updated Null: %v", rs3)
+		return verify(rs3)
+	})
+	if r.Fatal(err) {
+		return err
+	}
+	err = r.Step("GetNullVolume after partial update", func() error {
+		rs5, err := c1.GetNullVolume(ctx, &pb.GetNullVolumeRequest{Name: rs1.Name})
+		if err != nil {
+			return err
+		}
+		return verify(rs5)
+	})
+	if r.Fatal(err) {
+		return err
+	}
+	return r.Step("DeleteNullVolume", func() error {
+		_, err := c1.DeleteNullVolume(ctx, &pb.DeleteNullVolumeRequest{Name: rs1.Name})
+		if err != nil {
+			return err
+		}
+		r.Untrack(rs1.Name)
+		return nil
+	})
+}
+
+// executeNvmeRemoteControllerFieldMask checks that an update with a partial
+// field mask only changes the masked fields
+func executeNvmeRemoteControllerFieldMask(ctx context.Context, c4 pb.NvmeRemoteControllerServiceClient, f *Fixtures, r *Report) (err error) {
+	r.Begin("NvmeRemoteController field masks")
+	defer r.Cleanup(ctx, &err)
+
+	verify := func(controller *pb.NvmeRemoteController) error {
+		if err := verifyField("queue_size", controller.QueueSize, 128); err != nil {
+			return err
+		}
+		if err := verifyField("io_queues_count", controller.IoQueuesCount, 4); err != nil {
+			return err
+		}
+		return verifyField("multipath", controller.Multipath, pb.NvmeMultipath_NVME_MULTIPATH_MULTIPATH)
+	}
+
+	var rr0 *pb.NvmeRemoteController
+	err = r.Step("CreateNvmeRemoteController", func() (err error) {
+		rr0, err = c4.CreateNvmeRemoteController(ctx, &pb.CreateNvmeRemoteControllerRequest{
+			NvmeRemoteControllerId: f.ID("mask-nvme-remote"),
+			NvmeRemoteController: &pb.NvmeRemoteController{
+				Multipath:     pb.NvmeMultipath_NVME_MULTIPATH_MULTIPATH,
+				IoQueuesCount: 4,
+				QueueSize:     64,
+				Tcp:           &pb.TcpController{},
+			}})
+		if err != nil {
+			return err
+		}
+		r.Track(rr0.Name, func(ctx context.Context) error {
+			_, err := c4.DeleteNvmeRemoteController(ctx, &pb.DeleteNvmeRemoteControllerRequest{Name: rr0.Name, AllowMissing: true})
+			return err
+		})
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	err = r.Step("UpdateNvmeRemoteController with partial field mask", func() error {
+		rr1, err := c4.UpdateNvmeRemoteController(ctx, &pb.UpdateNvmeRemoteControllerRequest{
+			UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"queue_size"}},
+			NvmeRemoteController: &pb.NvmeRemoteController{
+				Name:          rr0.Name,
+				Multipath:     pb.NvmeMultipath_NVME_MULTIPATH_FAILOVER,
+				IoQueuesCount: 8,
+				QueueSize:     128,
+				Tcp:           &pb.TcpController{},
+			}})
+		if err != nil {
+			return err
+		}
+		log.Printf("Updated NvmeRemoteController: %v", rr1)
+		return verify(rr1)
+	})
+	if r.Fatal(err) {
+		return err
+	}
+	err = r.Step("GetNvmeRemoteController after partial update", func() error {
+		rr2, err := c4.GetNvmeRemoteController(ctx, &pb.GetNvmeRemoteControllerRequest{Name: rr0.Name})
+		if err != nil {
+			return err
+		}
+		return verify(rr2)
+	})
+	if r.Fatal(err) {
+		return err
+	}
+	return r.Step("DeleteNvmeRemoteController", func() error {
+		_, err := c4.DeleteNvmeRemoteController(ctx, &pb.DeleteNvmeRemoteControllerRequest{Name: rr0.Name})
+		if err != nil {
+			return err
+		}
+		r.Untrack(rr0.Name)
+		return nil
+	})
+}
+
+// executeNvmeSubsystemFieldMask checks that an update with a partial field
+// mask only changes the masked fields
+func executeNvmeSubsystemFieldMask(ctx context.Context, c1 pb.FrontendNvmeServiceClient, f *Fixtures, r *Report) (err error) {
+	r.Begin("NvmeSubsystem field masks")
+	defer r.Cleanup(ctx, &err)
+
+	verify := func(subsystem *pb.NvmeSubsystem) error {
+		if err := verifyField("spec.max_namespaces", subsystem.GetSpec().GetMaxNamespaces(), 20); err != nil {
+			return err
+		}
+		if err := verifyField("spec.model_number", subsystem.GetSpec().GetModelNumber(), "OPI Model"); err != nil {
+			return err
+		}
+		return verifyField("spec.serial_number", subsystem.GetSpec().GetSerialNumber(), "OPI SN")
+	}
+
+	var rs1 *pb.NvmeSubsystem
+	err = r.Step("CreateNvmeSubsystem", func() (err error) {
+		rs1, err = c1.CreateNvmeSubsystem(ctx, &pb.CreateNvmeSubsystemRequest{
+			NvmeSubsystemId: f.ID("mask-subsystem"),
+			NvmeSubsystem: &pb.NvmeSubsystem{
+				Spec: &pb.NvmeSubsystemSpec{
+					ModelNumber:   "OPI Model",
+					SerialNumber:  "OPI SN",
+					MaxNamespaces: 10,
+					Hostnqn:       f.Hostnqn,
+					Nqn:           f.nqn(f.ID("mask-subsystem"))}}})
+		if err != nil {
+			return err
+		}
+		r.Track(rs1.Name, func(ctx context.Context) error {
+			_, err := c1.DeleteNvmeSubsystem(ctx, &pb.DeleteNvmeSubsystemRequest{Name: rs1.Name, AllowMissing: true})
+			return err
+		})
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	// UpdateNvmeSubsystem is not implemented by every server, so the fields
+	// are only verified if the update succeeded
+	var rs3 *pb.NvmeSubsystem
+	r.OptionalStep("UpdateNvmeSubsystem with partial field mask", func() (err error) {
+		rs3, err = c1.UpdateNvmeSubsystem(ctx, &pb.UpdateNvmeSubsystemRequest{
+			UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"spec.max_namespaces"}},
+			NvmeSubsystem: &pb.NvmeSubsystem{
+				Name: rs1.Name,
+				Spec: &pb.NvmeSubsystemSpec{
+					ModelNumber:   "Other Model",
+					SerialNumber:  "Other SN",
+					MaxNamespaces: 20,
+					Hostnqn:       f.Hostnqn,
+					Nqn:           f.nqn(f.ID("mask-subsystem"))}}})
+		if err != nil {
+			return err
+		}
+		log.Printf("Updated NvmeSubsystem: %v", rs3)
+		return nil
+	})
+	if rs3 != nil {
+		err = r.Step("GetNvmeSubsystem after partial update", func() error {
+			if err := verify(rs3); err != nil {
+				return err
+			}
+			rs5, err := c1.GetNvmeSubsystem(ctx, &pb.GetNvmeSubsystemRequest{Name: rs1.Name})
+			if err != nil {
+				return err
+			}
+			return verify(rs5)
+		})
+		if r.Fatal(err) {
+			return err
+		}
+	}
+	return r.Step("DeleteNvmeSubsystem", func() error {
+		_, err := c1.DeleteNvmeSubsystem(ctx, &pb.DeleteNvmeSubsystemRequest{Name: rs1.Name})
+		if err != nil {
+			return err
+		}
+		r.Untrack(rs1.Name)
+		return nil
+	})
+}
+
+// executeQosVolumeFieldMask checks that an update with a partial field mask
+// only changes the masked limits
+func executeQosVolumeFieldMask(ctx context.Context, c2 pb.MiddleendQosVolumeServiceClient, f *Fixtures, r *Report) (err error) {
+	r.Begin("QosVolume field masks")
+	defer r.Cleanup(ctx, &err)
+
+	verify := func(volume *pb.QosVolume) error {
+		if err := verifyField("limits.max.rd_bandwidth_mbs", volume.GetLimits().GetMax().GetRdBandwidthMbs(), 4); err != nil {
+			return err
+		}
+		if err := verifyField("limits.max.rw_bandwidth_mbs", volume.GetLimits().GetMax().GetRwBandwidthMbs(), 2); err != nil {
+			return err
+		}
+		return verifyField("volume_name_ref", volume.GetVolumeNameRef(), f.Volume)
+	}
+
+	var rs1 *pb.QosVolume
+	err = r.Step("CreateQosVolume", func() (err error) {
+		rs1, err = c2.CreateQosVolume(ctx, &pb.CreateQosVolumeRequest{
+			QosVolumeId: f.ID("mask-qos-volume"),
+			QosVolume: &pb.QosVolume{
+				VolumeNameRef: f.Volume,
+				Limits: &pb.Limits{
+					Max: &pb.QosLimit{
+						RdBandwidthMbs: 1,
+						RwBandwidthMbs: 2,
+					},
+				},
+			},
+		})
+		if err != nil {
+			return err
+		}
+		r.Track(rs1.Name, func(ctx context.Context) error {
+			_, err := c2.DeleteQosVolume(ctx, &pb.DeleteQosVolumeRequest{Name: rs1.Name, AllowMissing: true})
+			return err
+		})
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	err = r.Step("UpdateQosVolume with partial field mask", func() error {
+		rs3, err := c2.UpdateQosVolume(ctx, &pb.UpdateQosVolumeRequest{
+			UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"limits.max.rd_bandwidth_mbs"}},
+			QosVolume: &pb.QosVolume{
+				Name:          rs1.Name,
+				VolumeNameRef: f.Volume,
+				Limits: &pb.Limits{
+					Max: &pb.QosLimit{
+						RdBandwidthMbs: 4,
+						RwBandwidthMbs: 8,
+					},
+				},
+			},
+		})
+		if err != nil {
+			return err
+		}
+		log.Printf("Updated QosVolume: %v", rs3)
+		return verify(rs3)
+	})
+	if r.Fatal(err) {
+		return err
+	}
+	err = r.Step("GetQosVolume after partial update", func() error {
+		rs5, err := c2.GetQosVolume(ctx, &pb.GetQosVolumeRequest{Name: rs1.Name})
+		if err != nil {
+			return err
+		}
+		return verify(rs5)
+	})
+	if r.Fatal(err) {
+		return err
+	}
+	return r.Step("DeleteQosVolume", func() error {
+		_, err := c2.DeleteQosVolume(ctx, &pb.DeleteQosVolumeRequest{Name: rs1.Name})
+		if err != nil {
+			return err
+		}
+		r.Untrack(rs1.Name)
+		return nil
+	})
+}
@@ -7,11 +7,8 @@ package test
 
 import (
 	"context"
-	"fmt"
 	"log"
-	"path"
 
-	"github.com/google/uuid"
 	pb "github.com/opiproject/opi-api/storage/v1alpha1/gen/go"
 
 	"google.golang.org/grpc"
@@ -20,159 +17,234 @@ import (
 
 // DoMiddleend executes the middle end code
 func DoMiddleend(ctx context.Context, conn grpc.ClientConnInterface) error {
+	return RunMiddleend(ctx, conn, DefaultFixtures(), NewReport(false))
+}
+
+// RunMiddleend executes the middle end code with fixtures and records every
+// step in report
+func RunMiddleend(ctx context.Context, conn grpc.ClientConnInterface, fixtures *Fixtures, report *Report) error {
 	encryption := pb.NewMiddleendEncryptionServiceClient(conn)
 	qos := pb.NewMiddleendQosVolumeServiceClient(conn)
-	err := executeEncryptedVolume(ctx, encryption)
-	if err != nil {
+	err := executeEncryptedVolume(ctx, encryption, fixtures, report)
+	if report.Fatal(err) {
 		return err
 	}
-	err = executeQosVolume(ctx, qos)
-	if err != nil {
+	err = executeQosVolume(ctx, qos, fixtures, report)
+	if report.Fatal(err) {
 		return err
 	}
+	err = executeQosVolumeFieldMask(ctx, qos, fixtures, report)
+	if report.Fatal(err) {
+		return err
+	}
+	for _, checks := range []*resourceChecks{
+		encryptedVolumeChecks(encryption, fixtures),
+		qosVolumeChecks(qos, fixtures),
+	} {
+		err = executeErrorChecks(ctx, checks, fixtures, report)
+		if report.Fatal(err) {
+			return err
+		}
+	}
 	return nil
 }
 
-func executeEncryptedVolume(ctx context.Context, c1 pb.MiddleendEncryptionServiceClient) error {
-	log.Printf("=======================================")
-	log.Printf("Testing NewMiddleendEncryptionServiceClient")
-	log.Printf("=======================================")
+func executeEncryptedVolume(ctx context.Context, c1 pb.MiddleendEncryptionServiceClient, f *Fixtures, r *Report) (err error) {
+	r.Begin("EncryptedVolume")
+	defer r.Cleanup(ctx, &err)
 
 	// testing with and without {resource}_id field
-	for _, resourceID := range []string{"opi-encrypted-volume3", ""} {
-		rs1, err := c1.CreateEncryptedVolume(ctx, &pb.CreateEncryptedVolumeRequest{
-			EncryptedVolumeId: resourceID,
-			EncryptedVolume: &pb.EncryptedVolume{
-				VolumeNameRef: "Malloc1",
-				Key:           []byte("0123456789abcdef0123456789abcdee"),
-				Cipher:        pb.EncryptionType_ENCRYPTION_TYPE_AES_XTS_128,
-			},
+	for _, resourceID := range []string{f.ID("opi-encrypted-volume3"), ""} {
+		var rs1 *pb.EncryptedVolume
+		err := r.Step(caseName("CreateEncryptedVolume", resourceID), func() (err error) {
+			rs1, err = c1.CreateEncryptedVolume(ctx, &pb.CreateEncryptedVolumeRequest{
+				EncryptedVolumeId: resourceID,
+				EncryptedVolume: &pb.EncryptedVolume{
+					VolumeNameRef: f.Volume,
+					Key:           []byte("0123456789abcdef0123456789abcdee"),
+					Cipher:        pb.EncryptionType_ENCRYPTION_TYPE_AES_XTS_128,
+				},
+			})
+			if err != nil {
+				return err
+			}
+			r.Track(rs1.Name, func(ctx context.Context) error {
+				_, err := c1.DeleteEncryptedVolume(ctx, &pb.DeleteEncryptedVolumeRequest{Name: rs1.Name, AllowMissing: true})
+				return err
+			})
+			log.Printf("Added EncryptedVolume: %v", rs1.Name)
+			return verifyResourceName(rs1.Name, resourceID, resourceIDToVolumeName)
 		})
 		if err != nil {
 			return err
 		}
-		// verify
-		newResourceID := resourceID
-		if resourceID == "" {
-			parsed, err := uuid.Parse(path.Base(rs1.Name))
+		err = r.Step(caseName("UpdateEncryptedVolume", resourceID), func() error {
+			rs3, err := c1.UpdateEncryptedVolume(ctx, &pb.UpdateEncryptedVolumeRequest{
+				UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"*"}},
+				EncryptedVolume: &pb.EncryptedVolume{
+					Name:          rs1.Name,
+					VolumeNameRef: f.Volume,
+					Key:           []byte("0123456789abcdef0123456789abcdff"),
+					Cipher:        pb.EncryptionType_ENCRYPTION_TYPE_AES_XTS_128,
+				},
+			})
 			if err != nil {
 				return err
 			}
-			newResourceID = parsed.String()
-		}
-		fullname := resourceIDToVolumeName(newResourceID)
-		if rs1.Name != fullname {
-			return fmt.Errorf("server filled value '%s' is not matching user requested '%s'", rs1.Name, fullname)
-		}
-		log.Printf("Added EncryptedVolume: %v", rs1)
-		rs3, err := c1.UpdateEncryptedVolume(ctx, &pb.UpdateEncryptedVolumeRequest{
-			UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"*"}},
-			EncryptedVolume: &pb.EncryptedVolume{
-				Name:          rs1.Name,
-				VolumeNameRef: "Malloc1",
-				Key:           []byte("0123456789abcdef0123456789abcdff"),
-				Cipher:        pb.EncryptionType_ENCRYPTION_TYPE_AES_XTS_128,
-			},
+			log.Printf("Updated EncryptedVolume: %v", rs3.Name)
+			return nil
 		})
-		if err != nil {
+		if r.Fatal(err) {
 			return err
 		}
-		log.Printf("Updated EncryptedVolume: %v", rs3)
-		rs4, err := c1.ListEncryptedVolumes(ctx, &pb.ListEncryptedVolumesRequest{Parent: "todo"})
-		if err != nil {
+		err = r.Step(caseName("ListEncryptedVolumes", resourceID), func() error {
+			rs4, err := c1.ListEncryptedVolumes(ctx, &pb.ListEncryptedVolumesRequest{Parent: "todo"})
+			if err != nil {
+				return err
+			}
+			log.Printf("Listed EncryptedVolume: %v", rs4)
+			return nil
+		})
+		if r.Fatal(err) {
 			return err
 		}
-		log.Printf("Listed EncryptedVolume: %v", rs4)
-		rs5, err := c1.GetEncryptedVolume(ctx, &pb.GetEncryptedVolumeRequest{Name: rs1.Name})
-		if err != nil {
+		err = r.Step(caseName("GetEncryptedVolume", resourceID), func() error {
+			rs5, err := c1.GetEncryptedVolume(ctx, &pb.GetEncryptedVolumeRequest{Name: rs1.Name})
+			if err != nil {
+				return err
+			}
+			log.Printf("Got EncryptedVolume: %s", rs5.Name)
+			return nil
+		})
+		if r.Fatal(err) {
 			return err
 		}
-		log.Printf("Got EncryptedVolume: %s", rs5.Name)
-		rs6, err := c1.StatsEncryptedVolume(ctx, &pb.StatsEncryptedVolumeRequest{Name: rs1.Name})
-		if err != nil {
+		err = r.Step(caseName("StatsEncryptedVolume", resourceID), func() error {
+			rs6, err := c1.StatsEncryptedVolume(ctx, &pb.StatsEncryptedVolumeRequest{Name: rs1.Name})
+			if err != nil {
+				return err
+			}
+			log.Printf("Stats EncryptedVolume: %s", rs6.Stats)
+			return nil
+		})
+		if r.Fatal(err) {
 			return err
 		}
-		log.Printf("Stats EncryptedVolume: %s", rs6.Stats)
-		rs2, err := c1.DeleteEncryptedVolume(ctx, &pb.DeleteEncryptedVolumeRequest{Name: rs1.Name})
+		err = r.Step(caseName("DeleteEncryptedVolume", resourceID), func() error {
+			rs2, err := c1.DeleteEncryptedVolume(ctx, &pb.DeleteEncryptedVolumeRequest{Name: rs1.Name})
+			if err != nil {
+				return err
+			}
+			r.Untrack(rs1.Name)
+			log.Printf("Deleted EncryptedVolume: %v -> %v", rs1.Name, rs2)
+			return nil
+		})
 		if err != nil {
 			return err
 		}
-		log.Printf("Deleted EncryptedVolume: %v -> %v", rs1, rs2)
 	}
 	return nil
 }
 
-func executeQosVolume(ctx context.Context, c2 pb.MiddleendQosVolumeServiceClient) error {
-	log.Printf("=======================================")
-	log.Printf("Testing NewMiddleendQosVolumeServiceClient")
-	log.Printf("=======================================")
+func executeQosVolume(ctx context.Context, c2 pb.MiddleendQosVolumeServiceClient, f *Fixtures, r *Report) (err error) {
+	r.Begin("QosVolume")
+	defer r.Cleanup(ctx, &err)
 
 	// testing with and without {resource}_id field
-	for _, resourceID := range []string{"opi-qos-volume3", ""} {
-		rs1, err := c2.CreateQosVolume(ctx, &pb.CreateQosVolumeRequest{
-			QosVolumeId: resourceID,
-			QosVolume: &pb.QosVolume{
-				VolumeNameRef: "Malloc1",
-				Limits: &pb.Limits{
-					Max: &pb.QosLimit{
-						RwBandwidthMbs: 2,
+	for _, resourceID := range []string{f.ID("opi-qos-volume3"), ""} {
+		var rs1 *pb.QosVolume
+		err := r.Step(caseName("CreateQosVolume", resourceID), func() (err error) {
+			rs1, err = c2.CreateQosVolume(ctx, &pb.CreateQosVolumeRequest{
+				QosVolumeId: resourceID,
+				QosVolume: &pb.QosVolume{
+					VolumeNameRef: f.Volume,
+					Limits: &pb.Limits{
+						Max: &pb.QosLimit{
+							RwBandwidthMbs: 2,
+						},
 					},
 				},
-			},
+			})
+			if err != nil {
+				return err
+			}
+			r.Track(rs1.Name, func(ctx context.Context) error {
+				_, err := c2.DeleteQosVolume(ctx, &pb.DeleteQosVolumeRequest{Name: rs1.Name, AllowMissing: true})
+				return err
+			})
+			log.Printf("Added QosVolume: %v", rs1)
+			return verifyResourceName(rs1.Name, resourceID, resourceIDToVolumeName)
 		})
 		if err != nil {
 			return err
 		}
-		// verify
-		newResourceID := resourceID
-		if resourceID == "" {
-			parsed, err := uuid.Parse(path.Base(rs1.Name))
+		err = r.Step(caseName("UpdateQosVolume", resourceID), func() error {
+			rs3, err := c2.UpdateQosVolume(ctx, &pb.UpdateQosVolumeRequest{
+				UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"*"}},
+				QosVolume: &pb.QosVolume{
+					Name:          rs1.Name,
+					VolumeNameRef: f.Volume,
+					Limits: &pb.Limits{
+						Max: &pb.QosLimit{
+							RdBandwidthMbs: 2,
+						},
+					},
+				},
+			})
 			if err != nil {
 				return err
 			}
-			newResourceID = parsed.String()
-		}
-		fullname := resourceIDToVolumeName(newResourceID)
-		if rs1.Name != fullname {
-			return fmt.Errorf("server filled value '%s' is not matching user requested '%s'", rs1.Name, fullname)
-		}
-		log.Printf("Added QosVolume: %v", rs1)
-		rs3, err := c2.UpdateQosVolume(ctx, &pb.UpdateQosVolumeRequest{
-			UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"*"}},
-			QosVolume: &pb.QosVolume{
-				Name:          rs1.Name,
-				VolumeNameRef: "Malloc1",
-				Limits: &pb.Limits{
-					Max: &pb.QosLimit{
-						RdBandwidthMbs: 2,
-					},
-				},
-			},
+			log.Printf("Updated QosVolume: %v", rs3)
+			return nil
 		})
-		if err != nil {
+		if r.Fatal(err) {
 			return err
 		}
-		log.Printf("Updated QosVolume: %v", rs3)
-		rs4, err := c2.ListQosVolumes(ctx, &pb.ListQosVolumesRequest{Parent: "todo"})
-		if err != nil {
+		err = r.Step(caseName("ListQosVolumes", resourceID), func() error {
+			rs4, err := c2.ListQosVolumes(ctx, &pb.ListQosVolumesRequest{Parent: "todo"})
+			if err != nil {
+				return err
+			}
+			log.Printf("Listed QosVolume: %v", rs4)
+			return nil
+		})
+		if r.Fatal(err) {
 			return err
 		}
-		log.Printf("Listed QosVolume: %v", rs4)
-		rs5, err := c2.GetQosVolume(ctx, &pb.GetQosVolumeRequest{Name: rs1.Name})
-		if err != nil {
+		err = r.Step(caseName("GetQosVolume", resourceID), func() error {
+			rs5, err := c2.GetQosVolume(ctx, &pb.GetQosVolumeRequest{Name: rs1.Name})
+			if err != nil {
+				return err
+			}
+			log.Printf("Got QosVolume: %v", rs5.Name)
+			return nil
+		})
+		if r.Fatal(err) {
 			return err
 		}
-		log.Printf("Got QosVolume: %v", rs5.Name)
-		rs6, err := c2.StatsQosVolume(ctx, &pb.StatsQosVolumeRequest{Name: rs1.Name})
-		if err != nil {
+		err = r.Step(caseName("StatsQosVolume", resourceID), func() error {
+			rs6, err := c2.StatsQosVolume(ctx, &pb.StatsQosVolumeRequest{Name: rs1.Name})
+			if err != nil {
+				return err
+			}
+			log.Printf("Stats QosVolume: %v", rs6.Stats)
+			return nil
+		})
+		if r.Fatal(err) {
 			return err
 		}
-		log.Printf("Stats QosVolume: %v", rs6.Stats)
-		rs2, err := c2.DeleteQosVolume(ctx, &pb.DeleteQosVolumeRequest{Name: rs1.Name})
+		err = r.Step(caseName("DeleteQosVolume", resourceID), func() error {
+			rs2, err := c2.DeleteQosVolume(ctx, &pb.DeleteQosVolumeRequest{Name: rs1.Name})
+			if err != nil {
+				return err
+			}
+			r.Untrack(rs1.Name)
+			log.Printf("Deleted QosVolume: %v -> %v", rs1, rs2)
+			return nil
+		})
 		if err != nil {
 			return err
 		}
-		log.Printf("Deleted QosVolume: %v -> %v", rs1, rs2)
 	}
 	return nil
 }
@@ -0,0 +1,122 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (C) 2024 Intel Corporation
+
+// Package test implements compliance storage tests
+package test
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"google.golang.org/grpc/codes"
+)
+
+// paginationResources is the number of resources listed by the pagination
+// tests
+const paginationResources = 5
+
+// paginationPageSizes are the page sizes the resources are listed with, 0
+// lets the server choose the page size
+var paginationPageSizes = []int32{0, 1, 2, 3, paginationResources, paginationResources + 1}
+
+// resourceNames returns the names of resources
+func resourceNames[T interface{ GetName() string }](resources []T) []string {
+	names := make([]string, 0, len(resources))
+	for _, resource := range resources {
+		names = append(names, resource.GetName())
+	}
+	return names
+}
+
+// executePaginationChecks creates resources and pages through them with
+// different page sizes to check that no resource is listed twice or missing
+// and that invalid page tokens and sizes are rejected
+func executePaginationChecks(ctx context.Context, c *resourceChecks, f *Fixtures, r *Report) (err error) {
+	r.Begin(c.kind + " pagination")
+	defer r.Cleanup(ctx, &err)
+
+	var created []string
+	err = r.Step("Create"+c.kind+" resources", func() error {
+		for i := range paginationResources {
+			id := f.ID(fmt.Sprintf("page-%v-%d", strings.ToLower(c.kind), i))
+			name, err := c.create(ctx, "", id)
+			if err != nil {
+				return err
+			}
+			r.Track(name, func(ctx context.Context) error {
+				return c.delete(ctx, name, true)
+			})
+			created = append(created, name)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, pageSize := range paginationPageSizes {
+		err = r.Step(fmt.Sprintf("List%vs with page size %d", c.kind, pageSize), func() error {
+			return verifyPages(ctx, c, pageSize, created)
+		})
+		if r.Fatal(err) {
+			return err
+		}
+	}
+	err = r.Step("List"+c.kind+"s with invalid page token", func() error {
+		_, _, err := c.list(ctx, 1, "invalid-page-token")
+		return expectCode(err, codes.InvalidArgument)
+	})
+	if r.Fatal(err) {
+		return err
+	}
+	err = r.Step("List"+c.kind+"s with negative page size", func() error {
+		_, _, err := c.list(ctx, -1, "")
+		return expectCode(err, codes.InvalidArgument)
+	})
+	if r.Fatal(err) {
+		return err
+	}
+
+	// post cleanup: resources
+	return r.Step("Delete"+c.kind+" resources", func() error {
+		for _, name := range created {
+			if err := c.delete(ctx, name, false); err != nil {
+				return err
+			}
+			r.Untrack(name)
+		}
+		return nil
+	})
+}
+
+// verifyPages lists all pages with pageSize and checks that every created
+// resource is listed exactly once
+func verifyPages(ctx context.Context, c *resourceChecks, pageSize int32, created []string) error {
+	listed := map[string]bool{}
+	_, err := listAll(func(pageToken string) ([]string, string, error) {
+		names, next, err := c.list(ctx, pageSize, pageToken)
+		if err != nil {
+			return nil, "", err
+		}
+		if pageSize > 0 && len(names) > int(pageSize) {
+			return nil, "", fmt.Errorf("page has %d resources, expected at most %d", len(names), pageSize)
+		}
+		for _, name := range names {
+			if listed[name] {
+				return nil, "", fmt.Errorf("%v is listed twice", name)
+			}
+			listed[name] = true
+		}
+		return names, next, nil
+	})
+	if err != nil {
+		return err
+	}
+	for _, name := range created {
+		if !listed[name] {
+			return fmt.Errorf("%v is not listed", name)
+		}
+	}
+	return nil
+}
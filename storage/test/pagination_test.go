@@ -0,0 +1,92 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (C) 2024 Intel Corporation
+
+// Package test implements compliance storage tests
+package test
+
+import (
+	"context"
+	"slices"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// fakePaginationChecks implements resources in memory. The page token is the
+// index of the first resource of the page, overlap is the number of
+// resources repeated on the next page.
+func fakePaginationChecks(resources map[string]bool, ignorePageSize bool, overlap int) *resourceChecks {
+	checks := fakeChecks(resources, false)
+	checks.list = func(_ context.Context, pageSize int32, pageToken string) ([]string, string, error) {
+		if pageSize < 0 {
+			return nil, "", status.Error(codes.InvalidArgument, "negative page size")
+		}
+		start := 0
+		if pageToken != "" {
+			var err error
+			start, err = strconv.Atoi(pageToken)
+			if err != nil {
+				return nil, "", status.Errorf(codes.InvalidArgument, "unable to find pagination token %v", pageToken)
+			}
+		}
+
+		var names []string
+		for name := range resources {
+			names = append(names, name)
+		}
+		slices.Sort(names)
+
+		end := len(names)
+		if pageSize > 0 && !ignorePageSize && start+int(pageSize) < end {
+			end = start + int(pageSize)
+		}
+		next := ""
+		if end < len(names) {
+			next = strconv.Itoa(end - overlap)
+		}
+		return names[start:end], next, nil
+	}
+	return checks
+}
+
+func TestExecutePaginationChecks(t *testing.T) {
+	tests := map[string]struct {
+		ignorePageSize bool
+		overlap        int
+		wantErr        string
+	}{
+		"compliant server": {},
+		"page size ignored": {
+			ignorePageSize: true,
+			wantErr:        "Fake pagination: ListFakes with page size 2: page has 5 resources, expected at most 2",
+		},
+		"overlapping pages": {
+			overlap: 1,
+			wantErr: "Fake pagination: ListFakes with page size 3: fakes/page-fake-2 is listed twice",
+		},
+	}
+
+	for testName, tt := range tests {
+		t.Run(testName, func(t *testing.T) {
+			resources := map[string]bool{}
+			checks := fakePaginationChecks(resources, tt.ignorePageSize, tt.overlap)
+
+			report := NewReport(true)
+			require.NoError(t, executePaginationChecks(context.Background(), checks, DefaultFixtures(), report))
+			if tt.wantErr == "" {
+				require.NoError(t, report.Err())
+			} else {
+				require.ErrorContains(t, report.Err(), tt.wantErr)
+			}
+			require.Empty(t, resources)
+		})
+	}
+}
+
+func TestVerifyField(t *testing.T) {
+	require.NoError(t, verifyField("block_size", 512, 512))
+	require.EqualError(t, verifyField("block_size", 4096, 512), "field block_size is 4096 after update, expected 512")
+}
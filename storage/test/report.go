@@ -0,0 +1,40 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (C) 2024 Intel Corporation
+
+// Package test implements compliance storage tests
+package test
+
+import (
+	"github.com/opiproject/godpu/testing/report"
+)
+
+// ReportFormat defines the output format of a report
+type ReportFormat = report.Format
+
+// Enumerates all report formats
+const (
+	ReportFormatJUnit = report.FormatJUnit
+	ReportFormatJSON  = report.FormatJSON
+)
+
+// AllReportFormats contains all supported report formats
+var AllReportFormats = report.AllFormats
+
+// Case is the result of a single step of the compliance tests
+type Case = report.Case
+
+// Report records every step of the compliance tests as a test case
+type Report = report.Report
+
+// NewReport creates an empty report of the storage tests
+func NewReport(continueOnFailure bool) *Report {
+	return report.New("storage", continueOnFailure)
+}
+
+// caseName names the steps run with and without the {resource}_id field
+func caseName(rpc string, resourceID string) string {
+	if resourceID == "" {
+		return rpc + " without resource id"
+	}
+	return rpc + " with resource id"
+}
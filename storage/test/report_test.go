@@ -0,0 +1,77 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (C) 2024 Intel Corporation
+
+// Package test implements compliance storage tests
+package test
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	pb "github.com/opiproject/opi-api/storage/v1alpha1/gen/go"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+func TestRunMiddleendContinueOnFailure(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	server := grpc.NewServer()
+	pb.RegisterMiddleendEncryptionServiceServer(server, &pb.UnimplementedMiddleendEncryptionServiceServer{})
+	pb.RegisterMiddleendQosVolumeServiceServer(server, &pb.UnimplementedMiddleendQosVolumeServiceServer{})
+	go func() { _ = server.Serve(listener) }()
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient(listener.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+
+	tests := map[string]struct {
+		continueOnFailure bool
+		wantCases         []string
+		wantErr           bool
+	}{
+		"abort on first failure": {
+			continueOnFailure: false,
+			wantCases:         []string{"EncryptedVolume: CreateEncryptedVolume with resource id"},
+			wantErr:           true,
+		},
+		"continue after failure": {
+			continueOnFailure: true,
+			wantCases: []string{
+				"EncryptedVolume: CreateEncryptedVolume with resource id",
+				"QosVolume: CreateQosVolume with resource id",
+				"QosVolume field masks: CreateQosVolume",
+				"EncryptedVolume errors: GetEncryptedVolume of missing resource",
+				"EncryptedVolume errors: DeleteEncryptedVolume of missing resource",
+				"EncryptedVolume errors: DeleteEncryptedVolume of missing resource with allow_missing",
+				"EncryptedVolume errors: GetEncryptedVolume with malformed name",
+				"EncryptedVolume errors: CreateEncryptedVolume",
+				"QosVolume errors: GetQosVolume of missing resource",
+				"QosVolume errors: DeleteQosVolume of missing resource",
+				"QosVolume errors: DeleteQosVolume of missing resource with allow_missing",
+				"QosVolume errors: GetQosVolume with malformed name",
+				"QosVolume errors: CreateQosVolume",
+			},
+		},
+	}
+
+	for testName, tt := range tests {
+		t.Run(testName, func(t *testing.T) {
+			report := NewReport(tt.continueOnFailure)
+			err := RunMiddleend(context.Background(), conn, DefaultFixtures(), report)
+			require.Equal(t, tt.wantErr, err != nil)
+
+			cases := []string{}
+			for _, c := range report.Cases {
+				require.True(t, c.Failed())
+				require.ErrorContains(t, c.Err, "Unimplemented")
+				cases = append(cases, c.Suite+": "+c.Name)
+			}
+			require.Equal(t, tt.wantCases, cases)
+			require.Equal(t, len(tt.wantCases), report.Failures())
+		})
+	}
+}
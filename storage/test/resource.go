@@ -4,7 +4,30 @@
 // Package test implements compliance storage tests
 package test
 
-import "go.einride.tech/aip/resourcename"
+import (
+	"fmt"
+	"path"
+
+	"github.com/google/uuid"
+	"go.einride.tech/aip/resourcename"
+)
+
+// verifyResourceName checks the name the server filled for a resource
+// created with or without {resource}_id field
+func verifyResourceName(name string, resourceID string, toName func(string) string) error {
+	if resourceID == "" {
+		parsed, err := uuid.Parse(path.Base(name))
+		if err != nil {
+			return err
+		}
+		resourceID = parsed.String()
+	}
+	fullname := toName(resourceID)
+	if name != fullname {
+		return fmt.Errorf("server filled value '%s' is not matching user requested '%s'", name, fullname)
+	}
+	return nil
+}
 
 func resourceIDToVolumeName(resourceID string) string {
 	return resourcename.Join(
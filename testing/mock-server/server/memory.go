@@ -0,0 +1,192 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (C) 2024 Intel Corporation
+
+// Package server implements mock gRPC services
+package server
+
+import (
+	"context"
+	"path"
+	"strings"
+	"sync"
+
+	pb "github.com/opiproject/opi-api/storage/v1alpha1/gen/go"
+	"go.einride.tech/aip/resourcename"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+// InMemory mock gRPC server keeping malloc volumes and frontend nvme
+// resources in memory. Unlike GoopCSI it needs no stubs, so it suits tests
+// creating and deleting resources with generated names.
+type InMemory struct {
+	pb.UnimplementedMallocVolumeServiceServer
+	pb.UnimplementedFrontendNvmeServiceServer
+
+	mu          sync.Mutex
+	volumes     map[string]*pb.MallocVolume
+	subsystems  map[string]*pb.NvmeSubsystem
+	namespaces  map[string]*pb.NvmeNamespace
+	controllers map[string]*pb.NvmeController
+}
+
+var _ pb.MallocVolumeServiceServer = &InMemory{}
+var _ pb.FrontendNvmeServiceServer = &InMemory{}
+
+// NewInMemory creates an InMemory server without any resources
+func NewInMemory() *InMemory {
+	return &InMemory{
+		volumes:     map[string]*pb.MallocVolume{},
+		subsystems:  map[string]*pb.NvmeSubsystem{},
+		namespaces:  map[string]*pb.NvmeNamespace{},
+		controllers: map[string]*pb.NvmeController{},
+	}
+}
+
+// Register registers the services of the server with a gRPC server
+func (s *InMemory) Register(server *grpc.Server) {
+	pb.RegisterMallocVolumeServiceServer(server, s)
+	pb.RegisterFrontendNvmeServiceServer(server, s)
+}
+
+// CreateMallocVolume creates a mock MallocVolume
+func (s *InMemory) CreateMallocVolume(_ context.Context, request *pb.CreateMallocVolumeRequest) (*pb.MallocVolume, error) {
+	volume := proto.Clone(request.GetMallocVolume()).(*pb.MallocVolume)
+	volume.Name = resourcename.Join("volumes", request.GetMallocVolumeId())
+	return volume, create(&s.mu, s.volumes, volume.Name, volume)
+}
+
+// DeleteMallocVolume deletes a mock MallocVolume
+func (s *InMemory) DeleteMallocVolume(_ context.Context, request *pb.DeleteMallocVolumeRequest) (*emptypb.Empty, error) {
+	return &emptypb.Empty{}, remove(&s.mu, s.volumes, request.GetName(), request.GetAllowMissing())
+}
+
+// GetMallocVolume gets a mock MallocVolume
+func (s *InMemory) GetMallocVolume(_ context.Context, request *pb.GetMallocVolumeRequest) (*pb.MallocVolume, error) {
+	return get(&s.mu, s.volumes, request.GetName())
+}
+
+// CreateNvmeSubsystem creates a mock Nvme subsystem
+func (s *InMemory) CreateNvmeSubsystem(_ context.Context, request *pb.CreateNvmeSubsystemRequest) (*pb.NvmeSubsystem, error) {
+	subsystem := proto.Clone(request.GetNvmeSubsystem()).(*pb.NvmeSubsystem)
+	subsystem.Name = resourcename.Join("nvmeSubsystems", request.GetNvmeSubsystemId())
+	return subsystem, create(&s.mu, s.subsystems, subsystem.Name, subsystem)
+}
+
+// DeleteNvmeSubsystem deletes a mock Nvme subsystem
+func (s *InMemory) DeleteNvmeSubsystem(_ context.Context, request *pb.DeleteNvmeSubsystemRequest) (*emptypb.Empty, error) {
+	return &emptypb.Empty{}, remove(&s.mu, s.subsystems, request.GetName(), request.GetAllowMissing())
+}
+
+// GetNvmeSubsystem gets a mock Nvme subsystem
+func (s *InMemory) GetNvmeSubsystem(_ context.Context, request *pb.GetNvmeSubsystemRequest) (*pb.NvmeSubsystem, error) {
+	return get(&s.mu, s.subsystems, request.GetName())
+}
+
+// CreateNvmeNamespace creates a mock Nvme namespace in an existing subsystem
+func (s *InMemory) CreateNvmeNamespace(_ context.Context, request *pb.CreateNvmeNamespaceRequest) (*pb.NvmeNamespace, error) {
+	if _, err := get(&s.mu, s.subsystems, request.GetParent()); err != nil {
+		return nil, err
+	}
+	namespace := proto.Clone(request.GetNvmeNamespace()).(*pb.NvmeNamespace)
+	namespace.Name = resourcename.Join(request.GetParent(), "nvmeNamespaces", request.GetNvmeNamespaceId())
+	return namespace, create(&s.mu, s.namespaces, namespace.Name, namespace)
+}
+
+// DeleteNvmeNamespace deletes a mock Nvme namespace
+func (s *InMemory) DeleteNvmeNamespace(_ context.Context, request *pb.DeleteNvmeNamespaceRequest) (*emptypb.Empty, error) {
+	return &emptypb.Empty{}, remove(&s.mu, s.namespaces, request.GetName(), request.GetAllowMissing())
+}
+
+// GetNvmeNamespace gets a mock Nvme namespace
+func (s *InMemory) GetNvmeNamespace(_ context.Context, request *pb.GetNvmeNamespaceRequest) (*pb.NvmeNamespace, error) {
+	return get(&s.mu, s.namespaces, request.GetName())
+}
+
+// ListNvmeNamespaces lists the mock namespaces of a subsystem
+func (s *InMemory) ListNvmeNamespaces(_ context.Context, request *pb.ListNvmeNamespacesRequest) (*pb.ListNvmeNamespacesResponse, error) {
+	if _, err := get(&s.mu, s.subsystems, request.GetParent()); err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	response := &pb.ListNvmeNamespacesResponse{}
+	for name, namespace := range s.namespaces {
+		if strings.HasPrefix(name, request.GetParent()+"/") {
+			response.NvmeNamespaces = append(response.NvmeNamespaces, namespace)
+		}
+	}
+	return response, nil
+}
+
+// CreateNvmeController creates a mock Nvme controller in an existing subsystem
+func (s *InMemory) CreateNvmeController(_ context.Context, request *pb.CreateNvmeControllerRequest) (*pb.NvmeController, error) {
+	if _, err := get(&s.mu, s.subsystems, request.GetParent()); err != nil {
+		return nil, err
+	}
+	controller := proto.Clone(request.GetNvmeController()).(*pb.NvmeController)
+	controller.Name = resourcename.Join(request.GetParent(), "nvmeControllers", request.GetNvmeControllerId())
+	return controller, create(&s.mu, s.controllers, controller.Name, controller)
+}
+
+// DeleteNvmeController deletes a mock Nvme controller
+func (s *InMemory) DeleteNvmeController(_ context.Context, request *pb.DeleteNvmeControllerRequest) (*emptypb.Empty, error) {
+	return &emptypb.Empty{}, remove(&s.mu, s.controllers, request.GetName(), request.GetAllowMissing())
+}
+
+// GetNvmeController gets a mock Nvme controller
+func (s *InMemory) GetNvmeController(_ context.Context, request *pb.GetNvmeControllerRequest) (*pb.NvmeController, error) {
+	return get(&s.mu, s.controllers, request.GetName())
+}
+
+// ExposedNamespace checks if a namespace with the nguid is in the subsystem
+// with the nqn
+func (s *InMemory) ExposedNamespace(nqn, nguid string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for name, namespace := range s.namespaces {
+		subsystem := s.subsystems[path.Dir(path.Dir(name))]
+		if subsystem.GetSpec().GetNqn() == nqn && namespace.GetSpec().GetNguid() == nguid {
+			return true
+		}
+	}
+	return false
+}
+
+func create[T proto.Message](mu *sync.Mutex, resources map[string]T, name string, resource T) error {
+	mu.Lock()
+	defer mu.Unlock()
+
+	if _, ok := resources[name]; ok {
+		return status.Errorf(codes.AlreadyExists, "%v already exists", name)
+	}
+	resources[name] = resource
+	return nil
+}
+
+func get[T proto.Message](mu *sync.Mutex, resources map[string]T, name string) (T, error) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	resource, ok := resources[name]
+	if !ok {
+		return resource, status.Errorf(codes.NotFound, "unable to find key %v", name)
+	}
+	return resource, nil
+}
+
+func remove[T proto.Message](mu *sync.Mutex, resources map[string]T, name string, allowMissing bool) error {
+	mu.Lock()
+	defer mu.Unlock()
+
+	if _, ok := resources[name]; !ok && !allowMissing {
+		return status.Errorf(codes.NotFound, "unable to find key %v", name)
+	}
+	delete(resources, name)
+	return nil
+}
@@ -16,6 +16,8 @@ import (
 	"net/http"
 
 	"golang.org/x/net/context"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/emptypb"
 
 	pb "github.com/opiproject/opi-api/storage/v1alpha1/gen/go"
@@ -99,8 +101,11 @@ func (s *GoopCSI) ListNvmeRemoteControllers(_ context2.Context, request *pb.List
 // GetNvmeRemoteController Gets an Nvme Remote controller
 func (s *GoopCSI) GetNvmeRemoteController(_ context2.Context, request *pb.GetNvmeRemoteControllerRequest) (*pb.NvmeRemoteController, error) {
 	out := &pb.NvmeRemoteController{}
-	err := FindStub("NvmeRemoteControllerServiceServer", "GetNvmeRemoteController", request, out)
-	return out, err
+	if err := FindStub("NvmeRemoteControllerServiceServer", "GetNvmeRemoteController", request, out); err != nil {
+		// a controller without stub does not exist
+		return nil, status.Error(codes.NotFound, err.Error())
+	}
+	return out, nil
 }
 
 // ResetNvmeRemoteController Resets mock Remote Controller
@@ -116,15 +121,17 @@ func (s *GoopCSI) StatsNvmeRemoteController(_ context2.Context, _ *pb.StatsNvmeR
 }
 
 // ListNvmeRemoteNamespaces lists mock remote nvme namespaces
-func (s *GoopCSI) ListNvmeRemoteNamespaces(context2.Context, *pb.ListNvmeRemoteNamespacesRequest) (*pb.ListNvmeRemoteNamespacesResponse, error) {
-	// TODO implement me
-	panic("implement me")
+func (s *GoopCSI) ListNvmeRemoteNamespaces(_ context2.Context, request *pb.ListNvmeRemoteNamespacesRequest) (*pb.ListNvmeRemoteNamespacesResponse, error) {
+	out := &pb.ListNvmeRemoteNamespacesResponse{}
+	err := FindStub("NvmeRemoteControllerServiceServer", "ListNvmeRemoteNamespaces", request, out)
+	return out, err
 }
 
 // GetNvmeRemoteNamespace gets mock remote nvme namespace
-func (s *GoopCSI) GetNvmeRemoteNamespace(_ context2.Context, _ *pb.GetNvmeRemoteNamespaceRequest) (*pb.NvmeRemoteNamespace, error) {
-	// TODO implement me
-	panic("implement me")
+func (s *GoopCSI) GetNvmeRemoteNamespace(_ context2.Context, request *pb.GetNvmeRemoteNamespaceRequest) (*pb.NvmeRemoteNamespace, error) {
+	out := &pb.NvmeRemoteNamespace{}
+	err := FindStub("NvmeRemoteControllerServiceServer", "GetNvmeRemoteNamespace", request, out)
+	return out, err
 }
 
 // CreateNvmePath creates mock nvme path
@@ -148,9 +155,10 @@ func (s *GoopCSI) UpdateNvmePath(_ context2.Context, _ *pb.UpdateNvmePathRequest
 }
 
 // ListNvmePaths Lists mock NvmeRemote Paths
-func (s *GoopCSI) ListNvmePaths(_ context2.Context, _ *pb.ListNvmePathsRequest) (*pb.ListNvmePathsResponse, error) {
-	// TODO implement me
-	panic("implement me")
+func (s *GoopCSI) ListNvmePaths(_ context2.Context, request *pb.ListNvmePathsRequest) (*pb.ListNvmePathsResponse, error) {
+	out := &pb.ListNvmePathsResponse{}
+	err := FindStub("NvmeRemoteControllerServiceServer", "ListNvmePaths", request, out)
+	return out, err
 }
 
 // GetNvmePath Gets an Nvme Remote Path
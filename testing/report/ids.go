@@ -0,0 +1,27 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (C) 2024 Intel Corporation
+
+// Package report records the steps of compliance tests and writes them as
+// JUnit XML or JSON report
+package report
+
+import "github.com/google/uuid"
+
+// ResourceIDs derives the ids of the resources created by compliance tests
+type ResourceIDs struct {
+	// Prefix is prepended to the ids of all created resources
+	Prefix string `json:"prefix"`
+	// Suffix is appended to the ids of all created resources, e.g. a
+	// RandomSuffix to run tests against the same DPU in parallel
+	Suffix string `json:"suffix"`
+}
+
+// RandomSuffix returns a suffix isolating the resources of a test run
+func RandomSuffix() string {
+	return "-" + uuid.NewString()[:8]
+}
+
+// ID returns the id of a created resource
+func (r ResourceIDs) ID(name string) string {
+	return r.Prefix + name + r.Suffix
+}
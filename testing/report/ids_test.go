@@ -0,0 +1,19 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (C) 2024 Intel Corporation
+
+// Package report records the steps of compliance tests and writes them as
+// JUnit XML or JSON report
+package report
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestResourceIDs(t *testing.T) {
+	ids := ResourceIDs{Prefix: "ci-", Suffix: RandomSuffix()}
+	require.Len(t, ids.Suffix, 9)
+	require.NotEqual(t, ids.Suffix, RandomSuffix())
+	require.Equal(t, "ci-subsystem-test"+ids.Suffix, ids.ID("subsystem-test"))
+}
@@ -0,0 +1,344 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (C) 2024 Intel Corporation
+
+// Package report records the steps of compliance tests and writes them as
+// JUnit XML or JSON report
+package report
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Format defines the output format of a report
+type Format string
+
+// Enumerates all report formats
+const (
+	FormatJUnit Format = "junit"
+	FormatJSON  Format = "json"
+)
+
+// AllFormats contains all supported report formats
+var AllFormats = []Format{
+	FormatJUnit,
+	FormatJSON,
+}
+
+// CleanupTimeout bounds deleting the resources of a test, which may run
+// after the context of the tests was cancelled
+const CleanupTimeout = 30 * time.Second
+
+// Case is the result of a single step of the compliance tests
+type Case struct {
+	// Suite is the tested resource type, e.g. NvmeSubsystem
+	Suite string
+	// Name is the step, e.g. CreateNvmeSubsystem with resource id
+	Name     string
+	Duration time.Duration
+	// Err is nil if the step passed
+	Err error
+	// Skipped is set if a step of an optional API failed
+	Skipped bool
+}
+
+// Failed returns true if the step failed
+func (c *Case) Failed() bool {
+	return c.Err != nil && !c.Skipped
+}
+
+// Report records every step of the compliance tests as a test case
+type Report struct {
+	// Name is the name of the tested API, e.g. storage
+	Name string
+	// ContinueOnFailure runs the remaining steps of a resource type and the
+	// remaining resource types after a failed step instead of returning the
+	// error of the step. Steps depending on a failed step are not run.
+	ContinueOnFailure bool
+	Cases             []Case
+
+	suite   string
+	created []createdResource
+}
+
+// New creates an empty report of the tests of an API
+func New(name string, continueOnFailure bool) *Report {
+	return &Report{Name: name, ContinueOnFailure: continueOnFailure}
+}
+
+// Failures returns the number of failed steps
+func (r *Report) Failures() int {
+	failures := 0
+	for i := range r.Cases {
+		if r.Cases[i].Failed() {
+			failures++
+		}
+	}
+	return failures
+}
+
+// Err returns the errors of all failed steps, nil if no step failed
+func (r *Report) Err() error {
+	var errs []error
+	for i := range r.Cases {
+		if r.Cases[i].Failed() {
+			errs = append(errs, fmt.Errorf("%v: %v: %w", r.Cases[i].Suite, r.Cases[i].Name, r.Cases[i].Err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Write writes the report in the given format
+func (r *Report) Write(w io.Writer, format Format) error {
+	switch format {
+	case FormatJUnit:
+		return r.WriteJUnit(w)
+	case FormatJSON:
+		return r.WriteJSON(w)
+	default:
+		return fmt.Errorf("unknown report format: %v", format)
+	}
+}
+
+// Begin starts recording the steps of a resource type
+func (r *Report) Begin(suite string) {
+	r.suite = suite
+	log.Printf("=======================================")
+	log.Printf("Testing %v", suite)
+	log.Printf("=======================================")
+}
+
+// Step runs fn and records it as a test case. The error of fn is returned.
+func (r *Report) Step(name string, fn func() error) error {
+	start := time.Now()
+	err := fn()
+	r.Cases = append(r.Cases, Case{
+		Suite:    r.suite,
+		Name:     name,
+		Duration: time.Since(start),
+		Err:      err,
+	})
+	if err != nil {
+		log.Printf("%v: %v failed: %v", r.suite, name, err)
+	}
+	return err
+}
+
+// OptionalStep runs fn of an API, which is not implemented by every server.
+// A failure is recorded as skipped test case.
+func (r *Report) OptionalStep(name string, fn func() error) {
+	if r.Step(name, fn) != nil {
+		r.Cases[len(r.Cases)-1].Skipped = true
+	}
+}
+
+// Fatal returns true if the error of a step has to stop the tests. The
+// tests are always stopped if they were cancelled.
+func (r *Report) Fatal(err error) bool {
+	return err != nil && (!r.ContinueOnFailure || Canceled(err))
+}
+
+// CleanupFunc deletes a resource created by a step
+type CleanupFunc func(ctx context.Context) error
+
+// createdResource is a resource created by a step, which is deleted if the
+// test exits before deleting it
+type createdResource struct {
+	name    string
+	cleanup CleanupFunc
+}
+
+// Track registers a resource created by a step
+func (r *Report) Track(name string, cleanup CleanupFunc) {
+	r.created = append(r.created, createdResource{name: name, cleanup: cleanup})
+}
+
+// Untrack removes a resource deleted by a step
+func (r *Report) Untrack(name string) {
+	for i := len(r.created) - 1; i >= 0; i-- {
+		if r.created[i].name == name {
+			r.created = append(r.created[:i], r.created[i+1:]...)
+			return
+		}
+	}
+}
+
+// Cleanup deletes the resources left behind by a test in reverse order of
+// creation. It is deferred by every test, so resources are deleted on any
+// exit path, including cancellation of ctx. The first cleanup error is
+// stored in err unless it already holds the error of the test.
+func (r *Report) Cleanup(ctx context.Context, err *error) {
+	if len(r.created) == 0 {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), CleanupTimeout)
+	defer cancel()
+
+	for i := len(r.created) - 1; i >= 0; i-- {
+		resource := r.created[i]
+		cleanupErr := r.Step("cleanup "+resource.name, func() error {
+			return resource.cleanup(ctx)
+		})
+		if cleanupErr != nil && *err == nil {
+			*err = cleanupErr
+		}
+	}
+	r.created = nil
+}
+
+// Canceled returns true if err is caused by the cancellation of the tests
+func Canceled(err error) bool {
+	switch status.Code(err) {
+	case codes.Canceled, codes.DeadlineExceeded:
+		return true
+	}
+	return errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded)
+}
+
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Skipped  int              `xml:"skipped,attr"`
+	Time     string           `xml:"time,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name     string          `xml:"name,attr"`
+	Tests    int             `xml:"tests,attr"`
+	Failures int             `xml:"failures,attr"`
+	Skipped  int             `xml:"skipped,attr"`
+	Time     string          `xml:"time,attr"`
+	Cases    []junitTestCase `xml:"testcase"`
+
+	duration time.Duration
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	Classname string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitMessage `xml:"failure,omitempty"`
+	Skipped   *junitMessage `xml:"skipped,omitempty"`
+}
+
+type junitMessage struct {
+	Message string `xml:"message,attr"`
+}
+
+// WriteJUnit writes the report as JUnit XML with a test suite per resource
+// type
+func (r *Report) WriteJUnit(w io.Writer) error {
+	result := junitTestSuites{Name: r.Name}
+	var total time.Duration
+	index := map[string]int{}
+	for i := range r.Cases {
+		c := &r.Cases[i]
+		j, ok := index[c.Suite]
+		if !ok {
+			j = len(result.Suites)
+			index[c.Suite] = j
+			result.Suites = append(result.Suites, junitTestSuite{Name: c.Suite})
+		}
+		suite := &result.Suites[j]
+
+		testCase := junitTestCase{
+			Name:      c.Name,
+			Classname: c.Suite,
+			Time:      seconds(c.Duration),
+		}
+		switch {
+		case c.Skipped:
+			testCase.Skipped = &junitMessage{Message: c.Err.Error()}
+			suite.Skipped++
+			result.Skipped++
+		case c.Err != nil:
+			testCase.Failure = &junitMessage{Message: c.Err.Error()}
+			suite.Failures++
+			result.Failures++
+		}
+		suite.Cases = append(suite.Cases, testCase)
+		suite.Tests++
+		suite.duration += c.Duration
+		result.Tests++
+		total += c.Duration
+	}
+	for i := range result.Suites {
+		result.Suites[i].Time = seconds(result.Suites[i].duration)
+	}
+	result.Time = seconds(total)
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(result); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+type jsonReport struct {
+	Tests    int        `json:"tests"`
+	Failures int        `json:"failures"`
+	Skipped  int        `json:"skipped"`
+	Time     float64    `json:"time"`
+	Cases    []jsonCase `json:"cases"`
+}
+
+type jsonCase struct {
+	Suite   string  `json:"suite"`
+	Name    string  `json:"name"`
+	Time    float64 `json:"time"`
+	Error   string  `json:"error,omitempty"`
+	Skipped bool    `json:"skipped,omitempty"`
+}
+
+// WriteJSON writes the report as JSON. Durations are in seconds.
+func (r *Report) WriteJSON(w io.Writer) error {
+	result := jsonReport{Cases: []jsonCase{}}
+	var total time.Duration
+	for i := range r.Cases {
+		c := &r.Cases[i]
+		testCase := jsonCase{
+			Suite:   c.Suite,
+			Name:    c.Name,
+			Time:    c.Duration.Seconds(),
+			Skipped: c.Skipped,
+		}
+		if c.Err != nil {
+			testCase.Error = c.Err.Error()
+		}
+		switch {
+		case c.Skipped:
+			result.Skipped++
+		case c.Err != nil:
+			result.Failures++
+		}
+		result.Cases = append(result.Cases, testCase)
+		result.Tests++
+		total += c.Duration
+	}
+	result.Time = total.Seconds()
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(result)
+}
+
+func seconds(d time.Duration) string {
+	return fmt.Sprintf("%.3f", d.Seconds())
+}
@@ -0,0 +1,142 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (C) 2024 Intel Corporation
+
+// Package report records the steps of compliance tests and writes them as
+// JUnit XML or JSON report
+package report
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func newTestReport() *Report {
+	return &Report{Name: "storage", Cases: []Case{
+		{Suite: "NullVolume", Name: "CreateNullVolume with resource id", Duration: 1500 * time.Millisecond},
+		{Suite: "NullVolume", Name: "GetNullVolume with resource id", Duration: 250 * time.Millisecond, Err: errors.New("not found")},
+		{Suite: "VirtioBlk", Name: "StatsVirtioBlk with resource id", Duration: 250 * time.Millisecond, Err: errors.New("unimplemented"), Skipped: true},
+	}}
+}
+
+func TestReportErr(t *testing.T) {
+	report := newTestReport()
+	require.Equal(t, 1, report.Failures())
+	require.EqualError(t, report.Err(), "NullVolume: GetNullVolume with resource id: not found")
+
+	require.NoError(t, New("storage", false).Err())
+}
+
+func TestReportWriteJUnit(t *testing.T) {
+	var out bytes.Buffer
+	require.NoError(t, newTestReport().Write(&out, FormatJUnit))
+
+	require.Equal(t, `<?xml version="1.0" encoding="UTF-8"?>
+<testsuites name="storage" tests="3" failures="1" skipped="1" time="2.000">
+  <testsuite name="NullVolume" tests="2" failures="1" skipped="0" time="1.750">
+    <testcase name="CreateNullVolume with resource id" classname="NullVolume" time="1.500"></testcase>
+    <testcase name="GetNullVolume with resource id" classname="NullVolume" time="0.250">
+      <failure message="not found"></failure>
+    </testcase>
+  </testsuite>
+  <testsuite name="VirtioBlk" tests="1" failures="0" skipped="1" time="0.250">
+    <testcase name="StatsVirtioBlk with resource id" classname="VirtioBlk" time="0.250">
+      <skipped message="unimplemented"></skipped>
+    </testcase>
+  </testsuite>
+</testsuites>
+`, out.String())
+}
+
+func TestReportWriteJSON(t *testing.T) {
+	var out bytes.Buffer
+	require.NoError(t, newTestReport().Write(&out, FormatJSON))
+
+	var result jsonReport
+	require.NoError(t, json.Unmarshal(out.Bytes(), &result))
+	require.Equal(t, jsonReport{
+		Tests:    3,
+		Failures: 1,
+		Skipped:  1,
+		Time:     2,
+		Cases: []jsonCase{
+			{Suite: "NullVolume", Name: "CreateNullVolume with resource id", Time: 1.5},
+			{Suite: "NullVolume", Name: "GetNullVolume with resource id", Time: 0.25, Error: "not found"},
+			{Suite: "VirtioBlk", Name: "StatsVirtioBlk with resource id", Time: 0.25, Error: "unimplemented", Skipped: true},
+		},
+	}, result)
+}
+
+func TestReportWriteUnknownFormat(t *testing.T) {
+	require.EqualError(t, newTestReport().Write(&bytes.Buffer{}, "yaml"), "unknown report format: yaml")
+}
+
+func TestReportCleanup(t *testing.T) {
+	tests := map[string]struct {
+		testErr    error
+		cleanupErr error
+		wantErr    string
+	}{
+		"cleanup after success": {},
+		"test error is kept": {
+			testErr:    errors.New("test failed"),
+			cleanupErr: errors.New("delete failed"),
+			wantErr:    "test failed",
+		},
+		"cleanup error is returned": {
+			cleanupErr: errors.New("delete failed"),
+			wantErr:    "delete failed",
+		},
+	}
+
+	for testName, tt := range tests {
+		t.Run(testName, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+
+			var deleted []string
+			track := func(r *Report, name string, err error) {
+				r.Track(name, func(ctx context.Context) error {
+					require.NoError(t, ctx.Err())
+					deleted = append(deleted, name)
+					return err
+				})
+			}
+
+			report := New("storage", false)
+			err := func() (err error) {
+				defer report.Cleanup(ctx, &err)
+				track(report, "first", nil)
+				track(report, "second", tt.cleanupErr)
+				track(report, "third", nil)
+				report.Untrack("third")
+				return tt.testErr
+			}()
+
+			if tt.wantErr == "" {
+				require.NoError(t, err)
+			} else {
+				require.EqualError(t, err, tt.wantErr)
+			}
+			require.Equal(t, []string{"second", "first"}, deleted)
+			require.Len(t, report.Cases, 2)
+			require.Equal(t, "cleanup second", report.Cases[0].Name)
+			require.Equal(t, "cleanup first", report.Cases[1].Name)
+			require.Empty(t, report.created)
+		})
+	}
+}
+
+func TestCanceled(t *testing.T) {
+	require.True(t, Canceled(context.Canceled))
+	require.True(t, Canceled(status.Error(codes.DeadlineExceeded, "timeout")))
+	require.False(t, Canceled(status.Error(codes.NotFound, "missing")))
+	require.False(t, Canceled(errors.New("boom")))
+}